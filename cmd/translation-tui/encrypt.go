@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// Environment variable holding the passphrase of an encrypted data directory
+const envPassphrase = "TRANSLATION_TUI_PASSPHRASE"
+
+// runEncrypt implements the "encrypt" command, which encrypts the study data with a
+// passphrase or changes it.
+func runEncrypt(args []string) error {
+	fs := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: translation-tui encrypt")
+		fmt.Fprintf(fs.Output(), "Encrypts the history, vocab deck, dictionary, cache, offline queue, draft, conjugation tables and session transcripts with a new passphrase (or $%s).\n", envPassphrase)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if storage.Encrypted() {
+		if err := unlockStorage(); err != nil {
+			return err
+		}
+	}
+	passphrase := os.Getenv(envPassphrase)
+	if passphrase == "" || storage.Encrypted() {
+		var err error
+		if passphrase, err = askNewPassphrase(); err != nil {
+			return err
+		}
+	}
+	if err := storage.EncryptAll(passphrase); err != nil {
+		return err
+	}
+	fmt.Println("The study data is encrypted. Keep the passphrase safe: the data cannot be read without it.")
+	return nil
+}
+
+// runDecrypt implements the "decrypt" command, which stores the study data
+// unencrypted again.
+func runDecrypt(args []string) error {
+	fs := flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: translation-tui decrypt")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !storage.Encrypted() {
+		fmt.Println("The study data is not encrypted.")
+		return nil
+	}
+	if err := unlockStorage(); err != nil {
+		return err
+	}
+	if err := storage.DecryptAll(); err != nil {
+		return err
+	}
+	fmt.Println("The study data is no longer encrypted.")
+	return nil
+}
+
+// unlockStorage unlocks an encrypted data directory with the passphrase from the
+// environment, or asked for on the terminal.
+func unlockStorage() error {
+	if !storage.Encrypted() {
+		return nil
+	}
+	if passphrase := os.Getenv(envPassphrase); passphrase != "" {
+		return storage.Unlock(passphrase)
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return fmt.Errorf("%w: set %s", storage.ErrLocked, envPassphrase)
+	}
+	for attempt := 1; ; attempt++ {
+		passphrase, err := readPassphrase("Passphrase: ")
+		if err != nil {
+			return err
+		}
+		err = storage.Unlock(passphrase)
+		if !errors.Is(err, storage.ErrPassphrase) || attempt == 3 {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "Wrong passphrase, try again.")
+	}
+}
+
+// askNewPassphrase asks for a new passphrase twice on the terminal.
+func askNewPassphrase() (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("no terminal to ask for the passphrase; set %s", envPassphrase)
+	}
+	passphrase, err := readPassphrase("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("the passphrase must not be empty")
+	}
+	again, err := readPassphrase("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", errors.New("the passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// readPassphrase asks for a passphrase on the terminal without echoing it.
+func readPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	data, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(data), nil
+}
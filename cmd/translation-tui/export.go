@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/export"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+const dateLayout = "2006-01-02"
+
+// exportColumn describes one selectable column of an export.
+type exportColumn[T any] struct {
+	name  string
+	value func(T) string
+}
+
+// historyColumns lists the columns available when exporting history, in default order.
+var historyColumns = []exportColumn[storage.HistoryEntry]{
+	{"time", func(e storage.HistoryEntry) string { return e.Time.Format(time.RFC3339) }},
+	{"user_lang", func(e storage.HistoryEntry) string { return e.UserLang }},
+	{"target_lang", func(e storage.HistoryEntry) string { return e.TargetLang }},
+	{"original", func(e storage.HistoryEntry) string { return e.Original }},
+	{"translation", func(e storage.HistoryEntry) string { return e.Translation }},
+	{"words", func(e storage.HistoryEntry) string { return formatWords(e.Words) }},
+}
+
+// vocabColumns lists the columns available when exporting the vocab deck, in default order.
+var vocabColumns = []exportColumn[storage.VocabCard]{
+	{"word", func(c storage.VocabCard) string { return c.Word }},
+	{"analysis", func(c storage.VocabCard) string { return c.Analysis }},
+	{"lang", func(c storage.VocabCard) string { return c.Lang }},
+	{"sentence", func(c storage.VocabCard) string { return c.Sentence }},
+	{"translation", func(c storage.VocabCard) string { return c.Translation }},
+	{"added", func(c storage.VocabCard) string { return c.Added.Format(time.RFC3339) }},
+	{"level", func(c storage.VocabCard) string { return c.Level }},
+	{"priority", func(c storage.VocabCard) string { return strconv.FormatBool(c.Priority) }},
+}
+
+// dictionaryColumns lists the columns available when exporting the personal dictionary,
+// in default order.
+var dictionaryColumns = []exportColumn[storage.DictionaryEntry]{
+	{"lemma", func(e storage.DictionaryEntry) string { return e.Lemma }},
+	{"gloss", func(e storage.DictionaryEntry) string { return e.Gloss }},
+	{"lang", func(e storage.DictionaryEntry) string { return e.Lang }},
+	{"pos", func(e storage.DictionaryEntry) string { return e.PartOfSpeech }},
+	{"gender", func(e storage.DictionaryEntry) string { return e.Gender }},
+	{"level", func(e storage.DictionaryEntry) string { return e.Level }},
+	{"grammar", func(e storage.DictionaryEntry) string { return e.Grammar }},
+	{"forms", func(e storage.DictionaryEntry) string { return strings.Join(e.Forms, "; ") }},
+	{"sentences", func(e storage.DictionaryEntry) string { return strings.Join(e.Sentences, " | ") }},
+	{"first_seen", func(e storage.DictionaryEntry) string { return e.FirstSeen.Format(time.RFC3339) }},
+	{"last_seen", func(e storage.DictionaryEntry) string { return e.LastSeen.Format(time.RFC3339) }},
+	{"seen", func(e storage.DictionaryEntry) string { return strconv.Itoa(e.Seen) }},
+}
+
+// exportOptions holds the parsed flags of the export command.
+type exportOptions struct {
+	format  string
+	columns string
+	from    time.Time
+	to      time.Time
+	output  string
+}
+
+// runExport implements the "export" command: export <history|vocab|dictionary> [flags].
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "csv", "output format: csv, tsv or json, text, markdown or html for history, or org for vocab")
+	columns := fs.String("columns", "", "comma-separated list of columns (default: all)")
+	from := fs.String("from", "", "only include items on or after this date (YYYY-MM-DD)")
+	to := fs.String("to", "", "only include items on or before this date (YYYY-MM-DD)")
+	output := fs.String("o", "", "output file (default: stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: translation-tui export <history|vocab|dictionary> [flags]")
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 {
+		fs.Usage()
+		return fmt.Errorf("missing export target")
+	}
+	target := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	opts := exportOptions{format: *format, columns: *columns, output: *output}
+	document := slices.Contains(export.Formats, opts.format)
+	switch {
+	case opts.format == "csv", opts.format == "tsv", opts.format == "json":
+	case document && target != "history":
+		return fmt.Errorf("format %q is only available for history", opts.format)
+	case opts.format == export.FormatOrg && target != "vocab":
+		return fmt.Errorf("format %q is only available for vocab", opts.format)
+	case !document && opts.format != export.FormatOrg:
+		return fmt.Errorf("unknown format %q (use csv, tsv, json, text, markdown, html or org)", opts.format)
+	}
+	var err error
+	if *from != "" {
+		if opts.from, err = time.ParseInLocation(dateLayout, *from, time.Local); err != nil {
+			return fmt.Errorf("invalid --from date: %w", err)
+		}
+	}
+	if *to != "" {
+		if opts.to, err = time.ParseInLocation(dateLayout, *to, time.Local); err != nil {
+			return fmt.Errorf("invalid --to date: %w", err)
+		}
+		opts.to = opts.to.AddDate(0, 0, 1) // Make the end date inclusive
+	}
+
+	out := io.Writer(os.Stdout)
+	if opts.output != "" {
+		f, err := os.Create(opts.output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch target {
+	case "history":
+		entries, err := storage.LoadHistory()
+		if err != nil {
+			return err
+		}
+		date := func(e storage.HistoryEntry) time.Time { return e.Time }
+		if document {
+			return export.Write(out, opts.format, inRange(opts, entries, date))
+		}
+		return writeExport(out, opts, historyColumns, entries, date)
+	case "vocab":
+		cards, err := storage.LoadVocab()
+		if err != nil {
+			return err
+		}
+		date := func(c storage.VocabCard) time.Time { return c.Added }
+		if opts.format == export.FormatOrg {
+			return export.WriteOrg(out, inRange(opts, cards, date))
+		}
+		return writeExport(out, opts, vocabColumns, cards, date)
+	case "dictionary":
+		entries, err := storage.LoadDictionary()
+		if err != nil {
+			return err
+		}
+		return writeExport(out, opts, dictionaryColumns, entries, func(e storage.DictionaryEntry) time.Time { return e.FirstSeen })
+	default:
+		return fmt.Errorf("unknown export target %q (use history, vocab or dictionary)", target)
+	}
+}
+
+// writeExport writes the selected columns of all items within the date range.
+func writeExport[T any](w io.Writer, opts exportOptions, available []exportColumn[T], items []T, date func(T) time.Time) error {
+	columns, err := selectColumns(available, opts.columns)
+	if err != nil {
+		return err
+	}
+	selected := inRange(opts, items, date)
+	if opts.format == "json" {
+		return writeJSONExport(w, columns, selected)
+	}
+
+	cw := csv.NewWriter(w)
+	if opts.format == "tsv" {
+		cw.Comma = '\t'
+	}
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	for _, item := range selected {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = col.value(item)
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// inRange returns the items dated within the date range of opts.
+func inRange[T any](opts exportOptions, items []T, date func(T) time.Time) []T {
+	var selected []T
+	for _, item := range items {
+		d := date(item)
+		if !opts.from.IsZero() && d.Before(opts.from) {
+			continue
+		}
+		if !opts.to.IsZero() && !d.Before(opts.to) {
+			continue
+		}
+		selected = append(selected, item)
+	}
+	return selected
+}
+
+// writeJSONExport writes the items as a JSON array of objects with the columns as keys.
+func writeJSONExport[T any](w io.Writer, columns []exportColumn[T], items []T) error {
+	rows := make([]map[string]string, len(items))
+	for i, item := range items {
+		rows[i] = make(map[string]string, len(columns))
+		for _, col := range columns {
+			rows[i][col.name] = col.value(item)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rows); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// selectColumns resolves a comma-separated column list against the available columns.
+func selectColumns[T any](available []exportColumn[T], list string) ([]exportColumn[T], error) {
+	if strings.TrimSpace(list) == "" {
+		return available, nil
+	}
+	var selected []exportColumn[T]
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, col := range available {
+			if col.name == name {
+				selected = append(selected, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			names := make([]string, len(available))
+			for i, col := range available {
+				names[i] = col.name
+			}
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(names, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// formatWords flattens a word analysis into a single cell.
+func formatWords(words []translator.WordInfo) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = w.WordInTargetLang
+		if w.GrammaticalExplanation != "" {
+			parts[i] += ": " + w.GrammaticalExplanation
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/brittaao/translation-tui/internal/frequency"
+	"github.com/brittaao/translation-tui/internal/translate"
+)
+
+// runFrequency implements the "frequency" command: frequency [flags] <lang>...
+// It downloads the word frequency lists used to rank analyzed words.
+func runFrequency(args []string) error {
+	fs := flag.NewFlagSet("frequency", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config.toml (default: user config directory)")
+	profile := fs.String("profile", "", "configuration profile to use")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: translation-tui frequency [flags] <lang>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("expected at least one language code")
+	}
+
+	cfg, err := loadConfig(*configPath, *profile)
+	if err != nil {
+		return err
+	}
+	client, err := translate.NewHTTPClient(cfg.Network)
+	if err != nil {
+		return err
+	}
+	for _, lang := range fs.Args() {
+		n, err := frequency.Download(context.Background(), client, cfg.FrequencyURL, lang)
+		if err != nil {
+			return err
+		}
+		path, _ := frequency.Path(lang)
+		fmt.Printf("Installed %d words for %s in %s\n", n, lang, path)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/brittaao/translation-tui/internal/l10n"
+)
+
+// runI18n implements the "i18n" command: i18n [flags] <source.json|source.yaml>.
+// It translates the strings of a key-value resource file that are missing from the
+// output file and prints the changes as a diff before writing them.
+func runI18n(args []string) error {
+	fs := flag.NewFlagSet("i18n", flag.ContinueOnError)
+	from := fs.String("from", "en", "locale of the source file")
+	to := fs.String("to", "", "locale to translate to")
+	output := fs.String("o", "", "output file; existing translations in it are kept")
+	dryRun := fs.Bool("dry-run", false, "print the changes without writing the output file")
+	configPath := fs.String("config", "", "path to config.toml (default: user config directory)")
+	profile := fs.String("profile", "", "configuration profile to use")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: translation-tui i18n [flags] <source.json|source.yaml>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one resource file")
+	}
+	if *to == "" || *output == "" {
+		return errors.New("-to and -o are required")
+	}
+	input := fs.Arg(0)
+
+	cfg, err := loadConfig(*configPath, *profile)
+	if err != nil {
+		return err
+	}
+
+	res, err := l10n.LoadResource(input)
+	if err != nil {
+		return err
+	}
+	res.RenameRoot(*from, *to) // Rails files are nested under the locale
+
+	existing := map[string]string{}
+	if _, err := os.Stat(*output); err == nil {
+		prev, err := l10n.LoadResource(*output)
+		if err != nil {
+			return err
+		}
+		for _, e := range prev.Entries() {
+			existing[e.Key] = e.Value
+		}
+	}
+
+	var missing []*l10n.Entry
+	var messages []string
+	for _, e := range res.Entries() {
+		if prev := existing[e.Key]; prev != "" {
+			e.Set(prev)
+			delete(existing, e.Key)
+			continue
+		}
+		delete(existing, e.Key)
+		if e.Value == "" {
+			continue
+		}
+		missing = append(missing, e)
+		messages = append(messages, e.Value)
+	}
+
+	translated := 0
+	failed := 0
+	if len(missing) > 0 {
+		fmt.Printf("Translating %d strings from %s to %s...\n", len(missing), *from, *to)
+		translations, errs := translateMessages(context.Background(), cfg, languageCode(*from), languageCode(*to), messages)
+		failed = reportFailures(messages, errs)
+		for i, e := range missing {
+			if errs[i] != nil {
+				e.Remove() // Left out, so the next run tries again
+				continue
+			}
+			e.Set(translations[i])
+			fmt.Printf("+ %s: %q\n", e.Key, truncate(translations[i], 70))
+			translated++
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(existing)) {
+		fmt.Printf("- %s: %q (not in %s)\n", key, truncate(existing[key], 70), input)
+	}
+
+	if *dryRun {
+		fmt.Printf("Dry run: %d strings translated, %s not written\n", translated, *output)
+		return nil
+	}
+	if translated == 0 && len(existing) == 0 {
+		if failed > 0 {
+			fmt.Printf("No strings translated; %s not written\n", *output)
+			return nil
+		}
+		fmt.Println("No missing strings.")
+		return nil
+	}
+	data, err := res.Encode()
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", *output, err)
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	fmt.Printf("Translated %d of %d strings into %s", translated, len(missing), *output)
+	if failed > 0 {
+		fmt.Printf("; %d failed", failed)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestI18nRetriesFailedStrings(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	dir := t.TempDir()
+
+	// The first translation of the farewell loses its placeholder, which fails it
+	farewells := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		translation := "Hallo"
+		if strings.Contains(string(body), "Bye") {
+			farewells++
+			translation = "Tschüss"
+			if farewells > 1 {
+				translation = "Tschüss ⟦0⟧"
+			}
+		}
+		content, _ := json.Marshal(map[string]string{"input_language": "English", "cleaned_sentence": "", "translation": translation, "translation_language": "German"})
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"role": "assistant", "content": string(content)}},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	configPath := filepath.Join(dir, "config.toml")
+	config := "provider = \"openai\"\n\n[openai]\nbase_url = \"" + srv.URL + "\"\nmodel = \"test-model\"\n"
+	source := filepath.Join(dir, "en.json")
+	output := filepath.Join(dir, "de.json")
+	for path, data := range map[string]string{configPath: config, source: "{\n  \"hello\": \"Hello\",\n  \"bye\": \"Bye {name}\"\n}\n"} {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run := func() string {
+		t.Helper()
+		if err := runI18n([]string{"-to", "de", "-o", output, "-config", configPath, source}); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if got, want := run(), "{\n  \"hello\": \"Hallo\"\n}\n"; got != want {
+		t.Errorf("after a failed string, output =\n%s\nwant\n%s", got, want)
+	}
+	if got, want := run(), "{\n  \"hello\": \"Hallo\",\n  \"bye\": \"Tschüss {name}\"\n}\n"; got != want {
+		t.Errorf("after retrying, output =\n%s\nwant\n%s", got, want)
+	}
+	if farewells != 2 {
+		t.Errorf("farewell translated %d times, want 2", farewells)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/wordlist"
+)
+
+// vocabField is a field of a vocab card that a column of a word list can fill.
+type vocabField struct {
+	name string
+	set  func(c *storage.VocabCard, value string)
+}
+
+// vocabFields lists the fields a word list is mapped to, in the order they are asked
+// for. Only word is required.
+var vocabFields = []vocabField{
+	{"word", func(c *storage.VocabCard, v string) { c.Word = v }},
+	{"analysis", func(c *storage.VocabCard, v string) { c.Analysis = v }},
+	{"sentence", func(c *storage.VocabCard, v string) { c.Sentence = v }},
+	{"translation", func(c *storage.VocabCard, v string) { c.Translation = v }},
+	{"level", func(c *storage.VocabCard, v string) { c.Level = strings.ToUpper(v) }},
+}
+
+// defaultMapping maps the first column to the word and the second to its analysis, as
+// the front and back of a flashcard.
+var defaultMapping = map[string]int{"word": 0, "analysis": 1}
+
+// runImport implements the "import" command: import vocab [flags] <file>.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	lang := fs.String("lang", "", "language code of the words, such as de")
+	header := fs.Bool("header", false, "the first row names the columns")
+	mapping := fs.String("map", "", "columns of the fields, such as word=1,analysis=Back (default: asked for)")
+	dryRun := fs.Bool("dry-run", false, "print the first cards without importing")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: translation-tui import vocab [flags] <file.csv|file.tsv|anki.txt>")
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 || args[0] != "vocab" {
+		fs.Usage()
+		return errors.New("missing import target (use vocab)")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one word list")
+	}
+	if *lang == "" {
+		return errors.New("-lang is required")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to open word list: %w", err)
+	}
+	defer f.Close()
+	table, err := wordlist.Read(f, wordlist.Options{Header: *header})
+	if err != nil {
+		return err
+	}
+	if len(table.Rows) == 0 {
+		fmt.Println("No words to import.")
+		return nil
+	}
+
+	var columns map[string]int
+	switch {
+	case *mapping != "":
+		columns, err = parseMapping(*mapping, table)
+	case isTerminal(os.Stdin):
+		columns, err = askMapping(bufio.NewReader(os.Stdin), os.Stdout, table)
+	default:
+		columns = defaultMapping
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Mapping: %s\n", describeMapping(columns, table))
+
+	cards := mapCards(table, columns, *lang, time.Now())
+	if *dryRun {
+		for _, c := range cards[:min(len(cards), 5)] {
+			fmt.Printf("  %s: %q\n", c.Word, truncate(c.Analysis, 60))
+		}
+		fmt.Printf("Dry run: %d words not imported\n", len(cards))
+		return nil
+	}
+	added, err := storage.AddToVocab(cards)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d words into the vocab deck; %d were already in it\n", added, len(cards)-added)
+	return nil
+}
+
+// mapCards turns the rows of a word list into vocab cards, skipping rows without a word.
+// Line breaks within a field are joined with semicolons.
+func mapCards(table wordlist.Table, columns map[string]int, lang string, added time.Time) []storage.VocabCard {
+	var cards []storage.VocabCard
+	for _, row := range table.Rows {
+		card := storage.VocabCard{Lang: lang, Added: added}
+		for _, field := range vocabFields {
+			if i, ok := columns[field.name]; ok {
+				field.set(&card, strings.ReplaceAll(strings.TrimSpace(wordlist.Cell(row, i)), "\n", "; "))
+			}
+		}
+		if card.Word != "" {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+// parseMapping parses a list such as "word=1,analysis=Back", where a column is given
+// by its number, counting from 1, or by its name in the header.
+func parseMapping(list string, table wordlist.Table) (map[string]int, error) {
+	columns := map[string]int{}
+	for _, pair := range strings.Split(list, ",") {
+		name, column, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mapping %q (use field=column)", pair)
+		}
+		if !isVocabField(name) {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", name, vocabFieldNames())
+		}
+		i, err := findColumn(column, table)
+		if err != nil {
+			return nil, err
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["word"]; !ok {
+		return nil, errors.New("the word field must be mapped to a column")
+	}
+	return columns, nil
+}
+
+// askMapping shows the columns of the word list with their first values and asks which
+// column fills each field, offering the default mapping.
+func askMapping(in *bufio.Reader, out io.Writer, table wordlist.Table) (map[string]int, error) {
+	fmt.Fprintln(out, "Columns:")
+	for i := range table.Columns() {
+		fmt.Fprintf(out, "  %s: %q\n", columnName(i, table), truncate(wordlist.Cell(table.Rows[0], i), 50))
+	}
+	fmt.Fprintln(out, "Enter the column of each field by number or name; Enter keeps the suggestion, - leaves the field empty.")
+
+	columns := map[string]int{}
+	for _, field := range vocabFields {
+		suggestion, suggested := defaultMapping[field.name]
+		suggested = suggested && suggestion < table.Columns()
+		for {
+			if suggested {
+				fmt.Fprintf(out, "%s [%d]: ", field.name, suggestion+1)
+			} else {
+				fmt.Fprintf(out, "%s [-]: ", field.name)
+			}
+			answer, err := in.ReadString('\n')
+			if err != nil && answer == "" {
+				return nil, fmt.Errorf("mapping not finished: %w", err)
+			}
+			answer = strings.TrimSpace(answer)
+			if answer == "" && suggested {
+				columns[field.name] = suggestion
+				break
+			}
+			if answer == "" || answer == "-" {
+				if field.name == "word" {
+					fmt.Fprintln(out, "The word is required.")
+					continue
+				}
+				break
+			}
+			i, err := findColumn(answer, table)
+			if err != nil {
+				fmt.Fprintln(out, err)
+				continue
+			}
+			columns[field.name] = i
+			break
+		}
+	}
+	return columns, nil
+}
+
+// findColumn returns the index of the column given by its number, counting from 1, or
+// by its name in the header, ignoring case.
+func findColumn(column string, table wordlist.Table) (int, error) {
+	column = strings.TrimSpace(column)
+	if n, err := strconv.Atoi(column); err == nil {
+		if n < 1 || n > table.Columns() {
+			return 0, fmt.Errorf("no column %d (the word list has %d)", n, table.Columns())
+		}
+		return n - 1, nil
+	}
+	for i, name := range table.Header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no column named %q", column)
+}
+
+// describeMapping lists the mapped fields with their columns, in field order.
+func describeMapping(columns map[string]int, table wordlist.Table) string {
+	var parts []string
+	for _, field := range vocabFields {
+		if i, ok := columns[field.name]; ok {
+			parts = append(parts, fmt.Sprintf("%s ← %s", field.name, columnName(i, table)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// columnName names a column by its number and, if the word list has a header, its name.
+func columnName(i int, table wordlist.Table) string {
+	if name := wordlist.Cell(table.Header, i); name != "" {
+		return fmt.Sprintf("%d (%s)", i+1, name)
+	}
+	return strconv.Itoa(i + 1)
+}
+
+// isVocabField reports whether name is one of vocabFields.
+func isVocabField(name string) bool {
+	for _, field := range vocabFields {
+		if field.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// vocabFieldNames lists the names of vocabFields for error messages.
+func vocabFieldNames() string {
+	names := make([]string, len(vocabFields))
+	for i, field := range vocabFields {
+		names[i] = field.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
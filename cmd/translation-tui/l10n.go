@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/notify"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// translateMessages machine-translates localization messages from one language to another,
+// protecting placeholders. Messages whose placeholders do not survive translation fail.
+// A slow batch notifies on the desktop when it is done.
+func translateMessages(ctx context.Context, cfg config.Config, from, to string, messages []string) ([]string, []error) {
+	started := time.Now()
+	masked := make([]translator.Masked, len(messages))
+	reqs := make([]translator.Request, len(messages))
+	for i, msg := range messages {
+		masked[i] = translator.MaskPlaceholders(msg)
+		reqs[i] = translator.Request{Sentence: masked[i].Text, UserLang: from, TargetLang: to}
+	}
+	translations, errs := translate.TranslateBatch(ctx, cfg, reqs, masked)
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	body := fmt.Sprintf("%d of %d messages translated to %s", len(messages)-failed, len(messages), to)
+	if err := notify.Done(ctx, cfg.Notify, notify.ModeBatch, time.Since(started), "Batch translation done", body, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return translations, errs
+}
+
+// languageCode reduces a locale such as "de_DE" or "pt-BR" to its language code.
+func languageCode(locale string) string {
+	code, _, _ := strings.Cut(locale, "_")
+	code, _, _ = strings.Cut(code, "-")
+	return strings.ToLower(code)
+}
+
+// truncate shortens s for messages on the terminal.
+func truncate(s string, n int) string {
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// reportFailures prints the messages that could not be translated.
+func reportFailures(messages []string, errs []error) int {
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			fmt.Printf("  failed: %q: %v\n", truncate(messages[i], 60), err)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+const (
+	// Log files live in this subdirectory of the data directory
+	logDirName  = "logs"
+	logFileName = storage.AppName + ".log"
+
+	// Rotation settings
+	maxLogSize    = 5 << 20 // Rotate after 5 MB
+	maxLogBackups = 3       // Keep translation-tui.log.1 .. .3
+)
+
+// rotatingFile is an io.Writer that rotates the log file once it grows too large.
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	size int64
+}
+
+// openRotatingFile opens the log file for appending, creating its directory if needed.
+func openRotatingFile(path string) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	r := &rotatingFile{path: path}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p to the log file, rotating first if the size limit would be exceeded.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size+int64(len(p)) > maxLogSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts log.N to log.N+1, dropping the oldest, and starts a new file.
+func (r *rotatingFile) rotate() error {
+	r.file.Close()
+	for i := maxLogBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	os.Rename(r.path, r.path+".1")
+	return r.open()
+}
+
+// setupLogging installs the default slog logger. With an empty level, logging is
+// discarded so nothing is written to the terminal behind the TUI.
+func setupLogging(level string) error {
+	if level == "" {
+		slog.SetDefault(slog.New(slog.DiscardHandler))
+		return nil
+	}
+
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+		return fmt.Errorf("invalid log level %q (use debug, info, warn or error)", level)
+	}
+
+	dir, err := storage.DataDir()
+	if err != nil {
+		return err
+	}
+	w, err := openRotatingFile(filepath.Join(dir, logDirName, logFileName))
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: lvl})))
+	return nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/datasync"
+	"github.com/brittaao/translation-tui/internal/document"
+	"github.com/brittaao/translation-tui/internal/editor"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/internal/ui"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches subcommands or initializes and runs the TUI application.
+func run(args []string) error {
+	if len(args) == 0 || (args[0] != "encrypt" && args[0] != "decrypt") {
+		if err := unlockStorage(); err != nil {
+			return err
+		}
+	}
+	if len(args) > 0 {
+		switch args[0] {
+		case "encrypt":
+			return runEncrypt(args[1:])
+		case "decrypt":
+			return runDecrypt(args[1:])
+		case "export":
+			return runExport(args[1:])
+		case "import":
+			return runImport(args[1:])
+		case "po":
+			return runPO(args[1:])
+		case "i18n":
+			return runI18n(args[1:])
+		case "frequency":
+			return runFrequency(args[1:])
+		case "mcp":
+			return runMCP(args[1:])
+		case "serve-ssh":
+			return runServeSSH(args[1:])
+		}
+	}
+
+	cfg, opts, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	if err := setupLogging(cfg.LogLevel); err != nil {
+		return err
+	}
+	slog.Info("starting", "profile", cfg.Profile, "provider", cfg.Provider)
+	storage.SetPrivate(cfg.Private)
+
+	if cfg.Debug {
+		if err := translate.EnableDebug(cfg.DebugLog); err != nil {
+			return err
+		}
+	}
+
+	if opts.inline {
+		return runInline(cfg, opts)
+	}
+	if opts.stdio {
+		return runStdio(cfg)
+	}
+
+	if datasync.Enabled(cfg.Sync) && !cfg.Private {
+		syncData(datasync.Pull, cfg.Sync, "pull")
+		defer syncData(datasync.Push, cfg.Sync, "push")
+	}
+
+	newModel := ui.New
+	if cfg.NeedsAPIKey() {
+		keys, err := translate.ResolveAPIKeys(cfg.Gemini)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			newModel = ui.NewSetup // First run: ask for a key and store it
+		}
+	}
+	uiOpts := ui.Options{Review: opts.review}
+	if opts.document != "" {
+		doc, err := document.Load(opts.document)
+		if err != nil {
+			return err
+		}
+		uiOpts.Document = &doc
+	}
+	if opts.watch != "" {
+		tail, err := document.NewTail(opts.watch)
+		if err != nil {
+			return err
+		}
+		uiOpts.Watch = tail
+	}
+	m, err := newModel(cfg, uiOpts)
+	if err != nil {
+		return err
+	}
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("failed to run program: %w", err)
+	}
+
+	return nil
+}
+
+// syncData pulls or pushes the synced data, which Ctrl+C interrupts. A failed sync is
+// reported but does not stop the app, which works on the local data alone.
+func syncData(sync func(context.Context, config.SyncConfig) error, cfg config.SyncConfig, what string) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := sync(ctx, cfg); err != nil {
+		slog.Warn("sync failed", "op", what, "err", err)
+		fmt.Fprintf(os.Stderr, "Warning: failed to %s synced data: %v\n", what, err)
+	}
+}
+
+// runInline translates a single sentence in the inline quick mode, without the alternate screen.
+func runInline(cfg config.Config, opts options) error {
+	if opts.userLang == "" || opts.targetLang == "" {
+		return errors.New("--inline needs --user-lang and --target-lang")
+	}
+	if cfg.NeedsAPIKey() {
+		keys, err := translate.ResolveAPIKeys(cfg.Gemini)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no API key found; run without --inline once or set %s", translate.EnvAPIKey)
+		}
+	}
+	m, err := ui.NewInline(cfg, opts.userLang, opts.targetLang, opts.sentence)
+	if err != nil {
+		return err
+	}
+	final, err := tea.NewProgram(m).Run()
+	if err != nil {
+		return fmt.Errorf("failed to run program: %w", err)
+	}
+	if f, ok := final.(interface{ Err() error }); ok && f.Err() != nil {
+		return f.Err()
+	}
+	return nil
+}
+
+// runStdio serves the JSON-RPC protocol of editor plugins on stdin and stdout.
+func runStdio(cfg config.Config) error {
+	service, err := editor.NewService(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return service.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// options holds command line settings that are not part of the configuration.
+type options struct {
+	inline     bool
+	stdio      bool
+	userLang   string
+	targetLang string
+	sentence   string
+	document   string
+	watch      string
+	review     bool
+}
+
+// loadConfig reads the config file (the default one if path is empty) and applies the
+// profile (from $TRANSLATION_TUI_PROFILE if empty) and the environment.
+func loadConfig(path, profile string) (config.Config, error) {
+	if path == "" {
+		var err error
+		if path, err = config.DefaultPath(); err != nil {
+			return config.Config{}, err
+		}
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	if profile == "" {
+		profile = os.Getenv(config.EnvProfile)
+	}
+	if profile == "" {
+		profile = config.DefaultProfile
+	}
+	if err := cfg.ApplyProfile(profile); err != nil {
+		return config.Config{}, err
+	}
+	cfg.ApplyEnv()
+	return cfg, nil
+}
+
+// parseFlags builds the effective configuration from the config file, environment and flags,
+// in increasing order of precedence.
+func parseFlags(args []string) (config.Config, options, error) {
+	fs := flag.NewFlagSet(storage.AppName, flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config.toml (default: user config directory)")
+	profile := fs.String("profile", "", "configuration profile to use (default: $"+config.EnvProfile+" or \""+config.DefaultProfile+"\")")
+	model := fs.String("model", "", "Gemini model used for both translation and analysis")
+	translationModel := fs.String("translation-model", "", "Gemini model used for the translation step")
+	analysisModel := fs.String("analysis-model", "", "Gemini model used for the word analysis step")
+	debug := fs.Bool("debug", false, "record prompts, responses and timings of API calls (F12 opens the debug view)")
+	debugLogPath := fs.String("debug-log", "", "append recorded API calls to this file as JSON lines")
+	logLevel := fs.String("log-level", "", "write logs at this level (debug, info, warn, error) to the data directory")
+	var opts options
+	fs.BoolVar(&opts.inline, "inline", false, "translate one sentence in the terminal scrollback and exit (sentence may follow the flags)")
+	fs.StringVar(&opts.userLang, "user-lang", "", "code of the language you know, for --inline (e.g. sv)")
+	fs.StringVar(&opts.targetLang, "target-lang", "", "code of the language you learn, for --inline (e.g. de)")
+	fs.BoolVar(&opts.stdio, "stdio", false, "serve the JSON-RPC protocol of editor plugins on stdin and stdout")
+	fs.StringVar(&opts.document, "document", "", "read a .txt, .md or .epub file sentence by sentence")
+	fs.StringVar(&opts.watch, "watch", "", "translate lines as they are appended to this file")
+	fs.BoolVar(&opts.review, "review", false, "start with the review of the vocab cards due")
+	private := fs.Bool("private", false, "save nothing of this session: no history, vocab, dictionary, cache or debug log")
+	if err := fs.Parse(args); err != nil {
+		return config.Config{}, options{}, err
+	}
+	opts.sentence = strings.Join(fs.Args(), " ")
+
+	cfg, err := loadConfig(*configPath, *profile)
+	if err != nil {
+		return config.Config{}, options{}, err
+	}
+
+	if *model != "" {
+		cfg.Models.Translation = *model
+		cfg.Models.Analysis = *model
+	}
+	if *translationModel != "" {
+		cfg.Models.Translation = *translationModel
+	}
+	if *analysisModel != "" {
+		cfg.Models.Analysis = *analysisModel
+	}
+	if *debug {
+		cfg.Debug = true
+	}
+	if *logLevel != "" {
+		cfg.LogLevel = *logLevel
+	}
+	if *debugLogPath != "" {
+		cfg.Debug = true
+		cfg.DebugLog = *debugLogPath
+	}
+	if *private {
+		cfg.Private = true
+		cfg.DebugLog = ""
+	}
+	return cfg, opts, nil
+}
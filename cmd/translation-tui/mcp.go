@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/brittaao/translation-tui/internal/mcp"
+)
+
+// runMCP implements the "mcp" command: mcp [flags].
+// It serves the translate and analyze_words tools over stdio to MCP clients.
+func runMCP(args []string) error {
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+	configPath := fs.String("config", "", "path to config.toml (default: user config directory)")
+	profile := fs.String("profile", "", "configuration profile to use")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: translation-tui mcp [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("unexpected arguments")
+	}
+
+	cfg, err := loadConfig(*configPath, *profile)
+	if err != nil {
+		return err
+	}
+	// Logs must not go to stdout, which carries the protocol
+	if err := setupLogging(cfg.LogLevel); err != nil {
+		return err
+	}
+	slog.Info("serving MCP", "profile", cfg.Profile, "provider", cfg.Provider)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return mcp.Serve(ctx, cfg, os.Stdin, os.Stdout)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/brittaao/translation-tui/internal/l10n"
+)
+
+// runPO implements the "po" command: po [flags] <file.po|file.pot>.
+// It machine-translates untranslated messages and marks them fuzzy for review.
+func runPO(args []string) error {
+	fs := flag.NewFlagSet("po", flag.ContinueOnError)
+	from := fs.String("from", "en", "language code of the msgids")
+	to := fs.String("to", "", "language code to translate to (default: Language header)")
+	output := fs.String("o", "", "output file (default: overwrite the input; required for .pot files)")
+	configPath := fs.String("config", "", "path to config.toml (default: user config directory)")
+	profile := fs.String("profile", "", "configuration profile to use")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: translation-tui po [flags] <file.po|file.pot>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one PO file")
+	}
+	input := fs.Arg(0)
+	if *output == "" {
+		if strings.EqualFold(filepath.Ext(input), ".pot") {
+			return errors.New("a .pot template is not overwritten; give an output file with -o")
+		}
+		*output = input
+	}
+
+	cfg, err := loadConfig(*configPath, *profile)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(input)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", input, err)
+	}
+	catalog, err := l10n.ParsePO(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", input, err)
+	}
+
+	target := *to
+	if target == "" {
+		target = catalog.Header("Language")
+	}
+	if target == "" {
+		return errors.New("no target language: set -to or the Language header")
+	}
+	if *to != "" {
+		catalog.SetHeader("Language", *to)
+	}
+
+	var entries []*l10n.POEntry
+	var messages []string
+	for _, e := range catalog.Entries {
+		if e.Untranslated() {
+			entries = append(entries, e)
+			messages = append(messages, e.ID)
+			if e.IDPlural != "" {
+				messages = append(messages, e.IDPlural)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Println("No untranslated messages.")
+		return nil
+	}
+
+	fmt.Printf("Translating %d messages from %s to %s...\n", len(entries), *from, languageCode(target))
+	translations, errs := translateMessages(context.Background(), cfg, *from, languageCode(target), messages)
+	failed := reportFailures(messages, errs)
+
+	translated, i := 0, 0
+	nplurals := catalog.NPlurals()
+	for _, e := range entries {
+		singular, singularErr := translations[i], errs[i]
+		plural, pluralErr := singular, singularErr
+		i++
+		if e.IDPlural != "" {
+			plural, pluralErr = translations[i], errs[i]
+			i++
+		}
+		if singularErr != nil || pluralErr != nil {
+			continue
+		}
+		e.SetTranslation(singular, plural, nplurals)
+		translated++
+	}
+
+	var buf bytes.Buffer
+	if err := catalog.WritePO(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	fmt.Printf("Translated %d of %d messages (marked fuzzy) into %s", translated, len(entries), *output)
+	if failed > 0 {
+		fmt.Printf("; %d failed", failed)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/activeterm"
+	"github.com/charmbracelet/wish/bubbletea"
+	"github.com/muesli/termenv"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/internal/ui"
+)
+
+// Host key of the SSH server inside the data directory, created on first start
+const hostKeyFileName = "ssh_host_ed25519"
+
+// Time given to open sessions to end when the server stops
+const shutdownTimeout = 10 * time.Second
+
+// runServeSSH implements the "serve-ssh" command: serve-ssh [flags].
+// It hosts the TUI over SSH for the keys in an authorized_keys file, with this machine's
+// configuration, API key and data directory.
+func runServeSSH(args []string) error {
+	fs := flag.NewFlagSet("serve-ssh", flag.ContinueOnError)
+	listen := fs.String("listen", ":23234", "address to listen on")
+	authorizedKeys := fs.String("authorized-keys", "", "public keys allowed to connect (default: ~/.ssh/authorized_keys)")
+	configPath := fs.String("config", "", "path to config.toml (default: user config directory)")
+	profile := fs.String("profile", "", "configuration profile to use")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: translation-tui serve-ssh [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("unexpected arguments")
+	}
+
+	cfg, err := loadConfig(*configPath, *profile)
+	if err != nil {
+		return err
+	}
+	if err := setupLogging(cfg.LogLevel); err != nil {
+		return err
+	}
+	if cfg.NeedsAPIKey() {
+		keys, err := translate.ResolveAPIKeys(cfg.Gemini)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no API key found; run the TUI locally once or set %s", translate.EnvAPIKey)
+		}
+	}
+	// Fail on configuration errors such as unknown themes now rather than per session
+	if _, err := ui.New(cfg, ui.Options{}); err != nil {
+		return err
+	}
+
+	if *authorizedKeys == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		*authorizedKeys = filepath.Join(home, ".ssh", "authorized_keys")
+	}
+	if _, err := os.Stat(*authorizedKeys); err != nil {
+		return fmt.Errorf("no authorized keys, so nobody could connect: %w", err)
+	}
+	hostKey, err := storage.DataFile(hostKeyFileName)
+	if err != nil {
+		return err
+	}
+
+	// The styles are shared by all sessions and not rendered for a local terminal
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	lipgloss.SetHasDarkBackground(true)
+
+	srv, err := wish.NewServer(
+		wish.WithAddress(*listen),
+		wish.WithHostKeyPath(hostKey),
+		wish.WithAuthorizedKeys(*authorizedKeys),
+		wish.WithMiddleware(
+			bubbletea.Middleware(sessionHandler(cfg)),
+			activeterm.Middleware(),
+			logSessions,
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create SSH server: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+	host, port, _ := net.SplitHostPort(*listen)
+	if host == "" {
+		host = "localhost"
+	}
+	fmt.Printf("Serving translation-tui over SSH: ssh -p %s %s\n", port, host)
+	slog.Info("serving SSH", "address", *listen, "profile", cfg.Profile)
+
+	select {
+	case err := <-errs:
+		if err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+			return fmt.Errorf("SSH server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+		return fmt.Errorf("failed to stop SSH server: %w", err)
+	}
+	return nil
+}
+
+// sessionHandler starts a new TUI for every SSH session.
+func sessionHandler(cfg config.Config) bubbletea.Handler {
+	return func(sess ssh.Session) (tea.Model, []tea.ProgramOption) {
+		m, err := ui.New(cfg, ui.Options{Terminal: sess})
+		if err != nil {
+			// The configuration was checked on startup
+			slog.Error("failed to create session", "user", sess.User(), "error", err)
+			return nil, nil
+		}
+		return m, []tea.ProgramOption{tea.WithAltScreen()}
+	}
+}
+
+// logSessions logs the start and end of each SSH session.
+func logSessions(next ssh.Handler) ssh.Handler {
+	return func(sess ssh.Session) {
+		start := time.Now()
+		slog.Info("SSH session started", "user", sess.User(), "remote", sess.RemoteAddr().String())
+		next(sess)
+		slog.Info("SSH session ended", "user", sess.User(), "remote", sess.RemoteAddr().String(), "duration", time.Since(start))
+	}
+}
@@ -0,0 +1,71 @@
+// Package clipboard copies text to the system clipboard, with the clipboard command of
+// the desktop or, failing that, through the terminal.
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// command is a clipboard command reading the text from stdin, used if its
+// environment variable is set, or always if it names none.
+type command struct {
+	env  string
+	args []string
+}
+
+// commands are the clipboard commands tried in order.
+var commands = []command{
+	{"WAYLAND_DISPLAY", []string{"wl-copy"}},
+	{"DISPLAY", []string{"xclip", "-selection", "clipboard"}},
+	{"DISPLAY", []string{"xsel", "--clipboard", "--input"}},
+	{"", []string{"pbcopy"}},
+	{"", []string{"clip.exe"}},
+}
+
+// Write copies text to the clipboard with the first clipboard command found. Without
+// one, or in a session over SSH, where the commands would reach the clipboard of the
+// wrong machine, it asks the terminal on stdout to copy the text.
+func Write(ctx context.Context, text string) error {
+	if os.Getenv("SSH_TTY") == "" {
+		for _, c := range commands {
+			if c.env != "" && os.Getenv(c.env) == "" {
+				continue
+			}
+			if _, err := exec.LookPath(c.args[0]); err == nil {
+				return run(ctx, c.args, text)
+			}
+		}
+	}
+	WriteTerminal(os.Stdout, text)
+	return nil
+}
+
+// WriteTerminal asks the terminal written to by w to copy text to its clipboard, with
+// an OSC 52 escape sequence. Not all terminals support that, and there is no telling
+// whether one did.
+func WriteTerminal(w io.Writer, text string) {
+	termenv.NewOutput(w).Copy(text)
+}
+
+// run runs a clipboard command with text on stdin.
+func run(ctx context.Context, args []string, text string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("copying failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("copying failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package clipboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "clipboard.txt")
+	saved := commands
+	defer func() { commands = saved }()
+	commands = []command{
+		{"TTUI_NO_SUCH_DISPLAY", []string{"false"}},
+		{"", []string{"sh", "-c", "cat > " + out}},
+	}
+	t.Setenv("SSH_TTY", "")
+
+	if err := Write(context.Background(), "Guten Morgen"); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != "Guten Morgen" {
+		t.Errorf("clipboard = %q, %v", data, err)
+	}
+}
+
+func TestWriteTerminal(t *testing.T) {
+	t.Setenv("TERM", "xterm-256color")
+	var terminal bytes.Buffer
+	WriteTerminal(&terminal, "Gute Nacht")
+	if want := base64.StdEncoding.EncodeToString([]byte("Gute Nacht")); !strings.Contains(terminal.String(), "\x1b]52;c;"+want) {
+		t.Errorf("escape sequence = %q", terminal.String())
+	}
+}
@@ -0,0 +1,468 @@
+// Package config loads the user configuration from config.toml, profiles and the environment.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+const (
+	// Config file name inside the user's config directory
+	configFileName = "config.toml"
+
+	// Directory of prompt templates inside the user's config directory
+	promptsDirName = "prompts"
+
+	// Environment variables overriding the config file
+	envModel            = "TRANSLATION_TUI_MODEL"
+	envTranslationModel = "TRANSLATION_TUI_TRANSLATION_MODEL"
+	envAnalysisModel    = "TRANSLATION_TUI_ANALYSIS_MODEL"
+	EnvProfile          = "TRANSLATION_TUI_PROFILE"
+	EnvRecord           = "TRANSLATION_TUI_RECORD"
+	envReplay           = "TRANSLATION_TUI_REPLAY"
+	envSyncPassword     = "TRANSLATION_TUI_SYNC_PASSWORD"
+
+	// Profile used when none is selected
+	DefaultProfile = "default"
+
+	// Time limit of a single API request
+	defaultTimeout = 30 * time.Second
+
+	// Sentences of a paragraph translated at the same time
+	defaultConcurrency = 3
+
+	// Work finishing sooner than this does not notify
+	defaultNotifyDuration = 10 * time.Second
+
+	// Estimated tokens of the sentence input from which its counter warns
+	defaultWarnTokens = 1000
+
+	// Gemini authentication backends
+	BackendAPIKey   = "api_key"
+	BackendVertexAI = "vertex"
+
+	// Registers of translations
+	FormalityFormal   = "formal"
+	FormalityInformal = "informal"
+)
+
+// Config represents the user configuration loaded from config.toml.
+type Config struct {
+	Provider      string                   `toml:"provider"`
+	Timeout       time.Duration            `toml:"timeout"`
+	Concurrency   int                      `toml:"concurrency"`
+	Debug         bool                     `toml:"debug"`
+	DebugLog      string                   `toml:"debug_log"`
+	LogLevel      string                   `toml:"log_level"`
+	Models        translator.Models        `toml:"models"`
+	Generation    translator.Generation    `toml:"generation"`
+	Gemini        GeminiConfig             `toml:"gemini"`
+	DeepL         DeepLConfig              `toml:"deepl"`
+	OpenAI        OpenAIConfig             `toml:"openai"`
+	Fallback      FallbackConfig           `toml:"fallback"`
+	Network       NetworkConfig            `toml:"network"`
+	RateLimit     RateLimitConfig          `toml:"rate_limit"`
+	Input         InputConfig              `toml:"input"`
+	Theme         ThemeConfig              `toml:"theme"`
+	Pronunciation PronunciationConfig      `toml:"pronunciation"`
+	Hooks         HooksConfig              `toml:"hooks"`
+	Notify        NotifyConfig             `toml:"notify"`
+	Sync          SyncConfig               `toml:"sync"`
+	Profiles      map[string]ProfileConfig `toml:"profiles"`
+
+	// DoNotTranslate lists terms, such as names or product names, that are never translated.
+	DoNotTranslate []string `toml:"do_not_translate"`
+
+	// Level is the user's CEFR level (A1-C2) in the language being learned. Words above
+	// it are flagged in the analysis and can be saved as priority vocabulary.
+	Level string `toml:"level"`
+
+	// Formality is the register of translations: "formal", "informal" or empty to leave
+	// it to the model.
+	Formality string `toml:"formality"`
+
+	// Depth is how detailed the word analysis is: "brief" for glosses only, "standard"
+	// or "deep" for morphology, usage notes and collocations. Empty means standard.
+	Depth string `toml:"depth"`
+
+	// Etymology adds the origin of each analyzed word and its cognates in the user's
+	// language and KnownLanguages, at the cost of more tokens.
+	Etymology bool `toml:"etymology"`
+
+	// KnownLanguages are the codes of other languages the user knows, whose cognates
+	// the etymology points out.
+	KnownLanguages []string `toml:"known_languages"`
+
+	// StressMarks overlays the stress or pitch accent of the analyzed words on the
+	// sentence in the results, for languages whose spelling does not show it.
+	StressMarks bool `toml:"stress_marks"`
+
+	// Spellcheck underlines the words of the sentence input that the installed hunspell
+	// or aspell dictionaries do not know.
+	Spellcheck bool `toml:"spellcheck"`
+
+	// AnalyzeOnDemand skips the word analysis of each translation, saving tokens; words
+	// chosen in the results are analyzed instead.
+	AnalyzeOnDemand bool `toml:"analyze_on_demand"`
+
+	// Dictionary adds every analyzed word to the personal dictionary of its language.
+	Dictionary bool `toml:"dictionary"`
+
+	// Redact masks names, e-mail addresses and phone numbers before texts are sent to
+	// the models, and restores them in the results.
+	Redact bool `toml:"redact"`
+
+	// FrequencyURL is where the frequency command downloads word frequency lists from,
+	// with {lang} standing for the language code. Empty means frequency.DefaultURL.
+	FrequencyURL string `toml:"frequency_url"`
+
+	// WiktionaryURL is the Wiktionary edition words are looked up in. Empty means
+	// wiktionary.DefaultURL.
+	WiktionaryURL string `toml:"wiktionary_url"`
+
+	// TatoebaURL is the Tatoeba instance example sentences are searched in. Empty means
+	// tatoeba.DefaultURL.
+	TatoebaURL string `toml:"tatoeba_url"`
+
+	// SystemInstruction is sent to the model with both pipeline steps, e.g. "Always
+	// explain Serbian clitics explicitly".
+	SystemInstruction string `toml:"system_instruction"`
+
+	// TranslationExamples and AnalysisExamples are few-shot examples shown to the model
+	// before each request of their step.
+	TranslationExamples []translator.Example `toml:"translation_examples"`
+	AnalysisExamples    []translator.Example `toml:"analysis_examples"`
+
+	// Languages adds languages to both language menus, such as dialects or constructed
+	// languages, or renames built-in ones. Their script and notes are told to the model.
+	Languages []translator.Language `toml:"languages"`
+
+	// PromptsDir holds translation.tmpl and analysis.tmpl, Go templates replacing the
+	// built-in prompts of the pipeline steps. Empty means the prompts directory next to
+	// config.toml.
+	PromptsDir string `toml:"prompts_dir"`
+
+	// Transcript appends every translation to a Markdown transcript of the day, in
+	// TranscriptDir or, if that is empty, in the transcripts directory of the data
+	// directory.
+	Transcript    bool   `toml:"transcript"`
+	TranscriptDir string `toml:"transcript_dir"`
+
+	// ExportDir is where results exported from the results screen are written. Empty
+	// means the current directory.
+	ExportDir string `toml:"export_dir"`
+
+	// Keys maps action names to the keys bound to them, replacing the defaults.
+	Keys map[string][]string `toml:"keys"`
+
+	// Private keeps the session from saving anything of the texts translated in it; it
+	// is set by the --private flag rather than read from the file.
+	Private bool `toml:"-"`
+
+	// Profile is the name of the active profile; it is not read from the file.
+	Profile string `toml:"-"`
+
+	// Path is the config file the configuration was loaded from, where settings changed
+	// at runtime are saved. It is empty if it was not loaded from a file.
+	Path string `toml:"-"`
+}
+
+// ProfileConfig holds per-profile overrides of the top-level settings.
+type ProfileConfig struct {
+	Models              translator.Models     `toml:"models"`
+	Generation          translator.Generation `toml:"generation"`
+	SystemInstruction   string                `toml:"system_instruction"`
+	TranslationExamples []translator.Example  `toml:"translation_examples"`
+	AnalysisExamples    []translator.Example  `toml:"analysis_examples"`
+}
+
+// GeminiConfig selects how the Gemini client authenticates and where API keys come from,
+// and tunes the safety filters and thinking of the pipeline steps.
+type GeminiConfig struct {
+	Backend    string   `toml:"backend"`
+	APIKeys    []string `toml:"api_keys"`
+	APIKeyFile string   `toml:"api_key_file"`
+	Project    string   `toml:"project"`
+	Location   string   `toml:"location"`
+
+	// SafetyThreshold applies to all harm categories: block_low_and_above,
+	// block_medium_and_above, block_only_high, block_none or off. Empty means the API default.
+	SafetyThreshold string `toml:"safety_threshold"`
+
+	// Thinking budgets of the steps in tokens; unset means the model default, 0 disables
+	// thinking and -1 lets the model decide.
+	TranslationThinkingBudget *int32 `toml:"translation_thinking_budget"`
+	AnalysisThinkingBudget    *int32 `toml:"analysis_thinking_budget"`
+}
+
+// DeepLConfig holds the settings of the DeepL provider.
+type DeepLConfig struct {
+	APIKey   string `toml:"api_key"`
+	Endpoint string `toml:"endpoint"`
+}
+
+// OpenAIConfig holds the settings of the OpenAI-compatible provider.
+type OpenAIConfig struct {
+	BaseURL          string `toml:"base_url"`
+	APIKey           string `toml:"api_key"`
+	Model            string `toml:"model"`
+	AnalysisModel    string `toml:"analysis_model"`
+	StructuredOutput string `toml:"structured_output"`
+}
+
+// FallbackConfig configures what is used when the primary provider fails.
+type FallbackConfig struct {
+	Cache                bool     `toml:"cache"`
+	LibreTranslateURL    string   `toml:"libretranslate_url"`
+	LibreTranslateAPIKey string   `toml:"libretranslate_api_key"`
+	Models               []string `toml:"models"` // Gemini models tried in order when a model is not found or out of quota
+}
+
+// NetworkConfig holds proxy and TLS settings for all outgoing requests,
+// and the fixture directories used to record or replay them.
+type NetworkConfig struct {
+	Proxy              string `toml:"proxy"`
+	CAFile             string `toml:"ca_file"`
+	ClientCert         string `toml:"client_cert"`
+	ClientKey          string `toml:"client_key"`
+	InsecureSkipVerify bool   `toml:"insecure_skip_verify"`
+	RecordDir          string `toml:"record_dir"`
+	ReplayDir          string `toml:"replay_dir"`
+}
+
+// RateLimitConfig caps the model API calls of all features together, such as the free
+// tier quotas of Gemini. Zero leaves a limit off.
+type RateLimitConfig struct {
+	RequestsPerMinute int `toml:"requests_per_minute"`
+	TokensPerMinute   int `toml:"tokens_per_minute"`
+}
+
+// InputConfig limits the texts entered for translation. Zero leaves a limit off.
+type InputConfig struct {
+	WarnTokens int     `toml:"warn_tokens"` // Estimated tokens from which the input counter warns
+	MaxCost    float64 `toml:"max_cost"`    // Estimated USD above which translating asks first
+
+	// MaxInputTokens is the input token limit of the models, replacing the published
+	// limits of known models, such as for local models with a smaller context. 0 means
+	// the published limits.
+	MaxInputTokens int `toml:"max_input_tokens"`
+}
+
+// Sync backends
+const (
+	SyncGit    = "git"
+	SyncWebDAV = "webdav"
+)
+
+// SyncConfig selects where the study data of the data directory is synced to when the
+// app starts and exits. An empty Backend turns syncing off.
+type SyncConfig struct {
+	Backend  string `toml:"backend"` // SyncGit or SyncWebDAV
+	Remote   string `toml:"remote"`  // Git remote URL
+	Branch   string `toml:"branch"`  // Git branch; empty means "main"
+	URL      string `toml:"url"`     // WebDAV folder URL
+	Username string `toml:"username"`
+	Password string `toml:"password"` // Or set TRANSLATION_TUI_SYNC_PASSWORD
+}
+
+// PronunciationConfig selects where audio of words comes from and how it is played.
+// Commands are given as argument lists.
+type PronunciationConfig struct {
+	ForvoAPIKey string   `toml:"forvo_api_key"`
+	ForvoURL    string   `toml:"forvo_url"`
+	TTSCommand  []string `toml:"tts_command"` // {word}, {lang} and {file} are replaced
+	Player      []string `toml:"player"`      // The audio file is appended
+}
+
+// HooksConfig passes each completed translation as JSON to a command on stdin and as
+// the body of a POST request to a webhook.
+type HooksConfig struct {
+	Command []string          `toml:"command"`
+	Webhook string            `toml:"webhook"`
+	Headers map[string]string `toml:"headers"` // Sent with the webhook request, e.g. Authorization
+}
+
+// NotifyConfig selects which finished work shows a desktop notification, by mode such
+// as "paragraph" or "batch", once it took at least MinDuration.
+type NotifyConfig struct {
+	Modes       []string      `toml:"modes"`
+	MinDuration time.Duration `toml:"min_duration"`
+	Bell        bool          `toml:"bell"`    // Also ring the terminal bell
+	Command     []string      `toml:"command"` // {title} and {body} are replaced; empty tries the usual notifiers
+}
+
+// ThemeConfig selects the color theme and overrides single colors of it.
+// Colors are ANSI numbers ("39") or hex values ("#268bd2").
+type ThemeConfig struct {
+	Name               string `toml:"name"`
+	Title              string `toml:"title"`
+	Selected           string `toml:"selected"`
+	SelectedBackground string `toml:"selected_background"`
+	Normal             string `toml:"normal"`
+	Error              string `toml:"error"`
+	Success            string `toml:"success"`
+	Warning            string `toml:"warning"`
+	Label              string `toml:"label"`
+	Value              string `toml:"value"`
+	Masculine          string `toml:"masculine"`
+	Feminine           string `toml:"feminine"`
+	Neuter             string `toml:"neuter"`
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() Config {
+	return Config{
+		Provider:    translator.ProviderGemini,
+		Timeout:     defaultTimeout,
+		Concurrency: defaultConcurrency,
+		Gemini: GeminiConfig{
+			Backend: BackendAPIKey,
+		},
+		Models:     translator.DefaultModels(),
+		Generation: translator.DefaultGeneration(),
+		Fallback: FallbackConfig{
+			Cache: true,
+		},
+		StressMarks: true,
+		Spellcheck:  true,
+		Input: InputConfig{
+			WarnTokens: defaultWarnTokens,
+		},
+		Notify: NotifyConfig{
+			Modes:       []string{"paragraph", "document", "queue", "batch"},
+			MinDuration: defaultNotifyDuration,
+		},
+		Profile: DefaultProfile,
+	}
+}
+
+// DefaultPath returns the location of config.toml in the user's config directory.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, storage.AppName, configFileName), nil
+}
+
+// DefaultPromptsDir returns the directory of prompt templates in the user's config directory.
+func DefaultPromptsDir() (string, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), promptsDirName), nil
+}
+
+// Load reads the config file at path on top of the defaults.
+// A missing file is not an error.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	cfg.Path = path
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if cfg.Formality != "" && cfg.Formality != FormalityFormal && cfg.Formality != FormalityInformal {
+		return cfg, fmt.Errorf("unknown formality %q in config %s (available: %s, %s)", cfg.Formality, path, FormalityFormal, FormalityInformal)
+	}
+	if cfg.Depth != "" && !slices.Contains(translator.Depths, cfg.Depth) {
+		return cfg, fmt.Errorf("unknown analysis depth %q in config %s (available: %s)", cfg.Depth, path, strings.Join(translator.Depths, ", "))
+	}
+	if cfg.RateLimit.RequestsPerMinute < 0 || cfg.RateLimit.TokensPerMinute < 0 {
+		return cfg, fmt.Errorf("negative rate limit in config %s", path)
+	}
+	if cfg.Input.WarnTokens < 0 || cfg.Input.MaxCost < 0 {
+		return cfg, fmt.Errorf("negative input limit in config %s", path)
+	}
+	switch cfg.Sync.Backend {
+	case "":
+	case SyncGit:
+		if cfg.Sync.Remote == "" {
+			return cfg, fmt.Errorf("git sync in config %s needs a remote", path)
+		}
+	case SyncWebDAV:
+		if cfg.Sync.URL == "" {
+			return cfg, fmt.Errorf("webdav sync in config %s needs a url", path)
+		}
+	default:
+		return cfg, fmt.Errorf("unknown sync backend %q in config %s (available: %s, %s)", cfg.Sync.Backend, path, SyncGit, SyncWebDAV)
+	}
+	for i, lang := range cfg.Languages {
+		if lang.Code == "" || lang.Name == "" {
+			return cfg, fmt.Errorf("language %d in config %s needs a code and a name", i+1, path)
+		}
+	}
+	return cfg, nil
+}
+
+// ApplyProfile activates the named profile, overlaying its settings from the config file
+// and any choices persisted for it at runtime.
+func (c *Config) ApplyProfile(name string) error {
+	c.Profile = name
+	if p, ok := c.Profiles[name]; ok {
+		c.Models.Merge(p.Models)
+		c.Generation.Merge(p.Generation)
+		if p.SystemInstruction != "" {
+			c.SystemInstruction = p.SystemInstruction
+		}
+		if p.TranslationExamples != nil {
+			c.TranslationExamples = p.TranslationExamples
+		}
+		if p.AnalysisExamples != nil {
+			c.AnalysisExamples = p.AnalysisExamples
+		}
+	}
+	state, err := storage.LoadProfileState(name)
+	if err != nil {
+		return err
+	}
+	c.Models.Merge(state.Models)
+	c.Generation.Merge(state.Generation)
+	return nil
+}
+
+// ApplyEnv overrides config values with environment variables.
+func (c *Config) ApplyEnv() {
+	if v := os.Getenv(envModel); v != "" {
+		c.Models.Translation = v
+		c.Models.Analysis = v
+	}
+	if v := os.Getenv(envTranslationModel); v != "" {
+		c.Models.Translation = v
+	}
+	if v := os.Getenv(envAnalysisModel); v != "" {
+		c.Models.Analysis = v
+	}
+	if v := os.Getenv(EnvRecord); v != "" {
+		c.Network.RecordDir = v
+	}
+	if v := os.Getenv(envReplay); v != "" {
+		c.Network.ReplayDir = v
+	}
+	if v := os.Getenv(envSyncPassword); v != "" {
+		c.Sync.Password = v
+	}
+}
+
+// UsesGemini reports whether the configured providers need the Gemini API.
+func (c Config) UsesGemini() bool {
+	return c.Provider != translator.ProviderOpenAI
+}
+
+// NeedsAPIKey reports whether a Gemini API key is required to run.
+func (c Config) NeedsAPIKey() bool {
+	return c.UsesGemini() && c.Gemini.Backend != BackendVertexAI && c.Network.ReplayDir == ""
+}
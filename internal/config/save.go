@@ -0,0 +1,112 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// tableHeader matches the header of a TOML table, such as "[theme]".
+var tableHeader = regexp.MustCompile(`^\s*\[+\s*([^\]]+?)\s*\]+`)
+
+// SaveSetting sets key in table of the config file at path to value, a string or bool,
+// creating the file or table if needed. An empty table means a top-level key. The rest
+// of the file, including comments, is left as it is.
+func SaveSetting(path, table, key string, value any) error {
+	var encoded string
+	switch v := value.(type) {
+	case string:
+		encoded = strconv.Quote(v)
+	case bool:
+		encoded = strconv.FormatBool(v)
+	default:
+		return fmt.Errorf("unsupported type %T of setting %s", value, key)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	line := key + " = " + encoded
+	keyLine := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(key) + `\s*=`)
+	start, end := tableBounds(lines, table)
+	switch {
+	case start < 0:
+		lines = append(lines, "", "["+table+"]", line)
+	default:
+		replaced := false
+		for i := start; i < end; i++ {
+			if keyLine.MatchString(lines[i]) {
+				lines[i] = line
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			// Keep a top-level key above the first table, where TOML requires it
+			lines = append(lines[:end], append([]string{line}, lines[end:]...)...)
+		}
+	}
+	out := []byte(strings.Join(lines, "\n") + "\n")
+
+	var check Config
+	if _, err := toml.NewDecoder(bytes.NewReader(out)).Decode(&check); err != nil {
+		return fmt.Errorf("failed to update config %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, out, 0o600); err != nil {
+		return fmt.Errorf("failed to write config %s: %w", path, err)
+	}
+	return nil
+}
+
+// tableBounds returns the range of lines holding the keys of table, after its header up
+// to the next table. The top-level table is never missing; start is -1 for other missing
+// tables.
+func tableBounds(lines []string, table string) (start, end int) {
+	start = -1
+	if table == "" {
+		start = 0
+	}
+	for i, line := range lines {
+		m := tableHeader.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if start >= 0 {
+			return start, endOfKeys(lines, start, i)
+		}
+		if m[1] == table {
+			start = i + 1
+		}
+	}
+	if start < 0 {
+		return -1, -1
+	}
+	return start, endOfKeys(lines, start, len(lines))
+}
+
+// endOfKeys moves the end of a table's range back over the blank lines and comments
+// that precede the next table, so new keys are added right after the existing ones.
+func endOfKeys(lines []string, start, end int) int {
+	for end > start {
+		trimmed := strings.TrimSpace(lines[end-1])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			break
+		}
+		end--
+	}
+	return end
+}
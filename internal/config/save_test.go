@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	original := `# My settings
+provider = "openai" # switched for the trial
+
+[theme]
+# Colors
+title = "39"
+
+[fallback]
+libretranslate_url = "http://localhost:5000"
+`
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []struct {
+		table, key string
+		value      any
+	}{
+		{"", "provider", "gemini"},
+		{"", "level", "B1"},
+		{"theme", "name", "dark"},
+		{"fallback", "cache", false},
+		{"models", "translation", "gemini-2.5-pro"},
+	} {
+		if err := SaveSetting(path, s.table, s.key, s.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# My settings
+provider = "gemini"
+level = "B1"
+
+[theme]
+# Colors
+title = "39"
+name = "dark"
+
+[fallback]
+libretranslate_url = "http://localhost:5000"
+cache = false
+
+[models]
+translation = "gemini-2.5-pro"
+`
+	if string(data) != want {
+		t.Errorf("config =\n%s\nwant\n%s", data, want)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Provider != "gemini" || cfg.Theme.Name != "dark" || cfg.Fallback.Cache || cfg.Models.Translation != "gemini-2.5-pro" {
+		t.Errorf("loaded %+v", cfg)
+	}
+}
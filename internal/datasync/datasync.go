@@ -0,0 +1,131 @@
+// Package datasync keeps the study data of the data directory in step across machines
+// by syncing it through a git remote or a WebDAV folder: the app pulls when it starts
+// and pushes when it exits. Changes made on two machines in between are merged record
+// by record rather than one overwriting the other.
+package datasync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// maxAttempts is how often a push is retried when another machine pushed in between.
+const maxAttempts = 3
+
+// errConflict is returned by a backend when the remote changed since it was fetched.
+var errConflict = errors.New("the synced data changed during the sync")
+
+// backend stores the synced files somewhere all machines can reach.
+type backend interface {
+	// fetch returns the synced files as stored remotely, leaving out those not stored yet.
+	fetch(ctx context.Context) (map[string][]byte, error)
+
+	// store replaces the given remote files. It returns errConflict if any of them
+	// changed since the last fetch.
+	store(ctx context.Context, files map[string][]byte) error
+}
+
+// Enabled reports whether syncing is configured.
+func Enabled(cfg config.SyncConfig) bool {
+	return cfg.Backend != ""
+}
+
+// Pull merges the data synced from other machines into the local data directory.
+func Pull(ctx context.Context, cfg config.SyncConfig) error {
+	b, err := newBackend(cfg)
+	if err != nil {
+		return err
+	}
+	remote, err := b.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = mergeLocal(remote)
+	return err
+}
+
+// Push merges the local data with the data synced from other machines and stores the
+// result both remotely and locally. If another machine pushes at the same time, the
+// merge is repeated with its data.
+func Push(ctx context.Context, cfg config.SyncConfig) error {
+	b, err := newBackend(cfg)
+	if err != nil {
+		return err
+	}
+	for range maxAttempts {
+		remote, err := b.fetch(ctx)
+		if err != nil {
+			return err
+		}
+		merged, err := mergeLocal(remote)
+		if err != nil {
+			return err
+		}
+		changed := map[string][]byte{}
+		for name, data := range merged {
+			if !bytes.Equal(data, remote[name]) {
+				changed[name] = data
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		err = b.store(ctx, changed)
+		if !errors.Is(err, errConflict) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to sync after %d attempts: %w", maxAttempts, errConflict)
+}
+
+// newBackend returns the configured backend.
+func newBackend(cfg config.SyncConfig) (backend, error) {
+	switch cfg.Backend {
+	case config.SyncGit:
+		dir, err := storage.DataFile("sync")
+		if err != nil {
+			return nil, err
+		}
+		return &gitBackend{dir: dir, remote: cfg.Remote, branch: cfg.Branch}, nil
+	case config.SyncWebDAV:
+		return &webdavBackend{url: cfg.URL, username: cfg.Username, password: cfg.Password}, nil
+	default:
+		return nil, fmt.Errorf("unknown sync backend %q", cfg.Backend)
+	}
+}
+
+// mergeLocal merges the remote files into the local ones, writes the local files that
+// changed and returns the merged content of all files that exist on either side.
+func mergeLocal(remote map[string][]byte) (map[string][]byte, error) {
+	merged := map[string][]byte{}
+	for _, name := range storage.SyncedFiles {
+		path, err := storage.DataFile(name)
+		if err != nil {
+			return nil, err
+		}
+		local, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if local == nil && remote[name] == nil {
+			continue
+		}
+		data, err := storage.MergeFile(name, local, remote[name])
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(data, local) {
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				return nil, fmt.Errorf("failed to write %s: %w", name, err)
+			}
+		}
+		merged[name] = data
+	}
+	return merged, nil
+}
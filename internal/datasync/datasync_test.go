@@ -0,0 +1,216 @@
+package datasync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// useMachine points the data directory at the one of a machine.
+func useMachine(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", dir)
+}
+
+// addHistory records a translation on the current machine.
+func addHistory(t *testing.T, original string, at time.Time) {
+	t.Helper()
+	if err := storage.AppendHistory(storage.HistoryEntry{Time: at, UserLang: "en", TargetLang: "de", Original: original}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// historyOf returns the originals of the history of the current machine.
+func historyOf(t *testing.T) string {
+	t.Helper()
+	entries, err := storage.LoadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var originals []string
+	for _, e := range entries {
+		originals = append(originals, e.Original)
+	}
+	return strings.Join(originals, ", ")
+}
+
+// testMachines syncs two machines through cfg and checks that both end up with the
+// translations of either, with the vocab decks merged too.
+func testMachines(t *testing.T, cfg config.SyncConfig) {
+	ctx := context.Background()
+	laptop, desktop := t.TempDir(), t.TempDir()
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+
+	useMachine(t, laptop)
+	if err := Pull(ctx, cfg); err != nil {
+		t.Fatalf("first pull: %v", err)
+	}
+	addHistory(t, "Good morning", now)
+	if _, err := storage.AddToVocab([]storage.VocabCard{{Word: "Morgen", Lang: "de", Added: now}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Push(ctx, cfg); err != nil {
+		t.Fatalf("laptop push: %v", err)
+	}
+
+	useMachine(t, desktop)
+	addHistory(t, "Good night", now.Add(time.Hour))
+	if err := Pull(ctx, cfg); err != nil {
+		t.Fatalf("desktop pull: %v", err)
+	}
+	if got := historyOf(t); got != "Good morning, Good night" {
+		t.Errorf("desktop history after pull = %q", got)
+	}
+	if _, err := storage.AddToVocab([]storage.VocabCard{{Word: "Nacht", Lang: "de", Added: now.Add(time.Hour)}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Push(ctx, cfg); err != nil {
+		t.Fatalf("desktop push: %v", err)
+	}
+
+	useMachine(t, laptop)
+	if err := Pull(ctx, cfg); err != nil {
+		t.Fatalf("laptop pull: %v", err)
+	}
+	if got := historyOf(t); got != "Good morning, Good night" {
+		t.Errorf("laptop history after pull = %q", got)
+	}
+	cards, err := storage.LoadVocab()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 || cards[0].Word != "Morgen" || cards[1].Word != "Nacht" {
+		t.Errorf("laptop vocab = %+v", cards)
+	}
+	if err := Push(ctx, cfg); err != nil {
+		t.Errorf("push without changes: %v", err)
+	}
+}
+
+func TestGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	remote := filepath.Join(t.TempDir(), "study.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	testMachines(t, config.SyncConfig{Backend: config.SyncGit, Remote: remote})
+}
+
+// davServer is a WebDAV folder in memory that supports conditional uploads.
+type davServer struct {
+	mu    sync.Mutex
+	files map[string]string
+	etags map[string]int
+}
+
+func (d *davServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if user, pass, _ := r.BasicAuth(); user != "me" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/dav/")
+	data, exists := d.files[name]
+	etag := fmt.Sprintf(`"%d"`, d.etags[name])
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, data)
+	case http.MethodPut:
+		if (r.Header.Get("If-None-Match") == "*" && exists) || (r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != etag) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		d.files[name] = string(body)
+		d.etags[name]++
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestWebDAV(t *testing.T) {
+	dav := &davServer{files: map[string]string{}, etags: map[string]int{}}
+	srv := httptest.NewServer(dav)
+	defer srv.Close()
+	cfg := config.SyncConfig{Backend: config.SyncWebDAV, URL: srv.URL + "/dav", Username: "me", Password: "secret"}
+	testMachines(t, cfg)
+
+	// A file changed by another machine after the fetch is not overwritten.
+	b := &webdavBackend{url: cfg.URL, username: "me", password: "secret"}
+	if _, err := b.fetch(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	dav.etags["history.jsonl"]++
+	if err := b.store(context.Background(), map[string][]byte{"history.jsonl": nil}); !errors.Is(err, errConflict) {
+		t.Errorf("store after a change = %v, want conflict", err)
+	}
+
+	cfg.Password = "wrong"
+	if err := Pull(context.Background(), cfg); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("pull with a wrong password = %v", err)
+	}
+}
+
+func TestEncrypted(t *testing.T) {
+	dav := &davServer{files: map[string]string{}, etags: map[string]int{}}
+	srv := httptest.NewServer(dav)
+	defer srv.Close()
+	cfg := config.SyncConfig{Backend: config.SyncWebDAV, URL: srv.URL + "/dav", Username: "me", Password: "secret"}
+	laptop, desktop := t.TempDir(), t.TempDir()
+	for _, dir := range []string{desktop, laptop} {
+		useMachine(t, dir)
+		if err := storage.EncryptAll("correct horse"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { _ = storage.DecryptAll() })
+
+	addHistory(t, "Secret", time.Now())
+	if err := Push(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(dav.files["history.jsonl"], "Secret") {
+		t.Errorf("synced history is not encrypted: %s", dav.files["history.jsonl"])
+	}
+
+	useMachine(t, desktop)
+	if err := storage.Unlock("wrong"); !errors.Is(err, storage.ErrPassphrase) {
+		t.Errorf("unlock with a wrong passphrase = %v", err)
+	}
+	if err := Pull(context.Background(), cfg); !errors.Is(err, storage.ErrLocked) {
+		t.Errorf("pull while locked = %v", err)
+	}
+	if err := storage.Unlock("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Pull(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := historyOf(t); got != "Secret" {
+		t.Errorf("desktop history = %q", got)
+	}
+}
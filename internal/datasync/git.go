@@ -0,0 +1,136 @@
+package datasync
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// gitBackend syncs through a branch of a git remote, using a clone in the data
+// directory. Each push is a commit, so the remote keeps the history of the data.
+type gitBackend struct {
+	dir    string // The clone
+	remote string
+	branch string
+}
+
+// fetch brings the clone up to date with the remote branch, which may not exist yet,
+// and returns its files.
+func (g *gitBackend) fetch(ctx context.Context) (map[string][]byte, error) {
+	if _, err := os.Stat(filepath.Join(g.dir, ".git")); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(g.dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create sync clone: %w", err)
+		}
+		if _, err := g.git(ctx, "init", "--quiet"); err != nil {
+			return nil, err
+		}
+		if _, err := g.git(ctx, "remote", "add", "origin", g.remote); err != nil {
+			return nil, err
+		}
+	} else if _, err := g.git(ctx, "remote", "set-url", "origin", g.remote); err != nil {
+		return nil, err
+	}
+	if _, err := g.git(ctx, "fetch", "--quiet", "origin"); err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	if _, err := g.git(ctx, "rev-parse", "--verify", "--quiet", g.ref()); err != nil {
+		return files, nil // Nothing pushed yet
+	}
+	if _, err := g.git(ctx, "checkout", "--quiet", "-B", g.name(), g.ref()); err != nil {
+		return nil, err
+	}
+	if _, err := g.git(ctx, "reset", "--quiet", "--hard", g.ref()); err != nil {
+		return nil, err
+	}
+	for _, name := range storage.SyncedFiles {
+		data, err := os.ReadFile(filepath.Join(g.dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read synced %s: %w", name, err)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// store commits the files on top of the fetched branch and pushes them. A rejected
+// push means another machine pushed first.
+func (g *gitBackend) store(ctx context.Context, files map[string][]byte) error {
+	if _, err := g.git(ctx, "checkout", "--quiet", "-B", g.name()); err != nil {
+		return err
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(g.dir, name), data, 0o600); err != nil {
+			return fmt.Errorf("failed to write synced %s: %w", name, err)
+		}
+		if _, err := g.git(ctx, "add", "--", name); err != nil {
+			return err
+		}
+	}
+	host, _ := os.Hostname()
+	if _, err := g.git(ctx, "-c", "user.name="+cmp.Or(g.config(ctx, "user.name"), "translation-tui"),
+		"-c", "user.email="+cmp.Or(g.config(ctx, "user.email"), "translation-tui@localhost"),
+		"commit", "--quiet", "-m", "Sync from "+cmp.Or(host, "unknown host")); err != nil {
+		return err
+	}
+	if _, err := g.git(ctx, "push", "--quiet", "origin", "HEAD:refs/heads/"+g.name()); err != nil {
+		if strings.Contains(err.Error(), "rejected") || strings.Contains(err.Error(), "fetch first") {
+			return errConflict
+		}
+		return err
+	}
+	return nil
+}
+
+// name returns the branch synced to.
+func (g *gitBackend) name() string {
+	return cmp.Or(g.branch, "main")
+}
+
+// ref returns the remote-tracking ref of the branch.
+func (g *gitBackend) ref() string {
+	return "refs/remotes/origin/" + g.name()
+}
+
+// config returns a git setting, or "" if it is not set.
+func (g *gitBackend) config(ctx context.Context, name string) string {
+	out, err := g.git(ctx, "config", "--get", name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// git runs a git command in the clone without prompting for credentials, which would
+// hang while the terminal belongs to the app.
+func (g *gitBackend) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		command := args[0]
+		for i := 0; i+2 < len(args) && args[i] == "-c"; i += 2 {
+			command = args[i+2]
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", command, msg)
+		}
+		return "", fmt.Errorf("git %s: %w", command, err)
+	}
+	return stdout.String(), nil
+}
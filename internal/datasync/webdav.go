@@ -0,0 +1,100 @@
+package datasync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// webdavBackend syncs through a WebDAV folder, such as one of Nextcloud. Each file is
+// replaced only if it is unchanged since it was fetched, going by its ETag.
+type webdavBackend struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+	etags    map[string]string // ETags of the fetched files; missing files have none
+}
+
+// fetch downloads the synced files from the folder.
+func (w *webdavBackend) fetch(ctx context.Context) (map[string][]byte, error) {
+	w.etags = map[string]string{}
+	files := map[string][]byte{}
+	for _, name := range storage.SyncedFiles {
+		resp, err := w.do(ctx, http.MethodGet, name, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			continue
+		case resp.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("failed to download %s: %s", name, resp.Status)
+		case err != nil:
+			return nil, fmt.Errorf("failed to download %s: %w", name, err)
+		}
+		files[name] = data
+		w.etags[name] = resp.Header.Get("ETag")
+	}
+	return files, nil
+}
+
+// store uploads the files, each on condition that it has not changed since the fetch.
+func (w *webdavBackend) store(ctx context.Context, files map[string][]byte) error {
+	for name, data := range files {
+		header := http.Header{}
+		if etag, ok := w.etags[name]; !ok {
+			header.Set("If-None-Match", "*")
+		} else if etag != "" {
+			header.Set("If-Match", etag)
+		}
+		resp, err := w.do(ctx, http.MethodPut, name, data, header)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		case http.StatusPreconditionFailed:
+			return errConflict
+		default:
+			return fmt.Errorf("failed to upload %s: %s", name, resp.Status)
+		}
+	}
+	return nil
+}
+
+// do sends a request for a file of the folder.
+func (w *webdavBackend) do(ctx context.Context, method, name string, body []byte, header http.Header) (*http.Response, error) {
+	target, err := url.JoinPath(strings.TrimSuffix(w.url, "/")+"/", name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync url: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if w.username != "" || w.password != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+	client := w.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach sync server: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,156 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Largest article page that is read, in bytes
+const maxArticleSize = 5 << 20
+
+// IsURL reports whether s is a single http or https URL.
+func IsURL(s string) bool {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, " \t\n") {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// FetchArticle downloads the page at rawURL and extracts the article text.
+func FetchArticle(ctx context.Context, client *http.Client, rawURL string) (Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSpace(rawURL), nil)
+	if err != nil {
+		return Document{}, fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("Accept", "text/html")
+	resp, err := client.Do(req)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to fetch article: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Document{}, fmt.Errorf("failed to fetch article: %s", resp.Status)
+	}
+
+	title, text, err := ExtractArticle(io.LimitReader(resp.Body, maxArticleSize))
+	if err != nil {
+		return Document{}, err
+	}
+	sentences := translator.SplitSentences(text)
+	if len(sentences) == 0 {
+		return Document{}, fmt.Errorf("no article text found at %s", rawURL)
+	}
+	if title == "" {
+		title = rawURL
+	}
+	return Document{Path: rawURL, Title: title, Sentences: sentences}, nil
+}
+
+// skippedElements never contain article text.
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Nav: true, atom.Header: true,
+	atom.Footer: true, atom.Aside: true, atom.Form: true, atom.Button: true, atom.Figure: true,
+	atom.Svg: true, atom.Iframe: true,
+}
+
+// ExtractArticle returns the title and main text of an HTML page, in the manner of
+// readability tools: the text of the <article> or <main> element if there is one,
+// otherwise of the element containing the most paragraph text. Paragraphs and
+// headings are separated by blank lines.
+func ExtractArticle(r io.Reader) (title, text string, err error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	var root *html.Node
+	scores := map[*html.Node]int{}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if skippedElements[n.DataAtom] {
+				return
+			}
+			switch n.DataAtom {
+			case atom.Title:
+				if title == "" {
+					title = strings.TrimSpace(nodeText(n))
+				}
+			case atom.Article, atom.Main:
+				if root == nil {
+					root = n
+				}
+			case atom.P:
+				if n.Parent != nil {
+					scores[n.Parent] += len(nodeText(n))
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if root == nil {
+		best := 0
+		for n, score := range scores {
+			if score > best {
+				root, best = n, score
+			}
+		}
+	}
+	if root == nil {
+		return title, "", nil
+	}
+
+	var b strings.Builder
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+		if skippedElements[n.DataAtom] {
+			return
+		}
+		switch n.DataAtom {
+		case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.Li, atom.Blockquote:
+			if t := strings.TrimSpace(nodeText(n)); t != "" {
+				b.WriteString(t)
+				b.WriteString("\n\n")
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(root)
+	return title, b.String(), nil
+}
+
+// nodeText returns the text content of n, skipping elements that never contain article text.
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	if n.Type == html.ElementNode && skippedElements[n.DataAtom] {
+		return ""
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(nodeText(c))
+	}
+	return b.String()
+}
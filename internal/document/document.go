@@ -0,0 +1,208 @@
+// Package document loads plain text, Markdown and EPUB files and web articles as a list of sentences.
+package document
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Document is a loaded file split into sentences.
+type Document struct {
+	Path      string // Absolute file path, used to remember the reading position, or article URL
+	Title     string
+	Sentences []string
+}
+
+// Load reads the file at path and splits its text into sentences.
+// The format is chosen by the file extension: .epub, .md/.markdown or plain text.
+func Load(filePath string) (Document, error) {
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to resolve %s: %w", filePath, err)
+	}
+
+	var text string
+	switch strings.ToLower(filepath.Ext(abs)) {
+	case ".epub":
+		text, err = readEPUB(abs)
+	case ".md", ".markdown":
+		var data []byte
+		data, err = os.ReadFile(abs)
+		text = StripMarkdown(string(data))
+	default:
+		var data []byte
+		data, err = os.ReadFile(abs)
+		text = string(data)
+	}
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to read document %s: %w", filePath, err)
+	}
+
+	sentences := translator.SplitSentences(text)
+	if len(sentences) == 0 {
+		return Document{}, fmt.Errorf("document %s contains no text", filePath)
+	}
+	return Document{Path: abs, Title: filepath.Base(abs), Sentences: sentences}, nil
+}
+
+var (
+	mdFence    = regexp.MustCompile("(?s)```.*?```")
+	mdImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdHeading  = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s*`)
+	mdList     = regexp.MustCompile(`(?m)^\s*(?:[-*+]|\d+[.)])\s+`)
+	mdQuote    = regexp.MustCompile(`(?m)^\s*>\s?`)
+	mdEmphasis = regexp.MustCompile("[*_`~]+")
+)
+
+// StripMarkdown removes Markdown syntax, keeping the readable text. Headings, list
+// items and quotes end up in their own paragraphs so they are not merged with the
+// following sentence.
+func StripMarkdown(text string) string {
+	text = mdFence.ReplaceAllString(text, "")
+	text = mdImage.ReplaceAllString(text, "$1")
+	text = mdLink.ReplaceAllString(text, "$1")
+	text = mdHeading.ReplaceAllString(text, "\n")
+	text = mdList.ReplaceAllString(text, "\n")
+	text = mdQuote.ReplaceAllString(text, "")
+	text = mdEmphasis.ReplaceAllString(text, "")
+	return text
+}
+
+// readEPUB returns the text of an EPUB book, chapter by chapter in reading order.
+func readEPUB(filePath string) (string, error) {
+	r, err := zip.OpenReader(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	files := map[string]*zip.File{}
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			Path string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := decodeXML(files, "META-INF/container.xml", &container); err != nil {
+		return "", err
+	}
+	if len(container.Rootfiles) == 0 {
+		return "", errors.New("EPUB has no package document")
+	}
+	opfPath := container.Rootfiles[0].Path
+
+	var pkg struct {
+		Manifest []struct {
+			ID   string `xml:"id,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"manifest>item"`
+		Spine []struct {
+			IDRef string `xml:"idref,attr"`
+		} `xml:"spine>itemref"`
+	}
+	if err := decodeXML(files, opfPath, &pkg); err != nil {
+		return "", err
+	}
+	hrefs := map[string]string{}
+	for _, item := range pkg.Manifest {
+		hrefs[item.ID] = item.Href
+	}
+
+	var text strings.Builder
+	for _, ref := range pkg.Spine {
+		href, ok := hrefs[ref.IDRef]
+		if !ok {
+			continue
+		}
+		f, ok := files[path.Join(path.Dir(opfPath), href)]
+		if !ok {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		err = extractXHTMLText(rc, &text)
+		rc.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", href, err)
+		}
+	}
+	return text.String(), nil
+}
+
+// decodeXML decodes the XML file name from the archive into v.
+func decodeXML(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("EPUB is missing %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// blockElements start a new paragraph in the extracted text.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "blockquote": true, "section": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "tr": true,
+}
+
+// extractXHTMLText writes the text content of an XHTML document to w, separating
+// block elements by blank lines and skipping scripts and styles.
+func extractXHTMLText(r io.Reader, w *strings.Builder) error {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+	skip := 0
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch name := strings.ToLower(t.Name.Local); {
+			case name == "script" || name == "style" || name == "head":
+				skip++
+			case blockElements[name]:
+				w.WriteString("\n\n")
+			}
+		case xml.EndElement:
+			switch name := strings.ToLower(t.Name.Local); {
+			case name == "script" || name == "style" || name == "head":
+				skip--
+			case blockElements[name]:
+				w.WriteString("\n\n")
+			}
+		case xml.CharData:
+			if skip == 0 {
+				w.Write(t)
+			}
+		}
+	}
+}
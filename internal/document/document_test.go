@@ -0,0 +1,132 @@
+package document
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+func TestLoadMarkdown(t *testing.T) {
+	doc := writeFile(t, "notes.md", "# Titel\n\nDas ist **fett** und [ein Link](https://example.com).\n\n- Punkt eins\n- Punkt zwei\n\n```\ncode()\n```\n")
+	got, err := Load(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Titel", "Das ist fett und ein Link.", "Punkt eins", "Punkt zwei"}
+	if !slices.Equal(got.Sentences, want) {
+		t.Errorf("sentences = %q, want %q", got.Sentences, want)
+	}
+}
+
+func TestLoadEPUB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.epub")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	files := map[string]string{
+		"mimetype":               "application/epub+zip",
+		"META-INF/container.xml": `<container><rootfiles><rootfile full-path="OEBPS/content.opf"/></rootfiles></container>`,
+		"OEBPS/content.opf":      `<package><manifest><item id="c2" href="two.xhtml"/><item id="c1" href="one.xhtml"/></manifest><spine><itemref idref="c1"/><itemref idref="c2"/></spine></package>`,
+		"OEBPS/one.xhtml":        `<html><head><style>p {}</style></head><body><h1>Kapitel 1</h1><p>Jag heter Anna.&nbsp;Du heter Bo.</p></body></html>`,
+		"OEBPS/two.xhtml":        `<html><body><p>Vi bor i Stockholm</p></body></html>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Kapitel 1", "Jag heter Anna.", "Du heter Bo.", "Vi bor i Stockholm"}
+	if !slices.Equal(got.Sentences, want) {
+		t.Errorf("sentences = %q, want %q", got.Sentences, want)
+	}
+}
+
+// writeFile creates a file with the given content in a temporary directory.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExtractArticle(t *testing.T) {
+	page := `<html><head><title>Nyheter</title><script>var x = "Inte text.";</script></head><body>
+<nav><p>Hem. Sport. Kultur.</p></nav>
+<div class="content"><h1>Rubrik</h1><p>Första meningen. Andra <a href="#">meningen</a>.</p><p>Tredje meningen.</p></div>
+<footer><p>Copyright.</p></footer></body></html>`
+	title, text, err := ExtractArticle(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Nyheter" {
+		t.Errorf("title = %q, want %q", title, "Nyheter")
+	}
+	want := []string{"Rubrik", "Första meningen.", "Andra meningen.", "Tredje meningen."}
+	if got := translator.SplitSentences(text); !slices.Equal(got, want) {
+		t.Errorf("sentences = %q, want %q", got, want)
+	}
+}
+
+func TestTail(t *testing.T) {
+	path := writeFile(t, "notes.txt", "Already here.\n")
+	tail, err := NewTail(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendText := func(text string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(text); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func() []string {
+		lines, err := tail.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return lines
+	}
+
+	if lines := read(); len(lines) != 0 {
+		t.Errorf("lines before appending = %q, want none", lines)
+	}
+	appendText("Ich bin müde.\n\nWo ist")
+	if lines := read(); !slices.Equal(lines, []string{"Ich bin müde."}) {
+		t.Errorf("lines = %q", lines)
+	}
+	appendText(" der Bahnhof?\n")
+	if lines := read(); !slices.Equal(lines, []string{"Wo ist der Bahnhof?"}) {
+		t.Errorf("lines after finishing a line = %q", lines)
+	}
+
+	if err := os.WriteFile(path, []byte("Neu.\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if lines := read(); !slices.Equal(lines, []string{"Neu."}) {
+		t.Errorf("lines after truncation = %q", lines)
+	}
+}
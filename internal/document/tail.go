@@ -0,0 +1,76 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Largest chunk of a file read at once by a Tail, in bytes
+const maxTailRead = 1 << 20
+
+// Tail follows a growing text file, returning the lines appended to it.
+type Tail struct {
+	Path    string // Absolute file path
+	offset  int64
+	partial []byte // Start of a line that is not finished yet
+}
+
+// NewTail starts following the file at path from its current end, so that only lines
+// appended later are returned.
+func NewTail(filePath string) (*Tail, error) {
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", filePath, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", filePath, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("failed to watch %s: is a directory", filePath)
+	}
+	return &Tail{Path: abs, offset: info.Size()}, nil
+}
+
+// Read returns the non-empty lines completed since the last call. A line is complete once
+// its newline has been written. If the file was truncated or replaced by a shorter one,
+// it is followed from its start again.
+func (t *Tail) Read() ([]string, error) {
+	f, err := os.Open(t.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", t.Path, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", t.Path, err)
+	}
+	if info.Size() < t.offset {
+		t.offset = 0
+		t.partial = nil
+	}
+	if info.Size() == t.offset {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(io.NewSectionReader(f, t.offset, min(info.Size()-t.offset, maxTailRead)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", t.Path, err)
+	}
+	t.offset += int64(len(data))
+	data = append(t.partial, data...)
+	end := bytes.LastIndexByte(data, '\n')
+	t.partial = append([]byte(nil), data[end+1:]...)
+
+	var lines []string
+	for _, line := range strings.Split(string(data[:end+1]), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,216 @@
+// Package drill scores answers of the practice modes against the text expected: the
+// words heard of a sentence, or the characters typed of it. It also makes up the
+// numbers, dates, prices and times to write out in words, and the gaps of cloze tests.
+package drill
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// wordPattern matches the words of a sentence, such as "Haus", "l'eau" or "Jean-Luc".
+var wordPattern = regexp.MustCompile(`[\p{L}\p{M}\p{N}]+(?:['’-][\p{L}\p{M}\p{N}]+)*`)
+
+// Gap stands for the word left out of a cloze test.
+const Gap = "____"
+
+// Verdicts of a word of an answer
+const (
+	Correct = "correct"
+	Wrong   = "wrong"   // Another word was given in its place
+	Missing = "missing" // Left out of the answer
+	Extra   = "extra"   // In the answer but not expected
+)
+
+// WordScore is the verdict on a word of the text expected, or on an extra word of the answer.
+type WordScore struct {
+	Word    string // As expected, or as given if extra
+	Given   string // The word given in its place, if wrong
+	Verdict string
+}
+
+// Words lists the words of a text.
+func Words(text string) []string {
+	return wordPattern.FindAllString(text, -1)
+}
+
+// CompareWords scores the words of an answer against those expected, aligning them
+// on the longest sequence of words in common. Case and punctuation are ignored, but
+// not diacritics: "Strasse" for "Straße" is wrong.
+func CompareWords(expected, answer string) []WordScore {
+	want, got := Words(expected), Words(answer)
+	n, m := len(want), len(got)
+	// common[i][j] is the length of the longest common sequence of want[i:] and got[j:]
+	common := make([][]int, n+1)
+	for i := range common {
+		common[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if strings.EqualFold(want[i], got[j]) {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var scores []WordScore
+	var skippedWant, skippedGot []string
+	flush := func() {
+		// Words skipped on both sides between two matches were heard as one another
+		for k := range max(len(skippedWant), len(skippedGot)) {
+			switch {
+			case k >= len(skippedWant):
+				scores = append(scores, WordScore{Word: skippedGot[k], Verdict: Extra})
+			case k >= len(skippedGot):
+				scores = append(scores, WordScore{Word: skippedWant[k], Verdict: Missing})
+			default:
+				scores = append(scores, WordScore{Word: skippedWant[k], Given: skippedGot[k], Verdict: Wrong})
+			}
+		}
+		skippedWant, skippedGot = nil, nil
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && strings.EqualFold(want[i], got[j]):
+			flush()
+			scores = append(scores, WordScore{Word: want[i], Verdict: Correct})
+			i++
+			j++
+		case j == m || (i < n && common[i+1][j] >= common[i][j+1]):
+			skippedWant = append(skippedWant, want[i])
+			i++
+		default:
+			skippedGot = append(skippedGot, got[j])
+			j++
+		}
+	}
+	flush()
+	return scores
+}
+
+// Blank returns the text with the first occurrence of word, ignoring case, replaced by a
+// gap, as in a cloze test. It reports false if the text does not have the word.
+func Blank(text, word string) (string, bool) {
+	word = strings.Join(Words(word), " ")
+	for _, loc := range wordPattern.FindAllStringIndex(text, -1) {
+		if strings.EqualFold(text[loc[0]:loc[1]], word) {
+			return text[:loc[0]] + Gap + text[loc[1]:], true
+		}
+	}
+	return text, false
+}
+
+// CountCorrect returns the number of words scored correct and the number of words
+// expected.
+func CountCorrect(scores []WordScore) (correct, expected int) {
+	for _, s := range scores {
+		if s.Verdict == Correct {
+			correct++
+		}
+		if s.Verdict != Extra {
+			expected++
+		}
+	}
+	return correct, expected
+}
+
+// CharMistake is a character typed wrong: one expected, one given in its place, or both.
+type CharMistake struct {
+	Want string // Empty if typed in excess
+	Got  string // Empty if left out
+}
+
+// TypingScore is the score of a text typed against the text expected.
+type TypingScore struct {
+	Chars    int // Characters expected
+	Errors   int // Characters typed wrong, left out or typed in excess
+	Mistakes []CharMistake
+}
+
+// Accuracy returns the share of the characters expected that were typed right, in percent.
+func (s TypingScore) Accuracy() int {
+	if s.Chars == 0 {
+		return 0
+	}
+	return max(0, s.Chars-s.Errors) * 100 / s.Chars
+}
+
+// CompareChars scores typed text against the text expected, character by character,
+// by the fewest characters typed wrong, left out or typed in excess. Case and
+// diacritics count: "Strasse" for "Straße" has two errors.
+func CompareChars(expected, typed string) TypingScore {
+	want, got := []rune(expected), []rune(typed)
+	n, m := len(want), len(got)
+	// dist[i][j] is the number of errors typing want[i:] as got[j:]
+	dist := make([][]int, n+1)
+	for i := range dist {
+		dist[i] = make([]int, m+1)
+		dist[i][m] = n - i
+	}
+	for j := range m + 1 {
+		dist[n][j] = m - j
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if want[i] == got[j] {
+				dist[i][j] = dist[i+1][j+1]
+			} else {
+				dist[i][j] = 1 + min(dist[i+1][j+1], dist[i+1][j], dist[i][j+1])
+			}
+		}
+	}
+
+	score := TypingScore{Chars: n, Errors: dist[0][0]}
+	i, j := 0, 0
+	for i < n || j < m {
+		if i < n && j < m && want[i] == got[j] {
+			i++
+			j++
+			continue
+		}
+		// Of the edits that keep the fewest errors, the one followed by a match is
+		// taken, so that "Strasse" for "Straße" is ß typed as s and an s in excess
+		di, dj := -1, -1
+		for _, step := range [][2]int{{1, 1}, {1, 0}, {0, 1}} {
+			ni, nj := i+step[0], j+step[1]
+			if ni > n || nj > m || (step == [2]int{1, 1} && (i == n || j == m)) || dist[i][j] != 1+dist[ni][nj] {
+				continue
+			}
+			if di < 0 || (ni < n && nj < m && want[ni] == got[nj]) {
+				di, dj = step[0], step[1]
+				if ni < n && nj < m && want[ni] == got[nj] {
+					break
+				}
+			}
+		}
+		mistake := CharMistake{}
+		if di == 1 {
+			mistake.Want = string(want[i])
+		}
+		if dj == 1 {
+			mistake.Got = string(got[j])
+		}
+		score.Mistakes = append(score.Mistakes, mistake)
+		i += di
+		j += dj
+	}
+	return score
+}
+
+// SpecialChars lists the letters of a text beyond the ASCII alphabet, such as "ß" or
+// "š", each once in the order they appear.
+func SpecialChars(text string) []string {
+	var chars []string
+	seen := map[rune]bool{}
+	for _, r := range text {
+		if r > unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsMark(r)) && !seen[r] {
+			seen[r] = true
+			chars = append(chars, string(r))
+		}
+	}
+	return chars
+}
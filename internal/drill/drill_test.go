@@ -0,0 +1,90 @@
+package drill
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestCompareWords(t *testing.T) {
+	tests := []struct {
+		expected, answer string
+		want             []WordScore
+	}{
+		{
+			"Ich bin glücklich.", "ich bin glücklich",
+			[]WordScore{{"Ich", "", Correct}, {"bin", "", Correct}, {"glücklich", "", Correct}},
+		},
+		{
+			"Die Straße ist lang.", "Die Strasse lang",
+			[]WordScore{{"Die", "", Correct}, {"Straße", "Strasse", Wrong}, {"ist", "", Missing}, {"lang", "", Correct}},
+		},
+		{
+			"Dobar dan.", "dobar dan gospodine",
+			[]WordScore{{"Dobar", "", Correct}, {"dan", "", Correct}, {"gospodine", "", Extra}},
+		},
+		{"Hallo", "", []WordScore{{"Hallo", "", Missing}}},
+	}
+	for _, tt := range tests {
+		got := CompareWords(tt.expected, tt.answer)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CompareWords(%q, %q) = %+v, want %+v", tt.expected, tt.answer, got, tt.want)
+		}
+	}
+
+	correct, expected := CountCorrect(CompareWords("Die Straße ist lang.", "Die Strasse lang noch"))
+	if correct != 2 || expected != 4 {
+		t.Errorf("CountCorrect = %d/%d, want 2/4", correct, expected)
+	}
+}
+
+func TestBlank(t *testing.T) {
+	tests := []struct {
+		text, word, want string
+		ok               bool
+	}{
+		{"Ich bin glücklich.", "glücklich.", "Ich bin ____.", true},
+		{"Bist du glücklich? Ich bin glücklich.", "Glücklich", "Bist du ____? Ich bin glücklich.", true},
+		{"Das Glück ist da.", "glücklich", "Das Glück ist da.", false},
+	}
+	for _, tt := range tests {
+		got, ok := Blank(tt.text, tt.word)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Blank(%q, %q) = %q, %v, want %q, %v", tt.text, tt.word, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCompareChars(t *testing.T) {
+	score := CompareChars("Die Straße.", "Die Strasse")
+	want := []CharMistake{{"ß", "s"}, {"", "s"}, {".", ""}}
+	if score.Chars != 11 || score.Errors != 3 || !reflect.DeepEqual(score.Mistakes, want) {
+		t.Errorf("CompareChars = %+v", score)
+	}
+	if got := score.Accuracy(); got != 72 {
+		t.Errorf("Accuracy = %d, want 72", got)
+	}
+	if got := CompareChars("Čaša", "Čaša"); got.Errors != 0 || got.Accuracy() != 100 {
+		t.Errorf("exact copy = %+v", got)
+	}
+	if got := SpecialChars("Čaša je puna, ćao, čaša."); !reflect.DeepEqual(got, []string{"Č", "š", "ć", "č"}) {
+		t.Errorf("SpecialChars = %q", got)
+	}
+}
+
+func TestRandomFigure(t *testing.T) {
+	patterns := map[string]*regexp.Regexp{
+		Number: regexp.MustCompile(`^[0-9]{1,7}$`),
+		Date:   regexp.MustCompile(`^(19|20)[0-9]{2}-[01][0-9]-[0-3][0-9]$`),
+		Price:  regexp.MustCompile(`^[0-9]{1,3}\.[0-9]{2} €$`),
+		Time:   regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][05]$`),
+	}
+	for range 100 {
+		for _, kind := range FigureKinds {
+			figure := RandomFigure(kind)
+			if figure.Kind != kind || !patterns[kind].MatchString(figure.Digits) {
+				t.Errorf("RandomFigure(%q) = %+v", kind, figure)
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package drill
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Kinds of figures to write out in words
+const (
+	Number = "number"
+	Date   = "date"
+	Price  = "price"
+	Time   = "time"
+)
+
+// FigureKinds lists the kinds of figures in the order they are drilled.
+var FigureKinds = []string{Number, Date, Price, Time}
+
+// Figure is a number, date, price or time in digits, in a notation no language owns:
+// dates as 2024-03-14, prices as 12.49 €, times as 07:45.
+type Figure struct {
+	Kind   string
+	Digits string
+}
+
+// RandomFigure returns a figure of the kind with random digits. Numbers are mostly
+// small, as those are the ones said most, with some up to the millions.
+func RandomFigure(kind string) Figure {
+	switch kind {
+	case Date:
+		day := time.Date(1900+rand.IntN(150), time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, rand.IntN(365))
+		return Figure{Date, day.Format(time.DateOnly)}
+	case Price:
+		euros := []int{rand.IntN(10), rand.IntN(100), rand.IntN(1000)}[rand.IntN(3)]
+		return Figure{Price, fmt.Sprintf("%d.%02d €", euros, rand.IntN(100))}
+	case Time:
+		return Figure{Time, fmt.Sprintf("%02d:%02d", rand.IntN(24), rand.IntN(12)*5)}
+	}
+	limits := []int{20, 100, 1000, 10000, 10000000}
+	return Figure{Number, fmt.Sprint(rand.IntN(limits[rand.IntN(len(limits))]))}
+}
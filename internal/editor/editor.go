@@ -0,0 +1,139 @@
+// Package editor serves the JSON-RPC protocol used by editor plugins: translating and
+// analyzing selected text and looking up words. Calls use the same configuration,
+// cache and history as the TUI.
+package editor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/jsonrpc"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/internal/wiktionary"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// TextParams are the parameters of translate and analyze.
+type TextParams struct {
+	Text       string `json:"text"`
+	UserLang   string `json:"userLang"`   // Code of the language the user knows, e.g. "en"
+	TargetLang string `json:"targetLang"` // Code of the language being learned, e.g. "de"
+}
+
+// TranslateResult is the result of translate.
+type TranslateResult struct {
+	Original    string `json:"original"`
+	Translation string `json:"translation"`
+	Level       string `json:"level,omitempty"`
+	Degraded    string `json:"degraded,omitempty"` // Why a fallback produced the result
+	Cached      bool   `json:"cached,omitempty"`
+}
+
+// AnalyzeResult is the result of analyze.
+type AnalyzeResult struct {
+	TranslateResult
+	Words []translator.WordInfo `json:"words"`
+}
+
+// LookupParams are the parameters of lookupWord.
+type LookupParams struct {
+	Word string `json:"word"`
+	Lang string `json:"lang"` // Code of the language of the word, e.g. "de"
+}
+
+// Service implements the methods.
+type Service struct {
+	cfg    config.Config
+	client *http.Client
+
+	// run runs the pipeline; tests replace it.
+	run func(ctx context.Context, cfg config.Config, req translator.Request) (translate.Result, error)
+}
+
+// NewService creates the service with the configured providers and HTTP client.
+func NewService(cfg config.Config) (*Service, error) {
+	client, err := translate.NewHTTPClient(cfg.Network)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		cfg:    cfg,
+		client: client,
+		run: func(ctx context.Context, cfg config.Config, req translator.Request) (translate.Result, error) {
+			return translate.Run(ctx, cfg, req, nil)
+		},
+	}, nil
+}
+
+// Server creates the JSON-RPC server of the service.
+func (s *Service) Server() *jsonrpc.Server {
+	srv := jsonrpc.NewServer()
+	srv.Handle("translate", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		result, err := s.translate(ctx, raw)
+		if err != nil {
+			return nil, err
+		}
+		return result.TranslateResult, nil
+	})
+	srv.Handle("analyze", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		return s.translate(ctx, raw)
+	})
+	srv.Handle("lookupWord", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		params, err := jsonrpc.Params[LookupParams](raw)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(params.Word) == "" || params.Lang == "" {
+			return nil, jsonrpc.InvalidParams("word and lang are required")
+		}
+		return translator.RunStep(ctx, s.cfg.Timeout, "Looking up", nil, func(ctx context.Context) (*wiktionary.Entry, error) {
+			return wiktionary.Lookup(ctx, s.client, s.cfg.WiktionaryURL, params.Word, translator.LanguageName(params.Lang))
+		})
+	})
+	return srv
+}
+
+// Serve answers requests from r on w until r ends.
+func (s *Service) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	return s.Server().Serve(ctx, r, w)
+}
+
+// translate runs the pipeline for the text of a call and records it in the history.
+func (s *Service) translate(ctx context.Context, raw json.RawMessage) (AnalyzeResult, error) {
+	params, err := jsonrpc.Params[TextParams](raw)
+	if err != nil {
+		return AnalyzeResult{}, err
+	}
+	params.Text = strings.TrimSpace(params.Text)
+	if params.Text == "" || params.UserLang == "" || params.TargetLang == "" {
+		return AnalyzeResult{}, jsonrpc.InvalidParams("text, userLang and targetLang are required")
+	}
+	result, err := s.run(ctx, s.cfg, translator.Request{Sentence: params.Text, UserLang: params.UserLang, TargetLang: params.TargetLang})
+	if err != nil {
+		return AnalyzeResult{}, err
+	}
+	_ = translate.Record(ctx, s.cfg, storage.HistoryEntry{ // The history and hooks are best-effort here
+		Time:        time.Now(),
+		UserLang:    params.UserLang,
+		TargetLang:  params.TargetLang,
+		Original:    result.Original,
+		Translation: result.Translation,
+		Words:       result.Words,
+	})
+	return AnalyzeResult{
+		TranslateResult: TranslateResult{
+			Original:    result.Original,
+			Translation: result.Translation,
+			Level:       result.Level,
+			Degraded:    result.Degraded,
+			Cached:      result.Cached,
+		},
+		Words: result.Words,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package editor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+func TestServe(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	s := &Service{
+		cfg:    config.Default(),
+		client: http.DefaultClient,
+		run: func(ctx context.Context, cfg config.Config, req translator.Request) (translate.Result, error) {
+			if req.Sentence != "Ich bin müde" {
+				return translate.Result{}, errors.New("quota exceeded")
+			}
+			return translate.Result{Result: translator.Result{
+				Original:    "Ich bin müde",
+				Translation: "I am tired",
+				Words:       []translator.WordInfo{{WordInTargetLang: "müde", GrammaticalExplanation: "adjective"}},
+			}}, nil
+		},
+	}
+	in := strings.Join([]string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "translate", "params": {"text": "Ich bin müde", "userLang": "en", "targetLang": "de"}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "analyze", "params": {"text": "Ich bin müde", "userLang": "en", "targetLang": "de"}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "translate", "params": {"text": "Hallo", "userLang": "en", "targetLang": "de"}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "lookupWord", "params": {"word": ""}}`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var resp struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatal(err)
+		}
+		got[string(resp.ID)] = line
+	}
+	for id, want := range map[string]string{
+		"1": `"result":{"original":"Ich bin müde","translation":"I am tired"}`,
+		"2": `"words":[{"word_in_target_lang":"müde"`,
+		"3": `"message":"quota exceeded"`,
+		"4": `"code":-32602`,
+	} {
+		if !strings.Contains(got[id], want) {
+			t.Errorf("response %s = %s, want it to contain %s", id, got[id], want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package export writes translations as plain text or Markdown, free of the styling of
+// the terminal, for pasting into e-mails and documents, or as HTML study pages.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Formats of exported translations
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats lists the formats in the order they are documented.
+var Formats = []string{FormatText, FormatMarkdown, FormatHTML}
+
+// timeLayout is how the time of a translation is shown.
+const timeLayout = "2006-01-02 15:04"
+
+// Extension returns the file extension of a format, with the dot.
+func Extension(format string) string {
+	switch format {
+	case FormatMarkdown:
+		return ".md"
+	case FormatHTML:
+		return ".html"
+	}
+	return ".txt"
+}
+
+// Write writes the translations in the format: each with its language pair, time,
+// original, translation and analyzed words.
+func Write(w io.Writer, format string, entries []storage.HistoryEntry) error {
+	if format == FormatHTML {
+		return writeHTML(w, entries)
+	}
+	var s strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			s.WriteString("\n")
+		}
+		switch format {
+		case FormatText:
+			writeText(&s, e)
+		case FormatMarkdown:
+			writeMarkdown(&s, e)
+		default:
+			return fmt.Errorf("unknown export format %q (use %s)", format, strings.Join(Formats, ", "))
+		}
+	}
+	if _, err := io.WriteString(w, s.String()); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// ForeignSentence returns the sentence of a translation in the language learned: the
+// original or the translation, whichever the analyzed words are found in.
+func ForeignSentence(e storage.HistoryEntry) string {
+	original, translation := strings.ToLower(e.Original), strings.ToLower(e.Translation)
+	score := 0
+	for _, word := range e.Words {
+		w := strings.ToLower(word.WordInTargetLang)
+		if strings.Contains(original, w) {
+			score++
+		}
+		if strings.Contains(translation, w) {
+			score--
+		}
+	}
+	if score > 0 {
+		return e.Original
+	}
+	return e.Translation
+}
+
+// pair returns the language pair of a translation, such as "Swedish → German".
+func pair(e storage.HistoryEntry) string {
+	return translator.LanguageName(e.UserLang) + " → " + translator.LanguageName(e.TargetLang)
+}
+
+// writeText writes a translation as plain text.
+func writeText(s *strings.Builder, e storage.HistoryEntry) {
+	heading := pair(e)
+	if !e.Time.IsZero() {
+		heading += ", " + e.Time.Local().Format(timeLayout)
+	}
+	fmt.Fprintf(s, "%s\n%s\n\n", heading, strings.Repeat("-", len([]rune(heading))))
+	fmt.Fprintf(s, "Original:    %s\nTranslation: %s\n", e.Original, e.Translation)
+	if len(e.Words) == 0 {
+		return
+	}
+	s.WriteString("\nWords:\n")
+	for _, w := range e.Words {
+		s.WriteString("  " + w.WordInTargetLang)
+		if w.Gloss != "" {
+			s.WriteString(" (" + w.Gloss + ")")
+		}
+		if w.GrammaticalExplanation != "" {
+			s.WriteString(": " + w.GrammaticalExplanation)
+		}
+		s.WriteString("\n")
+	}
+}
+
+// writeMarkdown writes a translation as a Markdown section.
+func writeMarkdown(s *strings.Builder, e storage.HistoryEntry) {
+	heading := pair(e)
+	if !e.Time.IsZero() {
+		heading += " · " + e.Time.Local().Format(timeLayout)
+	}
+	fmt.Fprintf(s, "## %s\n\n", heading)
+	fmt.Fprintf(s, "> %s\n\n", strings.ReplaceAll(e.Original, "\n", "\n> "))
+	fmt.Fprintf(s, "**%s**\n", strings.TrimSpace(e.Translation))
+	if len(e.Words) > 0 {
+		s.WriteString("\n")
+		WriteWordTable(s, e.Words)
+	}
+}
+
+// WriteWordTable writes a word analysis as a Markdown table of the words, their
+// meaning and grammar.
+func WriteWordTable(s *strings.Builder, words []translator.WordInfo) {
+	s.WriteString("| Word | Meaning | Explanation |\n| --- | --- | --- |\n")
+	for _, w := range words {
+		fmt.Fprintf(s, "| %s | %s | %s |\n", MarkdownCell(w.WordInTargetLang), MarkdownCell(w.Gloss), MarkdownCell(w.GrammaticalExplanation))
+	}
+}
+
+// markdownCell escapes what would break a cell of a Markdown table.
+var markdownCell = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// MarkdownCell returns text escaped for a cell of a Markdown table.
+func MarkdownCell(text string) string {
+	return markdownCell.Replace(text)
+}
@@ -0,0 +1,180 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+func TestWrite(t *testing.T) {
+	entries := []storage.HistoryEntry{
+		{
+			UserLang: "sv", TargetLang: "de", Original: "jag är glad", Translation: "Ich bin glücklich.",
+			Words: []translator.WordInfo{{WordInTargetLang: "bin", Gloss: "är", GrammaticalExplanation: "1st person | singular"}},
+		},
+		{Time: time.Date(2026, 10, 15, 9, 30, 0, 0, time.Local), UserLang: "en", TargetLang: "es", Original: "Thanks", Translation: "Gracias"},
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FormatText, "Swedish → German\n----------------\n\n" +
+			"Original:    jag är glad\nTranslation: Ich bin glücklich.\n\n" +
+			"Words:\n  bin (är): 1st person | singular\n\n" +
+			"English → Spanish, 2026-10-15 09:30\n-----------------------------------\n\n" +
+			"Original:    Thanks\nTranslation: Gracias\n"},
+		{FormatMarkdown, "## Swedish → German\n\n> jag är glad\n\n**Ich bin glücklich.**\n\n" +
+			"| Word | Meaning | Explanation |\n| --- | --- | --- |\n| bin | är | 1st person \\| singular |\n\n" +
+			"## English → Spanish · 2026-10-15 09:30\n\n> Thanks\n\n**Gracias**\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var s strings.Builder
+			if err := Write(&s, tt.format, entries); err != nil {
+				t.Fatal(err)
+			}
+			if got := s.String(); got != tt.want {
+				t.Errorf("export =\n%s\nwant\n%s", got, tt.want)
+			}
+			if strings.Contains(s.String(), "\x1b") {
+				t.Error("export contains escape sequences")
+			}
+		})
+	}
+	if err := Write(&strings.Builder{}, "pdf", entries); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	audio, err := storage.DataFile(filepath.Join("audio", "de", "bin.wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(audio), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(audio, []byte("RIFF"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []storage.HistoryEntry{{
+		UserLang: "sv", TargetLang: "de", Original: "jag ser fram emot <det>", Translation: "Ich freue mich darauf, bin da.",
+		Words: []translator.WordInfo{
+			{WordInTargetLang: "freue mich", Gloss: "ser fram emot", GrammaticalExplanation: "reflexive verb", Idiom: true},
+			{WordInTargetLang: "bin", Gloss: "är", Lemma: "sein", PartOfSpeech: "verb", Level: "A1", Usage: "very common"},
+			{WordInTargetLang: "da", GrammaticalExplanation: "adverb"},
+		},
+	}}
+	var s strings.Builder
+	if err := Write(&s, FormatHTML, entries); err != nil {
+		t.Fatal(err)
+	}
+	page := s.String()
+	for _, want := range []string{
+		`<p class="sentence" lang="de">Ich <ruby>freue mich<rp>(</rp><rt>ser fram emot</rt><rp>)</rp></ruby> darauf, <ruby>bin<rp>(</rp><rt>är</rt><rp>)</rp></ruby> da.</p>`,
+		`<p class="translation" lang="sv">jag ser fram emot &lt;det&gt;</p>`,
+		`<span lang="de">bin</span> <span class="info">sein, verb, A1</span> <audio controls preload="none" src="file:///`,
+		`<p>Usage: very common</p>`,
+		`<span lang="de">da</span></summary>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page lacks %s:\n%s", want, page)
+		}
+	}
+}
+
+func TestWriteOrg(t *testing.T) {
+	cards := []storage.VocabCard{
+		{Word: "Morgen", Analysis: "noun, masculine\n* plural: Morgen", Sentence: "Guten Morgen!", Translation: "God morgon!", Lang: "de", Added: time.Date(2026, 10, 15, 9, 30, 0, 0, time.Local), Level: "A1"},
+		{Word: "sich freuen\nauf", Analysis: "reflexive verb", Lang: "de", Priority: true},
+	}
+	var s strings.Builder
+	if err := WriteOrg(&s, cards); err != nil {
+		t.Fatal(err)
+	}
+	want := "* Morgen :drill:\n:PROPERTIES:\n:LANG: de\n:CEFR: A1\n:ADDED: [2026-10-15 Thu 09:30]\n:END:\n" +
+		":ANALYSIS:\nnoun, masculine\n * plural: Morgen\nSentence: Guten Morgen!\nTranslation: God morgon!\n:END:\n" +
+		"* [#A] sich freuen auf :drill:\n:PROPERTIES:\n:LANG: de\n:END:\n:ANALYSIS:\nreflexive verb\n:END:\n"
+	if got := s.String(); got != want {
+		t.Errorf("org =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAppendTranscript(t *testing.T) {
+	dir := t.TempDir()
+	morning := time.Date(2026, 10, 15, 9, 30, 0, 0, time.Local)
+	entries := []storage.HistoryEntry{
+		{
+			Time: morning, UserLang: "sv", TargetLang: "de", Original: "jag är glad", Translation: "Ich bin glücklich.",
+			Words: []translator.WordInfo{
+				{WordInTargetLang: "bin", Gloss: "är", Level: "A1"},
+				{WordInTargetLang: "glücklich", Gloss: "glad", Level: "B1"},
+			},
+		},
+		{Time: morning.Add(time.Hour), UserLang: "sv", TargetLang: "de", Original: "tack", Translation: "danke"},
+	}
+	for _, e := range entries {
+		if err := AppendTranscript(dir, e, "A2"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "2026-10-15.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Study session 2026-10-15\n\n" +
+		"## 09:30 · Swedish → German\n\n> jag är glad\n\n**Ich bin glücklich.**\n\nKey words: **glücklich** (glad)\n\n" +
+		"## 10:30 · Swedish → German\n\n> tack\n\n**danke**\n"
+	if got := string(data); got != want {
+		t.Errorf("transcript =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAppendTranscriptEncrypted(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	entry := storage.HistoryEntry{Time: time.Date(2026, 10, 15, 9, 30, 0, 0, time.Local), UserLang: "sv", TargetLang: "de", Original: "tack", Translation: "danke"}
+	if err := AppendTranscript("", entry, ""); err != nil {
+		t.Fatal(err)
+	}
+	path, err := storage.DataFile(filepath.Join(storage.TranscriptDirName, "2026-10-15.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Encrypting covers the transcript written before, and sections appended after
+	if err := storage.EncryptAll("secret"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { storage.DecryptAll() })
+	entry.Time = entry.Time.Add(time.Hour)
+	entry.Original = "hej"
+	if err := AppendTranscript("", entry, ""); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tack") || strings.Contains(string(data), "hej") {
+		t.Errorf("encrypted transcript contains the sentences:\n%s", data)
+	}
+
+	if err := storage.DecryptAll(); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = os.ReadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Study session 2026-10-15\n\n## 09:30 · Swedish → German\n\n> tack\n\n**danke**\n" +
+		"\n## 10:30 · Swedish → German\n\n> hej\n\n**danke**\n"
+	if got := string(data); got != want {
+		t.Errorf("decrypted transcript =\n%s\nwant\n%s", got, want)
+	}
+}
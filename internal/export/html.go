@@ -0,0 +1,197 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/brittaao/translation-tui/internal/pronunciation"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// wordPattern matches the words of a sentence, such as "Haus", "l'eau" or "Jean-Luc".
+var wordPattern = regexp.MustCompile(`[\p{L}\p{M}\p{N}]+(?:['’-][\p{L}\p{M}\p{N}]+)*`)
+
+// htmlEntry is a translation as shown on the study page.
+type htmlEntry struct {
+	Heading   string
+	Lang      string  // Of the sentence in the language learned
+	Sentence  []Token // The sentence in the language learned
+	OtherLang string
+	Other     string // The sentence in the user's language
+	Words     []htmlWord
+}
+
+// Token is a part of a sentence: a word or expression with its gloss, or the text
+// between them.
+type Token struct {
+	Text  string
+	Gloss string
+	Word  bool // A word or expression rather than the text between them
+}
+
+// htmlWord is an analyzed word with its grammar notes.
+type htmlWord struct {
+	translator.WordInfo
+	Info  string       // Lemma, part of speech and level
+	Audio template.URL // Of the cached pronunciation
+}
+
+// htmlPage is the standalone study page: the sentences with glosses above their words,
+// and the grammar notes of each word folded away below them. The style sheet is
+// inline so that the file can be opened, mailed or printed on its own.
+var htmlPage = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Translation study page</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #222; }
+h2 { font-size: 1rem; font-weight: normal; color: #666; margin-top: 2.5rem; }
+.sentence { font-size: 1.6rem; line-height: 2.8; margin: 0; }
+rt { font-size: 0.5em; color: #2a6f97; }
+.translation { font-style: italic; margin-top: 0; }
+summary { cursor: pointer; font-weight: bold; }
+.info { font-weight: normal; color: #666; }
+details p { margin: 0.2rem 0 0.6rem 1.2rem; }
+audio { height: 1.8rem; vertical-align: middle; }
+@media print { audio { display: none; } }
+</style>
+</head>
+<body>
+<h1>Translation study page</h1>
+{{- range .}}{{$lang := .Lang}}
+<section>
+<h2>{{.Heading}}</h2>
+<p class="sentence" lang="{{.Lang}}">{{range .Sentence}}{{if .Gloss}}<ruby>{{.Text}}<rp>(</rp><rt>{{.Gloss}}</rt><rp>)</rp></ruby>{{else}}{{.Text}}{{end}}{{end}}</p>
+<p class="translation" lang="{{.OtherLang}}">{{.Other}}</p>
+{{- range .Words}}
+<details>
+<summary><span lang="{{$lang}}">{{.WordInTargetLang}}</span>{{with .Info}} <span class="info">{{.}}</span>{{end}}{{with .Audio}} <audio controls preload="none" src="{{.}}"></audio>{{end}}</summary>
+{{- with .GrammaticalExplanation}}
+<p>{{.}}</p>
+{{- end}}
+{{- with .Morphology}}
+<p>Morphology: {{.}}</p>
+{{- end}}
+{{- with .Usage}}
+<p>Usage: {{.}}</p>
+{{- end}}
+{{- with .FalseFriend}}
+<p>False friend: {{.}}</p>
+{{- end}}
+</details>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// writeHTML writes the translations as a study page.
+func writeHTML(w io.Writer, entries []storage.HistoryEntry) error {
+	page := make([]htmlEntry, len(entries))
+	for i, e := range entries {
+		heading := pair(e)
+		if !e.Time.IsZero() {
+			heading += " · " + e.Time.Local().Format(timeLayout)
+		}
+		foreign := ForeignSentence(e)
+		other := e.Original
+		if foreign == e.Original {
+			other = e.Translation
+		}
+		page[i] = htmlEntry{
+			Heading:   heading,
+			Lang:      e.TargetLang,
+			Sentence:  Annotate(foreign, e.Words),
+			OtherLang: e.UserLang,
+			Other:     other,
+			Words:     make([]htmlWord, len(e.Words)),
+		}
+		for j, word := range e.Words {
+			page[i].Words[j] = htmlWord{WordInfo: word, Info: wordInfo(word)}
+			if audio, ok := pronunciation.Cached(word.WordInTargetLang, e.TargetLang); ok {
+				page[i].Words[j].Audio = fileURL(audio.Path)
+			}
+		}
+	}
+	if err := htmlPage.Execute(w, page); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// Annotate splits a sentence into its words and expressions, with the glosses of those
+// analyzed, and the text between them.
+func Annotate(sentence string, words []translator.WordInfo) []Token {
+	locs := wordPattern.FindAllStringIndex(sentence, -1)
+	var tokens []Token
+	last := 0
+	for i := 0; i < len(locs); {
+		start := locs[i][0]
+		if start > last {
+			tokens = append(tokens, Token{Text: sentence[last:start]})
+		}
+		gloss, n := matchWord(sentence, locs[i:], words)
+		end := locs[i+n-1][1]
+		tokens = append(tokens, Token{Text: sentence[start:end], Gloss: gloss, Word: true})
+		last = end
+		i += n
+	}
+	if last < len(sentence) {
+		tokens = append(tokens, Token{Text: sentence[last:]})
+	}
+	return tokens
+}
+
+// matchWord returns the gloss of the longest analyzed word or expression starting at
+// the first of the word locations, and how many words it spans: at least one.
+func matchWord(sentence string, locs [][]int, words []translator.WordInfo) (string, int) {
+	gloss, n := "", 1
+	for _, w := range words {
+		parts := wordPattern.FindAllString(w.WordInTargetLang, -1)
+		if w.Gloss == "" || len(parts) == 0 || len(parts) > len(locs) || (gloss != "" && len(parts) <= n) {
+			continue
+		}
+		matched := true
+		for j, part := range parts {
+			if !strings.EqualFold(part, sentence[locs[j][0]:locs[j][1]]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			gloss, n = w.Gloss, len(parts)
+		}
+	}
+	return gloss, n
+}
+
+// wordInfo returns the lemma, part of speech and level of a word, as far as known.
+func wordInfo(w translator.WordInfo) string {
+	var parts []string
+	if w.Lemma != "" && !strings.EqualFold(w.Lemma, w.WordInTargetLang) {
+		parts = append(parts, w.Lemma)
+	}
+	for _, part := range []string{w.PartOfSpeech, w.Level} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fileURL returns the file: URL of a local file, for linking audio into the page.
+func fileURL(path string) template.URL {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return template.URL((&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String())
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// FormatOrg writes the vocab deck as an Org file for org-drill.
+const FormatOrg = "org"
+
+// orgTimeLayout is the layout of an inactive Org timestamp.
+const orgTimeLayout = "[2006-01-02 Mon 15:04]"
+
+// WriteOrg writes vocab cards as an Org file of org-drill cards: each word on a
+// heading tagged :drill:, with cards above the user's level at priority A, its language,
+// level and date in the properties, and the analysis, sentence and translation in an
+// ANALYSIS drawer that stays folded until the answer is shown.
+func WriteOrg(w io.Writer, cards []storage.VocabCard) error {
+	var s strings.Builder
+	for _, c := range cards {
+		s.WriteString("* ")
+		if c.Priority {
+			s.WriteString("[#A] ")
+		}
+		fmt.Fprintf(&s, "%s :drill:\n", strings.Join(strings.Fields(c.Word), " "))
+		s.WriteString(":PROPERTIES:\n")
+		fmt.Fprintf(&s, ":LANG: %s\n", c.Lang)
+		if c.Level != "" {
+			fmt.Fprintf(&s, ":CEFR: %s\n", c.Level)
+		}
+		if !c.Added.IsZero() {
+			fmt.Fprintf(&s, ":ADDED: %s\n", c.Added.Local().Format(orgTimeLayout))
+		}
+		s.WriteString(":END:\n:ANALYSIS:\n")
+		writeOrgLines(&s, "", c.Analysis)
+		writeOrgLines(&s, "Sentence: ", c.Sentence)
+		writeOrgLines(&s, "Translation: ", c.Translation)
+		s.WriteString(":END:\n")
+	}
+	if _, err := io.WriteString(w, s.String()); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// writeOrgLines writes text into a drawer, after label. Lines that Org would take for
+// headings or the end of the drawer are indented.
+func writeOrgLines(s *strings.Builder, label, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(label+text, "\n") {
+		if strings.HasPrefix(line, "*") || strings.HasPrefix(line, ":") {
+			line = " " + line
+		}
+		s.WriteString(line)
+		s.WriteString("\n")
+	}
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// AppendTranscript appends a translation to the Markdown transcript of its day, in dir
+// or, if dir is empty, in the transcripts directory of the data directory: the time,
+// original, translation and key words. Key words are those above the known level,
+// idioms and false friends, or all analyzed words if no level is known. The transcript
+// is encrypted while the data directory is.
+func AppendTranscript(dir string, entry storage.HistoryEntry, known string) error {
+	if dir == "" {
+		var err error
+		if dir, err = storage.DataFile(storage.TranscriptDirName); err != nil {
+			return err
+		}
+	}
+	day := entry.Time.Local().Format("2006-01-02")
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "\n## %s · %s\n\n", entry.Time.Local().Format("15:04"), pair(entry))
+	fmt.Fprintf(&s, "> %s\n\n", strings.ReplaceAll(entry.Original, "\n", "\n> "))
+	fmt.Fprintf(&s, "**%s**\n", strings.TrimSpace(entry.Translation))
+	var words []string
+	for _, w := range entry.Words {
+		if known != "" && !translator.AboveLevel(w.Level, known) && !w.Idiom && w.FalseFriend == "" {
+			continue
+		}
+		word := "**" + w.WordInTargetLang + "**"
+		if w.Gloss != "" {
+			word += " (" + w.Gloss + ")"
+		}
+		words = append(words, word)
+	}
+	if len(words) > 0 {
+		fmt.Fprintf(&s, "\nKey words: %s\n", strings.Join(words, " · "))
+	}
+
+	path := filepath.Join(dir, day+".md")
+	return storage.AppendTranscript(path, "# Study session "+day+"\n", s.String())
+}
@@ -0,0 +1,179 @@
+// Package frequency ranks words by how common they are in a language, using frequency
+// lists stored in the data directory.
+package frequency
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// DefaultURL is the location of the frequency lists that are downloaded, with {lang}
+// standing for the language code. The lists are built from OpenSubtitles and hold the
+// 50,000 most common words of each language.
+const DefaultURL = "https://raw.githubusercontent.com/hermitdave/FrequencyWords/master/content/2018/{lang}/{lang}_50k.txt"
+
+// Directory inside the data directory holding the lists, one <lang>.txt file per language
+const dirName = "frequency"
+
+// Largest list that is downloaded, in bytes
+const maxListSize = 32 << 20
+
+// ErrNotInstalled is returned by Load when there is no list for the language.
+var ErrNotInstalled = errors.New("no frequency list installed")
+
+// List maps words to their rank: 1 for the most common word.
+type List struct {
+	ranks map[string]int
+}
+
+// Parse reads a frequency list with one word per line, most common first. Anything after
+// the word on a line, such as the number of occurrences, is ignored.
+func Parse(r io.Reader) (*List, error) {
+	l := &List{ranks: map[string]int{}}
+	sc := bufio.NewScanner(r)
+	rank := 0
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		rank++
+		word := strings.ToLower(fields[0])
+		if _, ok := l.ranks[word]; !ok {
+			l.ranks[word] = rank
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read frequency list: %w", err)
+	}
+	return l, nil
+}
+
+// Rank returns the rank of word, or 0 if it is not in the list.
+func (l *List) Rank(word string) int {
+	return l.ranks[strings.ToLower(strings.TrimSpace(word))]
+}
+
+// Len returns the number of words in the list.
+func (l *List) Len() int {
+	return len(l.ranks)
+}
+
+// Band describes a rank for learners: how common a word is.
+func Band(rank int) string {
+	switch {
+	case rank <= 0:
+		return ""
+	case rank <= 1000:
+		return "top 1k"
+	case rank <= 5000:
+		return "top 5k"
+	case rank <= 10000:
+		return "top 10k"
+	}
+	return "rare"
+}
+
+// Path returns the location of the list of a language, creating its directory if needed.
+func Path(lang string) (string, error) {
+	dir, err := storage.DataFile(dirName)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create frequency directory: %w", err)
+	}
+	return filepath.Join(dir, lang+".txt"), nil
+}
+
+// Load reads the installed list of a language.
+func Load(lang string) (*List, error) {
+	path, err := Path(lang)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s", ErrNotInstalled, lang)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open frequency list: %w", err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+var (
+	mu     sync.Mutex
+	loaded = map[string]*List{}
+)
+
+// Lookup returns the list of a language, loading it once per process. It returns nil if
+// no list is installed or it cannot be read.
+func Lookup(lang string) *List {
+	mu.Lock()
+	defer mu.Unlock()
+	if l, ok := loaded[lang]; ok {
+		return l
+	}
+	l, err := Load(lang)
+	if err != nil {
+		l = nil
+	}
+	loaded[lang] = l
+	return l
+}
+
+// Download fetches the list of a language from urlTemplate (DefaultURL if empty) and
+// installs it. It returns the number of words in the list.
+func Download(ctx context.Context, client *http.Client, urlTemplate, lang string) (int, error) {
+	if urlTemplate == "" {
+		urlTemplate = DefaultURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(urlTemplate, "{lang}", lang), nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid frequency list URL: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download frequency list: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to download frequency list for %s: %s", lang, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxListSize))
+	if err != nil {
+		return 0, fmt.Errorf("failed to download frequency list: %w", err)
+	}
+	l, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	if l.Len() == 0 {
+		return 0, fmt.Errorf("frequency list for %s is empty", lang)
+	}
+
+	path, err := Path(lang)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return 0, fmt.Errorf("failed to save frequency list: %w", err)
+	}
+	mu.Lock()
+	loaded[lang] = l
+	mu.Unlock()
+	return l.Len(), nil
+}
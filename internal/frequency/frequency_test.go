@@ -0,0 +1,61 @@
+package frequency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	l, err := Parse(strings.NewReader("ich 1000\n\nbin 900\nIch 800\nglücklich\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]int{"ich": 1, "Ich": 1, "bin": 2, "glücklich": 4, "Bahnhof": 0}
+	for word, want := range tests {
+		if got := l.Rank(word); got != want {
+			t.Errorf("Rank(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+func TestBand(t *testing.T) {
+	tests := map[int]string{0: "", 1: "top 1k", 1000: "top 1k", 1001: "top 5k", 9999: "top 10k", 20000: "rare"}
+	for rank, want := range tests {
+		if got := Band(rank); got != want {
+			t.Errorf("Band(%d) = %q, want %q", rank, got, want)
+		}
+	}
+}
+
+func TestDownload(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/de/de_50k.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("ich 10\nbin 5\n"))
+	}))
+	defer srv.Close()
+
+	n, err := Download(context.Background(), srv.Client(), srv.URL+"/{lang}/{lang}_50k.txt", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("installed %d words, want 2", n)
+	}
+	l, err := Load("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Rank("bin") != 2 {
+		t.Errorf("Rank(bin) = %d, want 2", l.Rank("bin"))
+	}
+	if _, err := Download(context.Background(), srv.Client(), srv.URL+"/{lang}/{lang}_50k.txt", "xx"); err == nil {
+		t.Error("expected an error for a missing list")
+	}
+}
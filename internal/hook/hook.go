@@ -0,0 +1,81 @@
+// Package hook passes completed translations to the user's own tools, by running a
+// command with the result on stdin or posting it to a webhook.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// Largest part of a failed webhook response included in the error, in bytes
+const maxErrorBody = 512
+
+// Run passes the entry as JSON to the configured command and webhook. Both are run even
+// if one fails; their errors are joined.
+func Run(ctx context.Context, client *http.Client, cfg config.HooksConfig, entry storage.HistoryEntry) error {
+	if len(cfg.Command) == 0 && cfg.Webhook == "" {
+		return nil
+	}
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook payload: %w", err)
+	}
+	var errs []error
+	if len(cfg.Command) > 0 {
+		errs = append(errs, runCommand(ctx, cfg.Command, payload))
+	}
+	if cfg.Webhook != "" {
+		errs = append(errs, post(ctx, client, cfg, payload))
+	}
+	return errors.Join(errs...)
+}
+
+// runCommand runs the command with the payload on stdin.
+func runCommand(ctx context.Context, command []string, payload []byte) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("hook command failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("hook command failed: %w", err)
+	}
+	return nil
+}
+
+// post sends the payload to the webhook.
+func post(ctx context.Context, client *http.Client, cfg config.HooksConfig, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.Webhook, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range cfg.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		if msg := strings.TrimSpace(string(body)); msg != "" {
+			return fmt.Errorf("webhook failed: %s: %s", resp.Status, msg)
+		}
+		return fmt.Errorf("webhook failed: %s", resp.Status)
+	}
+	return nil
+}
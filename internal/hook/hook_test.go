@@ -0,0 +1,57 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+func TestRun(t *testing.T) {
+	entry := storage.HistoryEntry{UserLang: "en", TargetLang: "de", Original: "Hello", Translation: "Hallo"}
+
+	var got storage.HistoryEntry
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("request = %s %v", r.Method, r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	out := filepath.Join(t.TempDir(), "out.json")
+	cfg := config.HooksConfig{
+		Command: []string{"sh", "-c", "cat > " + out},
+		Webhook: srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}
+	if err := Run(context.Background(), srv.Client(), cfg, entry); err != nil {
+		t.Fatal(err)
+	}
+	if got.Translation != "Hallo" {
+		t.Errorf("webhook got %+v", got)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"translation":"Hallo"`) {
+		t.Errorf("command got %s", data)
+	}
+
+	failing := config.HooksConfig{Command: []string{"sh", "-c", "echo nope >&2; exit 1"}, Webhook: srv.URL + "/missing"}
+	srv.Config.Handler = http.NotFoundHandler()
+	err = Run(context.Background(), srv.Client(), failing, entry)
+	if err == nil || !strings.Contains(err.Error(), "nope") || !strings.Contains(err.Error(), "404") {
+		t.Errorf("failing hooks: got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+// Package jsonrpc serves JSON-RPC 2.0 over a stream of newline-delimited messages, the
+// framing used by the MCP stdio transport and the editor protocol.
+package jsonrpc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Version is the protocol version sent in every message.
+const Version = "2.0"
+
+// Largest message that is read, in bytes
+const maxMessageSize = 16 << 20
+
+// Error codes defined by the specification
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Error is a JSON-RPC error. Handlers return it to choose the code; other errors are
+// reported as internal errors.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Handler handles the calls of a method. Its result is encoded as JSON.
+type Handler func(ctx context.Context, params json.RawMessage) (any, error)
+
+// request is an incoming call or, without an ID, a notification.
+type request struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response answers a call.
+type response struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Server dispatches calls to the handlers of their methods.
+type Server struct {
+	methods map[string]Handler
+}
+
+// NewServer creates a server without methods.
+func NewServer() *Server {
+	return &Server{methods: map[string]Handler{}}
+}
+
+// Handle registers the handler of a method.
+func (s *Server) Handle(method string, h Handler) {
+	s.methods[method] = h
+}
+
+// Serve reads messages from r and writes the responses to w until r ends or ctx is
+// cancelled. Calls are handled concurrently, so responses may arrive out of order.
+// Batches are not supported.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu sync.Mutex // Serializes writes
+		wg sync.WaitGroup
+	)
+	enc := json.NewEncoder(w)
+	send := func(resp response) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := enc.Encode(resp); err != nil {
+			slog.Error("failed to write JSON-RPC response", "error", err)
+		}
+	}
+	defer wg.Wait()
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			send(response{Version: Version, ID: json.RawMessage("null"), Error: &Error{Code: CodeParseError, Message: "parse error: " + err.Error()}})
+			continue
+		}
+		if req.Version != Version || req.Method == "" {
+			if req.ID != nil {
+				send(response{Version: Version, ID: req.ID, Error: &Error{Code: CodeInvalidRequest, Message: "invalid request"}})
+			}
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := s.call(ctx, req)
+			if req.ID == nil {
+				return // Notifications are not answered
+			}
+			resp := response{Version: Version, ID: req.ID, Result: result}
+			if err != nil {
+				resp.Result = nil
+				resp.Error = toError(err)
+			} else if result == nil {
+				resp.Result = struct{}{}
+			}
+			send(resp)
+		}()
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("failed to read JSON-RPC message: %w", err)
+	}
+	return nil
+}
+
+// call runs the handler of the request's method.
+func (s *Server) call(ctx context.Context, req request) (any, error) {
+	h, ok := s.methods[req.Method]
+	if !ok {
+		return nil, &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	slog.Debug("JSON-RPC call", "method", req.Method)
+	result, err := h(ctx, req.Params)
+	if err != nil {
+		slog.Warn("JSON-RPC call failed", "method", req.Method, "error", err)
+	}
+	return result, err
+}
+
+// toError converts a handler error to a JSON-RPC error.
+func toError(err error) *Error {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	return &Error{Code: CodeInternalError, Message: err.Error()}
+}
+
+// Params decodes the parameters of a call, failing with CodeInvalidParams.
+func Params[T any](raw json.RawMessage) (T, error) {
+	var params T
+	if len(raw) == 0 {
+		return params, nil
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return params, &Error{Code: CodeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	return params, nil
+}
+
+// InvalidParams returns an error with CodeInvalidParams.
+func InvalidParams(format string, args ...any) error {
+	return &Error{Code: CodeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,63 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	s := NewServer()
+	s.Handle("add", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		params, err := Params[struct{ A, B int }](raw)
+		if err != nil {
+			return nil, err
+		}
+		return params.A + params.B, nil
+	})
+	s.Handle("fail", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		return nil, errors.New("boom")
+	})
+
+	in := strings.Join([]string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "add", "params": {"a": 1, "b": 2}}`,
+		`{"jsonrpc": "2.0", "method": "add", "params": {"a": 1, "b": 2}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "missing"}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "fail"}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "add", "params": "x"}`,
+		`not json`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	type resp struct {
+		ID     json.RawMessage `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *Error          `json:"error"`
+	}
+	got := map[string]resp{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var r resp
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatal(err)
+		}
+		got[string(r.ID)] = r
+	}
+	if len(got) != 5 {
+		t.Fatalf("got %d responses, want 5 (the notification is not answered):\n%s", len(got), out.String())
+	}
+	if r := got["1"]; string(r.Result) != "3" || r.Error != nil {
+		t.Errorf("add = %+v", r)
+	}
+	codes := map[string]int{"2": CodeMethodNotFound, "3": CodeInternalError, "4": CodeInvalidParams, "null": CodeParseError}
+	for id, code := range codes {
+		if r := got[id]; r.Error == nil || r.Error.Code != code {
+			t.Errorf("response %s = %+v, want error code %d", id, r, code)
+		}
+	}
+}
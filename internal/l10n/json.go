@@ -0,0 +1,212 @@
+package l10n
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonValue is a JSON value that keeps the order of object keys.
+type jsonValue struct {
+	members                     []jsonMember // Object members, if isObject
+	items                       []*jsonValue // Array items, if isArray
+	str                         string       // String value, if isString
+	raw                         string       // Numbers, booleans and null, verbatim
+	isObject, isArray, isString bool
+}
+
+// jsonMember is a key of a JSON object with its value.
+type jsonMember struct {
+	key   string
+	value *jsonValue
+}
+
+// jsonResource is a JSON localization file such as i18next or go-i18n messages.
+type jsonResource struct {
+	root   *jsonValue
+	indent string
+}
+
+// ParseJSONResource parses a JSON resource file, detecting its indentation.
+func ParseJSONResource(data []byte) (Resource, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after the top-level value")
+	}
+	return &jsonResource{root: root, indent: detectIndent(data)}, nil
+}
+
+// decodeJSONValue reads the next value from dec.
+func decodeJSONValue(dec *json.Decoder) (*jsonValue, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			v := &jsonValue{isObject: true}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				member, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				v.members = append(v.members, jsonMember{key, member})
+			}
+			_, err := dec.Token() // Closing brace
+			return v, err
+		case '[':
+			v := &jsonValue{isArray: true}
+			for dec.More() {
+				item, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				v.items = append(v.items, item)
+			}
+			_, err := dec.Token() // Closing bracket
+			return v, err
+		}
+		return nil, fmt.Errorf("unexpected %v", t)
+	case string:
+		return &jsonValue{isString: true, str: t}, nil
+	case json.Number:
+		return &jsonValue{raw: t.String()}, nil
+	case bool:
+		return &jsonValue{raw: strconv.FormatBool(t)}, nil
+	case nil:
+		return &jsonValue{raw: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
+
+// detectIndent returns the indentation of the first indented line, or two spaces.
+func detectIndent(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n")[1:] {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
+
+func (r *jsonResource) Entries() []*Entry {
+	var entries []*Entry
+	var walk func(path string, v, parent *jsonValue)
+	walk = func(path string, v, parent *jsonValue) {
+		switch {
+		case v.isObject:
+			for _, m := range v.members {
+				walk(joinKey(path, m.key), m.value, v)
+			}
+		case v.isArray:
+			for i, item := range v.items {
+				walk(joinKey(path, strconv.Itoa(i)), item, v)
+			}
+		case v.isString:
+			e := &Entry{Key: path, Value: v.str, set: func(s string) { v.str = s }}
+			if parent != nil && parent.isObject {
+				e.remove = func() {
+					parent.members = slices.DeleteFunc(parent.members, func(m jsonMember) bool { return m.value == v })
+				}
+			}
+			entries = append(entries, e)
+		}
+	}
+	walk("", r.root, nil)
+	return entries
+}
+
+func (r *jsonResource) RenameRoot(from, to string) bool {
+	if !r.root.isObject || len(r.root.members) != 1 || r.root.members[0].key != from {
+		return false
+	}
+	r.root.members[0].key = to
+	return true
+}
+
+func (r *jsonResource) Encode() ([]byte, error) {
+	var b strings.Builder
+	if err := encodeJSONValue(&b, r.root, r.indent, ""); err != nil {
+		return nil, err
+	}
+	b.WriteString("\n")
+	return []byte(b.String()), nil
+}
+
+// encodeJSONValue writes v with the given indentation, prefix being the current one.
+func encodeJSONValue(b *strings.Builder, v *jsonValue, indent, prefix string) error {
+	switch {
+	case v.isObject:
+		if len(v.members) == 0 {
+			b.WriteString("{}")
+			return nil
+		}
+		b.WriteString("{\n")
+		for i, m := range v.members {
+			b.WriteString(prefix + indent)
+			if err := encodeJSONString(b, m.key); err != nil {
+				return err
+			}
+			b.WriteString(": ")
+			if err := encodeJSONValue(b, m.value, indent, prefix+indent); err != nil {
+				return err
+			}
+			if i < len(v.members)-1 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(prefix + "}")
+	case v.isArray:
+		if len(v.items) == 0 {
+			b.WriteString("[]")
+			return nil
+		}
+		b.WriteString("[\n")
+		for i, item := range v.items {
+			b.WriteString(prefix + indent)
+			if err := encodeJSONValue(b, item, indent, prefix+indent); err != nil {
+				return err
+			}
+			if i < len(v.items)-1 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(prefix + "]")
+	case v.isString:
+		return encodeJSONString(b, v.str)
+	default:
+		b.WriteString(v.raw)
+	}
+	return nil
+}
+
+// encodeJSONString writes s as a JSON string without escaping HTML characters.
+func encodeJSONString(b *strings.Builder, s string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	b.WriteString(strings.TrimSuffix(buf.String(), "\n"))
+	return nil
+}
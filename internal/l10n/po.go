@@ -0,0 +1,344 @@
+// Package l10n reads and writes localization files: gettext PO catalogs and JSON or YAML resource files.
+package l10n
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// poLineKind classifies a line of a PO entry.
+type poLineKind int
+
+const (
+	poComment poLineKind = iota
+	poFlags
+	poKeyword // msgctxt, msgid or msgid_plural and their continuation lines
+	poMsgstr  // msgstr or msgstr[n] and their continuation lines
+)
+
+// poLine is one line of a PO file, kept verbatim.
+type poLine struct {
+	kind poLineKind
+	text string
+}
+
+// POEntry is a message of a PO catalog.
+type POEntry struct {
+	Context  string
+	ID       string
+	IDPlural string
+	Str      []string // msgstr, or msgstr[0..n] for plural messages
+	Flags    []string
+	Obsolete bool
+
+	lines    []poLine
+	modified bool
+}
+
+// Untranslated reports whether the entry is a message without any translation.
+func (e *POEntry) Untranslated() bool {
+	if e.ID == "" || e.Obsolete {
+		return false
+	}
+	return !slices.ContainsFunc(e.Str, func(s string) bool { return s != "" })
+}
+
+// SetTranslation sets the translation of the entry and marks it fuzzy for review.
+// Plural messages get nplurals forms: singular, the translation of msgid, in msgstr[0]
+// and plural, that of msgid_plural, in msgstr[1]. Further forms, such as the Polish
+// form for 5 to 21, are for numbers neither translation is meant for, so they are left
+// empty for the reviewer. Languages with a single form, such as Japanese, use it for
+// every number, so it gets plural.
+func (e *POEntry) SetTranslation(singular, plural string, nplurals int) {
+	switch {
+	case e.IDPlural == "":
+		e.Str = []string{singular}
+	case nplurals <= 1:
+		e.Str = []string{plural}
+	default:
+		e.Str = make([]string, nplurals)
+		e.Str[0], e.Str[1] = singular, plural
+	}
+	if !slices.Contains(e.Flags, "fuzzy") {
+		e.Flags = append([]string{"fuzzy"}, e.Flags...)
+	}
+	e.modified = true
+}
+
+// POFile is a parsed PO or POT catalog. Entries that are not modified are written
+// back byte for byte.
+type POFile struct {
+	Entries []*POEntry
+}
+
+// Header returns the value of a header field such as "Language", if present.
+func (f *POFile) Header(field string) string {
+	for _, e := range f.Entries {
+		if e.ID != "" || e.Context != "" || len(e.Str) == 0 {
+			continue
+		}
+		for _, line := range strings.Split(e.Str[0], "\n") {
+			if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(name), field) {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return ""
+}
+
+// SetHeader sets a header field, adding it if missing.
+func (f *POFile) SetHeader(field, value string) {
+	for _, e := range f.Entries {
+		if e.ID != "" || e.Context != "" || len(e.Str) == 0 {
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(e.Str[0], "\n"), "\n")
+		i := slices.IndexFunc(lines, func(l string) bool {
+			name, _, ok := strings.Cut(l, ":")
+			return ok && strings.EqualFold(strings.TrimSpace(name), field)
+		})
+		if i >= 0 {
+			lines[i] = field + ": " + value
+		} else {
+			lines = append(lines, field+": "+value)
+		}
+		e.Str[0] = strings.Join(lines, "\n") + "\n"
+		e.modified = true
+		return
+	}
+}
+
+var npluralsRE = regexp.MustCompile(`nplurals\s*=\s*(\d+)`)
+
+// NPlurals returns the number of plural forms declared in the Plural-Forms header, or 2.
+func (f *POFile) NPlurals() int {
+	if m := npluralsRE.FindStringSubmatch(f.Header("Plural-Forms")); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 2
+}
+
+// ParsePO reads a PO or POT catalog.
+func ParsePO(r io.Reader) (*POFile, error) {
+	f := &POFile{}
+	var cur *POEntry
+	var field *string // Keyword being continued by "..." lines
+	inMsgstr := false
+	flush := func() {
+		if cur != nil {
+			f.Entries = append(f.Entries, cur)
+		}
+		cur, field, inMsgstr = nil, nil, false
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		raw := sc.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			flush()
+			continue
+		}
+
+		obsolete := false
+		if strings.HasPrefix(line, "#~") {
+			obsolete = true
+			line = strings.TrimSpace(strings.TrimPrefix(line, "#~"))
+		}
+		if strings.HasPrefix(line, "#") && !obsolete {
+			// A comment after msgstr starts the next entry
+			if inMsgstr {
+				flush()
+			}
+			if cur == nil {
+				cur = &POEntry{}
+			}
+			kind := poComment
+			if strings.HasPrefix(line, "#,") {
+				kind = poFlags
+				for _, flag := range strings.Split(strings.TrimPrefix(line, "#,"), ",") {
+					if flag = strings.TrimSpace(flag); flag != "" {
+						cur.Flags = append(cur.Flags, flag)
+					}
+				}
+			}
+			cur.lines = append(cur.lines, poLine{kind, raw})
+			continue
+		}
+
+		keyword, rest, _ := strings.Cut(line, " ")
+		if strings.HasPrefix(line, `"`) {
+			keyword, rest = "", line
+		}
+		if keyword != "" && !strings.HasPrefix(keyword, "msgstr") && inMsgstr {
+			flush()
+		}
+		if cur == nil {
+			cur = &POEntry{}
+		}
+		cur.Obsolete = cur.Obsolete || obsolete
+
+		kind := poKeyword
+		switch {
+		case keyword == "":
+			if field == nil {
+				return nil, fmt.Errorf("line %d: string without keyword", lineNo)
+			}
+			if inMsgstr {
+				kind = poMsgstr
+			}
+		case keyword == "msgctxt":
+			field = &cur.Context
+		case keyword == "msgid":
+			field = &cur.ID
+		case keyword == "msgid_plural":
+			field = &cur.IDPlural
+		case keyword == "msgstr":
+			cur.Str = append(cur.Str, "")
+			field = &cur.Str[len(cur.Str)-1]
+			kind, inMsgstr = poMsgstr, true
+		case strings.HasPrefix(keyword, "msgstr["):
+			cur.Str = append(cur.Str, "")
+			field = &cur.Str[len(cur.Str)-1]
+			kind, inMsgstr = poMsgstr, true
+		default:
+			return nil, fmt.Errorf("line %d: unknown keyword %q", lineNo, keyword)
+		}
+		value, err := unquotePO(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		*field += value
+		cur.lines = append(cur.lines, poLine{kind, raw})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read PO file: %w", err)
+	}
+	flush()
+	return f, nil
+}
+
+// WritePO writes the catalog, separating entries by blank lines.
+func (f *POFile) WritePO(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for i, e := range f.Entries {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		for _, line := range e.render() {
+			bw.WriteString(line)
+			bw.WriteString("\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// render returns the lines of the entry: verbatim if unmodified, otherwise with
+// the flags and msgstr lines regenerated.
+func (e *POEntry) render() []string {
+	var out []string
+	if !e.modified {
+		for _, l := range e.lines {
+			out = append(out, l.text)
+		}
+		return out
+	}
+
+	flagsWritten := false
+	writeFlags := func() {
+		if !flagsWritten && len(e.Flags) > 0 {
+			out = append(out, "#, "+strings.Join(e.Flags, ", "))
+		}
+		flagsWritten = true
+	}
+	for _, l := range e.lines {
+		switch l.kind {
+		case poComment:
+			out = append(out, l.text)
+		case poFlags:
+			writeFlags()
+		case poKeyword:
+			writeFlags()
+			out = append(out, l.text)
+		}
+	}
+	writeFlags()
+	if e.IDPlural == "" {
+		q := quotePO(e.Str[0])
+		out = append(out, "msgstr "+q[0])
+		out = append(out, q[1:]...)
+	} else {
+		for i, s := range e.Str {
+			q := quotePO(s)
+			out = append(out, fmt.Sprintf("msgstr[%d] ", i)+q[0])
+			out = append(out, q[1:]...)
+		}
+	}
+	if e.Obsolete {
+		for i := range out {
+			if !strings.HasPrefix(out[i], "#") {
+				out[i] = "#~ " + out[i]
+			}
+		}
+	}
+	return out
+}
+
+// quotePO quotes a string for a PO file, splitting it after newlines.
+// The first returned line is meant to follow the keyword.
+func quotePO(s string) []string {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") {
+		return []string{escapePO(s)}
+	}
+	lines := []string{`""`}
+	for _, part := range strings.SplitAfter(s, "\n") {
+		if part != "" {
+			lines = append(lines, escapePO(part))
+		}
+	}
+	return lines
+}
+
+// escapePO returns s as a quoted PO string.
+func escapePO(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// unquotePO decodes a quoted PO string.
+func unquotePO(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	s = s[1 : len(s)-1]
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i == len(s)-1 {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,101 @@
+package l10n
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const samplePO = `# German translation.
+msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+#: main.go:10
+msgid "Hello, %s!"
+msgstr "Hallo, %s!"
+
+#: main.go:12
+#, c-format
+msgid "Delete file"
+msgstr ""
+
+msgctxt "menu"
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+#~ msgid "Old"
+#~ msgstr ""
+`
+
+func TestPORoundTrip(t *testing.T) {
+	f, err := ParsePO(strings.NewReader(samplePO))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.WritePO(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != samplePO {
+		t.Errorf("unmodified catalog changed:\n%s", buf.String())
+	}
+}
+
+func TestPOSetTranslation(t *testing.T) {
+	f, err := ParsePO(strings.NewReader(samplePO))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Header("Language"); got != "de" {
+		t.Errorf("Language = %q, want de", got)
+	}
+
+	var untranslated []*POEntry
+	for _, e := range f.Entries {
+		if e.Untranslated() {
+			untranslated = append(untranslated, e)
+		}
+	}
+	if len(untranslated) != 2 {
+		t.Fatalf("found %d untranslated entries, want 2", len(untranslated))
+	}
+	untranslated[0].SetTranslation("Datei löschen", "", f.NPlurals())
+	untranslated[1].SetTranslation("Eine Datei", "%d Dateien", f.NPlurals())
+
+	var buf bytes.Buffer
+	if err := f.WritePO(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"#: main.go:12\n#, fuzzy, c-format\nmsgid \"Delete file\"\nmsgstr \"Datei löschen\"\n",
+		"#, fuzzy\nmsgctxt \"menu\"\nmsgid \"One file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"Eine Datei\"\nmsgstr[1] \"%d Dateien\"\n",
+		"#~ msgid \"Old\"\n#~ msgstr \"\"\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestPOSetTranslationPluralForms(t *testing.T) {
+	tests := []struct {
+		nplurals int
+		want     []string
+	}{
+		{1, []string{"%d ファイル"}},
+		{2, []string{"ファイル", "%d ファイル"}},
+		{3, []string{"ファイル", "%d ファイル", ""}},
+	}
+	for _, tt := range tests {
+		e := &POEntry{ID: "One file", IDPlural: "%d files"}
+		e.SetTranslation("ファイル", "%d ファイル", tt.nplurals)
+		if !slices.Equal(e.Str, tt.want) {
+			t.Errorf("nplurals=%d: msgstr = %q, want %q", tt.nplurals, e.Str, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package l10n
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Resource is a key-value localization file whose string values can be replaced
+// while keeping key order, formatting choices and comments.
+type Resource interface {
+	// Entries returns the string values in file order.
+	Entries() []*Entry
+	// RenameRoot renames a single top-level key such as the locale of a Rails file.
+	RenameRoot(from, to string) bool
+	// Encode returns the file content.
+	Encode() ([]byte, error)
+}
+
+// Entry is a string value of a resource file, identified by its dotted key path.
+type Entry struct {
+	Key    string
+	Value  string
+	set    func(string)
+	remove func()
+}
+
+// Set replaces the value in the resource.
+func (e *Entry) Set(value string) {
+	e.Value = value
+	e.set(value)
+}
+
+// Remove deletes the key from the resource. Items of lists are emptied instead, so that
+// the items after them keep their keys.
+func (e *Entry) Remove() {
+	if e.remove == nil {
+		e.Set("")
+		return
+	}
+	e.Value = ""
+	e.remove()
+}
+
+// LoadResource reads a JSON or YAML resource file, chosen by its extension.
+func LoadResource(path string) (Resource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var r Resource
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		r, err = ParseJSONResource(data)
+	case ".yaml", ".yml":
+		r, err = ParseYAMLResource(data)
+	default:
+		return nil, fmt.Errorf("unsupported resource file %s (use .json, .yaml or .yml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// joinKey appends a key to a dotted path.
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,95 @@
+package l10n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONResource(t *testing.T) {
+	src := `{
+    "title": "Welcome",
+    "nav": {
+        "home": "Home <b>page</b>",
+        "count": 3
+    },
+    "list": [
+        "one",
+        null
+    ]
+}
+`
+	r, err := ParseJSONResource([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := r.Entries()
+	var keys []string
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	if got, want := strings.Join(keys, " "), "title nav.home list.0"; got != want {
+		t.Fatalf("keys = %q, want %q", got, want)
+	}
+	entries[0].Set("Willkommen")
+	out, err := r.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Replace(src, "Welcome", "Willkommen", 1); string(out) != want {
+		t.Errorf("encoded:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestYAMLResource(t *testing.T) {
+	src := `# Rails locale
+en:
+  greeting: "Hello %{name}"
+  # Shown in the footer
+  footer: Bye
+  count: 2
+`
+	r, err := ParseYAMLResource([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.RenameRoot("en", "de") {
+		t.Fatal("expected the locale key to be renamed")
+	}
+	entries := r.Entries()
+	if len(entries) != 2 || entries[0].Key != "de.greeting" || entries[1].Key != "de.footer" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	entries[0].Set("Hallo %{name}")
+	entries[1].Set("Tschüss")
+	out, err := r.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Rails locale
+de:
+  greeting: "Hallo %{name}"
+  # Shown in the footer
+  footer: Tschüss
+  count: 2
+`
+	if string(out) != want {
+		t.Errorf("encoded:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestRemoveEntry(t *testing.T) {
+	r, err := ParseYAMLResource([]byte("de:\n  greeting: Hello\n  footer: Bye\n  list:\n    - one\n    - two\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := r.Entries()
+	entries[0].Remove()
+	entries[2].Remove() // Items of lists keep their place
+	out, err := r.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "de:\n  footer: Bye\n  list:\n    - \"\"\n    - two\n"; string(out) != want {
+		t.Errorf("encoded:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,89 @@
+package l10n
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlResource is a YAML localization file such as Rails locale files.
+// Comments, key order and quoting styles are kept by editing the node tree.
+type yamlResource struct {
+	doc yaml.Node
+}
+
+// ParseYAMLResource parses a YAML resource file.
+func ParseYAMLResource(data []byte) (Resource, error) {
+	r := &yamlResource{}
+	if err := yaml.Unmarshal(data, &r.doc); err != nil {
+		return nil, err
+	}
+	if len(r.doc.Content) == 0 {
+		return nil, errors.New("empty document")
+	}
+	return r, nil
+}
+
+func (r *yamlResource) Entries() []*Entry {
+	var entries []*Entry
+	var walk func(path string, n, parent *yaml.Node)
+	walk = func(path string, n, parent *yaml.Node) {
+		switch n.Kind {
+		case yaml.DocumentNode:
+			for _, c := range n.Content {
+				walk(path, c, n)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				walk(joinKey(path, n.Content[i].Value), n.Content[i+1], n)
+			}
+		case yaml.SequenceNode:
+			for i, c := range n.Content {
+				walk(joinKey(path, strconv.Itoa(i)), c, n)
+			}
+		case yaml.ScalarNode:
+			if n.ShortTag() != "!!str" {
+				break
+			}
+			e := &Entry{Key: path, Value: n.Value, set: func(s string) { n.Value = s }}
+			if parent.Kind == yaml.MappingNode {
+				e.remove = func() {
+					for i := 1; i < len(parent.Content); i += 2 {
+						if parent.Content[i] == n {
+							parent.Content = slices.Delete(parent.Content, i-1, i+1)
+							return
+						}
+					}
+				}
+			}
+			entries = append(entries, e)
+		}
+	}
+	walk("", &r.doc, nil)
+	return entries
+}
+
+func (r *yamlResource) RenameRoot(from, to string) bool {
+	root := r.doc.Content[0]
+	if root.Kind != yaml.MappingNode || len(root.Content) != 2 || root.Content[0].Value != from {
+		return false
+	}
+	root.Content[0].Value = to
+	return true
+}
+
+func (r *yamlResource) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&r.doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,200 @@
+// Package mcp exposes the translation pipeline as a Model Context Protocol server, so that
+// editors and agent frameworks can call it as tools. Calls use the same configuration,
+// cache, do-not-translate list and history as the TUI.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/jsonrpc"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// protocolVersions lists the supported MCP versions, latest first.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// Tool describes a tool to clients.
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+// content is a block of a tool result.
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// toolResult is the result of a tools/call request. Failures of the tool are reported in
+// the result rather than as protocol errors, so that the calling model sees them.
+type toolResult struct {
+	Content           []content `json:"content"`
+	StructuredContent any       `json:"structuredContent,omitempty"`
+	IsError           bool      `json:"isError,omitempty"`
+}
+
+// sentenceArgs are the arguments of both tools.
+type sentenceArgs struct {
+	Text       string `json:"text"`
+	UserLang   string `json:"user_lang"`
+	TargetLang string `json:"target_lang"`
+}
+
+// sentenceSchema is the input schema of both tools.
+var sentenceSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"text":        map[string]any{"type": "string", "description": "Sentence to translate, in either language"},
+		"user_lang":   map[string]any{"type": "string", "description": "Code of the language the user knows, e.g. \"en\""},
+		"target_lang": map[string]any{"type": "string", "description": "Code of the language being learned, e.g. \"de\""},
+	},
+	"required": []string{"text", "user_lang", "target_lang"},
+}
+
+// Tools lists the tools of the server.
+var Tools = []Tool{
+	{
+		Name:        "translate",
+		Description: "Translate a sentence between the language the user knows and the one they learn. The input language is detected.",
+		InputSchema: sentenceSchema,
+	},
+	{
+		Name:        "analyze_words",
+		Description: "Analyze the words of a sentence in the language being learned: grammar, CEFR level, gender and idioms of each word.",
+		InputSchema: sentenceSchema,
+	},
+}
+
+// runFunc runs the pipeline; tests replace it.
+type runFunc func(ctx context.Context, cfg config.Config, req translator.Request) (translate.Result, error)
+
+// NewServer creates the JSON-RPC server of the MCP protocol with the tools.
+func NewServer(cfg config.Config) *jsonrpc.Server {
+	return newServer(cfg, func(ctx context.Context, cfg config.Config, req translator.Request) (translate.Result, error) {
+		return translate.Run(ctx, cfg, req, nil)
+	})
+}
+
+func newServer(cfg config.Config, run runFunc) *jsonrpc.Server {
+	s := jsonrpc.NewServer()
+	s.Handle("initialize", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		params, err := jsonrpc.Params[struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}](raw)
+		if err != nil {
+			return nil, err
+		}
+		protocol := protocolVersions[0]
+		if slices.Contains(protocolVersions, params.ProtocolVersion) {
+			protocol = params.ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": protocol,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": storage.AppName, "version": version()},
+		}, nil
+	})
+	s.Handle("ping", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		return nil, nil
+	})
+	s.Handle("notifications/initialized", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		return nil, nil
+	})
+	s.Handle("tools/list", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		return map[string]any{"tools": Tools}, nil
+	})
+	s.Handle("tools/call", func(ctx context.Context, raw json.RawMessage) (any, error) {
+		params, err := jsonrpc.Params[struct {
+			Name      string       `json:"name"`
+			Arguments sentenceArgs `json:"arguments"`
+		}](raw)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(Tools, func(t Tool) bool { return t.Name == params.Name }) {
+			return nil, jsonrpc.InvalidParams("unknown tool %q", params.Name)
+		}
+		return callTool(ctx, cfg, run, params.Name, params.Arguments), nil
+	})
+	return s
+}
+
+// Serve answers MCP requests from r on w until r ends.
+func Serve(ctx context.Context, cfg config.Config, r io.Reader, w io.Writer) error {
+	return NewServer(cfg).Serve(ctx, r, w)
+}
+
+// version returns the module version of the build, "devel" for local builds.
+func version() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "devel"
+}
+
+// callTool runs the pipeline for a tool call and records it in the history.
+func callTool(ctx context.Context, cfg config.Config, run runFunc, name string, args sentenceArgs) toolResult {
+	args.Text = strings.TrimSpace(args.Text)
+	if args.Text == "" || args.UserLang == "" || args.TargetLang == "" {
+		return errorResult(errors.New("text, user_lang and target_lang are required"))
+	}
+	result, err := run(ctx, cfg, translator.Request{Sentence: args.Text, UserLang: args.UserLang, TargetLang: args.TargetLang})
+	if err != nil {
+		return errorResult(err)
+	}
+	_ = translate.Record(ctx, cfg, storage.HistoryEntry{ // The history and hooks are best-effort here
+		Time:        time.Now(),
+		UserLang:    args.UserLang,
+		TargetLang:  args.TargetLang,
+		Original:    result.Original,
+		Translation: result.Translation,
+		Words:       result.Words,
+	})
+
+	var text strings.Builder
+	var structured any
+	switch name {
+	case "translate":
+		text.WriteString(result.Translation)
+		structured = map[string]any{
+			"original":    result.Original,
+			"translation": result.Translation,
+			"level":       result.Level,
+			"degraded":    result.Degraded,
+		}
+	case "analyze_words":
+		for _, w := range result.Words {
+			text.WriteString(w.WordInTargetLang)
+			if w.Level != "" {
+				fmt.Fprintf(&text, " [%s]", w.Level)
+			}
+			fmt.Fprintf(&text, " - %s\n", w.GrammaticalExplanation)
+		}
+		structured = map[string]any{
+			"translation": result.Translation,
+			"level":       result.Level,
+			"words":       result.Words,
+		}
+	}
+	if result.Degraded != "" {
+		text.WriteString("\n(degraded output: " + result.Degraded + ")")
+	}
+	return toolResult{Content: []content{{Type: "text", Text: strings.TrimSpace(text.String())}}, StructuredContent: structured}
+}
+
+// errorResult reports a failed tool call to the calling model.
+func errorResult(err error) toolResult {
+	return toolResult{Content: []content{{Type: "text", Text: err.Error()}}, IsError: true}
+}
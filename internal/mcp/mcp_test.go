@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// fakeRun translates "Ich bin müde" and fails for anything else.
+func fakeRun(ctx context.Context, cfg config.Config, req translator.Request) (translate.Result, error) {
+	if req.Sentence != "Ich bin müde" {
+		return translate.Result{}, errors.New("quota exceeded")
+	}
+	return translate.Result{Result: translator.Result{
+		Original:    "Ich bin müde",
+		Translation: "I am tired",
+		Level:       "A1",
+		Words: []translator.WordInfo{
+			{WordInTargetLang: "müde", GrammaticalExplanation: "adjective, tired", Level: "A1"},
+		},
+	}}, nil
+}
+
+// call sends the requests to a server using fakeRun and returns the responses by ID.
+func call(t *testing.T, requests ...string) map[string]json.RawMessage {
+	t.Helper()
+	var out bytes.Buffer
+	if err := newServer(config.Default(), fakeRun).Serve(context.Background(), strings.NewReader(strings.Join(requests, "\n")), &out); err != nil {
+		t.Fatal(err)
+	}
+	responses := map[string]json.RawMessage{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var resp struct {
+			ID     json.RawMessage `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Error != nil {
+			responses[string(resp.ID)] = resp.Error
+		} else {
+			responses[string(resp.ID)] = resp.Result
+		}
+	}
+	return responses
+}
+
+func TestInitializeAndList(t *testing.T) {
+	got := call(t,
+		`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "capabilities": {}}}`,
+		`{"jsonrpc": "2.0", "method": "notifications/initialized"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`,
+	)
+	if !strings.Contains(string(got["1"]), `"protocolVersion":"2025-03-26"`) {
+		t.Errorf("initialize = %s", got["1"])
+	}
+	var list struct {
+		Tools []Tool `json:"tools"`
+	}
+	if err := json.Unmarshal(got["2"], &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Tools) != 2 || list.Tools[0].Name != "translate" || list.Tools[1].Name != "analyze_words" {
+		t.Errorf("tools = %+v", list.Tools)
+	}
+}
+
+func TestToolsCall(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	got := call(t,
+		`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "translate", "arguments": {"text": "Ich bin müde", "user_lang": "en", "target_lang": "de"}}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "analyze_words", "arguments": {"text": "Ich bin müde", "user_lang": "en", "target_lang": "de"}}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "translate", "arguments": {"text": "Hallo", "user_lang": "en", "target_lang": "de"}}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "delete_everything", "arguments": {}}}`,
+	)
+	for id, want := range map[string]string{
+		"1": `"text":"I am tired"`,
+		"2": `"text":"müde [A1] - adjective, tired"`,
+		"3": `"isError":true`,
+		"4": `unknown tool`,
+	} {
+		if !strings.Contains(string(got[id]), want) {
+			t.Errorf("response %s = %s, want it to contain %s", id, got[id], want)
+		}
+	}
+
+	history, err := storage.LoadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Errorf("got %d history entries, want 2", len(history))
+	}
+}
@@ -0,0 +1,81 @@
+// Package notify tells the user that slow work finished while they were in another
+// window, with a desktop notification and optionally the terminal bell.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/config"
+)
+
+// Modes of work that can notify when they finish, as named in [notify] modes
+const (
+	ModeSentence  = "sentence"  // A single sentence
+	ModeParagraph = "paragraph" // Paragraphs, articles and several target languages
+	ModeDocument  = "document"  // A sentence of a document being read
+	ModeQueue     = "queue"     // The sentences queued while offline
+	ModeBatch     = "batch"     // The po and i18n commands
+)
+
+// Modes lists the modes in the order they are documented.
+var Modes = []string{ModeSentence, ModeParagraph, ModeDocument, ModeQueue, ModeBatch}
+
+// defaultCommands are the notification commands tried in order when none is configured.
+// {title} and {body} are replaced in their arguments.
+var defaultCommands = [][]string{
+	{"notify-send", "--app-name=translation-tui", "{title}", "{body}"},
+	{"osascript", "-e", `display notification "{body}" with title "{title}"`},
+	{"terminal-notifier", "-title", "{title}", "-message", "{body}"},
+}
+
+// Done notifies that work of the mode finished after elapsed, if the config asks for
+// notifications of that mode and the work took at least its minimum duration. The bell
+// is written to bell, which may be nil.
+func Done(ctx context.Context, cfg config.NotifyConfig, mode string, elapsed time.Duration, title, body string, bell io.Writer) error {
+	if !slices.Contains(cfg.Modes, mode) || elapsed < cfg.MinDuration {
+		return nil
+	}
+	if cfg.Bell && bell != nil {
+		_, _ = io.WriteString(bell, "\a")
+	}
+	command := cfg.Command
+	if len(command) == 0 {
+		for _, c := range defaultCommands {
+			if _, err := exec.LookPath(c[0]); err == nil {
+				command = c
+				break
+			}
+		}
+		if len(command) == 0 {
+			return nil // Nothing to show notifications with; the bell has to do
+		}
+	}
+	return run(ctx, command, title, body)
+}
+
+// run runs the notification command with the title and body filled in.
+func run(ctx context.Context, command []string, title, body string) error {
+	// Quotes would end the AppleScript string
+	r := strings.NewReplacer("{title}", strings.ReplaceAll(title, `"`, "'"), "{body}", strings.ReplaceAll(body, `"`, "'"))
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = r.Replace(arg)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("notification failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("notification failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/config"
+)
+
+func TestDone(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.txt")
+	cfg := config.NotifyConfig{
+		Modes:       []string{ModeBatch},
+		MinDuration: 10 * time.Second,
+		Bell:        true,
+		Command:     []string{"sh", "-c", `printf '%s|%s' "$0" "$1" > ` + out, "{title}", "{body}"},
+	}
+
+	tests := []struct {
+		name    string
+		mode    string
+		elapsed time.Duration
+		want    string
+	}{
+		{"mode not selected", ModeSentence, time.Minute, ""},
+		{"too quick", ModeBatch, time.Second, ""},
+		{"notified", ModeBatch, time.Minute, `Done|3 'strings'`}, // Quotes would end the AppleScript string
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bell bytes.Buffer
+			if err := Done(context.Background(), cfg, tt.mode, tt.elapsed, "Done", `3 "strings"`, &bell); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(out)
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if got := string(data); got != tt.want {
+				t.Errorf("notification = %q, want %q", got, tt.want)
+			}
+			if rang := bell.String() == "\a"; rang != (tt.want != "") {
+				t.Errorf("bell rang = %v", rang)
+			}
+		})
+	}
+}
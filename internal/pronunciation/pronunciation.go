@@ -0,0 +1,321 @@
+// Package pronunciation fetches audio of words, recorded by native speakers on Forvo or
+// synthesized by a local text-to-speech command, and of whole sentences, synthesized,
+// and plays it. Audio is cached in the data directory, so that words heard once can be
+// replayed offline.
+package pronunciation
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// DefaultForvoURL is the Forvo API that is queried.
+const DefaultForvoURL = "https://apifree.forvo.com"
+
+// Directory inside the data directory holding cached audio
+const cacheDirName = "audio"
+
+// Largest audio file that is downloaded, in bytes
+const maxAudioSize = 8 << 20
+
+// Sources of audio
+const (
+	SourceForvo = "Forvo"
+	SourceTTS   = "text-to-speech"
+)
+
+// ErrNotFound is returned when no source has audio of the word.
+var ErrNotFound = errors.New("no pronunciation found")
+
+// ErrNoPlayer is returned when no audio player is configured or installed.
+var ErrNoPlayer = errors.New("no audio player found (set [pronunciation] player)")
+
+// player is an audio player command, called with the audio file as its last argument.
+type player struct {
+	command []string
+	speed   []string // Arguments playing at {speed}, or none if it cannot
+}
+
+// defaultPlayers are the players tried in order when none is configured.
+var defaultPlayers = []player{
+	{[]string{"mpv", "--no-video", "--really-quiet"}, []string{"--speed={speed}"}},
+	{[]string{"ffplay", "-nodisp", "-autoexit", "-loglevel", "quiet"}, []string{"-af", "atempo={speed}"}},
+	{[]string{"afplay"}, []string{"-r", "{speed}"}},
+	{[]string{"paplay"}, nil},
+	{[]string{"aplay", "-q"}, nil},
+}
+
+// Options selects where audio comes from.
+type Options struct {
+	ForvoAPIKey string   // Forvo is only queried with a key
+	ForvoURL    string   // Empty means DefaultForvoURL
+	TTSCommand  []string // Synthesizes {word} in {lang} into the WAV file {file}, used if Forvo has no audio
+}
+
+// Audio is a cached pronunciation of a word.
+type Audio struct {
+	Path   string
+	Source string // SourceForvo or SourceTTS
+
+	uncached bool // Written to a temporary directory rather than the cache
+}
+
+// Discard removes audio that was not cached, once it is played.
+func (a Audio) Discard() {
+	if a.uncached {
+		_ = os.RemoveAll(filepath.Dir(a.Path))
+	}
+}
+
+// Fetch returns the pronunciation of word in lang (a code such as "de") from the cache,
+// from Forvo, or synthesized by the TTS command, in this order.
+func Fetch(ctx context.Context, client *http.Client, opts Options, word, lang string) (Audio, error) {
+	word = strings.TrimSpace(word)
+	dir, forvoPath, ttsPath, err := cachePaths(word, lang)
+	if err != nil {
+		return Audio{}, err
+	}
+	if audio, ok := cached(forvoPath, ttsPath); ok {
+		return audio, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Audio{}, err
+	}
+
+	if opts.ForvoAPIKey != "" {
+		err := fetchForvo(ctx, client, opts, word, lang, forvoPath)
+		if err == nil {
+			return Audio{Path: forvoPath, Source: SourceForvo}, nil
+		}
+		if !errors.Is(err, ErrNotFound) || len(opts.TTSCommand) == 0 {
+			return Audio{}, err
+		}
+	}
+	if len(opts.TTSCommand) == 0 {
+		return Audio{}, errors.New("no pronunciation source configured (set [pronunciation] forvo_api_key or tts_command)")
+	}
+	if err := synthesize(ctx, opts.TTSCommand, word, lang, ttsPath); err != nil {
+		return Audio{}, err
+	}
+	return Audio{Path: ttsPath, Source: SourceTTS}, nil
+}
+
+// Speak returns the sentence in lang synthesized by the TTS command, from the cache if
+// it was synthesized before. Forvo has words only. Private sessions and encrypted data
+// directories keep sentences out of the cache, which would give them away: the audio
+// is synthesized to a temporary file instead, which Discard removes.
+func Speak(ctx context.Context, command []string, sentence, lang string) (Audio, error) {
+	sentence = strings.TrimSpace(sentence)
+	if storage.Private() || storage.Encrypted() {
+		if len(command) == 0 {
+			return Audio{}, errors.New("no text-to-speech command configured for sentences (set [pronunciation] tts_command)")
+		}
+		dir, err := os.MkdirTemp("", "translation-tui-audio-")
+		if err != nil {
+			return Audio{}, err
+		}
+		audio := Audio{Path: filepath.Join(dir, "sentence.wav"), Source: SourceTTS, uncached: true}
+		if err := synthesize(ctx, command, sentence, lang, audio.Path); err != nil {
+			audio.Discard()
+			return Audio{}, err
+		}
+		return audio, nil
+	}
+
+	dir, err := storage.DataFile(filepath.Join(cacheDirName, lang))
+	if err != nil {
+		return Audio{}, err
+	}
+	// Named by hash, as sentences make for long file names
+	sum := sha256.Sum256([]byte(sentence))
+	audio := Audio{Path: filepath.Join(dir, "sentence-"+hex.EncodeToString(sum[:8])+".wav"), Source: SourceTTS}
+	if _, err := os.Stat(audio.Path); err == nil {
+		return audio, nil
+	}
+	if len(command) == 0 {
+		return Audio{}, errors.New("no text-to-speech command configured for sentences (set [pronunciation] tts_command)")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Audio{}, err
+	}
+	if err := synthesize(ctx, command, sentence, lang, audio.Path); err != nil {
+		return Audio{}, err
+	}
+	return audio, nil
+}
+
+// Cached returns the pronunciation of word in lang if it is in the cache, without
+// fetching it.
+func Cached(word, lang string) (Audio, bool) {
+	_, forvoPath, ttsPath, err := cachePaths(strings.TrimSpace(word), lang)
+	if err != nil {
+		return Audio{}, false
+	}
+	return cached(forvoPath, ttsPath)
+}
+
+// cachePaths returns the cache directory of lang and the paths of the Forvo and
+// synthesized audio of word in it.
+func cachePaths(word, lang string) (dir, forvoPath, ttsPath string, err error) {
+	dir, err = storage.DataFile(filepath.Join(cacheDirName, lang))
+	if err != nil {
+		return "", "", "", err
+	}
+	name := url.PathEscape(strings.ToLower(word))
+	return dir, filepath.Join(dir, name+".mp3"), filepath.Join(dir, name+".wav"), nil
+}
+
+// cached returns the audio at the first path that exists, preferring Forvo.
+func cached(forvoPath, ttsPath string) (Audio, bool) {
+	if _, err := os.Stat(forvoPath); err == nil {
+		return Audio{Path: forvoPath, Source: SourceForvo}, true
+	}
+	if _, err := os.Stat(ttsPath); err == nil {
+		return Audio{Path: ttsPath, Source: SourceTTS}, true
+	}
+	return Audio{}, false
+}
+
+// fetchForvo downloads the best-rated Forvo pronunciation of word to path.
+func fetchForvo(ctx context.Context, client *http.Client, opts Options, word, lang, path string) error {
+	base := opts.ForvoURL
+	if base == "" {
+		base = DefaultForvoURL
+	}
+	endpoint := strings.TrimSuffix(base, "/") + "/key/" + url.PathEscape(opts.ForvoAPIKey) +
+		"/format/json/action/word-pronunciations/word/" + url.PathEscape(word) +
+		"/language/" + url.PathEscape(lang) + "/order/rate-desc"
+	body, err := get(ctx, client, endpoint)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Items []struct {
+			PathMP3 string `json:"pathmp3"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("failed to parse Forvo response: %w", err)
+	}
+	if len(resp.Items) == 0 || resp.Items[0].PathMP3 == "" {
+		return fmt.Errorf("%w for %q on Forvo", ErrNotFound, word)
+	}
+	audio, err := get(ctx, client, resp.Items[0].PathMP3)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, audio, 0o600)
+}
+
+// get reads the body of a GET request.
+func get(ctx context.Context, client *http.Client, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Forvo URL: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL contains the API key, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("failed to query Forvo: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query Forvo: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAudioSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Forvo response: %w", err)
+	}
+	return body, nil
+}
+
+// synthesize runs the TTS command, replacing {word}, {lang} and {file} in its arguments.
+func synthesize(ctx context.Context, command []string, word, lang, path string) error {
+	r := strings.NewReplacer("{word}", word, "{lang}", lang, "{file}", path)
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = r.Replace(arg)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		_ = os.Remove(path) // Don't cache partial audio
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("text-to-speech failed: %w: %s", err, msg)
+		}
+		return fmt.Errorf("text-to-speech failed: %w", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("text-to-speech command wrote no audio to {file}: %w", err)
+	}
+	return nil
+}
+
+// Play plays the audio file with the player, or the first installed default player,
+// and waits until it is done.
+func Play(ctx context.Context, command []string, path string) error {
+	return PlayAt(ctx, command, path, 1)
+}
+
+// PlayAt plays the audio file at a speed, such as 0.75 for three quarters of the normal
+// speed, with the player, or the first installed default player, and waits until it is
+// done. A configured player is given the speed where {speed} is in its arguments.
+func PlayAt(ctx context.Context, command []string, path string, speed float64) error {
+	p := player{command: command}
+	if slices.ContainsFunc(command, func(arg string) bool { return strings.Contains(arg, "{speed}") }) {
+		p.speed = []string{} // Given in the command
+	}
+	if len(command) == 0 {
+		i := slices.IndexFunc(defaultPlayers, func(p player) bool {
+			_, err := exec.LookPath(p.command[0])
+			return err == nil
+		})
+		if i < 0 {
+			return ErrNoPlayer
+		}
+		p = defaultPlayers[i]
+	}
+	args := slices.Clone(p.command[1:])
+	if speed != 1 {
+		if p.speed == nil {
+			return fmt.Errorf("%s cannot change the speed of audio (set [pronunciation] player with {speed})", p.command[0])
+		}
+		args = append(args, p.speed...)
+	}
+	r := strings.NewReplacer("{speed}", strconv.FormatFloat(speed, 'f', -1, 64))
+	for i, arg := range args {
+		args[i] = r.Replace(arg)
+	}
+	args = append(args, path)
+	if out, err := exec.CommandContext(ctx, p.command[0], args...).CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err() // Stopped rather than failed
+		}
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("failed to play audio: %w: %s", err, msg)
+		}
+		return fmt.Errorf("failed to play audio: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package pronunciation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+func TestFetchForvo(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	requests := 0
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case r.URL.Path == "/audio/bahnhof.mp3":
+			w.Write([]byte("ID3 audio"))
+		case strings.Contains(r.URL.Path, "/word/Bahnhof/language/de/"):
+			w.Write([]byte(`{"attributes": {"total": 1}, "items": [{"word": "Bahnhof", "pathmp3": "` + srv.URL + `/audio/bahnhof.mp3"}]}`))
+		default:
+			w.Write([]byte(`{"attributes": {"total": 0}, "items": []}`))
+		}
+	}))
+	defer srv.Close()
+	opts := Options{ForvoAPIKey: "secret", ForvoURL: srv.URL}
+
+	a, err := Fetch(context.Background(), srv.Client(), opts, "Bahnhof", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Source != SourceForvo {
+		t.Errorf("source = %q, want %q", a.Source, SourceForvo)
+	}
+	if data, err := os.ReadFile(a.Path); err != nil || string(data) != "ID3 audio" {
+		t.Errorf("audio = %q, %v", data, err)
+	}
+
+	// The second fetch is served from the cache
+	if _, err := Fetch(context.Background(), srv.Client(), opts, "Bahnhof", "de"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2", requests)
+	}
+
+	if _, err := Fetch(context.Background(), srv.Client(), opts, "Xyz", "de"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing word: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestFetchTTS(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	opts := Options{TTSCommand: []string{"sh", "-c", `printf '%s/%s' "$1" "$2" > "$0"`, "{file}", "{lang}", "{word}"}}
+
+	a, err := Fetch(context.Background(), http.DefaultClient, opts, "Bahnhof", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Source != SourceTTS {
+		t.Errorf("source = %q, want %q", a.Source, SourceTTS)
+	}
+	if data, err := os.ReadFile(a.Path); err != nil || string(data) != "de/Bahnhof" {
+		t.Errorf("audio = %q, %v", data, err)
+	}
+
+	if _, err := Fetch(context.Background(), http.DefaultClient, Options{}, "Zug", "de"); err == nil {
+		t.Error("no source: got no error")
+	}
+}
+
+func TestSpeak(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	command := []string{"sh", "-c", `printf '%s' "$1" > "$0"`, "{file}", "{word}"}
+
+	a, err := Speak(context.Background(), command, "Wo ist der Bahnhof?", "de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(a.Path); err != nil || string(data) != "Wo ist der Bahnhof?" {
+		t.Errorf("audio = %q, %v", data, err)
+	}
+	if again, err := Speak(context.Background(), nil, "Wo ist der Bahnhof?", "de"); err != nil || again != a {
+		t.Errorf("cached: %+v, %v", again, err)
+	}
+	if _, err := Speak(context.Background(), nil, "Wo ist der Zug?", "de"); err == nil {
+		t.Error("no command: got no error")
+	}
+}
+
+func TestSpeakUncached(t *testing.T) {
+	command := []string{"sh", "-c", `printf '%s' "$1" > "$0"`, "{file}", "{word}"}
+	for _, tt := range []struct {
+		name  string
+		setup func(t *testing.T)
+	}{
+		{"private", func(t *testing.T) {
+			storage.SetPrivate(true)
+			t.Cleanup(func() { storage.SetPrivate(false) })
+		}},
+		{"encrypted", func(t *testing.T) {
+			path, err := storage.DataFile("encrypted")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, nil, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_DATA_HOME", t.TempDir())
+			tt.setup(t)
+
+			a, err := Speak(context.Background(), command, "Wo ist der Bahnhof?", "de")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data, err := os.ReadFile(a.Path); err != nil || string(data) != "Wo ist der Bahnhof?" {
+				t.Errorf("audio = %q, %v", data, err)
+			}
+			cache, err := storage.DataFile(cacheDirName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(cache); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("sentence cached in %s", cache)
+			}
+			a.Discard()
+			if _, err := os.Stat(a.Path); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("audio not discarded: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlayAt(t *testing.T) {
+	path := t.TempDir() + "/audio.wav"
+	player := []string{"sh", "-c", `printf '%s' "$0" > "$1"`, "{speed}"}
+	if err := PlayAt(context.Background(), player, path, 0.75); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "0.75" {
+		t.Errorf("played at %q, %v", data, err)
+	}
+	if err := PlayAt(context.Background(), []string{"true"}, path, 0.5); err == nil {
+		t.Error("player without {speed}: got no error")
+	}
+	if err := PlayAt(context.Background(), []string{"true"}, path, 1); err != nil {
+		t.Errorf("normal speed: %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+// Package spellcheck finds likely misspellings with the hunspell or aspell dictionaries
+// installed on the system.
+package spellcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// ErrNoDictionary is returned when no spellchecker has a dictionary of a language.
+var ErrNoDictionary = errors.New("no spelling dictionary installed")
+
+// commands are the spellcheckers tried in order. Each reads the text on stdin and lists
+// its misspelled words on stdout, one per line; {lang} stands for the language code and
+// {dict} for the name of its hunspell dictionary.
+var commands = [][]string{
+	{"hunspell", "-i", "utf-8", "-l", "-d", "{dict}"},
+	{"aspell", "list", "--encoding=utf-8", "--lang={lang}"},
+}
+
+// dictionaries maps language codes to their usual hunspell dictionary, which is named
+// after a locale. Other codes are used as they are.
+var dictionaries = map[string]string{
+	"de": "de_DE",
+	"en": "en_US",
+	"es": "es_ES",
+	"fr": "fr_FR",
+	"it": "it_IT",
+	"pt": "pt_PT",
+	"sr": "sr_RS",
+	"sv": "sv_SE",
+}
+
+// Misspelled returns the words of text that are in none of the dictionaries of langs,
+// once each in the order they appear, so that text may be in any of the languages.
+func Misspelled(ctx context.Context, text string, langs ...string) ([]string, error) {
+	var words []string
+	for i, lang := range langs {
+		unknown, err := check(ctx, text, lang)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			words = unknown
+			continue
+		}
+		words = slices.DeleteFunc(words, func(w string) bool { return !slices.Contains(unknown, w) })
+	}
+	return words, nil
+}
+
+// check lists the words of text missing from the dictionary of lang with the first
+// spellchecker that has one.
+func check(ctx context.Context, text, lang string) ([]string, error) {
+	dict := dictionaries[lang]
+	if dict == "" {
+		dict = lang
+	}
+	for _, c := range commands {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		args := make([]string, len(c))
+		for i, arg := range c {
+			args[i] = strings.NewReplacer("{lang}", lang, "{dict}", dict).Replace(arg)
+		}
+		var stdout bytes.Buffer
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		cmd.Stdout = &stdout
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue // Most likely no dictionary of the language
+		}
+		var words []string
+		for _, w := range strings.Fields(stdout.String()) {
+			if !slices.Contains(words, w) {
+				words = append(words, w)
+			}
+		}
+		return words, nil
+	}
+	return nil, fmt.Errorf("%w for %s", ErrNoDictionary, lang)
+}
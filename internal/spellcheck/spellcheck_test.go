@@ -0,0 +1,49 @@
+package spellcheck
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMisspelled(t *testing.T) {
+	dir := t.TempDir()
+	for lang, words := range map[string]string{"sv": "jag\när\nglad\n", "de": "ich\nbin\nglad\n"} {
+		if err := os.WriteFile(filepath.Join(dir, lang+".dic"), []byte(words), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Lists the words missing from a word list, and fails without one as hunspell does
+	saved := commands
+	t.Cleanup(func() { commands = saved })
+	commands = [][]string{
+		{"missing-spellchecker", "{lang}"},
+		{"sh", "-c", `test -f "$0" || exit 1; tr -s ' ' '\n' | grep -vxF -f "$0"; exit 0`, filepath.Join(dir, "{lang}.dic")},
+	}
+
+	tests := []struct {
+		name  string
+		text  string
+		langs []string
+		want  []string
+		err   error
+	}{
+		{"one language", "jag är gald gald", []string{"sv"}, []string{"gald"}, nil},
+		{"unknown in both", "ich bin glad jag gald", []string{"sv", "de"}, []string{"gald"}, nil},
+		{"no dictionary", "bonjour", []string{"sv", "fr"}, nil, ErrNoDictionary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Misspelled(context.Background(), tt.text, tt.langs...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Misspelled(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package srs schedules the review of vocab cards by spaced repetition, after the SM-2
+// algorithm: each card recalled comes back after a longer interval, growing by its ease,
+// and each card forgotten starts over.
+package srs
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// Grade is how well a card was recalled.
+type Grade int
+
+// Grades of a review, from forgotten to recalled at once
+const (
+	Again Grade = iota
+	Hard
+	Good
+	Easy
+)
+
+const (
+	initialEase = 2.5
+	minEase     = 1.3
+
+	// relearnDelay is how soon a forgotten card is due again.
+	relearnDelay = 10 * time.Minute
+)
+
+// IsDue reports whether a card is due for review at now. Cards never reviewed are due.
+func IsDue(c storage.VocabCard, now time.Time) bool {
+	return !c.Due.After(now)
+}
+
+// Due returns the cards due for review at now: priority cards first, then the others,
+// each in the order of the deck.
+func Due(cards []storage.VocabCard, now time.Time) []storage.VocabCard {
+	var due []storage.VocabCard
+	for _, c := range cards {
+		if IsDue(c, now) {
+			due = append(due, c)
+		}
+	}
+	slices.SortStableFunc(due, func(a, b storage.VocabCard) int {
+		switch {
+		case a.Priority == b.Priority:
+			return 0
+		case a.Priority:
+			return -1
+		}
+		return 1
+	})
+	return due
+}
+
+// Review returns the card as scheduled after reviewing it at now with the grade.
+func Review(c storage.VocabCard, g Grade, now time.Time) storage.VocabCard {
+	if c.Ease == 0 {
+		c.Ease = initialEase
+	}
+	c.Interval = Interval(c, g)
+	switch g {
+	case Again:
+		c.Ease = max(c.Ease-0.2, minEase)
+		c.Reps = 0
+		c.Lapses++
+		c.Due = now.Add(relearnDelay)
+		return c
+	case Hard:
+		c.Ease = max(c.Ease-0.15, minEase)
+	case Easy:
+		c.Ease += 0.15
+	}
+	c.Reps++
+	c.Due = now.AddDate(0, 0, c.Interval)
+	return c
+}
+
+// Interval returns the days until a card is next due if reviewed with the grade: none
+// if forgotten, one and then six while it is new, and otherwise the last interval
+// grown by the ease, less if hard and more if easy.
+func Interval(c storage.VocabCard, g Grade) int {
+	ease := c.Ease
+	if ease == 0 {
+		ease = initialEase
+	}
+	var days float64
+	switch {
+	case g == Again:
+		return 0
+	case c.Reps == 0:
+		days = 1
+	case c.Reps == 1:
+		days = 6
+	default:
+		days = float64(c.Interval) * ease
+	}
+	switch g {
+	case Hard:
+		days = max(1, float64(max(c.Interval, 1))*1.2)
+	case Easy:
+		days = max(days*1.3, 4)
+	}
+	return int(math.Round(days))
+}
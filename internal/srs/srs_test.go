@@ -0,0 +1,57 @@
+package srs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+func TestReview(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := storage.VocabCard{Word: "Haus", Lang: "de"}
+	if !IsDue(c, now) {
+		t.Fatal("new card not due")
+	}
+
+	for i, want := range []int{1, 6, 15} {
+		c = Review(c, Good, now)
+		if c.Interval != want {
+			t.Fatalf("review %d: interval %d, want %d", i+1, c.Interval, want)
+		}
+		if !c.Due.Equal(now.AddDate(0, 0, want)) {
+			t.Errorf("review %d: due %v", i+1, c.Due)
+		}
+	}
+	if c.Reps != 3 || c.Ease != initialEase {
+		t.Errorf("reps %d, ease %v", c.Reps, c.Ease)
+	}
+	if IsDue(c, now) {
+		t.Error("reviewed card still due")
+	}
+
+	c = Review(c, Again, now)
+	if c.Reps != 0 || c.Lapses != 1 || c.Interval != 0 || c.Ease != initialEase-0.2 {
+		t.Errorf("after lapse: %+v", c)
+	}
+	if !c.Due.Equal(now.Add(relearnDelay)) {
+		t.Errorf("due %v after lapse", c.Due)
+	}
+	if got := Interval(c, Easy); got != 4 {
+		t.Errorf("easy after lapse: %d days, want 4", got)
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cards := []storage.VocabCard{
+		{Word: "neu"},
+		{Word: "morgen", Due: now.Add(24 * time.Hour)},
+		{Word: "gestern", Due: now.Add(-24 * time.Hour)},
+		{Word: "sein", Due: now, Priority: true},
+	}
+	due := Due(cards, now)
+	if len(due) != 3 || due[0].Word != "sein" || due[1].Word != "neu" || due[2].Word != "gestern" {
+		t.Errorf("due %+v", due)
+	}
+}
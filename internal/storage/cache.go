@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Cache file name inside the data directory
+const cacheFileName = "cache.json"
+
+// CachedResult represents a stored pipeline result for a sentence.
+type CachedResult struct {
+	Original    string                `json:"original"`
+	Translation string                `json:"translation"`
+	Words       []translator.WordInfo `json:"words,omitempty"`
+	Level       string                `json:"level,omitempty"`
+	Tone        *translator.Tone      `json:"tone,omitempty"`
+	Models      translator.Models     `json:"models"`
+	Time        time.Time             `json:"time"`
+}
+
+// Result converts the cache entry back into a pipeline result.
+func (c CachedResult) Result() translator.Result {
+	return translator.Result{
+		Original:    c.Original,
+		Translation: c.Translation,
+		Words:       c.Words,
+		Level:       c.Level,
+		Tone:        c.Tone,
+		Models:      c.Models,
+	}
+}
+
+// cacheKey identifies a sentence within a language pair.
+func cacheKey(req translator.Request) string {
+	return req.UserLang + "|" + req.TargetLang + "|" + strings.TrimSpace(req.Sentence)
+}
+
+// loadCache reads the result cache.
+func loadCache() (map[string]CachedResult, error) {
+	path, err := DataFile(cacheFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]CachedResult{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache: %w", err)
+	}
+	if data, err = open(data); err != nil {
+		return nil, err
+	}
+	cache := map[string]CachedResult{}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse cache: %w", err)
+	}
+	return cache, nil
+}
+
+// LookupCache returns the cached result for the request, if any.
+func LookupCache(req translator.Request) (CachedResult, bool) {
+	if private.Load() {
+		return CachedResult{}, false
+	}
+	cache, err := loadCache()
+	if err != nil {
+		return CachedResult{}, false
+	}
+	c, ok := cache[cacheKey(req)]
+	if !ok {
+		slog.Debug("cache miss", "user_lang", req.UserLang, "target_lang", req.TargetLang)
+		return CachedResult{}, false
+	}
+	slog.Info("cache hit", "user_lang", req.UserLang, "target_lang", req.TargetLang, "cached_at", c.Time)
+	return c, true
+}
+
+// StoreCache stores a successful result for the request.
+func StoreCache(req translator.Request, result translator.Result) error {
+	if private.Load() {
+		return nil
+	}
+	cache, err := loadCache()
+	if err != nil {
+		return err
+	}
+	cache[cacheKey(req)] = CachedResult{
+		Original:    result.Original,
+		Translation: result.Translation,
+		Words:       result.Words,
+		Level:       result.Level,
+		Tone:        result.Tone,
+		Models:      result.Models,
+		Time:        time.Now(),
+	}
+	return saveCache(cache)
+}
+
+// saveCache writes the result cache.
+func saveCache(cache map[string]CachedResult) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if data, err = seal(data); err != nil {
+		return err
+	}
+	path, err := DataFile(cacheFileName)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
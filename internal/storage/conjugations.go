@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Conjugation table file name inside the data directory
+const conjugationsFileName = "conjugations.json"
+
+// conjugationsMu serializes changes to the conjugation tables within the process.
+var conjugationsMu sync.Mutex
+
+// conjugationKey identifies a verb of the target language of a language pair, whose
+// tenses and persons are named in the user's language.
+func conjugationKey(userLang, targetLang, verb string) string {
+	return userLang + "|" + targetLang + "|" + strings.ToLower(strings.TrimSpace(verb))
+}
+
+// loadConjugations reads the cached conjugation tables.
+func loadConjugations() (map[string]translator.Conjugation, error) {
+	path, err := DataFile(conjugationsFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]translator.Conjugation{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conjugations: %w", err)
+	}
+	if data, err = open(data); err != nil {
+		return nil, err
+	}
+	tables := map[string]translator.Conjugation{}
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, fmt.Errorf("failed to parse conjugations: %w", err)
+	}
+	return tables, nil
+}
+
+// LookupConjugation returns the cached conjugation table of a verb, if any.
+func LookupConjugation(userLang, targetLang, verb string) (translator.Conjugation, bool) {
+	conjugationsMu.Lock()
+	defer conjugationsMu.Unlock()
+	tables, err := loadConjugations()
+	if err != nil {
+		return translator.Conjugation{}, false
+	}
+	c, ok := tables[conjugationKey(userLang, targetLang, verb)]
+	return c, ok
+}
+
+// StoreConjugation caches the conjugation table of a verb, so that it is quizzed on
+// offline and without asking the model again.
+func StoreConjugation(userLang, targetLang, verb string, c translator.Conjugation) error {
+	if private.Load() {
+		return nil
+	}
+	conjugationsMu.Lock()
+	defer conjugationsMu.Unlock()
+	tables, err := loadConjugations()
+	if err != nil {
+		return err
+	}
+	tables[conjugationKey(userLang, targetLang, verb)] = c
+	return saveConjugations(tables)
+}
+
+func saveConjugations(tables map[string]translator.Conjugation) error {
+	data, err := json.Marshal(tables)
+	if err != nil {
+		return fmt.Errorf("failed to encode conjugations: %w", err)
+	}
+	if data, err = seal(data); err != nil {
+		return err
+	}
+	path, err := DataFile(conjugationsFileName)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write conjugations: %w", err)
+	}
+	return nil
+}
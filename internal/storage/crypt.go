@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Marker file inside the data directory whose presence means the study data is
+// encrypted. It holds a known text encrypted with the passphrase, to check it.
+const encryptionFileName = "encrypted"
+
+// sealedPrefix starts data encrypted with a passphrase: scrypt derives the key from
+// the passphrase and a random salt, and NaCl secretbox encrypts the data. The salt
+// travels with the data, so any machine knowing the passphrase can read it.
+const sealedPrefix = "ttui-sealed-v1:"
+
+// encryptionCheck is the text encrypted into the marker file.
+const encryptionCheck = "translation-tui"
+
+const (
+	saltSize  = 16
+	nonceSize = 24
+)
+
+var (
+	// ErrLocked is returned when encrypted data is read without the passphrase.
+	ErrLocked = errors.New("the data directory is encrypted; the passphrase is needed")
+
+	// ErrPassphrase is returned when encrypted data does not open with the passphrase.
+	ErrPassphrase = errors.New("wrong passphrase")
+)
+
+// crypt holds the passphrase of the session and the keys derived from it, which are
+// slow to derive on purpose.
+var crypt struct {
+	sync.Mutex
+	passphrase []byte
+	salt       []byte               // Salt of the data written in this session
+	keys       map[string]*[32]byte // Keys by salt
+}
+
+// Encrypted reports whether the study data of the data directory is encrypted.
+func Encrypted() bool {
+	path, err := DataFile(encryptionFileName)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Unlock checks the passphrase of the encrypted data directory and keeps it for the
+// session, so the data is read and written encrypted.
+func Unlock(passphrase string) error {
+	path, err := DataFile(encryptionFileName)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read encryption check: %w", err)
+	}
+	setPassphrase(passphrase)
+	check, err := open(data)
+	if err == nil && string(check) != encryptionCheck {
+		err = ErrPassphrase
+	}
+	if err != nil {
+		setPassphrase("")
+		return err
+	}
+	return nil
+}
+
+// EncryptAll encrypts the study data with a new passphrase: the history, vocab deck,
+// personal dictionary, result cache, offline queue, draft, conjugation tables and the
+// transcripts of the data directory. It also changes the passphrase
+// of data already encrypted, which must have been unlocked.
+func EncryptAll(passphrase string) error {
+	if passphrase == "" {
+		return errors.New("the passphrase must not be empty")
+	}
+	return rewriteAll(passphrase, func() error {
+		check, err := seal([]byte(encryptionCheck))
+		if err != nil {
+			return err
+		}
+		path, err := DataFile(encryptionFileName)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, check, 0o600); err != nil {
+			return fmt.Errorf("failed to write encryption check: %w", err)
+		}
+		return nil
+	})
+}
+
+// DecryptAll stores the unlocked study data unencrypted again.
+func DecryptAll() error {
+	return rewriteAll("", func() error {
+		path, err := DataFile(encryptionFileName)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove encryption check: %w", err)
+		}
+		return nil
+	})
+}
+
+// rewriteAll reads the study data with the current passphrase and writes it again
+// with the new one, then finishes with done.
+func rewriteAll(passphrase string, done func() error) error {
+	history, err := LoadHistory()
+	if err != nil {
+		return err
+	}
+	cards, err := LoadVocab()
+	if err != nil {
+		return err
+	}
+	dictionaryMu.Lock()
+	defer dictionaryMu.Unlock()
+	entries, err := loadDictionary()
+	if err != nil {
+		return err
+	}
+	cache, err := loadCache()
+	if err != nil {
+		return err
+	}
+	queueMu.Lock()
+	defer queueMu.Unlock()
+	queue, err := loadQueue()
+	if err != nil {
+		return err
+	}
+	draftMu.Lock()
+	defer draftMu.Unlock()
+	draft, err := loadDraft()
+	if err != nil {
+		return err
+	}
+	conjugationsMu.Lock()
+	defer conjugationsMu.Unlock()
+	conjugations, err := loadConjugations()
+	if err != nil {
+		return err
+	}
+	transcriptMu.Lock()
+	defer transcriptMu.Unlock()
+	transcripts, err := loadTranscripts()
+	if err != nil {
+		return err
+	}
+
+	setPassphrase(passphrase)
+	if err := writeHistory(history); err != nil {
+		return err
+	}
+	if cards != nil {
+		if err := SaveVocab(cards); err != nil {
+			return err
+		}
+	}
+	if entries != nil {
+		if err := saveDictionary(entries); err != nil {
+			return err
+		}
+	}
+	if len(cache) > 0 {
+		if err := saveCache(cache); err != nil {
+			return err
+		}
+	}
+	if queue != nil {
+		if err := saveQueue(queue); err != nil {
+			return err
+		}
+	}
+	if draft.Sentence != "" {
+		if err := saveDraft(draft); err != nil {
+			return err
+		}
+	}
+	if len(conjugations) > 0 {
+		if err := saveConjugations(conjugations); err != nil {
+			return err
+		}
+	}
+	if err := saveTranscripts(transcripts); err != nil {
+		return err
+	}
+	return done()
+}
+
+// setPassphrase sets the passphrase of the session, with a new salt for the data
+// written from now on. An empty passphrase writes data unencrypted.
+func setPassphrase(passphrase string) {
+	crypt.Lock()
+	defer crypt.Unlock()
+	crypt.passphrase = []byte(passphrase)
+	crypt.keys = map[string]*[32]byte{}
+	crypt.salt = nil
+	if passphrase != "" {
+		crypt.salt = make([]byte, saltSize)
+		if _, err := rand.Read(crypt.salt); err != nil {
+			panic(err) // crypto/rand does not fail on supported platforms
+		}
+	}
+}
+
+// unlocked reports whether the session has a passphrase, so that data is written
+// encrypted.
+func unlocked() bool {
+	crypt.Lock()
+	defer crypt.Unlock()
+	return len(crypt.passphrase) > 0
+}
+
+// key returns the key of the passphrase and salt, deriving it the first time.
+// crypt must be locked.
+func key(salt []byte) (*[32]byte, error) {
+	if k, ok := crypt.keys[string(salt)]; ok {
+		return k, nil
+	}
+	derived, err := scrypt.Key(crypt.passphrase, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	k := new([32]byte)
+	copy(k[:], derived)
+	crypt.keys[string(salt)] = k
+	return k, nil
+}
+
+// seal encrypts data with the passphrase of the session, as a single line of text.
+// Without a passphrase, data is returned as is.
+func seal(data []byte) ([]byte, error) {
+	crypt.Lock()
+	defer crypt.Unlock()
+	if len(crypt.passphrase) == 0 {
+		return data, nil
+	}
+	k, err := key(crypt.salt)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, fmt.Errorf("failed to create nonce: %w", err)
+	}
+	box := append(append([]byte{}, crypt.salt...), nonce[:]...)
+	box = secretbox.Seal(box, data, &nonce, k)
+	return append([]byte(sealedPrefix), base64.StdEncoding.EncodeToString(box)...), nil
+}
+
+// open decrypts data sealed with the passphrase of the session. Data that is not
+// sealed, such as that written before encrypting, is returned as is.
+func open(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if !bytes.HasPrefix(data, []byte(sealedPrefix)) {
+		return data, nil
+	}
+	box, err := base64.StdEncoding.DecodeString(string(data[len(sealedPrefix):]))
+	if err != nil || len(box) < saltSize+nonceSize+secretbox.Overhead {
+		return nil, errors.New("encrypted data is corrupted")
+	}
+	crypt.Lock()
+	defer crypt.Unlock()
+	if len(crypt.passphrase) == 0 {
+		return nil, ErrLocked
+	}
+	k, err := key(box[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	var nonce [nonceSize]byte
+	copy(nonce[:], box[saltSize:saltSize+nonceSize])
+	plain, ok := secretbox.Open(nil, box[saltSize+nonceSize:], &nonce, k)
+	if !ok {
+		return nil, ErrPassphrase
+	}
+	return plain, nil
+}
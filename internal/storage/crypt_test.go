@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+func TestSealOpen(t *testing.T) {
+	t.Cleanup(func() { setPassphrase("") })
+	plain := []byte(`{"sentence":"Ich bin glücklich."}`)
+
+	// Without a passphrase, data is written and read as it is
+	sealed, err := seal(plain)
+	if err != nil || !bytes.Equal(sealed, plain) {
+		t.Fatalf("seal without passphrase = %q, %v", sealed, err)
+	}
+
+	setPassphrase("secret")
+	if sealed, err = seal(plain); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(sealed, []byte(sealedPrefix)) || bytes.Contains(sealed, []byte("glücklich")) || bytes.ContainsRune(sealed, '\n') {
+		t.Errorf("sealed data = %q, want a line starting with %q", sealed, sealedPrefix)
+	}
+	if got, err := open(append(sealed, '\n')); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("open = %q, %v, want %q", got, err, plain)
+	}
+	if got, err := open(plain); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("open of unsealed data = %q, %v, want it as it is", got, err)
+	}
+	if _, err := open([]byte(sealedPrefix + "not base64")); err == nil {
+		t.Error("open of corrupted data succeeded")
+	}
+
+	setPassphrase("wrong")
+	if _, err := open(sealed); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("open with the wrong passphrase: err = %v, want %v", err, ErrPassphrase)
+	}
+	setPassphrase("")
+	if _, err := open(sealed); !errors.Is(err, ErrLocked) {
+		t.Errorf("open without passphrase: err = %v, want %v", err, ErrLocked)
+	}
+}
+
+func TestUnlock(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Cleanup(func() { setPassphrase("") })
+	if err := SaveVocab([]VocabCard{{Word: "Hund", Lang: "de"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptAll("secret"); err != nil {
+		t.Fatal(err)
+	}
+	if !Encrypted() {
+		t.Fatal("data directory not marked encrypted")
+	}
+
+	setPassphrase("") // A new session
+	if _, err := LoadVocab(); !errors.Is(err, ErrLocked) {
+		t.Errorf("LoadVocab before unlocking: err = %v, want %v", err, ErrLocked)
+	}
+	if err := Unlock("wrong"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("Unlock with the wrong passphrase: err = %v, want %v", err, ErrPassphrase)
+	}
+	if unlocked() {
+		t.Error("wrong passphrase kept for the session")
+	}
+	if err := Unlock("secret"); err != nil {
+		t.Fatalf("Unlock with the right passphrase: %v", err)
+	}
+	cards, err := LoadVocab()
+	if err != nil || len(cards) != 1 || cards[0].Word != "Hund" {
+		t.Errorf("LoadVocab after unlocking = %v, %v", cards, err)
+	}
+}
+
+// writeStudyData writes one of each file rewriteAll handles, returning the paths of
+// the files and the path of the transcript.
+func writeStudyData(t *testing.T, now time.Time) (paths []string, transcript string) {
+	t.Helper()
+	req := translator.Request{Sentence: "Jag är glad.", UserLang: "sv", TargetLang: "de"}
+	result := translator.Result{Original: "Jag är glad.", Translation: "Ich bin glücklich."}
+	conjugation := translator.Conjugation{Lemma: "sein", Forms: []translator.ConjugatedForm{{Tense: "presens", Person: "1:a person singular", Pronoun: "ich", Form: "bin"}}}
+	transcript = filepath.Join(os.Getenv("XDG_DATA_HOME"), AppName, TranscriptDirName, "2026-10-15.md")
+	for _, err := range []error{
+		AppendHistory(HistoryEntry{Time: now, UserLang: "sv", TargetLang: "de", Original: "Jag är glad.", Translation: "Ich bin glücklich."}),
+		SaveVocab([]VocabCard{{Word: "glücklich", Lang: "de", Sentence: "Ich bin glücklich."}}),
+		StoreCache(req, result),
+		AddToQueue(QueuedSentence{Queued: now, UserLang: "sv", TargetLang: "de", Sentence: "Jag är trött."}),
+		SaveDraft(Draft{Sentence: "Jag är hungrig.", Saved: now}),
+		StoreConjugation("sv", "de", "sein", conjugation),
+		AppendTranscript(transcript, "# Study session 2026-10-15\n", "\n> Jag är glad.\n"),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := AddToDictionary("de", "Ich bin glücklich.", []translator.WordInfo{{WordInTargetLang: "glücklich", Lemma: "glücklich"}}, now); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{historyFileName, vocabFileName, dictionaryFileName, cacheFileName, queueFileName, draftFileName, conjugationsFileName} {
+		path, err := DataFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return append(paths, transcript), transcript
+}
+
+func TestEncryptAllDecryptAll(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Cleanup(func() { setPassphrase("") })
+	now := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+	paths, transcript := writeStudyData(t, now)
+
+	if err := EncryptAll("secret"); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for line := range bytes.Lines(data) {
+			if !bytes.HasPrefix(line, []byte(sealedPrefix)) {
+				t.Errorf("%s not encrypted:\n%s", filepath.Base(path), data)
+				break
+			}
+		}
+	}
+
+	// Data written after encrypting is encrypted too, and everything reads back
+	if err := AppendHistory(HistoryEntry{Time: now.Add(time.Hour), UserLang: "sv", TargetLang: "de", Original: "Tack.", Translation: "Danke."}); err != nil {
+		t.Fatal(err)
+	}
+	if history, err := LoadHistory(); err != nil || len(history) != 2 || history[1].Translation != "Danke." {
+		t.Errorf("LoadHistory while encrypted = %v, %v", history, err)
+	}
+
+	if err := DecryptAll(); err != nil {
+		t.Fatal(err)
+	}
+	if Encrypted() {
+		t.Error("data directory still marked encrypted")
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, []byte(sealedPrefix)) {
+			t.Errorf("%s still encrypted:\n%s", filepath.Base(path), data)
+		}
+	}
+
+	history, err := LoadHistory()
+	if err != nil || len(history) != 2 || history[0].Original != "Jag är glad." {
+		t.Errorf("LoadHistory = %v, %v", history, err)
+	}
+	if cards, err := LoadVocab(); err != nil || len(cards) != 1 || cards[0].Word != "glücklich" {
+		t.Errorf("LoadVocab = %v, %v", cards, err)
+	}
+	if entries, err := LoadDictionary(); err != nil || len(entries) != 1 || entries[0].Lemma != "glücklich" {
+		t.Errorf("LoadDictionary = %v, %v", entries, err)
+	}
+	if c, ok := LookupCache(translator.Request{Sentence: "Jag är glad.", UserLang: "sv", TargetLang: "de"}); !ok || c.Translation != "Ich bin glücklich." {
+		t.Errorf("LookupCache = %v, %v", c, ok)
+	}
+	if queue, err := LoadQueue(); err != nil || len(queue) != 1 || queue[0].Sentence != "Jag är trött." {
+		t.Errorf("LoadQueue = %v, %v", queue, err)
+	}
+	if draft, err := LoadDraft(); err != nil || draft.Sentence != "Jag är hungrig." {
+		t.Errorf("LoadDraft = %v, %v", draft, err)
+	}
+	if c, ok := LookupConjugation("sv", "de", "sein"); !ok || c.Forms[0].Form != "bin" {
+		t.Errorf("LookupConjugation = %v, %v", c, ok)
+	}
+	data, err := os.ReadFile(transcript)
+	if want := "# Study session 2026-10-15\n\n> Jag är glad.\n"; err != nil || string(data) != want {
+		t.Errorf("transcript = %q, %v, want %q", data, err, want)
+	}
+}
+
+func TestPrivateLeavesNoFiles(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	SetPrivate(true)
+	t.Cleanup(func() { SetPrivate(false) })
+	writeStudyData(t, time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC))
+	for _, err := range []error{
+		SaveReadingPosition("/tmp/article.txt", ReadingPosition{}),
+		AddRecentPair(LanguagePair{UserLang: "sv", TargetLang: "de"}),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir, err := DataDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if path != dir {
+			left = append(left, strings.TrimPrefix(path, dir+string(filepath.Separator)))
+		}
+		return err
+	})
+	if len(left) > 0 {
+		t.Errorf("private session left %v in the data directory", left)
+	}
+	if _, ok := LookupCache(translator.Request{Sentence: "Jag är glad.", UserLang: "sv", TargetLang: "de"}); ok {
+		t.Error("private session used the result cache")
+	}
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Personal dictionary file name inside the data directory
+const dictionaryFileName = "dictionary.json"
+
+// maxDictionarySentences is the number of sentences kept for each dictionary entry.
+const maxDictionarySentences = 5
+
+// dictionaryMu serializes changes to the dictionary file within the process.
+var dictionaryMu sync.Mutex
+
+// DictionaryEntry is a word of the personal dictionary, built up from every word
+// analysis of a language.
+type DictionaryEntry struct {
+	Lang         string    `json:"lang"`
+	Lemma        string    `json:"lemma"`           // Dictionary form, or the word as seen if unknown
+	Forms        []string  `json:"forms,omitempty"` // Forms seen in sentences
+	Gloss        string    `json:"gloss,omitempty"`
+	PartOfSpeech string    `json:"pos,omitempty"`
+	Gender       string    `json:"gender,omitempty"`
+	Level        string    `json:"level,omitempty"`
+	Grammar      string    `json:"grammar"`             // Explanation of its first sighting
+	Sentences    []string  `json:"sentences,omitempty"` // Where it was seen, oldest first
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+	Seen         int       `json:"seen"` // Number of analyses it was in
+}
+
+// Matches reports whether the lemma, a form or the gloss of the entry contains query,
+// ignoring case.
+func (e DictionaryEntry) Matches(query string) bool {
+	query = strings.ToLower(query)
+	for _, s := range append([]string{e.Lemma, e.Gloss}, e.Forms...) {
+		if strings.Contains(strings.ToLower(s), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadDictionary reads the personal dictionary, sorted by language and lemma.
+func LoadDictionary() ([]DictionaryEntry, error) {
+	dictionaryMu.Lock()
+	defer dictionaryMu.Unlock()
+	return loadDictionary()
+}
+
+// AddToDictionary adds the analyzed words of a foreign-language sentence in lang to the
+// personal dictionary, or notes another sighting of the words already in it. It
+// returns the number of new entries.
+func AddToDictionary(lang, sentence string, words []translator.WordInfo, seen time.Time) (int, error) {
+	dictionaryMu.Lock()
+	defer dictionaryMu.Unlock()
+	entries, err := loadDictionary()
+	if err != nil {
+		return 0, err
+	}
+	index := make(map[string]int, len(entries))
+	for i, e := range entries {
+		index[dictionaryKey(e.Lang, e.Lemma)] = i
+	}
+
+	added := 0
+	for _, w := range words {
+		form := strings.TrimSpace(w.WordInTargetLang)
+		if form == "" {
+			continue
+		}
+		lemma := cmp.Or(strings.TrimSpace(w.Lemma), form)
+		key := dictionaryKey(lang, lemma)
+		i, ok := index[key]
+		if !ok {
+			entries = append(entries, DictionaryEntry{Lang: lang, Lemma: lemma, Grammar: w.GrammaticalExplanation, FirstSeen: seen})
+			i = len(entries) - 1
+			index[key] = i
+			added++
+		}
+		e := &entries[i]
+		e.Gloss = cmp.Or(e.Gloss, w.Gloss)
+		e.PartOfSpeech = cmp.Or(e.PartOfSpeech, w.PartOfSpeech)
+		e.Gender = cmp.Or(e.Gender, w.Gender)
+		e.Level = cmp.Or(w.Level, e.Level)
+		if !slices.Contains(e.Forms, form) {
+			e.Forms = append(e.Forms, form)
+		}
+		if sentence != "" && !slices.Contains(e.Sentences, sentence) && len(e.Sentences) < maxDictionarySentences {
+			e.Sentences = append(e.Sentences, sentence)
+		}
+		e.LastSeen = seen
+		e.Seen++
+	}
+	return added, saveDictionary(entries)
+}
+
+// dictionaryKey identifies a dictionary entry regardless of capitalization.
+func dictionaryKey(lang, lemma string) string {
+	return lang + "|" + strings.ToLower(lemma)
+}
+
+func loadDictionary() ([]DictionaryEntry, error) {
+	path, err := DataFile(dictionaryFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+	if data, err = open(data); err != nil {
+		return nil, err
+	}
+	var entries []DictionaryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse dictionary: %w", err)
+	}
+	return entries, nil
+}
+
+func saveDictionary(entries []DictionaryEntry) error {
+	if private.Load() {
+		return nil
+	}
+	slices.SortFunc(entries, func(a, b DictionaryEntry) int {
+		return cmp.Or(cmp.Compare(a.Lang, b.Lang), cmp.Compare(strings.ToLower(a.Lemma), strings.ToLower(b.Lemma)))
+	})
+	path, err := DataFile(dictionaryFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dictionary: %w", err)
+	}
+	if data, err = seal(data); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write dictionary: %w", err)
+	}
+	return nil
+}
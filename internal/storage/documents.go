@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Reading positions file name inside the data directory
+const documentsFileName = "documents.json"
+
+// ReadingPosition records where reading of a document stopped.
+type ReadingPosition struct {
+	Sentence int       `json:"sentence"`
+	Total    int       `json:"total"`
+	Time     time.Time `json:"time"`
+}
+
+// loadReadingPositions reads the reading positions of all documents, keyed by absolute path.
+func loadReadingPositions() (map[string]ReadingPosition, error) {
+	path, err := DataFile(documentsFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]ReadingPosition{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reading positions: %w", err)
+	}
+	positions := map[string]ReadingPosition{}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("failed to parse reading positions: %w", err)
+	}
+	return positions, nil
+}
+
+// LoadReadingPosition returns the reading position of the document at path, if any.
+func LoadReadingPosition(path string) (ReadingPosition, error) {
+	positions, err := loadReadingPositions()
+	if err != nil {
+		return ReadingPosition{}, err
+	}
+	return positions[path], nil
+}
+
+// SaveReadingPosition persists the reading position of the document at path.
+func SaveReadingPosition(path string, pos ReadingPosition) error {
+	if private.Load() {
+		return nil
+	}
+	positions, err := loadReadingPositions()
+	if err != nil {
+		return err
+	}
+	positions[path] = pos
+	file, err := DataFile(documentsFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(positions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode reading positions: %w", err)
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write reading positions: %w", err)
+	}
+	return nil
+}
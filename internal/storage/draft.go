@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Draft file name inside the data directory
+const draftFileName = "draft.json"
+
+// draftMu serializes changes to the draft file within the process.
+var draftMu sync.Mutex
+
+// Draft is the sentence that was being typed, kept until it is translated.
+type Draft struct {
+	Sentence string    `json:"sentence"`
+	Saved    time.Time `json:"saved"`
+}
+
+// LoadDraft reads the saved draft, which is empty if there is none.
+func LoadDraft() (Draft, error) {
+	draftMu.Lock()
+	defer draftMu.Unlock()
+	return loadDraft()
+}
+
+// SaveDraft persists the sentence being typed, or removes the draft if it is empty.
+func SaveDraft(draft Draft) error {
+	draftMu.Lock()
+	defer draftMu.Unlock()
+	return saveDraft(draft)
+}
+
+func loadDraft() (Draft, error) {
+	path, err := DataFile(draftFileName)
+	if err != nil {
+		return Draft{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Draft{}, nil
+	}
+	if err != nil {
+		return Draft{}, fmt.Errorf("failed to read draft: %w", err)
+	}
+	if data, err = open(data); err != nil {
+		return Draft{}, err
+	}
+	var draft Draft
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return Draft{}, fmt.Errorf("failed to parse draft: %w", err)
+	}
+	return draft, nil
+}
+
+func saveDraft(draft Draft) error {
+	if private.Load() {
+		return nil
+	}
+	path, err := DataFile(draftFileName)
+	if err != nil {
+		return err
+	}
+	if draft.Sentence == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove draft: %w", err)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(draft, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode draft: %w", err)
+	}
+	if data, err = seal(data); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write draft: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SyncedFiles are the files of the data directory that follow the user across machines:
+// the study data, but not caches, queues or per-machine state.
+var SyncedFiles = []string{historyFileName, vocabFileName, dictionaryFileName, documentsFileName}
+
+// MergeFile merges two versions of a synced file, such as the local one and the one
+// synced from another machine, and returns the merged content. Either may be nil if
+// that side has no such file. Records of either side are kept, so nothing added on
+// one machine is lost; where both sides changed the same record, the newer or larger
+// one wins. Deleting a record only lasts if no other machine still has it. Encrypted
+// files are opened with the passphrase of the session, and the merged file is
+// encrypted if the data directory is.
+func MergeFile(name string, local, remote []byte) ([]byte, error) {
+	switch name {
+	case historyFileName:
+		return mergeHistory(local, remote)
+	case vocabFileName:
+		return mergeJSON(name, local, remote, mergeVocab)
+	case dictionaryFileName:
+		return mergeJSON(name, local, remote, mergeDictionary)
+	case documentsFileName:
+		return mergeJSON(name, local, remote, mergeReadingPositions)
+	default:
+		return nil, fmt.Errorf("%s is not a synced file", name)
+	}
+}
+
+// mergeJSON decodes both versions of a JSON file, merges them with merge and encodes the
+// result as the app writes it.
+func mergeJSON[T any](name string, local, remote []byte, merge func(local, remote T) T) ([]byte, error) {
+	var l, r T
+	plainLocal, err := open(local)
+	if err != nil {
+		return nil, err
+	}
+	plainRemote, err := open(remote)
+	if err != nil {
+		return nil, err
+	}
+	if len(plainLocal) > 0 {
+		if err := json.Unmarshal(plainLocal, &l); err != nil {
+			return nil, fmt.Errorf("failed to parse local %s: %w", name, err)
+		}
+	}
+	if len(plainRemote) > 0 {
+		if err := json.Unmarshal(plainRemote, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse synced %s: %w", name, err)
+		}
+	}
+	data, err := json.MarshalIndent(merge(l, r), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	switch {
+	case bytes.Equal(data, plainLocal):
+		return local, nil
+	case bytes.Equal(data, plainRemote):
+		return remote, nil
+	}
+	return seal(data)
+}
+
+// mergeHistory merges two history files into one ordered by time, keeping each
+// translation once.
+func mergeHistory(local, remote []byte) ([]byte, error) {
+	files := [][]byte{local, remote}
+	seen := map[string]bool{}
+	var entries []HistoryEntry
+	var sides [2][]HistoryEntry // Distinct entries of local and remote, in file order
+	for i, data := range files {
+		inSide := map[string]bool{}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			plain, err := open([]byte(line))
+			if errors.Is(err, ErrLocked) || errors.Is(err, ErrPassphrase) {
+				return nil, err
+			}
+			var entry HistoryEntry
+			if err != nil || json.Unmarshal(plain, &entry) != nil {
+				continue // Skip corrupted lines as LoadHistory does
+			}
+			key := entry.Time.Format(time.RFC3339Nano) + "|" + entry.Original
+			if !inSide[key] {
+				inSide[key] = true
+				sides[i] = append(sides[i], entry)
+			}
+			if !seen[key] {
+				seen[key] = true
+				entries = append(entries, entry)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+	}
+	// A side already holding every entry in order is kept as it is, encrypted or not.
+	for i, side := range sides {
+		if len(side) == len(entries) && slices.IsSortedFunc(side, compareHistory) {
+			return files[i], nil
+		}
+	}
+	slices.SortStableFunc(entries, compareHistory)
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := historyLine(entry)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(line)
+	}
+	return buf.Bytes(), nil
+}
+
+// compareHistory orders history entries by time.
+func compareHistory(a, b HistoryEntry) int {
+	return a.Time.Compare(b.Time)
+}
+
+// mergeVocab keeps every card of either deck once, in the order they were added. A
+// card in both is merged field by field, so that what each machine did to it counts:
+// the schedule of the later review, the higher counters, and the priority and analysis
+// either side gave it.
+func mergeVocab(local, remote []VocabCard) []VocabCard {
+	index := make(map[string]int, len(local))
+	merged := make([]VocabCard, 0, len(local)+len(remote))
+	for _, c := range append(local, remote...) {
+		i, ok := index[vocabKey(c)]
+		if !ok {
+			index[vocabKey(c)] = len(merged)
+			merged = append(merged, c)
+			continue
+		}
+		m := &merged[i]
+		if c.Due.After(m.Due) {
+			m.Due, m.Interval, m.Ease = c.Due, c.Interval, c.Ease
+		}
+		m.Reps = max(m.Reps, c.Reps)
+		m.Lapses = max(m.Lapses, c.Lapses)
+		m.GenderAsked = max(m.GenderAsked, c.GenderAsked)
+		m.GenderMissed = max(m.GenderMissed, c.GenderMissed)
+		m.Priority = m.Priority || c.Priority
+		m.Level = cmp.Or(m.Level, c.Level)
+		if m.PartOfSpeech == "" && c.PartOfSpeech != "" {
+			m.Lemma, m.PartOfSpeech = c.Lemma, c.PartOfSpeech
+			m.Gender, m.Article = c.Gender, c.Article
+		}
+		if c.Added.Before(m.Added) {
+			m.Added = c.Added
+		}
+	}
+	slices.SortStableFunc(merged, func(a, b VocabCard) int { return a.Added.Compare(b.Added) })
+	return merged
+}
+
+// mergeDictionary keeps every word of either dictionary once, combining the sightings
+// of words in both.
+func mergeDictionary(local, remote []DictionaryEntry) []DictionaryEntry {
+	index := make(map[string]int, len(local))
+	merged := make([]DictionaryEntry, 0, len(local)+len(remote))
+	for _, e := range append(local, remote...) {
+		key := dictionaryKey(e.Lang, e.Lemma)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, e)
+			continue
+		}
+		m := &merged[i]
+		m.Gloss = cmp.Or(m.Gloss, e.Gloss)
+		m.PartOfSpeech = cmp.Or(m.PartOfSpeech, e.PartOfSpeech)
+		m.Gender = cmp.Or(m.Gender, e.Gender)
+		m.Level = cmp.Or(m.Level, e.Level)
+		m.Grammar = cmp.Or(m.Grammar, e.Grammar)
+		for _, form := range e.Forms {
+			if !slices.Contains(m.Forms, form) {
+				m.Forms = append(m.Forms, form)
+			}
+		}
+		for _, sentence := range e.Sentences {
+			if !slices.Contains(m.Sentences, sentence) && len(m.Sentences) < maxDictionarySentences {
+				m.Sentences = append(m.Sentences, sentence)
+			}
+		}
+		if e.FirstSeen.Before(m.FirstSeen) {
+			m.FirstSeen = e.FirstSeen
+		}
+		if e.LastSeen.After(m.LastSeen) {
+			m.LastSeen = e.LastSeen
+		}
+		m.Seen = max(m.Seen, e.Seen)
+	}
+	slices.SortFunc(merged, func(a, b DictionaryEntry) int {
+		return cmp.Or(cmp.Compare(a.Lang, b.Lang), cmp.Compare(strings.ToLower(a.Lemma), strings.ToLower(b.Lemma)))
+	})
+	return merged
+}
+
+// mergeReadingPositions keeps the latest reading position of each document.
+func mergeReadingPositions(local, remote map[string]ReadingPosition) map[string]ReadingPosition {
+	merged := make(map[string]ReadingPosition, len(local)+len(remote))
+	for _, positions := range []map[string]ReadingPosition{local, remote} {
+		for path, pos := range positions {
+			if prev, ok := merged[path]; !ok || pos.Time.After(prev.Time) {
+				merged[path] = pos
+			}
+		}
+	}
+	return merged
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeVocabFields(t *testing.T) {
+	added := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	card := VocabCard{Word: "Hund", Lang: "de", Sentence: "Der Hund bellt.", Added: added}
+
+	// One machine reviewed the card, the other quizzed its gender and conjugated it wrong
+	local := card
+	local.Due, local.Interval, local.Ease, local.Reps = added.AddDate(0, 0, 6), 6, 2.5, 2
+	remote := card
+	remote.Due = added.AddDate(0, 0, 1)
+	remote.Priority, remote.GenderAsked, remote.GenderMissed = true, 3, 1
+	remote.Lemma, remote.PartOfSpeech, remote.Gender, remote.Article = "Hund", "noun", "masculine", "der"
+	other := VocabCard{Word: "Katze", Lang: "de", Added: added.Add(time.Hour)}
+
+	want := []VocabCard{{
+		Word: "Hund", Lang: "de", Sentence: "Der Hund bellt.", Added: added,
+		Priority: true, Due: added.AddDate(0, 0, 6), Interval: 6, Ease: 2.5, Reps: 2,
+		Lemma: "Hund", PartOfSpeech: "noun", Gender: "masculine", Article: "der",
+		GenderAsked: 3, GenderMissed: 1,
+	}, other}
+	for _, sides := range [][2][]VocabCard{{{local}, {remote, other}}, {{remote, other}, {local}}} {
+		if got := mergeVocab(sides[0], sides[1]); !reflect.DeepEqual(got, want) {
+			t.Errorf("mergeVocab(%+v, %+v) =\n%+v\nwant\n%+v", sides[0], sides[1], got, want)
+		}
+	}
+}
+
+func TestMergeFileVocab(t *testing.T) {
+	local, err := json.Marshal([]VocabCard{{Word: "Hund", Lang: "de", Reps: 1, Lapses: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := json.Marshal([]VocabCard{{Word: "Hund", Lang: "de", Reps: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := MergeFile(vocabFileName, local, remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cards []VocabCard
+	if err := json.Unmarshal(data, &cards); err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].Reps != 3 || cards[0].Lapses != 2 {
+		t.Errorf("merged deck = %+v, want one card with 3 reps and 2 lapses", cards)
+	}
+}
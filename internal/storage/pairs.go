@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+const (
+	// Recent language pairs file name inside the data directory
+	pairsFileName = "pairs.json"
+
+	// Most language pairs that are remembered
+	maxRecentPairs = 10
+)
+
+// LanguagePair is a language the user knows paired with one they learn.
+type LanguagePair struct {
+	UserLang   string `json:"user_lang"`
+	TargetLang string `json:"target_lang"`
+}
+
+// LoadRecentPairs reads the recently used language pairs, most recent first.
+func LoadRecentPairs() ([]LanguagePair, error) {
+	path, err := DataFile(pairsFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recent pairs: %w", err)
+	}
+	var pairs []LanguagePair
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return nil, fmt.Errorf("failed to parse recent pairs: %w", err)
+	}
+	return pairs, nil
+}
+
+// AddRecentPair moves pair to the front of the recently used language pairs, keeping
+// at most maxRecentPairs.
+func AddRecentPair(pair LanguagePair) error {
+	if private.Load() {
+		return nil
+	}
+	pairs, err := LoadRecentPairs()
+	if err != nil {
+		return err
+	}
+	pairs = slices.DeleteFunc(pairs, func(p LanguagePair) bool { return p == pair })
+	pairs = append([]LanguagePair{pair}, pairs...)
+	if len(pairs) > maxRecentPairs {
+		pairs = pairs[:maxRecentPairs]
+	}
+	file, err := DataFile(pairsFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(pairs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recent pairs: %w", err)
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write recent pairs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package storage
+
+import "sync/atomic"
+
+// private is set for sessions that must leave no trace of the texts translated in
+// them: nothing is written to the data directory and the result cache is not used.
+var private atomic.Bool
+
+// SetPrivate turns the private mode of the session on or off.
+func SetPrivate(on bool) {
+	private.Store(on)
+}
+
+// Private reports whether the session is private.
+func Private() bool {
+	return private.Load()
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Offline queue file name inside the data directory
+const queueFileName = "queue.json"
+
+// queueMu serializes changes to the queue file within the process, since sentences can
+// be queued while earlier ones are being translated.
+var queueMu sync.Mutex
+
+// QueuedSentence is a sentence entered while the provider was offline, kept until it
+// is translated and looked at.
+type QueuedSentence struct {
+	Queued     time.Time          `json:"queued"`
+	UserLang   string             `json:"user_lang"`
+	TargetLang string             `json:"target_lang"`
+	Sentence   string             `json:"sentence"`
+	Result     *translator.Result `json:"result,omitempty"` // Set once translated
+	Err        string             `json:"error,omitempty"`  // Set if translating failed
+}
+
+// Pending reports whether the sentence still waits to be translated.
+func (q QueuedSentence) Pending() bool {
+	return q.Result == nil && q.Err == ""
+}
+
+// Same reports whether q and other are the same queued sentence.
+func (q QueuedSentence) Same(other QueuedSentence) bool {
+	return q.Queued.Equal(other.Queued) && q.Sentence == other.Sentence
+}
+
+// LoadQueue reads the offline queue, oldest first.
+func LoadQueue() ([]QueuedSentence, error) {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+	return loadQueue()
+}
+
+// UpdateQueue replaces the offline queue with what fn makes of it.
+func UpdateQueue(fn func([]QueuedSentence) []QueuedSentence) error {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+	queue, err := loadQueue()
+	if err != nil {
+		return err
+	}
+	return saveQueue(fn(queue))
+}
+
+// AddToQueue appends a sentence to the offline queue.
+func AddToQueue(item QueuedSentence) error {
+	return UpdateQueue(func(queue []QueuedSentence) []QueuedSentence {
+		return append(queue, item)
+	})
+}
+
+func loadQueue() ([]QueuedSentence, error) {
+	path, err := DataFile(queueFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline queue: %w", err)
+	}
+	if data, err = open(data); err != nil {
+		return nil, err
+	}
+	var queue []QueuedSentence
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("failed to parse offline queue: %w", err)
+	}
+	return queue, nil
+}
+
+func saveQueue(queue []QueuedSentence) error {
+	if private.Load() {
+		return nil
+	}
+	path, err := DataFile(queueFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode offline queue: %w", err)
+	}
+	if data, err = seal(data); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	return nil
+}
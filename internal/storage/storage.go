@@ -0,0 +1,362 @@
+// Package storage persists history, the vocab deck, the personal dictionary, profile state, reading
+// positions, recent language pairs, the result cache and the offline queue in the user's data directory.
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+const (
+	// Directory name used under the user's data directory
+	AppName = "translation-tui"
+
+	// File names inside the data directory
+	historyFileName = "history.jsonl"
+	vocabFileName   = "vocab.json"
+	profileFileName = "profiles.json"
+)
+
+// HistoryEntry represents a single completed translation.
+type HistoryEntry struct {
+	Time        time.Time             `json:"time"`
+	UserLang    string                `json:"user_lang"`
+	TargetLang  string                `json:"target_lang"`
+	Original    string                `json:"original"`
+	Translation string                `json:"translation"`
+	Words       []translator.WordInfo `json:"words,omitempty"`
+}
+
+// VocabCard represents a single word saved to the vocabulary deck.
+type VocabCard struct {
+	Word        string    `json:"word"`
+	Analysis    string    `json:"analysis"`
+	Sentence    string    `json:"sentence"`
+	Translation string    `json:"translation"`
+	Lang        string    `json:"lang"`
+	Added       time.Time `json:"added"`
+	Level       string    `json:"level,omitempty"`    // Estimated CEFR level of the word
+	Priority    bool      `json:"priority,omitempty"` // Above the user's level when saved, or conjugated wrong since
+	Due         time.Time `json:"due,omitzero"`       // When the card is next reviewed; due at once if never reviewed
+	Interval    int       `json:"interval,omitempty"` // Days until the review after the last one
+	Ease        float64   `json:"ease,omitempty"`     // Factor the interval grows by, or 0 before the first review
+	Reps        int       `json:"reps,omitempty"`     // Reviews recalled in a row
+	Lapses      int       `json:"lapses,omitempty"`   // Reviews forgotten
+
+	Lemma        string `json:"lemma,omitempty"`   // Dictionary form, such as "sein" for "bin"
+	PartOfSpeech string `json:"pos,omitempty"`     // In the user's language, such as "verb"
+	Gender       string `json:"gender,omitempty"`  // Of a noun, one of the translator.Gender constants
+	Article      string `json:"article,omitempty"` // Of a noun, such as "der"
+
+	GenderAsked  int `json:"gender_asked,omitempty"`  // Times the gender quiz asked for the noun's gender
+	GenderMissed int `json:"gender_missed,omitempty"` // Times it was answered wrong
+}
+
+// ForeignSentence returns the sentence of the card in the language learned: the
+// sentence the word was saved from or its translation, whichever has the word.
+func (c VocabCard) ForeignSentence() string {
+	if strings.Contains(strings.ToLower(c.Sentence), strings.ToLower(c.Word)) {
+		return c.Sentence
+	}
+	return c.Translation
+}
+
+// ProfileState holds choices made at runtime that persist per profile.
+type ProfileState struct {
+	Models     translator.Models     `json:"models"`
+	Generation translator.Generation `json:"generation"`
+}
+
+// DataDir returns the directory used for persistent data, creating it if needed.
+// It follows XDG_DATA_HOME and falls back to ~/.local/share.
+func DataDir() (string, error) {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	dir := filepath.Join(base, AppName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return dir, nil
+}
+
+// DataFile returns the full path of a file inside the data directory.
+func DataFile(name string) (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// AppendHistory appends a translation to the history file.
+func AppendHistory(entry HistoryEntry) error {
+	if private.Load() {
+		return nil
+	}
+	path, err := DataFile(historyFileName)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	line, err := historyLine(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// historyLine encodes a history entry as a line of the history file, encrypted on its
+// own if the data directory is, so that entries can be appended.
+func historyLine(entry HistoryEntry) ([]byte, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode history entry: %w", err)
+	}
+	if data, err = seal(data); err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// writeHistory replaces the history file with the entries.
+func writeHistory(entries []HistoryEntry) error {
+	path, err := DataFile(historyFileName)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := historyLine(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// LoadHistory reads all translations from the history file, oldest first.
+func LoadHistory() ([]HistoryEntry, error) {
+	path, err := DataFile(historyFileName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		data, err := open([]byte(line))
+		if errors.Is(err, ErrLocked) || errors.Is(err, ErrPassphrase) {
+			return nil, err
+		}
+		var entry HistoryEntry
+		if err != nil || json.Unmarshal(data, &entry) != nil {
+			continue // Skip corrupted lines rather than losing the whole history
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return entries, nil
+}
+
+// LoadVocab reads the vocabulary deck.
+func LoadVocab() ([]VocabCard, error) {
+	path, err := DataFile(vocabFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vocab deck: %w", err)
+	}
+	if data, err = open(data); err != nil {
+		return nil, err
+	}
+	var cards []VocabCard
+	if err := json.Unmarshal(data, &cards); err != nil {
+		return nil, fmt.Errorf("failed to parse vocab deck: %w", err)
+	}
+	return cards, nil
+}
+
+// SaveVocab writes the vocabulary deck, replacing the previous file atomically.
+func SaveVocab(cards []VocabCard) error {
+	if private.Load() {
+		return nil
+	}
+	path, err := DataFile(vocabFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cards, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode vocab deck: %w", err)
+	}
+	if data, err = seal(data); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write vocab deck: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write vocab deck: %w", err)
+	}
+	return nil
+}
+
+// AddToVocab merges new cards into the deck, skipping words already saved for the same language
+// but completing their part of speech and gender if they lack them.
+// It returns the number of cards actually added.
+func AddToVocab(newCards []VocabCard) (int, error) {
+	cards, err := LoadVocab()
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]int, len(cards))
+	for i, c := range cards {
+		seen[vocabKey(c)] = i
+	}
+	added, completed := 0, false
+	for _, c := range newCards {
+		if i, ok := seen[vocabKey(c)]; ok {
+			// Cards saved before the analysis gave them keep their schedule, but
+			// gain the dictionary form, part of speech and gender
+			if cards[i].PartOfSpeech == "" && c.PartOfSpeech != "" {
+				cards[i].Lemma, cards[i].PartOfSpeech = c.Lemma, c.PartOfSpeech
+				cards[i].Gender, cards[i].Article = c.Gender, c.Article
+				completed = true
+			}
+			continue
+		}
+		seen[vocabKey(c)] = len(cards)
+		cards = append(cards, c)
+		added++
+	}
+	if added == 0 && !completed {
+		return 0, nil
+	}
+	return added, SaveVocab(cards)
+}
+
+// UpdateVocab replaces the saved cards of the same words and languages as cards, such
+// as after reviewing them. Cards no longer in the deck are left out.
+func UpdateVocab(cards []VocabCard) error {
+	saved, err := LoadVocab()
+	if err != nil {
+		return err
+	}
+	updated := make(map[string]VocabCard, len(cards))
+	for _, c := range cards {
+		updated[vocabKey(c)] = c
+	}
+	for i, c := range saved {
+		if u, ok := updated[vocabKey(c)]; ok {
+			saved[i] = u
+		}
+	}
+	return SaveVocab(saved)
+}
+
+// vocabKey identifies a card by language and case-insensitive word.
+func vocabKey(c VocabCard) string {
+	return c.Lang + "|" + strings.ToLower(c.Word)
+}
+
+// LoadProfileStates reads the persisted state of all profiles.
+func LoadProfileStates() (map[string]ProfileState, error) {
+	path, err := DataFile(profileFileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]ProfileState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile state: %w", err)
+	}
+	states := map[string]ProfileState{}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to parse profile state: %w", err)
+	}
+	return states, nil
+}
+
+// LoadProfileState reads the persisted state of a single profile.
+func LoadProfileState(profile string) (ProfileState, error) {
+	states, err := LoadProfileStates()
+	if err != nil {
+		return ProfileState{}, err
+	}
+	return states[profile], nil
+}
+
+// SaveProfileState persists the state of a single profile, keeping the others intact.
+func SaveProfileState(profile string, state ProfileState) error {
+	states, err := LoadProfileStates()
+	if err != nil {
+		return err
+	}
+	states[profile] = state
+	path, err := DataFile(profileFileName)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode profile state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write profile state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TranscriptDirName is the directory inside the data directory holding the transcripts
+// unless another is configured.
+const TranscriptDirName = "transcripts"
+
+// transcriptMu serializes changes to transcripts within the process.
+var transcriptMu sync.Mutex
+
+// AppendTranscript appends a section to the Markdown transcript at path, starting a new
+// transcript with header. While the data directory is encrypted, each section is
+// encrypted on its own, like the entries of the history, so that sections can be
+// appended; decrypting the data directory makes the transcripts readable again.
+// Private sessions write no transcripts.
+func AppendTranscript(path, header, section string) error {
+	if private.Load() {
+		return nil
+	}
+	if Encrypted() && !unlocked() {
+		return ErrLocked
+	}
+	transcriptMu.Lock()
+	defer transcriptMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer f.Close()
+	text := section
+	if info, err := f.Stat(); err == nil && info.Size() == 0 {
+		text = header + text
+	}
+	data, err := seal([]byte(text))
+	if err != nil {
+		return err
+	}
+	if unlocked() {
+		data = append(data, '\n') // Ends the encrypted line
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return nil
+}
+
+// openTranscript decrypts the sections of a transcript that are encrypted, keeping
+// those written before encrypting as they are.
+func openTranscript(data []byte) ([]byte, error) {
+	var plain []byte
+	for line := range bytes.Lines(data) {
+		if !bytes.HasPrefix(line, []byte(sealedPrefix)) {
+			plain = append(plain, line...)
+			continue
+		}
+		section, err := open(line)
+		if err != nil {
+			return nil, err
+		}
+		plain = append(plain, section...)
+	}
+	return plain, nil
+}
+
+// loadTranscripts reads the transcripts of the data directory by path, decrypted.
+// Transcripts written to another directory are not the data directory's to encrypt.
+func loadTranscripts() (map[string][]byte, error) {
+	dir, err := DataFile(TranscriptDirName)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	transcripts := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transcript: %w", err)
+		}
+		if transcripts[path], err = openTranscript(data); err != nil {
+			return nil, err
+		}
+	}
+	return transcripts, nil
+}
+
+// saveTranscripts writes transcripts again with the passphrase of the session, each
+// encrypted as a whole.
+func saveTranscripts(transcripts map[string][]byte) error {
+	for path, plain := range transcripts {
+		data, err := seal(plain)
+		if err != nil {
+			return err
+		}
+		if unlocked() {
+			data = append(data, '\n')
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o600); err != nil {
+			return fmt.Errorf("failed to write transcript: %w", err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return fmt.Errorf("failed to write transcript: %w", err)
+		}
+	}
+	return nil
+}
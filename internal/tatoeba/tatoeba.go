@@ -0,0 +1,211 @@
+// Package tatoeba searches Tatoeba for example sentences written by people, with their
+// human translations. Results are cached in the data directory, so that examples found
+// once are available offline.
+package tatoeba
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// DefaultURL is the Tatoeba instance that is searched.
+const DefaultURL = "https://tatoeba.org"
+
+// Directory inside the data directory holding cached searches
+const cacheDirName = "tatoeba"
+
+// Largest number of examples kept of a search
+const maxExamples = 10
+
+// Largest response that is read, in bytes
+const maxResponseSize = 4 << 20
+
+// userAgent identifies requests to Tatoeba.
+const userAgent = "translation-tui (https://github.com/brittaao/translation-tui)"
+
+// ErrNotFound is returned when Tatoeba has no translated example of the phrase.
+var ErrNotFound = errors.New("no Tatoeba examples")
+
+// Example is a sentence containing the phrase, with a translation.
+type Example struct {
+	ID          int    `json:"id"`
+	Text        string `json:"text"`
+	Translation string `json:"translation"`
+	URL         string `json:"url"` // Page of the sentence, for citing it
+}
+
+// Examples are the results of a search.
+type Examples struct {
+	Phrase   string    `json:"phrase"`
+	Lang     string    `json:"lang"`
+	UserLang string    `json:"user_lang"`
+	Examples []Example `json:"examples"`
+	Fetched  time.Time `json:"fetched"`
+}
+
+// languageCodes maps the language codes of the app to the ISO 639-3 codes Tatoeba uses.
+var languageCodes = map[string]string{
+	"de": "deu",
+	"en": "eng",
+	"es": "spa",
+	"fr": "fra",
+	"it": "ita",
+	"pt": "por",
+	"sr": "srp",
+	"sv": "swe",
+}
+
+// Search returns sentences in lang containing phrase, translated to userLang, from the
+// cache or the Tatoeba instance at baseURL (DefaultURL if empty). Languages are given by
+// their codes, such as "de".
+func Search(ctx context.Context, client *http.Client, baseURL, phrase, lang, userLang string) (*Examples, error) {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	from, ok := languageCodes[lang]
+	if !ok {
+		return nil, fmt.Errorf("Tatoeba search does not support language %q", lang)
+	}
+	to, ok := languageCodes[userLang]
+	if !ok {
+		return nil, fmt.Errorf("Tatoeba search does not support language %q", userLang)
+	}
+	phrase = strings.TrimSpace(phrase)
+	if e, ok := loadCached(phrase, lang, userLang); ok {
+		return e, nil
+	}
+
+	examples, err := fetch(ctx, client, baseURL, phrase, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("%w of %q", ErrNotFound, phrase)
+	}
+	e := &Examples{Phrase: phrase, Lang: lang, UserLang: userLang, Examples: examples, Fetched: time.Now()}
+	_ = storeCached(e) // The cache is best-effort
+	return e, nil
+}
+
+// fetch queries the search API for sentences containing the exact phrase that have a
+// translation, preferring direct translations over indirect ones.
+func fetch(ctx context.Context, client *http.Client, baseURL, phrase, from, to string) ([]Example, error) {
+	q := url.Values{
+		"query":      {`="` + phrase + `"`},
+		"from":       {from},
+		"to":         {to},
+		"trans_to":   {to},
+		"orphans":    {"no"},
+		"unapproved": {"no"},
+		"sort":       {"relevance"},
+	}
+	base := strings.TrimSuffix(baseURL, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/en/api_v0/search?"+q.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Tatoeba URL: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Tatoeba: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query Tatoeba: %s", resp.Status)
+	}
+
+	type sentence struct {
+		ID   int    `json:"id"`
+		Text string `json:"text"`
+		Lang string `json:"lang"`
+	}
+	var page struct {
+		Results []struct {
+			sentence
+			// Direct translations come first, then translations of translations
+			Translations [][]sentence `json:"translations"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to parse Tatoeba response: %w", err)
+	}
+
+	var examples []Example
+	for _, r := range page.Results {
+		if len(examples) == maxExamples {
+			break
+		}
+	translations:
+		for _, group := range r.Translations {
+			for _, t := range group {
+				if t.Lang == to {
+					examples = append(examples, Example{
+						ID:          r.ID,
+						Text:        r.Text,
+						Translation: t.Text,
+						URL:         base + "/en/sentences/show/" + strconv.Itoa(r.ID),
+					})
+					break translations
+				}
+			}
+		}
+	}
+	return examples, nil
+}
+
+// cachePath returns the cache file of a search for phrase in lang.
+func cachePath(phrase, lang, userLang string) (string, error) {
+	dir, err := storage.DataFile(filepath.Join(cacheDirName, lang+"-"+userLang))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, url.PathEscape(strings.ToLower(phrase))+".json"), nil
+}
+
+// loadCached returns the cached results of a search, if any.
+func loadCached(phrase, lang, userLang string) (*Examples, bool) {
+	path, err := cachePath(phrase, lang, userLang)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var e Examples
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+// storeCached caches the results of a search.
+func storeCached(e *Examples) error {
+	if storage.Private() {
+		return nil
+	}
+	path, err := cachePath(e.Phrase, e.Lang, e.UserLang)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
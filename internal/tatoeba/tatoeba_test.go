@@ -0,0 +1,74 @@
+package tatoeba
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const searchResponse = `{
+  "paging": {"Sentences": {"count": 2}},
+  "results": [
+    {"id": 1, "text": "Wo ist der Bahnhof?", "lang": "deu", "translations": [
+      [{"id": 2, "text": "Where is the station?", "lang": "eng"}],
+      []
+    ]},
+    {"id": 3, "text": "Der Bahnhof ist alt.", "lang": "deu", "translations": [
+      [{"id": 4, "text": "Stationen är gammal.", "lang": "swe"}],
+      [{"id": 5, "text": "The station is old.", "lang": "eng"}]
+    ]},
+    {"id": 6, "text": "Ich gehe zum Bahnhof.", "lang": "deu", "translations": [[], []]}
+  ]
+}`
+
+func TestSearch(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		if q.Get("from") != "deu" || q.Get("to") != "eng" {
+			t.Errorf("query = %v", q)
+		}
+		if q.Get("query") != `="Bahnhof"` {
+			w.Write([]byte(`{"results": []}`))
+			return
+		}
+		w.Write([]byte(searchResponse))
+	}))
+	defer srv.Close()
+
+	e, err := Search(context.Background(), srv.Client(), srv.URL, "Bahnhof", "de", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Example{
+		{ID: 1, Text: "Wo ist der Bahnhof?", Translation: "Where is the station?", URL: srv.URL + "/en/sentences/show/1"},
+		{ID: 3, Text: "Der Bahnhof ist alt.", Translation: "The station is old.", URL: srv.URL + "/en/sentences/show/3"},
+	}
+	if len(e.Examples) != len(want) {
+		t.Fatalf("examples = %+v, want %+v", e.Examples, want)
+	}
+	for i := range want {
+		if e.Examples[i] != want[i] {
+			t.Errorf("example %d = %+v, want %+v", i, e.Examples[i], want[i])
+		}
+	}
+
+	// The second search is served from the cache
+	if _, err := Search(context.Background(), srv.Client(), srv.URL, "Bahnhof", "de", "en"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests, want 1", requests)
+	}
+
+	if _, err := Search(context.Background(), srv.Client(), srv.URL, "Xyz", "de", "en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("no results: got %v, want ErrNotFound", err)
+	}
+	if _, err := Search(context.Background(), srv.Client(), srv.URL, "Bahnhof", "xx", "en"); err == nil {
+		t.Error("unsupported language: got no error")
+	}
+}
@@ -0,0 +1,120 @@
+package translate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+const (
+	// Environment variable holding the Gemini API key
+	EnvAPIKey = "GEMINI_API_KEY"
+
+	// Keyring entry holding the Gemini API key
+	keyringService = storage.AppName
+	keyringUser    = "gemini-api-key"
+
+	// Key file written by the setup screen when no keyring is available
+	apiKeyFileName = "api_key"
+)
+
+// ResolveAPIKeys collects the configured Gemini API keys in order of preference:
+// environment, config file, key file and OS keyring. Duplicates are removed.
+func ResolveAPIKeys(cfg config.GeminiConfig) ([]string, error) {
+	var keys []string
+	add := func(key string) {
+		key = strings.TrimSpace(key)
+		if key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	add(os.Getenv(EnvAPIKey))
+	for _, key := range cfg.APIKeys {
+		add(key)
+	}
+
+	keyFile := cfg.APIKeyFile
+	if keyFile == "" {
+		if path, err := defaultAPIKeyFile(); err == nil {
+			keyFile = path
+		}
+	}
+	if keyFile != "" {
+		fileKeys, err := readAPIKeyFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range fileKeys {
+			add(key)
+		}
+	}
+
+	if key, err := keyring.Get(keyringService, keyringUser); err == nil {
+		add(key)
+	}
+	return keys, nil
+}
+
+// readAPIKeyFile reads one key per line from a file that must not be readable by other users.
+// A missing file is not an error.
+func readAPIKeyFile(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API key file: %w", err)
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		return nil, fmt.Errorf("API key file %s is accessible by other users (mode %v); run: chmod 600 %s", path, info.Mode().Perm(), path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API key file: %w", err)
+	}
+	var keys []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			keys = append(keys, line)
+		}
+	}
+	return keys, nil
+}
+
+// defaultAPIKeyFile returns the location of the key file in the user's config directory.
+func defaultAPIKeyFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, storage.AppName, apiKeyFileName), nil
+}
+
+// StoreAPIKey saves the key in the OS keyring, falling back to the key file.
+// It returns a description of where the key was stored.
+func StoreAPIKey(key string) (string, error) {
+	if err := keyring.Set(keyringService, keyringUser, key); err == nil {
+		return "OS keyring", nil
+	}
+
+	path, err := defaultAPIKeyFile()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(key+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write API key file: %w", err)
+	}
+	return path, nil
+}
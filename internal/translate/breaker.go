@@ -0,0 +1,86 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// breakerThreshold is the number of failed API calls in a row that opens the circuit.
+const breakerThreshold = 3
+
+// ErrOffline is returned instead of calling the provider while the circuit is open.
+var ErrOffline = errors.New("provider unavailable after repeated failures")
+
+// circuit counts failed API calls in a row. Once breakerThreshold calls failed, it is
+// open: requests fail fast with ErrOffline, or are answered by the fallback chain,
+// until a call or a Probe succeeds.
+type circuit struct {
+	mu       sync.Mutex
+	failures int
+}
+
+var breaker circuit
+
+func init() {
+	translator.OnCall(recordCircuit)
+}
+
+// recordCircuit counts a failed call towards opening the circuit and closes it after a
+// successful one. Blocked prompts and cancelled requests say nothing about the provider.
+func recordCircuit(c translator.Call) {
+	if strings.HasPrefix(c.Err, "blocked") || strings.Contains(c.Err, context.Canceled.Error()) {
+		return
+	}
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	if c.Err == "" {
+		breaker.failures = 0
+		return
+	}
+	breaker.failures++
+}
+
+// Offline reports whether the circuit is open.
+func Offline() bool {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	return breaker.failures >= breakerThreshold
+}
+
+// ResetCircuit closes the circuit, letting the next request try the provider again.
+func ResetCircuit() {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	breaker.failures = 0
+}
+
+// checkCircuit returns ErrOffline while the circuit is open.
+func checkCircuit() error {
+	if Offline() {
+		return ErrOffline
+	}
+	return nil
+}
+
+// Probe checks whether the provider is reachable again with a short translation,
+// closing the circuit if it is.
+func Probe(ctx context.Context, cfg config.Config) error {
+	provider, _, err := NewProviders(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	req := translator.Request{Sentence: "Hello", UserLang: "en", TargetLang: "de"}
+	_, err = translator.RunStep(ctx, cfg.Timeout, "Checking the connection", nil, func(ctx context.Context) (*translator.TranslationStep, error) {
+		return provider.Translate(ctx, req)
+	})
+	if err != nil {
+		return err
+	}
+	ResetCircuit()
+	return nil
+}
@@ -0,0 +1,77 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Number of API calls kept for the debug view
+const maxDebugRecords = 50
+
+// debugRecorder keeps the most recent API calls and optionally appends them to a log file.
+// It is shared by all providers, which run outside the Bubble Tea event loop.
+type debugRecorder struct {
+	mu      sync.Mutex
+	enabled bool
+	records []translator.Call
+	logFile *os.File
+}
+
+// debugLog is the global recorder; recording is off unless enabled via --debug or F12.
+var debugLog = &debugRecorder{}
+
+func init() {
+	translator.OnCall(recordDebug)
+}
+
+// EnableDebug turns on recording, appending records to logPath as JSON lines if it is set.
+func EnableDebug(logPath string) error {
+	debugLog.mu.Lock()
+	defer debugLog.mu.Unlock()
+	debugLog.enabled = true
+	if logPath == "" || debugLog.logFile != nil {
+		return nil
+	}
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open debug log: %w", err)
+	}
+	debugLog.logFile = f
+	return nil
+}
+
+// DebugEnabled reports whether API calls are being recorded.
+func DebugEnabled() bool {
+	debugLog.mu.Lock()
+	defer debugLog.mu.Unlock()
+	return debugLog.enabled
+}
+
+// recordDebug stores a record if recording is enabled.
+func recordDebug(c translator.Call) {
+	debugLog.mu.Lock()
+	defer debugLog.mu.Unlock()
+	if !debugLog.enabled {
+		return
+	}
+	debugLog.records = append(debugLog.records, c)
+	if len(debugLog.records) > maxDebugRecords {
+		debugLog.records = debugLog.records[len(debugLog.records)-maxDebugRecords:]
+	}
+	if debugLog.logFile != nil {
+		if data, err := json.Marshal(c); err == nil {
+			debugLog.logFile.Write(append(data, '\n'))
+		}
+	}
+}
+
+// DebugRecords returns a copy of the recorded API calls, oldest first.
+func DebugRecords() []translator.Call {
+	debugLog.mu.Lock()
+	defer debugLog.mu.Unlock()
+	return append([]translator.Call(nil), debugLog.records...)
+}
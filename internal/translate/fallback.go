@@ -0,0 +1,49 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// runFallback tries the configured fallback chain after the primary pipeline failed:
+// first a cached result for the same sentence, then LibreTranslate.
+// The returned result is labeled as degraded output.
+func runFallback(ctx context.Context, cfg config.Config, req translator.Request, primaryErr error, progress translator.Progress) (Result, bool) {
+	if cfg.Fallback.Cache {
+		if cached, ok := storage.LookupCache(req); ok {
+			return Result{
+				Result:   cached.Result(),
+				Degraded: fmt.Sprintf("cached result from %s (%v)", cached.Models.Translation, primaryErr),
+				Cached:   true,
+			}, true
+		}
+	}
+
+	if cfg.Fallback.LibreTranslateURL != "" {
+		httpClient, err := NewHTTPClient(cfg.Network)
+		if err != nil {
+			return Result{}, false
+		}
+		libre := translator.NewLibreTranslateProvider(cfg.Fallback.LibreTranslateURL, cfg.Fallback.LibreTranslateAPIKey, httpClient)
+		redacted, masked := redactRequest(cfg, req, translator.Masked{})
+		step, err := translator.RunStep(ctx, cfg.Timeout, "Trying LibreTranslate", progress, func(ctx context.Context) (*translator.TranslationStep, error) {
+			return libre.Translate(ctx, redacted)
+		})
+		if err == nil {
+			return Result{
+				Result: translator.Result{
+					Original:    masked.Expand(step.CleanedSentence),
+					Translation: masked.Expand(step.Translation),
+					Models:      translator.Models{Translation: libre.TranslationModel()},
+				},
+				Degraded: fmt.Sprintf("basic LibreTranslate translation without analysis (%v)", primaryErr),
+			}, true
+		}
+	}
+
+	return Result{}, false
+}
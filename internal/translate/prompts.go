@@ -0,0 +1,62 @@
+package translate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// File names of the prompt templates inside the prompts directory
+const (
+	translationPromptFile = "translation.tmpl"
+	analysisPromptFile    = "analysis.tmpl"
+)
+
+// LoadPrompts reads the prompt templates from the configured prompts directory and adds
+// the system instruction and examples of the config. Missing templates are left nil, so
+// the built-in prompts are used for them.
+func LoadPrompts(cfg config.Config) (translator.Prompts, error) {
+	dir := cfg.PromptsDir
+	if dir == "" {
+		var err error
+		if dir, err = config.DefaultPromptsDir(); err != nil {
+			return translator.Prompts{}, err
+		}
+	}
+	translation, err := loadPrompt(filepath.Join(dir, translationPromptFile))
+	if err != nil {
+		return translator.Prompts{}, err
+	}
+	analysis, err := loadPrompt(filepath.Join(dir, analysisPromptFile))
+	if err != nil {
+		return translator.Prompts{}, err
+	}
+	return translator.Prompts{
+		Translation:         translation,
+		Analysis:            analysis,
+		System:              cfg.SystemInstruction,
+		TranslationExamples: cfg.TranslationExamples,
+		AnalysisExamples:    cfg.AnalysisExamples,
+	}, nil
+}
+
+// loadPrompt parses the template at path, or returns nil if there is none.
+func loadPrompt(path string) (*template.Template, error) {
+	text, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt template: %w", err)
+	}
+	tmpl, err := translator.ParsePrompt(filepath.Base(path), string(text))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return tmpl, nil
+}
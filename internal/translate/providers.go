@@ -0,0 +1,180 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"google.golang.org/genai"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+const (
+	// Environment variables holding provider API keys
+	envDeepLAPIKey  = "DEEPL_API_KEY"
+	envOpenAIAPIKey = "OPENAI_API_KEY"
+
+	// replayKey is the placeholder Gemini API key used when replaying without a real key.
+	replayKey = "replay"
+)
+
+// NewProviders creates the providers for both pipeline steps according to the config.
+// Providers that only translate fall back to Gemini for word analysis.
+func NewProviders(ctx context.Context, cfg config.Config) (translator.TranslationProvider, translator.AnalysisProvider, error) {
+	httpClient, err := NewHTTPClient(cfg.Network)
+	if err != nil {
+		return nil, nil, err
+	}
+	prompts, err := LoadPrompts(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, lang := range cfg.Languages {
+		translator.RegisterLanguage(lang)
+	}
+	translator.SetRateLimit(cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.TokensPerMinute)
+
+	switch cfg.Provider {
+	case "", translator.ProviderGemini:
+		gemini, err := newGeminiProvider(ctx, cfg, httpClient)
+		if err != nil {
+			return nil, nil, err
+		}
+		gemini.SetPrompts(prompts)
+		return gemini, gemini, nil
+	case translator.ProviderDeepL:
+		apiKey := cfg.DeepL.APIKey
+		if apiKey == "" {
+			apiKey = os.Getenv(envDeepLAPIKey)
+		}
+		if apiKey == "" {
+			return nil, nil, fmt.Errorf("DeepL API key not set (use [deepl] api_key or %s)", envDeepLAPIKey)
+		}
+		deepl, err := translator.NewDeepLProvider(apiKey, cfg.DeepL.Endpoint, httpClient)
+		if err != nil {
+			return nil, nil, err
+		}
+		gemini, err := newGeminiProvider(ctx, cfg, httpClient)
+		if err != nil {
+			return nil, nil, err
+		}
+		gemini.SetPrompts(prompts)
+		return deepl, gemini, nil
+	case translator.ProviderOpenAI:
+		if cfg.OpenAI.Model == "" {
+			return nil, nil, fmt.Errorf("no model configured for the openai provider (set [openai] model)")
+		}
+		apiKey := cfg.OpenAI.APIKey
+		if apiKey == "" {
+			apiKey = os.Getenv(envOpenAIAPIKey)
+		}
+		openai, err := translator.NewOpenAIProvider(translator.OpenAIOptions{
+			BaseURL:          cfg.OpenAI.BaseURL,
+			APIKey:           apiKey,
+			Model:            cfg.OpenAI.Model,
+			AnalysisModel:    cfg.OpenAI.AnalysisModel,
+			StructuredOutput: cfg.OpenAI.StructuredOutput,
+			Prompts:          prompts,
+			Generation:       &cfg.Generation,
+		}, httpClient)
+		if err != nil {
+			return nil, nil, err
+		}
+		return openai, openai, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown provider %q", cfg.Provider)
+	}
+}
+
+// ConfiguredModels returns the models the configured providers will use for both steps.
+func ConfiguredModels(cfg config.Config) translator.Models {
+	switch cfg.Provider {
+	case translator.ProviderDeepL:
+		return translator.Models{Translation: translator.ProviderDeepL, Analysis: cfg.Models.Analysis}
+	case translator.ProviderOpenAI:
+		analysis := cfg.OpenAI.AnalysisModel
+		if analysis == "" {
+			analysis = cfg.OpenAI.Model
+		}
+		return translator.Models{Translation: cfg.OpenAI.Model, Analysis: analysis}
+	}
+	return cfg.Models
+}
+
+// newGeminiProvider creates a Gemini provider using the configured models and fallback
+// models, sampling parameters, safety threshold and thinking budgets.
+func newGeminiProvider(ctx context.Context, cfg config.Config, httpClient *http.Client) (*translator.GeminiProvider, error) {
+	threshold, err := translator.ParseSafetyThreshold(cfg.Gemini.SafetyThreshold)
+	if err != nil {
+		return nil, err
+	}
+	clients, err := NewClients(ctx, cfg, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	generators := make([]translator.ContentGenerator, len(clients))
+	for i, client := range clients {
+		generators[i] = client.Models
+	}
+	gemini := translator.NewGeminiProvider(generators, cfg.Models)
+	gemini.SetFallbackModels(cfg.Fallback.Models)
+	gemini.SetSettings(translator.GeminiSettings{
+		Generation:                cfg.Generation,
+		SafetyThreshold:           threshold,
+		TranslationThinkingBudget: cfg.Gemini.TranslationThinkingBudget,
+		AnalysisThinkingBudget:    cfg.Gemini.AnalysisThinkingBudget,
+	})
+	return gemini, nil
+}
+
+// NewClients creates Gemini API clients, one per configured API key, or a single client
+// authenticating through Vertex AI with application default credentials.
+func NewClients(ctx context.Context, cfg config.Config, httpClient *http.Client) ([]*genai.Client, error) {
+	switch cfg.Gemini.Backend {
+	case "", config.BackendAPIKey:
+		keys, err := ResolveAPIKeys(cfg.Gemini)
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 0 && cfg.Network.ReplayDir != "" {
+			keys = []string{replayKey} // Recorded responses don't need a real key
+		}
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("no Gemini API key found (set %s, [gemini] api_keys, or run the setup screen)", EnvAPIKey)
+		}
+		clients := make([]*genai.Client, 0, len(keys))
+		for _, key := range keys {
+			client, err := newClient(ctx, &genai.ClientConfig{Backend: genai.BackendGeminiAPI, APIKey: key, HTTPClient: httpClient})
+			if err != nil {
+				return nil, err
+			}
+			clients = append(clients, client)
+		}
+		return clients, nil
+	case config.BackendVertexAI:
+		client, err := newClient(ctx, &genai.ClientConfig{
+			Backend:    genai.BackendVertexAI,
+			Project:    cfg.Gemini.Project,
+			Location:   cfg.Gemini.Location,
+			HTTPClient: httpClient,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []*genai.Client{client}, nil
+	default:
+		return nil, fmt.Errorf("unknown gemini backend %q (use %q or %q)", cfg.Gemini.Backend, config.BackendAPIKey, config.BackendVertexAI)
+	}
+}
+
+// newClient creates a single Gemini API client.
+func newClient(ctx context.Context, cc *genai.ClientConfig) (*genai.Client, error) {
+	client, err := genai.NewClient(ctx, cc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return client, nil
+}
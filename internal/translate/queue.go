@@ -0,0 +1,73 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// ProcessQueue translates the sentences of the offline queue that are still pending,
+// storing each result in the queue and the history. It returns how many were
+// translated and how many failed.
+func ProcessQueue(ctx context.Context, cfg config.Config) (done, failed int, err error) {
+	queue, err := storage.LoadQueue()
+	if err != nil {
+		return 0, 0, err
+	}
+	var pending []storage.QueuedSentence
+	for _, item := range queue {
+		if item.Pending() {
+			pending = append(pending, item)
+		}
+	}
+	if len(pending) == 0 {
+		return 0, 0, nil
+	}
+
+	reqs := make([]translator.Request, len(pending))
+	for i, item := range pending {
+		reqs[i] = translator.Request{Sentence: item.Sentence, UserLang: item.UserLang, TargetLang: item.TargetLang}
+	}
+	results := make([]Result, len(pending))
+	errs := make([]error, len(pending))
+	RunBatch(ctx, cfg, reqs, func(i int, result Result, err error) {
+		results[i], errs[i] = result, err
+	})
+
+	var recordErrs []error
+	for i := range pending {
+		switch {
+		case errors.Is(errs[i], ErrOffline):
+			continue // Still offline; stays pending
+		case errs[i] != nil:
+			pending[i].Err = errs[i].Error()
+			failed++
+		default:
+			pending[i].Result = &results[i].Result
+			done++
+			recordErrs = append(recordErrs, Record(ctx, cfg, storage.HistoryEntry{
+				Time:        time.Now(),
+				UserLang:    pending[i].UserLang,
+				TargetLang:  pending[i].TargetLang,
+				Original:    results[i].Original,
+				Translation: results[i].Translation,
+				Words:       results[i].Words,
+			}))
+		}
+	}
+	err = storage.UpdateQueue(func(queue []storage.QueuedSentence) []storage.QueuedSentence {
+		for i, item := range queue {
+			for _, p := range pending {
+				if item.Same(p) {
+					queue[i] = p
+				}
+			}
+		}
+		return queue
+	})
+	return done, failed, errors.Join(append(recordErrs, err)...)
+}
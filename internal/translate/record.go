@@ -0,0 +1,39 @@
+package translate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/export"
+	"github.com/brittaao/translation-tui/internal/hook"
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// Record appends a completed translation to the history and passes it to the configured
+// hooks, its analyzed words to the personal dictionary if it is kept, and the
+// translation to the transcript of the day if one is written. The hooks run even if the
+// history cannot be written. Private sessions record nothing.
+func Record(ctx context.Context, cfg config.Config, entry storage.HistoryEntry) error {
+	if cfg.Private {
+		return nil
+	}
+	err := storage.AppendHistory(entry)
+	if cfg.Dictionary && len(entry.Words) > 0 {
+		_, dictErr := storage.AddToDictionary(entry.TargetLang, export.ForeignSentence(entry), entry.Words, entry.Time)
+		err = errors.Join(err, dictErr)
+	}
+	if cfg.Transcript {
+		err = errors.Join(err, export.AppendTranscript(cfg.TranscriptDir, entry, cfg.Level))
+	}
+	if len(cfg.Hooks.Command) == 0 && cfg.Hooks.Webhook == "" {
+		return err
+	}
+	client, clientErr := NewHTTPClient(cfg.Network)
+	if clientErr != nil {
+		return errors.Join(err, clientErr)
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	return errors.Join(err, hook.Run(ctx, client, cfg.Hooks, entry))
+}
@@ -0,0 +1,158 @@
+package translate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/brittaao/translation-tui/internal/config"
+)
+
+// Query parameters carrying credentials, left out of fixtures and fixture names
+var secretParams = []string{"key", "auth_key", "api_key"}
+
+// replayTransport records HTTP exchanges to fixture files or answers requests from them,
+// so the app can run offline for demos, screenshots and tests.
+type replayTransport struct {
+	dir    string
+	record bool
+	next   http.RoundTripper
+}
+
+// fixture is a recorded request and its response, stored as one JSON file.
+type fixture struct {
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	RequestBody  string `json:"request_body,omitempty"`
+	Status       int    `json:"status"`
+	ContentType  string `json:"content_type,omitempty"`
+	ResponseBody string `json:"response_body"`
+}
+
+// newReplayTransport wraps next according to the config: replaying takes precedence
+// over recording, and next is returned unchanged if neither is enabled.
+func newReplayTransport(cfg config.NetworkConfig, next http.RoundTripper) http.RoundTripper {
+	switch {
+	case cfg.ReplayDir != "":
+		return &replayTransport{dir: cfg.ReplayDir}
+	case cfg.RecordDir != "":
+		return &replayTransport{dir: cfg.RecordDir, record: true, next: next}
+	default:
+		return next
+	}
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	rawURL := redactURL(req.URL)
+	path := filepath.Join(t.dir, fixtureName(req.Method, rawURL, body))
+
+	if !t.record {
+		return replayFixture(req, path)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	f := fixture{
+		Method:       req.Method,
+		URL:          rawURL,
+		RequestBody:  string(body),
+		Status:       resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ResponseBody: string(respBody),
+	}
+	if err := saveFixture(path, f); err != nil {
+		slog.Warn("failed to record response", "path", path, "error", err)
+	}
+	return resp, nil
+}
+
+// replayFixture answers req with the response recorded at path.
+func replayFixture(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no recorded response for %s %s (record one with %s)", req.Method, redactURL(req.URL), config.EnvRecord)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	slog.Debug("replaying response", "path", path, "status", f.Status)
+
+	header := make(http.Header)
+	if f.ContentType != "" {
+		header.Set("Content-Type", f.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(f.ResponseBody))),
+		ContentLength: int64(len(f.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// saveFixture writes a recorded exchange to path, creating the directory if needed.
+func saveFixture(path string, f fixture) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// fixtureName derives a stable file name from the request, so the same prompt
+// sent to the same endpoint replays the same response.
+func fixtureName(method, rawURL string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", method, rawURL)
+	h.Write(body)
+	return fmt.Sprintf("%x.json", h.Sum(nil)[:8])
+}
+
+// redactURL returns the URL without query parameters carrying credentials.
+func redactURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	for _, param := range secretParams {
+		query.Del(param)
+	}
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
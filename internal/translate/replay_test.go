@@ -0,0 +1,66 @@
+package translate
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/brittaao/translation-tui/internal/config"
+)
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"echo": "` + string(body) + `"}`))
+	}))
+
+	post := func(client *http.Client, body string) (string, error) {
+		resp, err := client.Post(srv.URL+"/v1/generate?key=secret", "application/json", strings.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		return string(data), err
+	}
+
+	recorder, err := NewHTTPClient(config.NetworkConfig{RecordDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := post(recorder, "hola")
+	if err != nil {
+		t.Fatalf("recording failed: %v", err)
+	}
+	srv.Close()
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("recorded %d fixtures, want 1", len(files))
+	}
+	data, _ := os.ReadFile(dir + "/" + files[0].Name())
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("fixture contains the API key: %s", data)
+	}
+
+	replayer, err := NewHTTPClient(config.NetworkConfig{ReplayDir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := post(replayer, "hola")
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if replayed != recorded {
+		t.Errorf("replayed %q, want %q", replayed, recorded)
+	}
+
+	if _, err := post(replayer, "adiós"); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("unrecorded request: err = %v", err)
+	}
+}
@@ -0,0 +1,414 @@
+// Package translate connects the translation pipeline to the app: it creates the
+// configured providers and HTTP client, resolves API keys, and applies caching,
+// fallbacks and debug recording.
+package translate
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/frequency"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Result is a pipeline result as shown by the app.
+type Result struct {
+	translator.Result
+	Degraded      string // Non-empty when produced by a fallback, describing why
+	Cached        bool   // Served from the result cache
+	ModelFallback string // Non-empty when a fallback model replaced an unavailable one, describing which
+}
+
+// Run performs translation and word analysis with the configured providers.
+// If the pipeline fails, the configured fallback chain is tried before returning the error.
+// Successful results are cached for the fallback.
+func Run(ctx context.Context, cfg config.Config, req translator.Request, progress translator.Progress) (Result, error) {
+	result, err := runPipeline(ctx, cfg, req, progress)
+	if err != nil {
+		slog.Error("translation failed", "user_lang", req.UserLang, "target_lang", req.TargetLang, "error", err)
+		if fallback, ok := runFallback(ctx, cfg, req, err, progress); ok {
+			slog.Warn("using fallback result", "reason", fallback.Degraded)
+			rankWords(fallback.Words, req.TargetLang)
+			return fallback, nil
+		}
+		return Result{}, err
+	}
+
+	if cfg.Fallback.Cache {
+		_ = storage.StoreCache(req, result) // The cache is best-effort
+	}
+	rankWords(result.Words, req.TargetLang)
+	return Result{Result: result, ModelFallback: describeModelFallback(ConfiguredModels(cfg), result.Models)}, nil
+}
+
+// describeModelFallback describes which configured models were replaced by fallback
+// models, or returns "" if none were.
+func describeModelFallback(configured, used translator.Models) string {
+	if used == configured {
+		return ""
+	}
+	if configured.Translation == configured.Analysis && used.Translation == used.Analysis {
+		return fmt.Sprintf("%s is unavailable, using %s", configured.Translation, used.Translation)
+	}
+	var replaced []string
+	if used.Translation != configured.Translation {
+		replaced = append(replaced, fmt.Sprintf("%s is unavailable, translating with %s", configured.Translation, used.Translation))
+	}
+	if used.Analysis != configured.Analysis {
+		replaced = append(replaced, fmt.Sprintf("%s is unavailable, analyzing with %s", configured.Analysis, used.Analysis))
+	}
+	return strings.Join(replaced, "; ")
+}
+
+// RunBatch runs Run for each request, with at most cfg.Concurrency requests at once.
+// done is called from the worker goroutines as each request finishes.
+func RunBatch(ctx context.Context, cfg config.Config, reqs []translator.Request, done func(i int, result Result, err error)) {
+	runConcurrently(len(reqs), cfg.Concurrency, func(i int) {
+		result, err := Run(ctx, cfg, reqs[i], nil)
+		done(i, result, err)
+	})
+}
+
+// TranslateBatch translates each request without word analysis or fallbacks, with at most
+// cfg.Concurrency requests at once. masks, if not nil, hold the spans already masked in
+// the sentence of each request, such as placeholders; personal data is masked after them
+// and they are restored in the translation, which fails if it lost one. It returns the
+// translations in request order; failed requests have an empty translation and their
+// error in errs.
+func TranslateBatch(ctx context.Context, cfg config.Config, reqs []translator.Request, masks []translator.Masked) (translations []string, errs []error) {
+	translations = make([]string, len(reqs))
+	errs = make([]error, len(reqs))
+	provider, _, err := NewProviders(ctx, cfg)
+	if err == nil {
+		err = checkCircuit()
+	}
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return translations, errs
+	}
+	runConcurrently(len(reqs), cfg.Concurrency, func(i int) {
+		var protected translator.Masked
+		if masks != nil {
+			protected = masks[i]
+		}
+		req, masked := redactRequest(cfg, reqs[i], protected)
+		step, err := translator.RunStep(ctx, cfg.Timeout, "Translating", nil, func(ctx context.Context) (*translator.TranslationStep, error) {
+			return provider.Translate(ctx, req)
+		})
+		if err != nil {
+			errs[i] = err
+			return
+		}
+		if len(protected.Spans) == 0 {
+			translations[i] = masked.Expand(step.Translation)
+			return
+		}
+		translations[i], errs[i] = masked.Restore(step.Translation)
+	})
+	return translations, errs
+}
+
+// analysisProviderAs returns the configured analysis provider as T, the interface of an
+// optional feature such as conversations.
+func analysisProviderAs[T any](ctx context.Context, cfg config.Config, feature string) (T, error) {
+	var zero T
+	if err := checkCircuit(); err != nil {
+		return zero, err
+	}
+	_, analysisProvider, err := NewProviders(ctx, cfg)
+	if err != nil {
+		return zero, err
+	}
+	p, ok := analysisProvider.(T)
+	if !ok {
+		return zero, fmt.Errorf("provider %s does not support %s", cfg.Provider, feature)
+	}
+	return p, nil
+}
+
+// Chat returns the reply of the configured analysis model to a conversation. langs are
+// the languages the conversation is held in.
+func Chat(ctx context.Context, cfg config.Config, system string, history []translator.Message, langs ...string) (string, error) {
+	chat, err := analysisProviderAs[translator.ChatProvider](ctx, cfg, "conversations")
+	if err != nil {
+		return "", err
+	}
+	var masked translator.Masked
+	if cfg.Redact {
+		system = masked.Redact(system, langs...)
+		history = slices.Clone(history)
+		for i := range history {
+			history[i].Text = masked.Redact(history[i].Text, langs...)
+		}
+	}
+	reply, err := translator.RunStep(ctx, cfg.Timeout, "Replying", nil, func(ctx context.Context) (string, error) {
+		return chat.Chat(ctx, system, history)
+	})
+	return masked.Expand(reply), err
+}
+
+// Compare explains the difference between two phrasings in the target language of req
+// with the configured analysis model.
+func Compare(ctx context.Context, cfg config.Config, first, second string, req translator.Request) (*translator.Comparison, error) {
+	comparer, err := analysisProviderAs[translator.ComparisonProvider](ctx, cfg, "comparisons")
+	if err != nil {
+		return nil, err
+	}
+	var masked translator.Masked
+	if cfg.Redact {
+		first = masked.Redact(first, req.UserLang, req.TargetLang)
+		second = masked.Redact(second, req.UserLang, req.TargetLang)
+	}
+	comparison, err := translator.RunStep(ctx, cfg.Timeout, "Comparing", nil, func(ctx context.Context) (*translator.Comparison, error) {
+		return comparer.Compare(ctx, first, second, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comparison, masked.ExpandAll(comparison)
+}
+
+// Simplify rewrites a target-language sentence at a simpler CEFR level, or paraphrases it
+// if level is empty, with the configured analysis model.
+func Simplify(ctx context.Context, cfg config.Config, sentence, level string, req translator.Request) (*translator.Simplification, error) {
+	simplifier, err := analysisProviderAs[translator.SimplificationProvider](ctx, cfg, "simplification")
+	if err != nil {
+		return nil, err
+	}
+	var masked translator.Masked
+	if cfg.Redact {
+		sentence = masked.Redact(sentence, req.TargetLang)
+	}
+	simplification, err := translator.RunStep(ctx, cfg.Timeout, "Simplifying", nil, func(ctx context.Context) (*translator.Simplification, error) {
+		return simplifier.Simplify(ctx, sentence, level, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return simplification, masked.ExpandAll(simplification)
+}
+
+// DetectLanguage identifies the language of text with the configured analysis model.
+// langs are the languages text may be in.
+func DetectLanguage(ctx context.Context, cfg config.Config, text string, langs ...string) (*translator.Detection, error) {
+	detector, err := analysisProviderAs[translator.DetectionProvider](ctx, cfg, "language detection")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Redact {
+		var masked translator.Masked
+		text = masked.Redact(text, langs...)
+	}
+	return translator.RunStep(ctx, cfg.Timeout, "Detecting language", nil, func(ctx context.Context) (*translator.Detection, error) {
+		return detector.DetectLanguage(ctx, text)
+	})
+}
+
+// redactRequest masks the personal data in the sentence of req if the config asks for
+// it, returning the request to send and what restores the data. masked holds the spans
+// already masked in the sentence, whose tokens the personal data continues.
+func redactRequest(cfg config.Config, req translator.Request, masked translator.Masked) (translator.Request, translator.Masked) {
+	masked.Spans = slices.Clone(masked.Spans)
+	if cfg.Redact {
+		req.Sentence = masked.Redact(req.Sentence, req.UserLang, req.TargetLang)
+		req.Glossary = nil
+	}
+	return req, masked
+}
+
+// runConcurrently calls fn for 0..n-1 with at most limit calls running at once.
+func runConcurrently(n, limit int, fn func(i int)) {
+	sem := make(chan struct{}, max(1, limit))
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// rankWords sets the frequency rank of the analyzed words from the installed frequency
+// list of the language, if there is one. Multi-word expressions are not ranked.
+func rankWords(words []translator.WordInfo, lang string) {
+	list := frequency.Lookup(lang)
+	if list == nil {
+		return
+	}
+	for i := range words {
+		if !words[i].Grouped() {
+			words[i].Rank = list.Rank(words[i].WordInTargetLang)
+		}
+	}
+}
+
+// AnalyzeWords analyzes the chosen words of a foreign-language sentence, or all of its
+// words if req.Words is empty, with the configured analysis provider.
+func AnalyzeWords(ctx context.Context, cfg config.Config, foreignSentence string, req translator.Request) ([]translator.WordInfo, error) {
+	p, err := newPipeline(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	words, err := p.Analyze(ctx, foreignSentence, withDefaults(cfg, req), nil)
+	if err != nil {
+		return nil, err
+	}
+	rankWords(words, req.TargetLang)
+	return words, nil
+}
+
+// runPipeline performs the translation and word analysis steps with the configured providers.
+func runPipeline(ctx context.Context, cfg config.Config, req translator.Request, progress translator.Progress) (translator.Result, error) {
+	p, err := newPipeline(ctx, cfg)
+	if err != nil {
+		return translator.Result{}, err
+	}
+	return p.Run(ctx, withDefaults(cfg, req), progress)
+}
+
+// newPipeline returns the pipeline of the configured providers, or ErrOffline while the
+// circuit is open.
+func newPipeline(ctx context.Context, cfg config.Config) (translator.Pipeline, error) {
+	if err := checkCircuit(); err != nil {
+		return translator.Pipeline{}, err
+	}
+	translationProvider, analysisProvider, err := NewProviders(ctx, cfg)
+	if err != nil {
+		return translator.Pipeline{}, err
+	}
+	return translator.Pipeline{
+		Translator: translationProvider,
+		Analyzer:   analysisProvider,
+		Timeout:    cfg.Timeout,
+
+		DoNotTranslate: cfg.DoNotTranslate,
+		SkipAnalysis:   cfg.AnalyzeOnDemand,
+		Redact:         cfg.Redact,
+	}, nil
+}
+
+// withDefaults fills in the options of req left empty from the config.
+func withDefaults(cfg config.Config, req translator.Request) translator.Request {
+	if req.Level == "" {
+		req.Level = cfg.Level
+	}
+	if req.Formality == "" {
+		req.Formality = cfg.Formality
+	}
+	if req.Depth == "" {
+		req.Depth = cfg.Depth
+	}
+	if cfg.Etymology && req.Cognates == nil {
+		for _, lang := range append([]string{req.UserLang}, cfg.KnownLanguages...) {
+			if lang != "" && lang != req.TargetLang && !slices.Contains(req.Cognates, lang) {
+				req.Cognates = append(req.Cognates, lang)
+			}
+		}
+	}
+	return req
+}
+
+// GradeNumber grades answer as a number, date, price or time of the kind written out in
+// the target language of req, with the configured analysis model. The figure and answer
+// are sent as they are: masking their digits would leave nothing to grade.
+func GradeNumber(ctx context.Context, cfg config.Config, figure, kind, answer string, req translator.Request) (*translator.NumberGrade, error) {
+	grader, err := analysisProviderAs[translator.NumberGradingProvider](ctx, cfg, "number grading")
+	if err != nil {
+		return nil, err
+	}
+	return translator.RunStep(ctx, cfg.Timeout, "Grading", nil, func(ctx context.Context) (*translator.NumberGrade, error) {
+		return grader.GradeNumber(ctx, figure, kind, answer, req)
+	})
+}
+
+// WordDetails asks the configured analysis model for details of a word of the target
+// language of req as used in sentence. Related words that are the word itself are left
+// out, as are empty phrases.
+func WordDetails(ctx context.Context, cfg config.Config, word, sentence string, req translator.Request) (*translator.WordDetails, error) {
+	detailer, err := analysisProviderAs[translator.WordDetailsProvider](ctx, cfg, "word details")
+	if err != nil {
+		return nil, err
+	}
+	var masked translator.Masked
+	if cfg.Redact {
+		sentence = masked.Redact(sentence, req.TargetLang)
+	}
+	details, err := translator.RunStep(ctx, cfg.Timeout, "Finding related words", nil, func(ctx context.Context) (*translator.WordDetails, error) {
+		return detailer.WordDetails(ctx, word, sentence, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	same := func(r translator.RelatedWord) bool {
+		return strings.TrimSpace(r.Word) == "" || strings.EqualFold(strings.TrimSpace(r.Word), word)
+	}
+	details.Synonyms = slices.DeleteFunc(details.Synonyms, same)
+	details.Antonyms = slices.DeleteFunc(details.Antonyms, same)
+	details.Phrases = slices.DeleteFunc(details.Phrases, func(p translator.Phrase) bool {
+		return strings.TrimSpace(p.Text) == ""
+	})
+	return details, masked.ExpandAll(details)
+}
+
+// Conjugate returns the conjugation table of a verb in the target language of req from
+// the cache, or asks the configured analysis model for it and caches it.
+func Conjugate(ctx context.Context, cfg config.Config, verb string, req translator.Request) (*translator.Conjugation, error) {
+	if c, ok := storage.LookupConjugation(req.UserLang, req.TargetLang, verb); ok {
+		return &c, nil
+	}
+	conjugator, err := analysisProviderAs[translator.ConjugationProvider](ctx, cfg, "conjugation")
+	if err != nil {
+		return nil, err
+	}
+	c, err := translator.RunStep(ctx, cfg.Timeout, "Conjugating", nil, func(ctx context.Context) (*translator.Conjugation, error) {
+		return conjugator.Conjugate(ctx, verb, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	_ = storage.StoreConjugation(req.UserLang, req.TargetLang, verb, *c) // The cache is best-effort
+	return c, nil
+}
+
+// CaseQuiz makes up a quiz on the cases the prepositions and verbs of sentences in the
+// target language of req govern, with the configured analysis model. Questions whose
+// answer is not a case of the language are left out.
+func CaseQuiz(ctx context.Context, cfg config.Config, sentences []string, req translator.Request) (*translator.CaseQuiz, error) {
+	cases, ok := translator.Cases[req.TargetLang]
+	if !ok {
+		return nil, fmt.Errorf("no case quiz for %s", translator.LanguageName(req.TargetLang))
+	}
+	quizzer, err := analysisProviderAs[translator.CaseQuizProvider](ctx, cfg, "case quizzes")
+	if err != nil {
+		return nil, err
+	}
+	var masked translator.Masked
+	if cfg.Redact {
+		sentences = slices.Clone(sentences)
+		for i := range sentences {
+			sentences[i] = masked.Redact(sentences[i], req.TargetLang)
+		}
+	}
+	quiz, err := translator.RunStep(ctx, cfg.Timeout, "Making up questions", nil, func(ctx context.Context) (*translator.CaseQuiz, error) {
+		return quizzer.CaseQuiz(ctx, sentences, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	quiz.Questions = slices.DeleteFunc(quiz.Questions, func(q translator.CaseQuestion) bool {
+		return !slices.Contains(cases, q.Answer)
+	})
+	return quiz, masked.ExpandAll(quiz)
+}
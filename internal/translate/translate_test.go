@@ -0,0 +1,108 @@
+package translate
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+func TestTranslateBatchRedactsAfterPlaceholders(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	var sent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		content := `{"input_language": "English", "cleaned_sentence": "Hello ⟦0⟧, write to ⟦1⟧", "translation": "Hallo ⟦0⟧, schreib an ⟦1⟧", "translation_language": "German"}`
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"role": "assistant", "content": content}},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := config.Default()
+	cfg.Provider = translator.ProviderOpenAI
+	cfg.OpenAI = config.OpenAIConfig{BaseURL: srv.URL, Model: "test-model"}
+	cfg.Timeout = 5 * time.Second
+	cfg.Redact = true
+
+	masked := translator.MaskPlaceholders("Hello %s, write to support@example.com")
+	reqs := []translator.Request{{Sentence: masked.Text, UserLang: "en", TargetLang: "de"}}
+	translations, errs := TranslateBatch(t.Context(), cfg, reqs, []translator.Masked{masked})
+	if errs[0] != nil {
+		t.Fatalf("TranslateBatch failed: %v", errs[0])
+	}
+	if strings.Contains(sent, "support@example.com") || !strings.Contains(sent, "⟦1⟧") {
+		t.Errorf("e-mail address not masked after the placeholder: %s", sent)
+	}
+	if want := "Hallo %s, schreib an support@example.com"; translations[0] != want {
+		t.Errorf("translation = %q, want %q", translations[0], want)
+	}
+}
+
+func TestChatKeepsGermanNouns(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	var sent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"role": "assistant", "content": "Gern, Anna!"}},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := config.Default()
+	cfg.Provider = translator.ProviderOpenAI
+	cfg.OpenAI = config.OpenAIConfig{BaseURL: srv.URL, Model: "test-model"}
+	cfg.Timeout = 5 * time.Second
+	cfg.Redact = true
+
+	history := []translator.Message{{Role: translator.RoleUser, Text: "Ich trinke gern Kaffee mit Milch."}}
+	reply, err := Chat(t.Context(), cfg, "Antworte auf Deutsch.", history, "en", "de")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if !strings.Contains(sent, "Kaffee mit Milch") {
+		t.Errorf("German nouns masked as names: %s", sent)
+	}
+	if want := "Gern, Anna!"; reply != want {
+		t.Errorf("reply = %q, want %q", reply, want)
+	}
+}
+
+func TestEstimateRequestLimit(t *testing.T) {
+	cfg := config.Default()
+	cfg.Provider = translator.ProviderOpenAI
+	cfg.OpenAI = config.OpenAIConfig{Model: "gpt-4o-mini"}
+	text := strings.Repeat("Jag är glad. ", 40_000)
+
+	est := EstimateRequest(cfg, text, 1)
+	if est.Limit != 128_000 || est.RequestTokens <= est.Limit {
+		t.Errorf("estimate = %d tokens with limit %d, want more than the limit 128000", est.RequestTokens, est.Limit)
+	}
+
+	// max_input_tokens limits models whose limit is not known, and replaces known ones
+	cfg.OpenAI.Model = "llama3.1"
+	if est := EstimateRequest(cfg, text, 1); est.Limit != 0 {
+		t.Errorf("limit of an unknown model = %d, want 0", est.Limit)
+	}
+	cfg.Input.MaxInputTokens = 8_192
+	if est := EstimateRequest(cfg, text, 1); est.Limit != 8_192 {
+		t.Errorf("limit with max_input_tokens = %d, want 8192", est.Limit)
+	}
+	cfg.OpenAI.Model = "gpt-4.1"
+	if est := EstimateRequest(cfg, "Jag är glad.", 1); est.Limit != 8_192 || est.RequestTokens > est.Limit {
+		t.Errorf("short text = %d tokens with limit %d, want within 8192", est.RequestTokens, est.Limit)
+	}
+}
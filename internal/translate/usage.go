@@ -0,0 +1,157 @@
+package translate
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Pricing holds list prices in USD per million tokens.
+type Pricing struct {
+	Input  float64
+	Output float64
+}
+
+// knownPricing maps model name prefixes to their published pricing.
+// The Models API does not expose prices, so these are hints only.
+var knownPricing = map[string]Pricing{
+	"gemini-2.5-pro":        {1.25, 10.00},
+	"gemini-2.5-flash":      {0.30, 2.50},
+	"gemini-2.5-flash-lite": {0.10, 0.40},
+	"gemini-2.0-flash":      {0.10, 0.40},
+	"gemini-2.0-flash-lite": {0.075, 0.30},
+}
+
+// LookupPricing returns the pricing of the longest matching known model prefix.
+func LookupPricing(model string) (Pricing, bool) {
+	best := ""
+	for prefix := range knownPricing {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return Pricing{}, false
+	}
+	return knownPricing[best], true
+}
+
+// knownInputLimits maps model name prefixes to their published input token limits.
+// Models of other providers, such as local ones, are limited by max_input_tokens.
+var knownInputLimits = map[string]int{
+	"gemini-2.5-pro":        1_048_576,
+	"gemini-2.5-flash":      1_048_576,
+	"gemini-2.5-flash-lite": 1_048_576,
+	"gemini-2.0-flash":      1_048_576,
+	"gemini-2.0-flash-lite": 1_048_576,
+	"gemini-1.5-pro":        2_097_152,
+	"gemini-1.5-flash":      1_048_576,
+	"gpt-5":                 272_000,
+	"gpt-4.1":               1_047_576,
+	"gpt-4o":                128_000,
+	"gpt-4-turbo":           128_000,
+	"gpt-4":                 8_192,
+	"gpt-3.5-turbo":         16_385,
+	"o1":                    200_000,
+	"o1-mini":               128_000,
+	"o3":                    200_000,
+	"o4-mini":               200_000,
+}
+
+// LookupInputLimit returns the input token limit of the longest matching known model prefix.
+func LookupInputLimit(model string) (int, bool) {
+	best := ""
+	for prefix := range knownInputLimits {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return 0, false
+	}
+	return knownInputLimits[best], true
+}
+
+const (
+	// Tokens of the instructions a prompt adds to the text
+	promptTokens = 500
+
+	// Output tokens of the word analysis per token of the text
+	analysisTokensPerToken = 50
+)
+
+// Estimate is the rough size and cost of translating and analyzing a text, before
+// sending it.
+type Estimate struct {
+	Tokens        int     // Of the text
+	RequestTokens int     // Of the largest request, with its prompt
+	Limit         int     // Smallest input token limit of the models, or 0 if unknown
+	Cost          float64 // Estimated USD, counting only models with known pricing
+	Priced        bool    // Whether the pricing of a model is known
+}
+
+// EstimateRequest roughly estimates translating and analyzing text with the configured
+// models, as often as there are target languages. The limit is max_input_tokens if it
+// is set.
+func EstimateRequest(cfg config.Config, text string, targets int) Estimate {
+	tokens := translator.EstimateTokens(text)
+	est := Estimate{Tokens: tokens, RequestTokens: promptTokens + tokens}
+	models := ConfiguredModels(cfg)
+	type step struct {
+		model  string
+		output int // Estimated tokens
+	}
+	steps := []step{{models.Translation, tokens}}
+	if !cfg.AnalyzeOnDemand {
+		steps = append(steps, step{models.Analysis, analysisTokensPerToken * tokens})
+	}
+	est.Limit = cfg.Input.MaxInputTokens
+	for _, step := range steps {
+		if limit, ok := LookupInputLimit(step.model); ok && cfg.Input.MaxInputTokens == 0 && (est.Limit == 0 || limit < est.Limit) {
+			est.Limit = limit
+		}
+		if p, ok := LookupPricing(step.model); ok {
+			est.Cost += float64(max(targets, 1)) * (float64(est.RequestTokens)*p.Input + float64(step.output)*p.Output) / 1e6
+			est.Priced = true
+		}
+	}
+	return est
+}
+
+// Usage sums up the API calls made in this session.
+type Usage struct {
+	Calls        int
+	InputTokens  int
+	OutputTokens int
+	Cost         float64 // Estimated USD, counting only models with known pricing
+}
+
+var (
+	usageMu      sync.Mutex
+	sessionUsage Usage
+)
+
+func init() {
+	translator.OnCall(recordUsage)
+}
+
+// recordUsage adds a call to the session usage.
+func recordUsage(c translator.Call) {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	sessionUsage.Calls++
+	sessionUsage.InputTokens += c.InputTokens
+	sessionUsage.OutputTokens += c.OutputTokens
+	if p, ok := LookupPricing(c.Model); ok {
+		sessionUsage.Cost += (float64(c.InputTokens)*p.Input + float64(c.OutputTokens)*p.Output) / 1e6
+	}
+}
+
+// SessionUsage returns the usage of all API calls made so far.
+func SessionUsage() Usage {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	return sessionUsage
+}
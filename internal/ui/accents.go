@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// accents maps letters and quotes to the characters made from them, which the accent
+// key cycles through when the languages of the pair have none of them.
+var accents = map[rune]string{
+	'a':  "àáâãäåāăąæ",
+	'c':  "çćč",
+	'd':  "ďđ",
+	'e':  "èéêëēėęě",
+	'g':  "ğ",
+	'i':  "ìíîïīı",
+	'l':  "ł",
+	'n':  "ñńň",
+	'o':  "òóôõöøōőœ",
+	'r':  "ř",
+	's':  "ßśšşș",
+	't':  "ťţț",
+	'u':  "ùúûüūůű",
+	'y':  "ýÿ",
+	'z':  "źżž",
+	'"':  "“”„«»",
+	'\'': "‘’‚",
+	'?':  "¿",
+	'!':  "¡",
+}
+
+// languageCharacters are the letters and quotes of a language missing from a US
+// keyboard, in the order the accent key offers them.
+var languageCharacters = map[string]string{
+	"cs": "áčďéěíňóřšťúůýž„“",
+	"da": "æøå»«",
+	"de": "äöüß„“‚‘",
+	"en": "“”‘’",
+	"es": "áéíñóúü¿¡«»",
+	"fi": "äöå”’",
+	"fr": "àâæçéèêëîïôœùûüÿ«»",
+	"hu": "áéíóöőúüű„”",
+	"it": "àèéìíîòóù«»",
+	"nl": "éëïó“”",
+	"no": "æøå«»",
+	"pl": "ąćęłńóśźż„”",
+	"pt": "ãáâàçéêíóôõú«»",
+	"ro": "ăâîșț„”",
+	"sr": "čćđšž„”",
+	"sv": "åäö”’",
+	"tr": "çğıöşü“”",
+}
+
+// accentBase returns the letter or quote a character is made from, or the character
+// itself.
+func accentBase(r rune) rune {
+	for base, variants := range accents {
+		if strings.ContainsRune(variants, r) {
+			return base
+		}
+	}
+	return r
+}
+
+// accentCycle returns the characters that the accent key cycles through for a lower
+// case character, starting with its base: those of the target language, then of the
+// language the user knows, or all known ones if the languages have none.
+func (m model) accentCycle(r rune) []rune {
+	base := accentBase(r)
+	cycle := []rune{base}
+	for _, lang := range []string{m.foreignLang(), m.userLang} {
+		for _, c := range languageCharacters[lang] {
+			if c != base && accentBase(c) == base && !slices.Contains(cycle, c) {
+				cycle = append(cycle, c)
+			}
+		}
+	}
+	if len(cycle) == 1 {
+		cycle = append(cycle, []rune(accents[base])...)
+	}
+	return cycle
+}
+
+// foreignLang returns the language learned, or the first of several.
+func (m model) foreignLang() string {
+	if len(m.fanout) > 0 {
+		return m.fanout[0]
+	}
+	return m.targetLang
+}
+
+// cycleAccent replaces the last character of the sentence input with the next accented
+// form of it, such as a → ä → å → a, keeping its case.
+func (m model) cycleAccent() (tea.Model, tea.Cmd) {
+	last, size := utf8.DecodeLastRuneInString(m.input)
+	if size == 0 {
+		m.status = normalStyle.Render("Type a letter first, then press " + m.bindingKeys(m.keys.Accent) + " for its accents")
+		return m, nil
+	}
+	upper := unicode.IsUpper(last)
+	cycle := m.accentCycle(unicode.ToLower(last))
+	if len(cycle) == 1 {
+		m.status = normalStyle.Render("No accents for " + string(last))
+		return m, nil
+	}
+	i := max(slices.Index(cycle, unicode.ToLower(last)), 0)
+	next := cycle[(i+1)%len(cycle)]
+	if upper {
+		next = unicode.ToUpper(next)
+	}
+	m.editInput(m.input[:len(m.input)-size]+string(next), editTyping)
+	m.status = ""
+	return m, nil
+}
+
+// specialCharacters returns the characters of the language learned for the picker,
+// lower case first.
+func (m model) specialCharacters() []rune {
+	chars := []rune(languageCharacters[m.foreignLang()])
+	for _, c := range languageCharacters[m.foreignLang()] {
+		if u := unicode.ToUpper(c); u != c && u > unicode.MaxASCII && !slices.Contains(chars, u) {
+			chars = append(chars, u)
+		}
+	}
+	return chars
+}
+
+// openCharPicker shows the special characters of the language learned below the
+// sentence input.
+func (m model) openCharPicker() (tea.Model, tea.Cmd) {
+	if len(m.specialCharacters()) == 0 {
+		m.status = normalStyle.Render("No special characters known for " + m.getLangName(m.foreignLang()))
+		return m, nil
+	}
+	m.pickingChar = true
+	m.charCursor = 0
+	m.status = ""
+	return m, nil
+}
+
+// updateCharPicker handles key presses while a special character is picked. Select
+// types the character under the cursor; typing anything else closes the picker.
+func (m model) updateCharPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	chars := m.specialCharacters()
+	if _, ok := typedText(msg); ok {
+		m.pickingChar = false
+		return m.typeText(msg)
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Characters):
+		m.pickingChar = false
+	case key.Matches(msg, m.keys.PrevSentence):
+		m.charCursor = max(m.charCursor-1, 0)
+	case key.Matches(msg, m.keys.NextSentence):
+		m.charCursor = min(m.charCursor+1, len(chars)-1)
+	case key.Matches(msg, m.keys.Select):
+		m.pickingChar = false
+		m.editInput(appendText(m.input, string(chars[m.charCursor])), editTyping)
+	}
+	return m, nil
+}
+
+// viewCharPicker renders the special characters with the cursor on one of them.
+func (m model) viewCharPicker() string {
+	var s strings.Builder
+	s.WriteString(labelStyle.Render("Characters: "))
+	for i, c := range m.specialCharacters() {
+		if i > 0 {
+			s.WriteString(" ")
+		}
+		if i == m.charCursor {
+			s.WriteString(selectedStyle.Render("[" + string(c) + "]"))
+		} else {
+			s.WriteString(string(c))
+		}
+	}
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.PrevSentence, "Previous"}, helpEntry{m.keys.NextSentence, "Next"}, helpEntry{m.keys.Select, "Insert"}, helpEntry{m.keys.Back, "Close"})))
+	return s.String()
+}
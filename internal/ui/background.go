@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/document"
+	"github.com/brittaao/translation-tui/internal/notify"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// backgroundResult represents a finished translation of a sentence entered while
+// another was being translated.
+type backgroundResult struct {
+	index int // Of model.background
+	translate.Result
+	err error
+}
+
+// backgroundRecorded represents a sentence translated in the background stored in the
+// history, which unlike storageResult keeps the status of the translations.
+type backgroundRecorded struct {
+	err error
+}
+
+// translateInBackground creates a tea.Cmd that translates a sentence without showing
+// its progress.
+func translateInBackground(cfg config.Config, userLang, targetLang, sentence string, index int) tea.Cmd {
+	return func() tea.Msg {
+		req := translator.Request{Sentence: sentence, UserLang: userLang, TargetLang: targetLang}
+		result, err := translate.Run(context.Background(), cfg, req, nil)
+		return backgroundResult{index: index, Result: result, err: err}
+	}
+}
+
+// isBackgroundList reports whether the results show the sentences translated in the
+// background rather than a paragraph.
+func (m model) isBackgroundList() bool {
+	return len(m.paragraph) > 0 && m.paragraph[0].queued
+}
+
+// submitInBackground translates the entered sentence in the background while the
+// sentence before it is still being translated, which joins the results list too, so
+// that the next one can be typed right away.
+func (m model) submitInBackground() (tea.Model, tea.Cmd) {
+	if document.IsURL(m.input) || len(translator.SplitSentences(m.input)) > 1 {
+		m.status = warningStyle.Render("Only single sentences are translated in the background; wait for the current translation")
+		return m, nil
+	}
+	if m.foreground == 0 {
+		m.background = append(m.background, paragraphItem{sentence: m.translating, queued: true})
+		m.foreground = len(m.background)
+	}
+	m.background = append(m.background, paragraphItem{sentence: m.input, queued: true})
+	m.input = ""
+	m.forgetEdits()
+	m.status = m.backgroundStatus()
+	return m, translateInBackground(m.cfg, m.userLang, m.targetLang, m.background[len(m.background)-1].sentence, len(m.background)-1)
+}
+
+// keepInBackground adds the result of the sentence translated from the input to the
+// results list instead of showing it, since the next sentence is being typed.
+func (m model) keepInBackground(msg translationResult) (tea.Model, tea.Cmd) {
+	if m.foreground == 0 {
+		m.background = append(m.background, paragraphItem{sentence: m.translating, queued: true})
+		m.foreground = len(m.background)
+	}
+	i := m.foreground - 1
+	m.foreground = 0
+	m.translating = ""
+	return m.handleBackgroundResult(backgroundResult{index: i, Result: msg.Result, err: msg.err})
+}
+
+// handleBackgroundResult stores a sentence translated in the background in the results
+// list, and in the history if it succeeded.
+func (m model) handleBackgroundResult(msg backgroundResult) (tea.Model, tea.Cmd) {
+	// Cloned so that copies of the model never share the items
+	m.background = slices.Clone(m.background)
+	item := &m.background[msg.index]
+	item.result = msg.Result
+	item.err = msg.err
+	item.done = true
+	if m.isBackgroundList() && msg.index < len(m.paragraph) {
+		m.paragraph = slices.Clone(m.paragraph)
+		m.paragraph[msg.index] = *item
+		if m.state == stateShowResults && m.sentenceIndex == msg.index {
+			m.showSentence(msg.index)
+		}
+	}
+	m.status = m.backgroundStatus()
+	if msg.err != nil {
+		return m, notifyFailed(m.cfg, notify.ModeSentence, m.started, msg.err)
+	}
+	entry := storage.HistoryEntry{
+		Time:        time.Now(),
+		UserLang:    m.userLang,
+		TargetLang:  m.targetLang,
+		Original:    msg.Original,
+		Translation: msg.Translation,
+		Words:       msg.Words,
+	}
+	m.session = append(m.session, entry)
+	cfg := m.cfg
+	return m, func() tea.Msg {
+		return backgroundRecorded{err: translate.Record(context.Background(), cfg, entry)}
+	}
+}
+
+// backgroundStatus describes how many of the sentences translated in the background
+// are ready.
+func (m model) backgroundStatus() string {
+	done := 0
+	for _, item := range m.background {
+		if item.done {
+			done++
+		}
+	}
+	return normalStyle.Render(fmt.Sprintf("Translated in the background: %d of %d ready; %s shows them", done, len(m.background), m.bindingKeys(m.keys.Background)))
+}
+
+// openBackground shows the overview of the sentences translated in the background,
+// which the results then page through like the sentences of a paragraph.
+func (m model) openBackground() (tea.Model, tea.Cmd) {
+	if len(m.background) == 0 {
+		m.status = normalStyle.Render("No sentences translated in the background. Press " + m.bindingKeys(m.keys.Select) + " while a translation is running to translate the next one too.")
+		return m, nil
+	}
+	m.source = ""
+	m.paragraph = slices.Clone(m.background)
+	m.showResults()
+	m.showSentence(len(m.paragraph) - 1)
+	m.showOverview = true
+	m.status = ""
+	return m, nil
+}
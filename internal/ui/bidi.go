@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Terminals print characters from left to right in the order they come, so text in
+// Arabic, Hebrew or another right-to-left script has to be given to them reordered, or
+// it reads backwards.
+
+// isRTLClass reports whether characters of a bidi class are written right to left.
+func isRTLClass(c bidi.Class) bool {
+	return c == bidi.R || c == bidi.AL
+}
+
+// hasRTL reports whether s contains any right-to-left letters.
+func hasRTL(s string) bool {
+	for _, r := range s {
+		if p, _ := bidi.LookupRune(r); isRTLClass(p.Class()) {
+			return true
+		}
+	}
+	return false
+}
+
+// rtlBase reports whether s reads right to left as a whole: whether its first letter
+// with a direction is of a right-to-left script.
+func rtlBase(s string) bool {
+	for _, r := range s {
+		p, _ := bidi.LookupRune(r)
+		switch {
+		case p.Class() == bidi.L:
+			return false
+		case isRTLClass(p.Class()):
+			return true
+		}
+	}
+	return false
+}
+
+// visualOrder returns a line of text in the order its characters are shown from left to
+// right, reading right to left as a whole if rtl is set. Right-to-left words are
+// reversed, keeping combining marks on their letters and mirroring brackets, while
+// numbers and left-to-right words within them keep their own order.
+func visualOrder(line string, rtl bool) string {
+	if !hasRTL(line) {
+		return line
+	}
+	var p bidi.Paragraph
+	var opts []bidi.Option
+	if rtl {
+		opts = append(opts, bidi.DefaultDirection(bidi.RightToLeft))
+	}
+	if _, err := p.SetString(line, opts...); err != nil {
+		return line
+	}
+	order, err := p.Order()
+	if err != nil {
+		return line
+	}
+
+	// The runs only tell the direction, so their embedding levels are rebuilt: on a
+	// right-to-left line anything left to right is embedded in it, and on a left-to-right
+	// line a number following right-to-left words is
+	type cluster struct {
+		text  string
+		level int
+	}
+	var clusters []cluster
+	for i := range order.NumRuns() {
+		run := order.Run(i)
+		text := run.String()
+		level, numberEnd := 0, 0
+		switch {
+		case run.Direction() == bidi.RightToLeft:
+			level = 1
+		case rtl:
+			level = 2
+		case i > 0:
+			for j, r := range text {
+				p, _ := bidi.LookupRune(r)
+				if p.Class() == bidi.L {
+					break
+				}
+				if p.Class() == bidi.EN || p.Class() == bidi.AN {
+					numberEnd = j + utf8.RuneLen(r)
+				}
+			}
+		}
+		g := uniseg.NewGraphemes(text)
+		for g.Next() {
+			c := cluster{g.Str(), level}
+			if start, _ := g.Positions(); start < numberEnd {
+				c.level = 2
+			}
+			if c.level%2 == 1 && utf8.RuneCountInString(c.text) == 1 {
+				c.text = bidi.ReverseString(c.text) // Mirrors brackets
+			}
+			clusters = append(clusters, c)
+		}
+	}
+
+	// Reverse every sequence at or above each level, from the highest down to the lowest
+	// right-to-left one
+	highest := 0
+	for _, c := range clusters {
+		highest = max(highest, c.level)
+	}
+	for level := highest; level >= 1; level-- {
+		for start := 0; start < len(clusters); {
+			if clusters[start].level < level {
+				start++
+				continue
+			}
+			end := start
+			for end < len(clusters) && clusters[end].level >= level {
+				end++
+			}
+			slices.Reverse(clusters[start:end])
+			start = end
+		}
+	}
+	var b strings.Builder
+	for _, c := range clusters {
+		b.WriteString(c.text)
+	}
+	return b.String()
+}
+
+// bidiText returns a short text shown within a line, such as a word or a table cell,
+// in visual order.
+func bidiText(s string) string {
+	return visualOrder(s, rtlBase(s))
+}
+
+// lineWidth returns the width of the terminal, or the one assumed before it is known.
+func (m model) lineWidth() int {
+	if m.width == 0 {
+		return defaultLineWidth
+	}
+	return m.width
+}
+
+// alignRight wraps right-to-left text to the width of the terminal and shows each line
+// in visual order against its right edge.
+func (m model) alignRight(text string, style lipgloss.Style) string {
+	width := m.lineWidth()
+	lines := strings.Split(lipgloss.NewStyle().Width(width).Render(text), "\n")
+	for i, line := range lines {
+		line = visualOrder(strings.TrimRight(line, " "), true)
+		lines[i] = lipgloss.PlaceHorizontal(width, lipgloss.Right, style.Render(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeDirected writes a sentence after its label: on the same line if it reads left to
+// right, or right-aligned on lines of its own if it reads right to left.
+func (m model) writeDirected(s *strings.Builder, text string, style lipgloss.Style) {
+	if !rtlBase(text) {
+		s.WriteString(style.Render(bidiText(text)))
+		return
+	}
+	s.WriteString("\n")
+	s.WriteString(m.alignRight(text, style))
+}
+
+// viewInputRTL renders a right-to-left sentence input against the right edge, with the
+// cursor left of its last line where typing goes on. Its misspelled words are only
+// listed below it, as reordering would break their underlines.
+func (m model) viewInputRTL() string {
+	text := m.alignRight(m.input, lipgloss.NewStyle())
+	last := strings.LastIndex(text, "\n") + 1
+	line := text[last:]
+	content := strings.TrimLeft(line, " ")
+
+	// Spaces typed last are left of the words, as is the cursor
+	typed := strings.Repeat(" ", len(m.input)-len(strings.TrimRight(m.input, " ")))
+	if m.cursor%2 == 0 {
+		typed = "█" + typed
+	}
+	indent := max(len(line)-len(content)-lipgloss.Width(typed), 0)
+	return text[:last] + strings.Repeat(" ", indent) + typed + content
+}
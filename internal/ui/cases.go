@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/export"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Most recent sentences of the history the case quiz is made up from
+const maxCaseSentences = 20
+
+// caseQuizResult carries the questions of the case quiz.
+type caseQuizResult struct {
+	questions []translator.CaseQuestion
+	err       error
+}
+
+// loadCaseQuiz creates a tea.Cmd that has the model make up a case quiz from the most
+// recent sentences of the history in the target language.
+func loadCaseQuiz(cfg config.Config, userLang, targetLang string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := storage.LoadHistory()
+		if err != nil {
+			return caseQuizResult{err: err}
+		}
+		seen := map[string]bool{}
+		var sentences []string
+		for i := len(entries) - 1; i >= 0 && len(sentences) < maxCaseSentences; i-- {
+			text := strings.Join(strings.Fields(export.ForeignSentence(entries[i])), " ")
+			if entries[i].TargetLang != targetLang || text == "" || seen[text] {
+				continue
+			}
+			seen[text] = true
+			sentences = append(sentences, text)
+		}
+		if len(sentences) == 0 {
+			return caseQuizResult{questions: []translator.CaseQuestion{}}
+		}
+		req := translator.Request{UserLang: userLang, TargetLang: targetLang}
+		quiz, err := translate.CaseQuiz(context.Background(), cfg, sentences, req)
+		if err != nil {
+			return caseQuizResult{err: err}
+		}
+		if quiz.Questions == nil {
+			quiz.Questions = []translator.CaseQuestion{}
+		}
+		return caseQuizResult{questions: quiz.Questions}
+	}
+}
+
+// openCases starts the case quiz on the prepositions and verbs of recent sentences.
+// Only languages with Cases have one.
+func (m model) openCases() (model, tea.Cmd) {
+	m.navigate(stateCases)
+	m.caseQuestions = nil
+	m.practiceIndex = 0
+	m.caseCursor = 0
+	m.caseAnswer = ""
+	m.caseRight, m.caseTotal = 0, 0
+	m.err = nil
+	m.status = ""
+	if translator.Cases[m.targetLang] == nil {
+		return m, nil
+	}
+	m.loading = true
+	m.loadingStep = "Making up questions"
+	m.deadline = time.Time{}
+	return m, tea.Batch(loadCaseQuiz(m.cfg, m.userLang, m.targetLang), m.spinner.Tick)
+}
+
+// handleCaseQuiz starts the case quiz with the first of its questions.
+func (m model) handleCaseQuiz(msg caseQuizResult) (tea.Model, tea.Cmd) {
+	if m.state != stateCases || !m.loading {
+		return m, nil // Left the quiz while it was made up
+	}
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	m.caseQuestions = msg.questions
+	return m, nil
+}
+
+// updateCases handles key presses in the case quiz: a case is picked and answered with
+// Select, which then goes on to the next question.
+func (m model) updateCases(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cases := translator.Cases[m.targetLang]
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.loading = false
+		return m.back()
+	case m.practiceIndex >= len(m.caseQuestions):
+	case key.Matches(msg, m.keys.Select) && m.caseAnswer != "":
+		m.practiceIndex++
+		m.caseAnswer = ""
+		m.caseCursor = 0
+	case key.Matches(msg, m.keys.Select):
+		m.caseAnswer = cases[m.caseCursor]
+		m.caseTotal++
+		if m.caseAnswer == m.caseQuestions[m.practiceIndex].Answer {
+			m.caseRight++
+		}
+	case m.caseAnswer != "":
+	case key.Matches(msg, m.keys.Up):
+		m.caseCursor = max(m.caseCursor-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.caseCursor = min(m.caseCursor+1, len(cases)-1)
+	}
+	return m, nil
+}
+
+// viewCases renders the case quiz: the phrase with the governed words in their
+// dictionary form and the cases to pick from, and once answered, the phrase as said
+// with an explanation if the answer was wrong.
+func (m model) viewCases() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Case Quiz: " + m.getLangName(m.targetLang)))
+	s.WriteString("\n\n")
+
+	cases := translator.Cases[m.targetLang]
+	switch {
+	case cases == nil:
+		s.WriteString(normalStyle.Render(fmt.Sprintf("%s has no cases to practice. The case quiz is for German, Serbian, Croatian and Bosnian.", m.getLangName(m.targetLang))))
+		s.WriteString("\n\n")
+	case m.loading:
+		s.WriteString(labelStyle.Render(m.loadingView()))
+		s.WriteString("\n\n")
+	case m.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	case m.caseQuestions == nil:
+	case m.practiceIndex >= len(m.caseQuestions) && len(m.caseQuestions) == 0:
+		s.WriteString(normalStyle.Render("No prepositions or verbs governing a case in your recent sentences. Translate some more to be quizzed on them."))
+		s.WriteString("\n\n")
+	case m.practiceIndex >= len(m.caseQuestions):
+		s.WriteString(successStyle.Render(fmt.Sprintf("Done: %d of %d right (%d%%).", m.caseRight, m.caseTotal, percent(m.caseRight, m.caseTotal))))
+		s.WriteString("\n\n")
+	default:
+		q := m.caseQuestions[m.practiceIndex]
+		s.WriteString(normalStyle.Render(fmt.Sprintf("Question %d of %d", m.practiceIndex+1, len(m.caseQuestions))))
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render("Phrase: "))
+		s.WriteString(valueStyle.Render(q.Phrase))
+		s.WriteString("\n")
+		s.WriteString(normalStyle.Render(fmt.Sprintf("Which case does %q take here?", q.Word)))
+		s.WriteString("\n\n")
+		for i, c := range cases {
+			line := capitalize(c)
+			switch {
+			case m.caseAnswer != "" && c == q.Answer:
+				s.WriteString(successStyle.Render("✓ " + line))
+			case m.caseAnswer != "" && c == m.caseAnswer:
+				s.WriteString(errorStyle.Render("✗ " + line))
+			case m.caseAnswer == "" && i == m.caseCursor:
+				s.WriteString(selectedStyle.Render("> " + line))
+			default:
+				s.WriteString(normalStyle.Render("  " + line))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		if m.caseAnswer == "" {
+			break
+		}
+		if m.caseAnswer == q.Answer {
+			s.WriteString(successStyle.Render("Correct: " + q.Example))
+		} else {
+			s.WriteString(errorStyle.Render(fmt.Sprintf("Not quite: %s, %s", q.Answer, q.Example)))
+			if q.Explanation != "" {
+				s.WriteString("\n")
+				s.WriteString(normalStyle.Render(q.Explanation))
+			}
+		}
+		s.WriteString(normalStyle.Render(fmt.Sprintf("\n\nSession: %d of %d right (%d%%)", m.caseRight, m.caseTotal, percent(m.caseRight, m.caseTotal))))
+		s.WriteString("\n\n")
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	switch {
+	case m.practiceIndex >= len(m.caseQuestions):
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Back"})))
+	case m.caseAnswer == "":
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, "Answer"}, helpEntry{m.keys.Back, "Back"})))
+	default:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Next question"}, helpEntry{m.keys.Back, "Back"})))
+	}
+	return s.String()
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/drill"
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// clozeItem is a saved sentence with a saved word of it left out.
+type clozeItem struct {
+	text        string // With drill.Gap for the word
+	answer      string // The word as it appears in the sentence
+	translation string
+}
+
+// clozeResult carries the cloze items of a language.
+type clozeResult struct {
+	items []clozeItem
+	err   error
+}
+
+// loadCloze creates a tea.Cmd that makes a cloze item of each vocab card of lang whose
+// sentence has the word, in random order.
+func loadCloze(lang string) tea.Cmd {
+	return func() tea.Msg {
+		cards, err := storage.LoadVocab()
+		if err != nil {
+			return clozeResult{err: err}
+		}
+		var items []clozeItem
+		for _, c := range cards {
+			sentence := strings.TrimSpace(c.ForeignSentence())
+			text, ok := drill.Blank(sentence, c.Word)
+			if c.Lang != lang || !ok {
+				continue
+			}
+			translation := c.Translation
+			if sentence == strings.TrimSpace(c.Translation) {
+				translation = c.Sentence
+			}
+			answer := strings.Join(drill.Words(c.Word), " ")
+			items = append(items, clozeItem{text: text, answer: answer, translation: strings.TrimSpace(translation)})
+		}
+		rand.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+		return clozeResult{items: items}
+	}
+}
+
+// openCloze starts the cloze test on the saved words of the language learned.
+func (m model) openCloze() (model, tea.Cmd) {
+	m.navigate(stateCloze)
+	m.cloze = nil
+	m.practiceIndex = 0
+	m.practiceInput = ""
+	m.clozeChecked = false
+	m.clozeRight, m.clozeTotal = 0, 0
+	m.status = ""
+	return m, loadCloze(m.targetLang)
+}
+
+// handleCloze starts the cloze test with the first of its sentences.
+func (m model) handleCloze(msg clozeResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		return m, nil
+	}
+	m.cloze = msg.items
+	if m.cloze == nil {
+		m.cloze = []clozeItem{} // Loaded, but none saved
+	}
+	if next, cmd, ok := m.studyTurn(len(m.cloze) == 0); ok {
+		return next, cmd
+	}
+	return m, nil
+}
+
+// updateCloze handles key presses in the cloze test. The missing word is typed and
+// checked with Select, which then goes on to the next sentence.
+func (m model) updateCloze(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	done := m.practiceIndex >= len(m.cloze)
+	if text, ok := typedText(msg); ok && !done && !m.clozeChecked {
+		m.practiceInput += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		return m.back()
+	case done:
+	case key.Matches(msg, m.keys.Select) && m.clozeChecked:
+		m.practiceIndex++
+		m.practiceInput = ""
+		m.clozeChecked = false
+		if next, cmd, ok := m.studyTurn(m.practiceIndex >= len(m.cloze)); ok {
+			return next, cmd
+		}
+	case key.Matches(msg, m.keys.Select) && strings.TrimSpace(m.practiceInput) != "":
+		m.clozeChecked = true
+		m.clozeTotal++
+		right := 0
+		if strings.EqualFold(strings.TrimSpace(m.practiceInput), m.cloze[m.practiceIndex].answer) {
+			m.clozeRight++
+			right = 1
+		}
+		m.recordStudy(right, 1)
+	case msg.Type == tea.KeyBackspace && !m.clozeChecked:
+		if len(m.practiceInput) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.practiceInput)
+			m.practiceInput = m.practiceInput[:len(m.practiceInput)-size]
+		}
+	}
+	return m, nil
+}
+
+// viewCloze renders the cloze test: the sentence with its gap and meaning, the word
+// being typed, and once checked, whether it was the word left out.
+func (m model) viewCloze() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Cloze: " + m.getLangName(m.targetLang)))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.cloze == nil:
+	case m.practiceIndex >= len(m.cloze) && len(m.cloze) == 0:
+		s.WriteString(normalStyle.Render("No saved words yet. Save words from the results to fill them in the sentences they came from."))
+		s.WriteString("\n\n")
+	case m.practiceIndex >= len(m.cloze):
+		s.WriteString(successStyle.Render(fmt.Sprintf("Done: %d of %d right (%d%%).", m.clozeRight, m.clozeTotal, percent(m.clozeRight, m.clozeTotal))))
+		s.WriteString("\n\n")
+	default:
+		item := m.cloze[m.practiceIndex]
+		s.WriteString(normalStyle.Render(fmt.Sprintf("Sentence %d of %d", m.practiceIndex+1, len(m.cloze))))
+		s.WriteString("\n\n")
+		s.WriteString(valueStyle.Render(item.text))
+		s.WriteString("\n")
+		if item.translation != "" {
+			s.WriteString(labelStyle.Render("Meaning: "))
+			s.WriteString(valueStyle.Render(item.translation))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		s.WriteString(labelStyle.Render("Word: "))
+		s.WriteString(m.practiceInput)
+		if !m.clozeChecked {
+			s.WriteString("█\n\n")
+			break
+		}
+		s.WriteString("\n\n")
+		if strings.EqualFold(strings.TrimSpace(m.practiceInput), item.answer) {
+			s.WriteString(successStyle.Render("Correct"))
+		} else {
+			s.WriteString(errorStyle.Render("Not quite: " + item.answer))
+		}
+		s.WriteString(normalStyle.Render(fmt.Sprintf(" · session: %d of %d", m.clozeRight, m.clozeTotal)))
+		s.WriteString("\n\n")
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	switch {
+	case m.practiceIndex >= len(m.cloze):
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Back"})))
+	case m.clozeChecked:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Next sentence"}, helpEntry{m.keys.Back, "Back"})))
+	default:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Check"}, helpEntry{m.keys.Back, "Back"}) + " | Type the missing word"))
+	}
+	return s.String()
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// comparisonResult represents the outcome of comparing two phrasings.
+type comparisonResult struct {
+	comparison *translator.Comparison
+	err        error
+}
+
+// comparePhrasings creates a tea.Cmd that asks the model to explain the difference
+// between two phrasings in the target language.
+func comparePhrasings(cfg config.Config, userLang, targetLang, first, second string) tea.Cmd {
+	return func() tea.Msg {
+		req := translator.Request{UserLang: userLang, TargetLang: targetLang}
+		comparison, err := translate.Compare(context.Background(), cfg, first, second, req)
+		return comparisonResult{comparison: comparison, err: err}
+	}
+}
+
+// openCompare shows the two input fields of the explain-the-difference mode.
+func (m model) openCompare() (tea.Model, tea.Cmd) {
+	m.navigate(stateCompare)
+	m.compareInputs = [2]string{}
+	m.compareField = 0
+	m.comparison = nil
+	m.err = nil
+	m.status = ""
+	return m, nil
+}
+
+// updateCompare handles key presses in the explain-the-difference mode.
+func (m model) updateCompare(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	field := &m.compareInputs[m.compareField]
+	if text, ok := typedText(msg); ok {
+		*field += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Debug):
+		return m.openDebugView()
+	case key.Matches(msg, m.keys.Back):
+		m.pop()
+		m.loading = false
+		return m, nil
+	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.Down), key.Matches(msg, m.keys.Expand):
+		m.compareField = 1 - m.compareField
+	case key.Matches(msg, m.keys.Select):
+		first, second := strings.TrimSpace(m.compareInputs[0]), strings.TrimSpace(m.compareInputs[1])
+		if first == "" || second == "" {
+			m.compareField = 1
+			if first == "" {
+				m.compareField = 0
+			}
+			return m, nil
+		}
+		if m.loading {
+			return m, nil
+		}
+		m.loading = true
+		m.loadingStep = "Comparing"
+		m.deadline = time.Time{}
+		m.err = nil
+		return m, tea.Batch(comparePhrasings(m.cfg, m.userLang, m.targetLang, first, second), m.spinner.Tick)
+	case msg.Type == tea.KeyBackspace:
+		if len(*field) > 0 {
+			_, size := utf8.DecodeLastRuneInString(*field)
+			*field = (*field)[:len(*field)-size]
+		}
+	}
+	return m, nil
+}
+
+// handleComparisonResult shows the comparison of the two phrasings.
+func (m model) handleComparisonResult(msg comparisonResult) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	m.comparison = msg.comparison
+	return m, nil
+}
+
+// viewCompare renders the two input fields and, once available, the comparison.
+func (m model) viewCompare() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Explain The Difference:"))
+	s.WriteString("\n\n")
+	s.WriteString(fmt.Sprintf("Two %s phrasings to compare:\n\n", m.getLangName(m.targetLang)))
+	for i, label := range []string{"First: ", "Second:"} {
+		line := fmt.Sprintf("%s %s", label, m.compareInputs[i])
+		if i == m.compareField {
+			s.WriteString(selectedStyle.Render("> " + line + "█"))
+		} else {
+			s.WriteString(normalStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	switch {
+	case m.loading:
+		s.WriteString(labelStyle.Render(m.loadingView()))
+		s.WriteString("\n\n")
+	case m.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	case m.comparison != nil:
+		c := m.comparison
+		for _, part := range []struct{ label, text string }{
+			{"Meaning", c.Meaning},
+			{"Register", c.Register},
+			{"Grammar", c.Grammar},
+			{"Use the first", c.UseFirst},
+			{"Use the second", c.UseSecond},
+		} {
+			s.WriteString(labelStyle.Render(part.label + ": "))
+			s.WriteString(valueStyle.Render(part.text))
+			s.WriteString("\n\n")
+		}
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Compare"}, helpEntry{m.keys.Down, "Other field"}, helpEntry{m.keys.Back, "Back"}, helpEntry{m.keys.Help, "Help"})))
+	return s.String()
+}
@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// appendText adds typed text to s in composed form, so that an accent sent after its
+// letter by a dead key, compose key or input method joins it: e followed by a combining
+// acute accent becomes é.
+func appendText(s, text string) string {
+	return norm.NFC.String(s + text)
+}
+
+// deleteLast removes the last character of s as it is shown, which may take several
+// runes, such as a letter with combining accents, a Hangul syllable in jamo or an emoji
+// with a skin tone.
+func deleteLast(s string) string {
+	last := 0
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		last, _ = g.Positions()
+	}
+	return s[:last]
+}
@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// verbsResult carries the saved verbs of a language, for the conjugation quiz.
+type verbsResult struct {
+	verbs []storage.VocabCard
+	err   error
+}
+
+// conjugationResult represents the conjugation table of a verb.
+type conjugationResult struct {
+	verb        string
+	conjugation *translator.Conjugation
+	err         error
+}
+
+// loadVerbs creates a tea.Cmd that reads the vocab cards of lang saved as verbs, one
+// per dictionary form, in random order.
+func loadVerbs(lang string) tea.Cmd {
+	return func() tea.Msg {
+		cards, err := storage.LoadVocab()
+		if err != nil {
+			return verbsResult{err: err}
+		}
+		seen := map[string]bool{}
+		var verbs []storage.VocabCard
+		for _, c := range cards {
+			lemma := strings.ToLower(dictionaryForm(c))
+			if c.Lang != lang || !isVerb(c.PartOfSpeech) || seen[lemma] {
+				continue
+			}
+			seen[lemma] = true
+			verbs = append(verbs, c)
+		}
+		rand.Shuffle(len(verbs), func(i, j int) { verbs[i], verbs[j] = verbs[j], verbs[i] })
+		return verbsResult{verbs: verbs}
+	}
+}
+
+// isVerb reports whether a part of speech, named in the user's language, is a verb:
+// "verb", "Verb", "verbe" and "verbo" are, "adverb" is not.
+func isVerb(pos string) bool {
+	pos = strings.ToLower(pos)
+	return (strings.Contains(pos, "verb") && !strings.Contains(pos, "adverb")) || strings.Contains(pos, "glagol")
+}
+
+// dictionaryForm returns the dictionary form of a saved word, or the word as saved if
+// the analysis gave none.
+func dictionaryForm(c storage.VocabCard) string {
+	if c.Lemma != "" {
+		return c.Lemma
+	}
+	return c.Word
+}
+
+// conjugateVerb creates a tea.Cmd that reads the conjugation table of a verb from the
+// cache or asks the model for it.
+func conjugateVerb(cfg config.Config, userLang, targetLang, verb string) tea.Cmd {
+	return func() tea.Msg {
+		req := translator.Request{UserLang: userLang, TargetLang: targetLang}
+		conjugation, err := translate.Conjugate(context.Background(), cfg, verb, req)
+		return conjugationResult{verb: verb, conjugation: conjugation, err: err}
+	}
+}
+
+// openConjugation starts the conjugation quiz on the saved verbs of the language learned.
+func (m model) openConjugation() (model, tea.Cmd) {
+	m.navigate(stateConjugation)
+	m.verbs = nil
+	m.practiceIndex = 0
+	m.conjugationRight, m.conjugationTotal = 0, 0
+	m.err = nil
+	m.status = ""
+	return m, loadVerbs(m.targetLang)
+}
+
+// handleVerbs starts the conjugation quiz with the first of the saved verbs.
+func (m model) handleVerbs(msg verbsResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		return m, nil
+	}
+	m.verbs = msg.verbs
+	if m.verbs == nil {
+		m.verbs = []storage.VocabCard{} // Loaded, but none saved
+	}
+	return m.askVerb()
+}
+
+// askVerb fetches the conjugation table of the current verb, to ask for one of its forms.
+func (m model) askVerb() (model, tea.Cmd) {
+	m.conjugation = nil
+	m.conjugationChecked = false
+	m.practiceInput = ""
+	m.err = nil
+	if next, cmd, ok := m.studyTurn(m.practiceIndex >= len(m.verbs)); ok {
+		return next, cmd
+	}
+	if m.practiceIndex >= len(m.verbs) {
+		return m, nil
+	}
+	m.loading = true
+	m.loadingStep = "Conjugating"
+	m.deadline = time.Time{}
+	return m, tea.Batch(conjugateVerb(m.cfg, m.userLang, m.targetLang, dictionaryForm(m.verbs[m.practiceIndex])), m.spinner.Tick)
+}
+
+// handleConjugation asks for a form of the current verb picked from its conjugation
+// table, or skips the verb if it turns out not to be one.
+func (m model) handleConjugation(msg conjugationResult) (tea.Model, tea.Cmd) {
+	if m.state != stateConjugation || m.practiceIndex >= len(m.verbs) || msg.verb != dictionaryForm(m.verbs[m.practiceIndex]) {
+		return m, nil // Left the quiz while conjugating
+	}
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	if len(msg.conjugation.Forms) == 0 {
+		m.status = warningStyle.Render(fmt.Sprintf("Skipped %s: not a verb", msg.verb))
+		m.practiceIndex++
+		return m.askVerb()
+	}
+	m.conjugation = msg.conjugation
+	m.conjugationForm = msg.conjugation.Forms[rand.IntN(len(msg.conjugation.Forms))]
+	return m, nil
+}
+
+// checkConjugation reports whether the answer is the form asked for, ignoring case and
+// extra spaces, and the pronoun if it was typed along.
+func checkConjugation(form translator.ConjugatedForm, answer string) bool {
+	answer = strings.Join(strings.Fields(answer), " ")
+	want := strings.Join(strings.Fields(form.Form), " ")
+	if strings.EqualFold(answer, want) {
+		return true
+	}
+	return form.Pronoun != "" && strings.EqualFold(answer, form.Pronoun+" "+want)
+}
+
+// updateConjugation handles key presses in the conjugation quiz. The form is typed and
+// checked with Select, which then goes on to the next verb. A verb conjugated wrong is
+// made a priority card due at once, so that the review brings it up first.
+func (m model) updateConjugation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	asking := m.conjugation != nil && !m.conjugationChecked
+	if text, ok := typedText(msg); ok && asking {
+		m.practiceInput += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.loading = false
+		return m.back()
+	case m.practiceIndex >= len(m.verbs):
+	case key.Matches(msg, m.keys.Select) && m.conjugationChecked:
+		m.practiceIndex++
+		m.status = ""
+		return m.askVerb()
+	case key.Matches(msg, m.keys.Select) && m.err != nil:
+		return m.askVerb() // Try the verb again
+	case key.Matches(msg, m.keys.Select) && asking && strings.TrimSpace(m.practiceInput) != "":
+		m.conjugationChecked = true
+		m.conjugationTotal++
+		if checkConjugation(m.conjugationForm, m.practiceInput) {
+			m.conjugationRight++
+			m.recordStudy(1, 1)
+			return m, nil
+		}
+		m.recordStudy(0, 1)
+		card := m.verbs[m.practiceIndex]
+		card.Priority = true
+		card.Due = time.Now()
+		return m, saveReview(card)
+	case msg.Type == tea.KeyBackspace && asking:
+		if len(m.practiceInput) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.practiceInput)
+			m.practiceInput = m.practiceInput[:len(m.practiceInput)-size]
+		}
+	}
+	return m, nil
+}
+
+// viewConjugation renders the conjugation quiz: the verb with the tense and person
+// asked for, the form being typed, and once checked, the verdict with the forms of
+// the verb in that tense.
+func (m model) viewConjugation() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Conjugation: " + m.getLangName(m.targetLang)))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.verbs == nil:
+	case m.practiceIndex >= len(m.verbs) && len(m.verbs) == 0:
+		s.WriteString(normalStyle.Render("No saved verbs yet. Save words from the results to practice conjugating the verbs among them."))
+		s.WriteString("\n\n")
+	case m.practiceIndex >= len(m.verbs):
+		s.WriteString(successStyle.Render(fmt.Sprintf("Done: %d of %d forms right (%d%%).", m.conjugationRight, m.conjugationTotal, percent(m.conjugationRight, m.conjugationTotal))))
+		s.WriteString("\n")
+		if m.conjugationRight < m.conjugationTotal {
+			s.WriteString(normalStyle.Render("The verbs you got wrong come up first in the review."))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	case m.loading:
+		s.WriteString(labelStyle.Render(m.loadingView()))
+		s.WriteString("\n\n")
+	case m.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	case m.conjugation != nil:
+		form := m.conjugationForm
+		s.WriteString(normalStyle.Render(fmt.Sprintf("Verb %d of %d", m.practiceIndex+1, len(m.verbs))))
+		s.WriteString("\n\n")
+		for _, part := range []struct{ label, text string }{
+			{"Verb:   ", m.conjugation.Lemma},
+			{"Tense:  ", form.Tense},
+			{"Person: ", form.Person},
+		} {
+			s.WriteString(labelStyle.Render(part.label))
+			s.WriteString(valueStyle.Render(part.text))
+			s.WriteString("\n")
+		}
+		s.WriteString(labelStyle.Render("Form:   "))
+		if form.Pronoun != "" {
+			s.WriteString(normalStyle.Render(form.Pronoun + " "))
+		}
+		s.WriteString(m.practiceInput)
+		if !m.conjugationChecked {
+			s.WriteString("█\n\n")
+			break
+		}
+		s.WriteString("\n\n")
+		if checkConjugation(form, m.practiceInput) {
+			s.WriteString(successStyle.Render("Correct"))
+		} else {
+			s.WriteString(errorStyle.Render("Not quite: " + form.Form))
+		}
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render(form.Tense + ":"))
+		s.WriteString("\n")
+		for _, f := range m.conjugation.Forms {
+			if f.Tense != form.Tense {
+				continue
+			}
+			line := strings.TrimSpace(f.Pronoun + " " + f.Form)
+			if f == form {
+				s.WriteString(selectedStyle.Render("> " + line))
+			} else {
+				s.WriteString(normalStyle.Render("  " + line))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString(normalStyle.Render(fmt.Sprintf("\nSession: %d of %d right (%d%%)", m.conjugationRight, m.conjugationTotal, percent(m.conjugationRight, m.conjugationTotal))))
+		s.WriteString("\n\n")
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	switch {
+	case m.practiceIndex >= len(m.verbs):
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Back"})))
+	case m.err != nil:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Try again"}, helpEntry{m.keys.Back, "Back"})))
+	case m.conjugationChecked:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Next verb"}, helpEntry{m.keys.Back, "Back"})))
+	default:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Check"}, helpEntry{m.keys.Back, "Back"}) + " | Type the form"))
+	}
+	return s.String()
+}
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// cefrLevels are the levels a practice conversation can be held at.
+var cefrLevels = translator.CEFRLevels
+
+// Level selected when a conversation is first opened
+const defaultChatLevel = 1
+
+// chatMessage is a turn of the practice conversation with its translation and word
+// analysis, once expanded.
+type chatMessage struct {
+	translator.Message
+	result    *translate.Result
+	err       error
+	expanded  bool
+	expanding bool
+}
+
+// chatReply represents the model's reply in the conversation.
+type chatReply struct {
+	text string
+	err  error
+}
+
+// chatExpansion represents the translation and word analysis of a conversation message.
+type chatExpansion struct {
+	index int
+	translate.Result
+	err error
+}
+
+// sendChat creates a tea.Cmd that asks the model for its next reply.
+func sendChat(cfg config.Config, system string, history []translator.Message, langs ...string) tea.Cmd {
+	return func() tea.Msg {
+		text, err := translate.Chat(context.Background(), cfg, system, history, langs...)
+		return chatReply{text: text, err: err}
+	}
+}
+
+// expandMessage creates a tea.Cmd that translates and analyzes conversation message i.
+func expandMessage(cfg config.Config, userLang, targetLang string, i int, text string) tea.Cmd {
+	return func() tea.Msg {
+		req := translator.Request{Sentence: text, UserLang: userLang, TargetLang: targetLang}
+		result, err := translate.Run(context.Background(), cfg, req, nil)
+		return chatExpansion{index: i, Result: result, err: err}
+	}
+}
+
+// openConversation shows the level selection of a new practice conversation.
+func (m model) openConversation() (tea.Model, tea.Cmd) {
+	m.navigate(stateConversation)
+	m.chat = nil
+	m.chatStarted = false
+	m.chatSelected = -1
+	m.input = ""
+	m.err = nil
+	m.status = ""
+	return m, nil
+}
+
+// chatHistory returns the turns of the conversation for the model.
+func (m model) chatHistory() []translator.Message {
+	history := make([]translator.Message, len(m.chat))
+	for i, msg := range m.chat {
+		history[i] = msg.Message
+	}
+	return history
+}
+
+// chatInstruction returns the system instruction for the selected level.
+func (m model) chatInstruction() string {
+	return translator.ConversationInstruction(m.userLang, m.targetLang, cefrLevels[m.chatLevel])
+}
+
+// updateConversation handles key presses in the conversation practice mode.
+func (m model) updateConversation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if text, ok := typedText(msg); ok && m.chatStarted {
+		m.input += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Debug):
+		return m.openDebugView()
+	case key.Matches(msg, m.keys.Back):
+		m.pop()
+		m.input = ""
+		m.loading = false
+		return m, nil
+	case !m.chatStarted:
+		return m.updateChatLevel(msg)
+	case key.Matches(msg, m.keys.Select):
+		text := strings.TrimSpace(m.input)
+		if text == "" || m.loading {
+			return m, nil
+		}
+		m.chat = append(m.chat, chatMessage{Message: translator.Message{Role: translator.RoleUser, Text: text}})
+		m.input = ""
+		m.err = nil
+		m.loading = true
+		m.loadingStep = "Replying"
+		return m, tea.Batch(sendChat(m.cfg, m.chatInstruction(), m.chatHistory(), m.userLang, m.targetLang), m.spinner.Tick)
+	case key.Matches(msg, m.keys.Up):
+		m.chatSelected = m.nextModelMessage(m.chatSelected, -1)
+	case key.Matches(msg, m.keys.Down):
+		m.chatSelected = m.nextModelMessage(m.chatSelected, 1)
+	case key.Matches(msg, m.keys.Expand):
+		return m.toggleExpansion()
+	case msg.Type == tea.KeyBackspace:
+		if len(m.input) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.input)
+			m.input = m.input[:len(m.input)-size]
+		}
+	}
+	return m, nil
+}
+
+// updateChatLevel handles the level selection before the conversation starts.
+func (m model) updateChatLevel(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.chatLevel > 0 {
+			m.chatLevel--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.chatLevel < len(cefrLevels)-1 {
+			m.chatLevel++
+		}
+	case key.Matches(msg, m.keys.Select):
+		m.chatStarted = true
+		m.loading = true
+		m.loadingStep = "Replying"
+		return m, tea.Batch(sendChat(m.cfg, m.chatInstruction(), nil, m.userLang, m.targetLang), m.spinner.Tick)
+	}
+	return m, nil
+}
+
+// nextModelMessage returns the index of the next model message from i in direction dir,
+// or i if there is none.
+func (m model) nextModelMessage(i, dir int) int {
+	if i < 0 && dir < 0 {
+		i = len(m.chat)
+	}
+	for j := i + dir; j >= 0 && j < len(m.chat); j += dir {
+		if m.chat[j].Role == translator.RoleModel {
+			return j
+		}
+	}
+	return i
+}
+
+// toggleExpansion shows or hides the translation and word analysis of the selected
+// model message, running the pipeline the first time.
+func (m model) toggleExpansion() (tea.Model, tea.Cmd) {
+	if m.chatSelected < 0 || m.chatSelected >= len(m.chat) {
+		return m, nil
+	}
+	msg := &m.chat[m.chatSelected]
+	if msg.result != nil || msg.expanding {
+		msg.expanded = !msg.expanded
+		return m, nil
+	}
+	msg.expanded = true
+	msg.expanding = true
+	msg.err = nil
+	return m, expandMessage(m.cfg, m.userLang, m.targetLang, m.chatSelected, msg.Text)
+}
+
+// handleChatReply adds the model's reply to the conversation and selects it.
+func (m model) handleChatReply(msg chatReply) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	m.chat = append(m.chat, chatMessage{Message: translator.Message{Role: translator.RoleModel, Text: msg.text}})
+	m.chatSelected = len(m.chat) - 1
+	return m, nil
+}
+
+// handleChatExpansion stores the translation and analysis of a conversation message.
+func (m model) handleChatExpansion(msg chatExpansion) (tea.Model, tea.Cmd) {
+	if msg.index >= len(m.chat) {
+		return m, nil
+	}
+	item := &m.chat[msg.index]
+	item.expanding = false
+	if msg.err != nil {
+		item.err = msg.err
+		return m, nil
+	}
+	item.result = &msg.Result
+	m.usedModels = msg.Models
+	return m, nil
+}
+
+// viewConversation renders the level selection or the conversation with expanded
+// messages and the input line.
+func (m model) viewConversation() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Conversation Practice"))
+	s.WriteString("\n\n")
+
+	if !m.chatStarted {
+		s.WriteString(fmt.Sprintf("Practice %s with the model. Choose your level:\n\n", m.getLangName(m.targetLang)))
+		for i, level := range cefrLevels {
+			if i == m.chatLevel {
+				s.WriteString(selectedStyle.Render("> " + level))
+			} else {
+				s.WriteString(normalStyle.Render("  " + level))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, "Start"}, helpEntry{m.keys.Back, "Back"}, helpEntry{m.keys.Help, "Help"})))
+		return s.String()
+	}
+
+	s.WriteString(fmt.Sprintf("%s | Level %s\n\n", m.getLangName(m.targetLang), cefrLevels[m.chatLevel]))
+	for i, msg := range m.chat {
+		if msg.Role == translator.RoleUser {
+			s.WriteString(labelStyle.Render("  You: "))
+			s.WriteString(normalStyle.Render(msg.Text))
+			s.WriteString("\n\n")
+			continue
+		}
+		prefix := "  "
+		style := valueStyle
+		if i == m.chatSelected {
+			prefix = "> "
+			style = selectedStyle
+		}
+		s.WriteString(labelStyle.Render(prefix + "Partner: "))
+		s.WriteString(style.Render(msg.Text))
+		s.WriteString("\n")
+		if msg.expanded {
+			switch {
+			case msg.expanding:
+				s.WriteString(normalStyle.Render("    Translating..."))
+				s.WriteString("\n")
+			case msg.err != nil:
+				s.WriteString(errorStyle.Render(fmt.Sprintf("    Error: %v", msg.err)))
+				s.WriteString("\n")
+			case msg.result != nil:
+				s.WriteString(labelStyle.Render("    Translation: "))
+				s.WriteString(successStyle.Render(msg.result.Translation))
+				s.WriteString("\n\n")
+				writeWordAnalysis(&s, msg.result.Words, m.cfg.Level, -1, nil)
+			}
+		}
+		s.WriteString("\n")
+	}
+
+	if m.loading {
+		s.WriteString(labelStyle.Render(m.spinner.View() + " Partner is replying..."))
+		s.WriteString("\n\n")
+	}
+	if m.err != nil {
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	}
+	s.WriteString(fmt.Sprintf("You: %s█\n\n", m.input))
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Send"}, helpEntry{m.keys.Up, "Previous message"}, helpEntry{m.keys.Down, "Next message"}, helpEntry{m.keys.Expand, "Translate & analyze"}, helpEntry{m.keys.Back, "Back"}, helpEntry{m.keys.Help, "Help"})))
+	return s.String()
+}
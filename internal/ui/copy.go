@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/clipboard"
+	"github.com/brittaao/translation-tui/internal/export"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// copyFormat is a way of copying the current result to the clipboard.
+type copyFormat struct {
+	name string
+	text func(m model) string
+}
+
+// copyFormats lists the choices of the copy menu.
+var copyFormats = []copyFormat{
+	{"Translation only", func(m model) string { return m.translation }},
+	{"Original and translation", func(m model) string { return m.originalSentence + "\n" + m.translation }},
+	{"Markdown table", model.markdownTable},
+	{"Anki TSV row", model.ankiRow},
+	{"Full JSON", model.resultJSON},
+}
+
+// copyFormatNames lists the names of the copy formats for the menu.
+func copyFormatNames() []string {
+	names := make([]string, len(copyFormats))
+	for i, format := range copyFormats {
+		names[i] = format.name
+	}
+	return names
+}
+
+// copiedResult is the current result as copied in JSON.
+type copiedResult struct {
+	UserLang    string                `json:"user_lang"`
+	TargetLang  string                `json:"target_lang"`
+	Original    string                `json:"original"`
+	Translation string                `json:"translation"`
+	Level       string                `json:"level,omitempty"`
+	Tone        *translator.Tone      `json:"tone,omitempty"`
+	Words       []translator.WordInfo `json:"words,omitempty"`
+}
+
+// hasResult reports whether there is a result to copy or export.
+func (m model) hasResult() bool {
+	if item, ok := m.currentSentence(); ok && item.err != nil {
+		return false
+	}
+	return m.translation != ""
+}
+
+// updateCopyMenu handles key presses in the copy menu.
+func (m model) updateCopyMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Copy):
+		m.copying = false
+	case key.Matches(msg, m.keys.Up):
+		m.copyCursor = max(m.copyCursor-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.copyCursor = min(m.copyCursor+1, len(copyFormats)-1)
+	case key.Matches(msg, m.keys.Select):
+		format := copyFormats[m.copyCursor]
+		m.copying = false
+		m.status = ""
+		return m, copyToClipboard(format.text(m), strings.ToLower(format.name), m.terminal)
+	}
+	return m, nil
+}
+
+// copyToClipboard creates a tea.Cmd that copies text to the clipboard, through the
+// terminal of a remote session if there is one.
+func copyToClipboard(text, what string, terminal io.Writer) tea.Cmd {
+	return func() tea.Msg {
+		if terminal != nil {
+			clipboard.WriteTerminal(terminal, text)
+		} else if err := clipboard.Write(context.Background(), text); err != nil {
+			return storageResult{err: err}
+		}
+		return storageResult{status: successStyle.Render(fmt.Sprintf("Copied %s to the clipboard", what))}
+	}
+}
+
+// writeMenu renders a menu of choices for acting on the result, such as the formats
+// it can be copied in.
+func (m model) writeMenu(s *strings.Builder, title string, choices []string, cursor int, action string) {
+	s.WriteString(labelStyle.Render(title))
+	s.WriteString("\n")
+	for i, choice := range choices {
+		if i == cursor {
+			s.WriteString(selectedStyle.Render("> " + choice))
+		} else {
+			s.WriteString(normalStyle.Render("  " + choice))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, action}, helpEntry{m.keys.Back, "Cancel"})))
+	s.WriteString("\n\n")
+}
+
+// otherSentence returns the sentence of the result in the user's language.
+func (m model) otherSentence() string {
+	if m.foreignSentence() == m.originalSentence {
+		return m.translation
+	}
+	return m.originalSentence
+}
+
+// markdownTable returns the result as a Markdown table of the sentence and, below it,
+// one of the analyzed words.
+func (m model) markdownTable() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "| %s | %s |\n| --- | --- |\n", m.getLangName(m.targetLang), m.getLangName(m.userLang))
+	fmt.Fprintf(&s, "| %s | %s |\n", export.MarkdownCell(m.foreignSentence()), export.MarkdownCell(m.otherSentence()))
+	if len(m.wordAnalysis) > 0 {
+		s.WriteString("\n")
+		export.WriteWordTable(&s, m.wordAnalysis)
+	}
+	return s.String()
+}
+
+// ankiRow returns the result as a row for importing into Anki: the foreign-language
+// sentence, the one in the user's language, and the analyzed words as HTML.
+func (m model) ankiRow() string {
+	field := strings.NewReplacer("\t", " ", "\r\n", "<br>", "\n", "<br>")
+	words := make([]string, len(m.wordAnalysis))
+	for i, w := range m.wordAnalysis {
+		words[i] = "<b>" + w.WordInTargetLang + "</b>"
+		if w.GrammaticalExplanation != "" {
+			words[i] += ": " + w.GrammaticalExplanation
+		}
+	}
+	return strings.Join([]string{
+		field.Replace(m.foreignSentence()),
+		field.Replace(m.otherSentence()),
+		field.Replace(strings.Join(words, "<br>")),
+	}, "\t") + "\n"
+}
+
+// resultJSON returns the result with its full word analysis as JSON.
+func (m model) resultJSON() string {
+	data, err := json.MarshalIndent(copiedResult{
+		UserLang:    m.userLang,
+		TargetLang:  m.targetLang,
+		Original:    m.originalSentence,
+		Translation: m.translation,
+		Level:       m.sentenceLevel,
+		Tone:        m.sentenceTone,
+		Words:       m.wordAnalysis,
+	}, "", "  ")
+	if err != nil {
+		return "" // Strings and slices of them always encode
+	}
+	return string(data) + "\n"
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/translate"
+)
+
+// openDebugView switches to the debug view, enabling recording if it was off.
+func (m model) openDebugView() (model, tea.Cmd) {
+	if !translate.DebugEnabled() {
+		translate.EnableDebug("")
+	}
+	m.navigate(stateDebug)
+	m.debugOffset = 0
+	return m, nil
+}
+
+// updateDebugView handles key presses in the debug view.
+func (m model) updateDebugView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	lines := m.debugLines()
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Debug, m.keys.Back):
+		m.pop()
+	case key.Matches(msg, m.keys.Up):
+		if m.debugOffset > 0 {
+			m.debugOffset--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.debugOffset < len(lines)-1 {
+			m.debugOffset++
+		}
+	case key.Matches(msg, m.keys.PageUp):
+		m.debugOffset = max(0, m.debugOffset-m.debugPageSize())
+	case key.Matches(msg, m.keys.PageDown):
+		m.debugOffset = min(max(0, len(lines)-1), m.debugOffset+m.debugPageSize())
+	case key.Matches(msg, m.keys.Home):
+		m.debugOffset = 0
+	case key.Matches(msg, m.keys.End):
+		m.debugOffset = max(0, len(lines)-m.debugPageSize())
+	}
+	return m, nil
+}
+
+// debugPageSize returns the number of record lines that fit on screen.
+func (m model) debugPageSize() int {
+	if m.height <= 0 {
+		return 20
+	}
+	lines := 7 // Title, help and status bar take the remaining lines
+	if m.timings.Total() > 0 {
+		lines += 2
+	}
+	return max(1, m.height-lines)
+}
+
+// debugLines renders all recorded API calls as plain lines, newest first.
+func (m model) debugLines() []string {
+	records := translate.DebugRecords()
+	var lines []string
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		lines = append(lines, fmt.Sprintf("── %s | %s %s | %s | %s | tokens in/out: %d/%d",
+			r.Time.Format("15:04:05"), r.Provider, r.Model, r.Step, r.Latency.Round(time.Millisecond), r.InputTokens, r.OutputTokens))
+		if r.Err != "" {
+			lines = append(lines, "ERROR: "+r.Err)
+		}
+		lines = append(lines, "PROMPT:")
+		lines = append(lines, strings.Split(r.Prompt, "\n")...)
+		if r.Schema != nil {
+			schema, _ := json.MarshalIndent(r.Schema, "", "  ")
+			lines = append(lines, "SCHEMA:")
+			lines = append(lines, strings.Split(string(schema), "\n")...)
+		}
+		lines = append(lines, "RESPONSE:")
+		lines = append(lines, strings.Split(r.Response, "\n")...)
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// viewDebug renders the scrollable debug view.
+func (m model) viewDebug() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Debug: API Calls"))
+	s.WriteString("\n\n")
+	if m.timings.Total() > 0 {
+		s.WriteString(labelStyle.Render(fmt.Sprintf("Last result: %s in total (%s)", m.timings.Total().Round(time.Millisecond), m.timings)))
+		s.WriteString("\n\n")
+	}
+
+	lines := m.debugLines()
+	if len(lines) == 0 {
+		s.WriteString("No API calls recorded yet.\n")
+	}
+	end := min(len(lines), m.debugOffset+m.debugPageSize())
+	for _, line := range lines[min(m.debugOffset, end):end] {
+		s.WriteString(normalStyle.Render(line))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.PageUp, "Page up"}, helpEntry{m.keys.PageDown, "Page down"}, helpEntry{m.keys.Debug, "Back"}, helpEntry{m.keys.Back, "Back"})))
+	return s.String()
+}
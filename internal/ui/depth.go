@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"cmp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// depthSetting is the settings row of the analysis depth, also changed with its own key.
+var depthSetting = setting{
+	name: "Analysis depth", key: "depth",
+	value: func(m model) string { return cmp.Or(m.cfg.Depth, translator.DepthStandard) },
+	change: func(m *model, dir int) any {
+		m.cfg.Depth = cycle(translator.Depths, cmp.Or(m.cfg.Depth, translator.DepthStandard), dir)
+		return m.cfg.Depth
+	},
+}
+
+// cycleDepth switches to the next analysis depth for the following translations and
+// saves it to the config file.
+func (m model) cycleDepth() (tea.Model, tea.Cmd) {
+	value := depthSetting.change(&m, 1)
+	m.status = normalStyle.Render("Analysis depth: " + m.cfg.Depth + " (from the next translation)")
+	return m, saveSetting(m.cfg.Path, depthSetting, value)
+}
+
+// writeDeepAnalysis renders the morphology, usage notes and collocations of a word from
+// a deep analysis, and its etymology if asked for.
+func writeDeepAnalysis(s *strings.Builder, word translator.WordInfo) {
+	if word.Morphology != "" {
+		s.WriteString(labelStyle.Render("      Morphology: "))
+		s.WriteString(normalStyle.Render(word.Morphology))
+		s.WriteString("\n")
+	}
+	if word.Usage != "" {
+		s.WriteString(labelStyle.Render("      Usage: "))
+		s.WriteString(normalStyle.Render(word.Usage))
+		s.WriteString("\n")
+	}
+	if len(word.Collocations) > 0 {
+		s.WriteString(labelStyle.Render("      Collocations: "))
+		s.WriteString(normalStyle.Render(strings.Join(word.Collocations, ", ")))
+		s.WriteString("\n")
+	}
+	if word.Etymology != "" {
+		s.WriteString(labelStyle.Render("      Etymology: "))
+		s.WriteString(normalStyle.Render(word.Etymology))
+		s.WriteString("\n")
+	}
+}
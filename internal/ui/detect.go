@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/document"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// detectOption is the entry of the target language menu that detects the language of
+// each sentence instead of fixing it in advance.
+var detectOption = language{"auto", "Detect automatically"}
+
+// detectionResult represents the outcome of detecting the language of a sentence.
+type detectionResult struct {
+	detection *translator.Detection
+	err       error
+}
+
+// detectLanguage creates a tea.Cmd that identifies the language of a sentence.
+func detectLanguage(cfg config.Config, sentence string, langs ...string) tea.Cmd {
+	return func() tea.Msg {
+		detection, err := translate.DetectLanguage(context.Background(), cfg, sentence, langs...)
+		return detectionResult{detection: detection, err: err}
+	}
+}
+
+// targetMenu returns the languages offered as the other side of the pair. Detection is
+// offered for single sentences only, since documents and watched files need a fixed pair.
+func (m model) targetMenu() []language {
+	langs := getAvailableTargetLanguages(m.targetLangs, m.userLang)
+	if m.doc == nil && m.watch == nil {
+		langs = append([]language{detectOption}, langs...)
+	}
+	return langs
+}
+
+// startDetection identifies the language of the typed sentence before translating it.
+// Articles are fetched before their language is known, so the pair is asked for instead.
+func (m model) startDetection() (tea.Model, tea.Cmd) {
+	if document.IsURL(m.input) {
+		return m.askTargetLanguage("Articles need a language pair: choose the language to translate into"), nil
+	}
+	m.loading = true
+	m.loadingStep = "Detecting language"
+	m.deadline = time.Time{}
+	m.err = nil
+	langs := []string{m.userLang}
+	for _, lang := range m.targetLangs {
+		langs = append(langs, lang.code)
+	}
+	return m, tea.Batch(detectLanguage(m.cfg, m.input, langs...), m.spinner.Tick)
+}
+
+// handleDetectionResult translates the sentence into the user's language if it is in
+// another language, and asks for the other side of the pair if it is the user's own
+// language or could not be told apart.
+func (m model) handleDetectionResult(msg detectionResult) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.failure = msg.err
+		m.state = stateError
+		return m, nil
+	}
+	d := msg.detection
+	switch {
+	case d.Language == "" || d.Ambiguous:
+		return m.askTargetLanguage(fmt.Sprintf("Could not tell the language for sure (maybe %s): choose the other language", displayName(d))), nil
+	case d.Language == m.userLang:
+		return m.askTargetLanguage(fmt.Sprintf("Detected %s: choose the language to translate into", displayName(d))), nil
+	}
+	m.detected = d
+	m.targetLang = d.Language
+	m.status = successStyle.Render("Detected " + displayName(d))
+	next, cmd := m.startTranslation()
+	return next, tea.Batch(cmd, rememberPair(m.userLang, m.targetLang))
+}
+
+// askTargetLanguage shows the target language menu for the pending sentence.
+func (m model) askTargetLanguage(reason string) model {
+	m.returnTo(stateSelectTargetLang)
+	m.langs = getAvailableTargetLanguages(m.targetLangs, m.userLang)
+	m.filteredLangs = m.langs
+	m.status = warningStyle.Render(reason)
+	return m
+}
+
+// needTargetLanguage explains that a feature needs a language pair while nothing has
+// been detected yet.
+func (m model) needTargetLanguage() (tea.Model, tea.Cmd) {
+	m.status = warningStyle.Render("Translate a sentence first so its language can be detected")
+	return m, nil
+}
+
+// displayName names a detected language, falling back to its code.
+func displayName(d *translator.Detection) string {
+	if d.Name != "" {
+		return d.Name
+	}
+	return d.Language
+}
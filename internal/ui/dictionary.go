@@ -0,0 +1,225 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// dictionarySetting is the settings row that keeps the personal dictionary.
+var dictionarySetting = setting{
+	name: "Dictionary", key: "dictionary",
+	value: func(m model) string {
+		if m.cfg.Dictionary {
+			return "collect words"
+		}
+		return "off"
+	},
+	change: func(m *model, dir int) any {
+		m.cfg.Dictionary = !m.cfg.Dictionary
+		return m.cfg.Dictionary
+	},
+}
+
+// dictionaryResult carries the personal dictionary as loaded.
+type dictionaryResult struct {
+	entries []storage.DictionaryEntry
+	err     error
+}
+
+// loadDictionary creates a tea.Cmd that reads the personal dictionary.
+func loadDictionary() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := storage.LoadDictionary()
+		return dictionaryResult{entries: entries, err: err}
+	}
+}
+
+// addToDictionary creates a tea.Cmd that adds words analyzed apart from a translation
+// to the personal dictionary, if it is kept.
+func (m model) addToDictionary(sentence string, words []translator.WordInfo) tea.Cmd {
+	if !m.cfg.Dictionary || len(words) == 0 {
+		return nil
+	}
+	lang := m.targetLang
+	return func() tea.Msg {
+		_, err := storage.AddToDictionary(lang, sentence, words, time.Now())
+		return storageResult{err: err}
+	}
+}
+
+// openDictionary shows the personal dictionary of the language being learned.
+func (m model) openDictionary() (model, tea.Cmd) {
+	m.navigate(stateDictionary)
+	m.dictionarySearch = ""
+	m.searchingDictionary = false
+	m.selectedEntry = 0
+	return m, loadDictionary()
+}
+
+// handleDictionaryResult shows the personal dictionary as loaded.
+func (m model) handleDictionaryResult(msg dictionaryResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		return m, nil
+	}
+	m.dictionary = msg.entries
+	m.selectedEntry = 0
+	return m, nil
+}
+
+// dictionaryView returns the dictionary entries of the language being learned that
+// match the search.
+func (m model) dictionaryView() []storage.DictionaryEntry {
+	var view []storage.DictionaryEntry
+	for _, e := range m.dictionary {
+		if e.Lang == m.targetLang && (m.dictionarySearch == "" || e.Matches(m.dictionarySearch)) {
+			view = append(view, e)
+		}
+	}
+	return view
+}
+
+// updateDictionary handles key presses in the personal dictionary. Filter starts a
+// search, which is typed until Select keeps it or Back clears it.
+func (m model) updateDictionary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.searchingDictionary {
+		if text, ok := typedText(msg); ok {
+			m.dictionarySearch += text
+			m.selectedEntry = 0
+			return m, nil
+		}
+	}
+	n := len(m.dictionaryView())
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case m.searchingDictionary && key.Matches(msg, m.keys.Select):
+		m.searchingDictionary = false
+	case m.searchingDictionary && key.Matches(msg, m.keys.Back):
+		m.searchingDictionary = false
+		m.dictionarySearch = ""
+		m.selectedEntry = 0
+	case m.searchingDictionary && msg.Type == tea.KeyBackspace:
+		if len(m.dictionarySearch) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.dictionarySearch)
+			m.dictionarySearch = m.dictionarySearch[:len(m.dictionarySearch)-size]
+			m.selectedEntry = 0
+		}
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Dictionary):
+		m.pop()
+	case key.Matches(msg, m.keys.Filter):
+		m.searchingDictionary = true
+	case key.Matches(msg, m.keys.Up):
+		m.selectedEntry = max(m.selectedEntry-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.selectedEntry = min(m.selectedEntry+1, max(n-1, 0))
+	case key.Matches(msg, m.keys.PageUp):
+		m.selectedEntry = max(m.selectedEntry-m.dictionaryPageSize(), 0)
+	case key.Matches(msg, m.keys.PageDown):
+		m.selectedEntry = min(m.selectedEntry+m.dictionaryPageSize(), max(n-1, 0))
+	}
+	return m, nil
+}
+
+// dictionaryPageSize returns the number of entries listed at once, leaving room for
+// the details of the selected one.
+func (m model) dictionaryPageSize() int {
+	if m.height <= 0 {
+		return 15
+	}
+	return max(3, m.height-20)
+}
+
+// viewDictionary renders the personal dictionary of the language being learned: a
+// page of its entries around the selected one, followed by the details of that one.
+func (m model) viewDictionary() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(fmt.Sprintf("Dictionary: %s", m.getLangName(m.targetLang))))
+	s.WriteString("\n\n")
+	if m.searchingDictionary || m.dictionarySearch != "" {
+		cursor := ""
+		if m.searchingDictionary {
+			cursor = "█"
+		}
+		s.WriteString(fmt.Sprintf("Search: %s%s\n\n", m.dictionarySearch, cursor))
+	}
+
+	view := m.dictionaryView()
+	switch {
+	case len(view) == 0 && m.dictionarySearch != "":
+		s.WriteString(normalStyle.Render("No words match the search."))
+		s.WriteString("\n\n")
+	case len(view) == 0 && !m.cfg.Dictionary:
+		s.WriteString(normalStyle.Render("No words yet. Turn on Dictionary in the settings to collect every analyzed word."))
+		s.WriteString("\n\n")
+	case len(view) == 0:
+		s.WriteString(normalStyle.Render("No words yet. Every analyzed word is collected here."))
+		s.WriteString("\n\n")
+	}
+
+	page := m.dictionaryPageSize()
+	first := max(0, min(m.selectedEntry-page/2, len(view)-page))
+	for i := first; i < min(first+page, len(view)); i++ {
+		e := view[i]
+		line := e.Lemma
+		if e.Gloss != "" {
+			line += " — " + e.Gloss
+		}
+		if i == m.selectedEntry {
+			s.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			s.WriteString(normalStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+	if m.selectedEntry < len(view) {
+		s.WriteString("\n")
+		writeDictionaryEntry(&s, view[m.selectedEntry])
+	}
+
+	s.WriteString("\n")
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	if m.searchingDictionary {
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Keep search"}, helpEntry{m.keys.Back, "Clear search"}) + " | Type to search"))
+	} else {
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Filter, "Search"}, helpEntry{m.keys.Back, "Back"})))
+	}
+	return s.String()
+}
+
+// writeDictionaryEntry renders what is known of a dictionary entry.
+func writeDictionaryEntry(s *strings.Builder, e storage.DictionaryEntry) {
+	heading := e.Lemma
+	for _, detail := range []string{e.PartOfSpeech, e.Gender, e.Level} {
+		if detail != "" {
+			heading += ", " + detail
+		}
+	}
+	s.WriteString(labelStyle.Render(heading))
+	s.WriteString("\n")
+	if len(e.Forms) > 0 {
+		s.WriteString(normalStyle.Render("Forms: " + strings.Join(e.Forms, ", ")))
+		s.WriteString("\n")
+	}
+	if e.Grammar != "" {
+		s.WriteString(normalStyle.Render(e.Grammar))
+		s.WriteString("\n")
+	}
+	for _, sentence := range e.Sentences {
+		s.WriteString(normalStyle.Render("  “" + sentence + "”"))
+		s.WriteString("\n")
+	}
+	s.WriteString(normalStyle.Render(fmt.Sprintf("First seen %s, last seen %s (%d×)", e.FirstSeen.Format("2006-01-02"), e.LastSeen.Format("2006-01-02"), e.Seen)))
+	s.WriteString("\n")
+}
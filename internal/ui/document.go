@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/document"
+	"github.com/brittaao/translation-tui/internal/notify"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+)
+
+// Options selects optional modes of the TUI.
+type Options struct {
+	// Document is read sentence by sentence instead of typing sentences.
+	Document *document.Document
+
+	// Watch is followed and the lines appended to it are translated as they come in.
+	Watch *document.Tail
+
+	// Terminal is that of a session on another machine, such as over SSH, which is asked
+	// to copy to its clipboard instead of this machine's clipboard.
+	Terminal io.Writer
+
+	// Review opens the review of the vocab cards due, above the first screen.
+	Review bool
+}
+
+// apply sets up the model for the selected modes, restoring the reading position of a document.
+func (o Options) apply(m model) (model, error) {
+	m.watch = o.Watch
+	m.terminal = o.Terminal
+	if o.Review {
+		m.navigate(stateReview)
+	}
+	if o.Document == nil {
+		return m, nil
+	}
+	pos, err := storage.LoadReadingPosition(o.Document.Path)
+	if err != nil {
+		return m, err
+	}
+	m.doc = o.Document
+	m.docIndex = min(pos.Sentence, len(m.doc.Sentences)-1)
+	m.docResults = map[int]translate.Result{}
+	return m, nil
+}
+
+// openDocument shows the document at the saved reading position.
+func (m model) openDocument() (tea.Model, tea.Cmd) {
+	m.state = stateDocument
+	m.err = nil
+	if m.docIndex > 0 {
+		m.status = normalStyle.Render(fmt.Sprintf("Continuing at sentence %d", m.docIndex+1))
+	}
+	return m, nil
+}
+
+// updateDocument handles key presses while reading a document.
+func (m model) updateDocument(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Debug):
+		return m.openDebugView()
+	case key.Matches(msg, m.keys.Model):
+		return m.openModelPicker()
+	case key.Matches(msg, m.keys.Back):
+		return m.back()
+	case key.Matches(msg, m.keys.PrevSentence), key.Matches(msg, m.keys.Up):
+		return m.moveInDocument(m.docIndex - 1)
+	case key.Matches(msg, m.keys.NextSentence), key.Matches(msg, m.keys.Down):
+		return m.moveInDocument(m.docIndex + 1)
+	case key.Matches(msg, m.keys.Home):
+		return m.moveInDocument(0)
+	case key.Matches(msg, m.keys.End):
+		return m.moveInDocument(len(m.doc.Sentences) - 1)
+	case key.Matches(msg, m.keys.Select):
+		if _, ok := m.docResults[m.docIndex]; ok || m.loading {
+			return m, nil
+		}
+		m.loading = true
+		m.loadingStep = ""
+		m.deadline = time.Time{}
+		m.err = nil
+		m.started = time.Now()
+		m.docPending = m.docIndex
+		return m, tea.Batch(translateSentence(m.cfg, m.userLang, m.targetLang, m.doc.Sentences[m.docIndex]), loadingTick(), m.spinner.Tick)
+	case key.Matches(msg, m.keys.Save):
+		if r, ok := m.docResults[m.docIndex]; ok {
+			return m, saveWordsToVocab(m.targetLang, r.Original, r.Translation, r.Words, m.cfg.Level)
+		}
+	case key.Matches(msg, m.keys.SaveAbove):
+		if r, ok := m.docResults[m.docIndex]; ok {
+			return m.saveWordsAboveLevel(r.Original, r.Translation, r.Words)
+		}
+	}
+	return m, nil
+}
+
+// moveInDocument shows sentence i and saves it as the reading position.
+func (m model) moveInDocument(i int) (tea.Model, tea.Cmd) {
+	if i < 0 || i >= len(m.doc.Sentences) || i == m.docIndex {
+		return m, nil
+	}
+	m.docIndex = i
+	m.err = nil
+	m.status = ""
+	return m, saveReadingPosition(m.doc.Path, i, len(m.doc.Sentences))
+}
+
+// handleDocumentResult stores the translation of a document sentence.
+func (m model) handleDocumentResult(msg translationResult) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m, notifyFailed(m.cfg, notify.ModeDocument, m.started, msg.err)
+	}
+	m.docResults[m.docPending] = msg.Result
+	m.usedModels = msg.Models
+	m.timings = msg.Timings
+	m.degraded = msg.Degraded
+	m.cached = msg.Cached
+	m.warnModelFallback(msg.Result)
+	entry := storage.HistoryEntry{
+		Time:        time.Now(),
+		UserLang:    m.userLang,
+		TargetLang:  m.targetLang,
+		Original:    msg.Original,
+		Translation: msg.Translation,
+		Words:       msg.Words,
+	}
+	m.session = append(m.session, entry)
+	return m, tea.Batch(recordHistory(m.cfg, entry), notifyDone(m.cfg, notify.ModeDocument, m.started, "Translation ready", msg.Translation))
+}
+
+// saveReadingPosition creates a tea.Cmd that persists the reading position of a document.
+func saveReadingPosition(path string, sentence, total int) tea.Cmd {
+	return func() tea.Msg {
+		return storageResult{err: storage.SaveReadingPosition(path, storage.ReadingPosition{
+			Sentence: sentence,
+			Total:    total,
+			Time:     time.Now(),
+		})}
+	}
+}
+
+// viewDocument renders the current sentence with its neighbours and, once requested,
+// its translation and word analysis.
+func (m model) viewDocument() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Reading: " + m.doc.Title))
+	s.WriteString("\n\n")
+	total := len(m.doc.Sentences)
+	s.WriteString(fmt.Sprintf("%s ↔ %s | Sentence %d of %d (%d%%)\n\n",
+		m.getLangName(m.userLang), m.getLangName(m.targetLang), m.docIndex+1, total, (m.docIndex+1)*100/total))
+
+	if m.docIndex > 0 {
+		s.WriteString(normalStyle.Render("  " + m.doc.Sentences[m.docIndex-1]))
+		s.WriteString("\n")
+	}
+	s.WriteString(labelStyle.Render("> "))
+	s.WriteString(valueStyle.Render(m.doc.Sentences[m.docIndex]))
+	s.WriteString("\n")
+	if m.docIndex < total-1 {
+		s.WriteString(normalStyle.Render("  " + m.doc.Sentences[m.docIndex+1]))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	switch r, ok := m.docResults[m.docIndex]; {
+	case ok:
+		if r.Degraded != "" {
+			s.WriteString(warningStyle.Render("⚠ Degraded output: " + r.Degraded))
+			s.WriteString("\n\n")
+		}
+		s.WriteString(labelStyle.Render("Translation: "))
+		s.WriteString(successStyle.Render(r.Translation))
+		s.WriteString("\n\n")
+		if r.Level != "" {
+			s.WriteString(labelStyle.Render("Level: "))
+			s.WriteString(levelBadge(r.Level, m.cfg.Level))
+			s.WriteString("\n\n")
+		}
+		writeWordAnalysis(&s, r.Words, m.cfg.Level, -1, nil)
+		s.WriteString("\n")
+	case m.loading && m.docPending == m.docIndex:
+		s.WriteString(labelStyle.Render(m.loadingView()))
+		s.WriteString("\n\n")
+	case m.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.PrevSentence, "Previous"}, helpEntry{m.keys.NextSentence, "Next"}, helpEntry{m.keys.Select, "Translate"}, helpEntry{m.keys.Save, "Save words"}, helpEntry{m.keys.SaveAbove, "Save above level"}, helpEntry{m.keys.Back, "Back"}, helpEntry{m.keys.Help, "Help"})))
+	return s.String()
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// draftInterval is how often the sentence being typed is saved.
+const draftInterval = 3 * time.Second
+
+// draftTickMsg saves the sentence being typed if it changed.
+type draftTickMsg struct{}
+
+// draftResult carries the draft saved by an earlier run.
+type draftResult struct {
+	draft storage.Draft
+	err   error
+}
+
+// draftTick creates a tea.Cmd that saves the draft after draftInterval.
+func draftTick() tea.Cmd {
+	return tea.Tick(draftInterval, func(time.Time) tea.Msg { return draftTickMsg{} })
+}
+
+// loadDraft creates a tea.Cmd that reads the draft saved by an earlier run.
+func loadDraft() tea.Cmd {
+	return func() tea.Msg {
+		draft, err := storage.LoadDraft()
+		return draftResult{draft: draft, err: err}
+	}
+}
+
+// saveDraft creates a tea.Cmd that saves the sentence being typed, or removes the
+// draft if it is empty.
+func saveDraft(sentence string) tea.Cmd {
+	return func() tea.Msg {
+		if err := storage.SaveDraft(storage.Draft{Sentence: sentence, Saved: time.Now()}); err != nil {
+			return storageResult{err: err}
+		}
+		return nil // Saving quietly keeps the status
+	}
+}
+
+// keepDraft saves sentence as the draft if it changed since it was last saved.
+func (m *model) keepDraft(sentence string) tea.Cmd {
+	if sentence == m.draft {
+		return nil
+	}
+	m.draft = sentence
+	return saveDraft(sentence)
+}
+
+// restoreDraft puts the draft back into the sentence input if it is shown empty, as
+// after going back to the language menus or when the app starts again.
+func (m *model) restoreDraft() {
+	if m.state != stateInputSentence || m.input != "" || m.draft == "" || m.loading {
+		return
+	}
+	m.input = m.draft
+	m.status = normalStyle.Render("Restored the sentence you were typing")
+}
+
+// keepDraftOnTransition saves the sentence input when its screen is left, which other
+// screens may clear, and restores it when the screen is shown again.
+func (m model) keepDraftOnTransition(prev model, cmd tea.Cmd) (model, tea.Cmd) {
+	if prev.state == stateInputSentence {
+		cmd = tea.Batch(cmd, m.keepDraft(prev.input))
+	}
+	m.restoreDraft()
+	return m, cmd
+}
+
+// handleDraftTick saves the sentence being typed and waits for the next save.
+func (m model) handleDraftTick() (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.state == stateInputSentence {
+		cmd = m.keepDraft(m.input)
+	}
+	return m, tea.Batch(cmd, draftTick())
+}
+
+// handleDraftResult restores the draft of an earlier run, if the sentence input is
+// shown already.
+func (m model) handleDraftResult(msg draftResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		return m, nil
+	}
+	m.draft = msg.draft.Sentence
+	m.restoreDraft()
+	return m, nil
+}
@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/internal/wiktionary"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Largest number of Wiktionary senses shown for a word
+const maxSenses = 8
+
+// wiktionaryLookup is the state of a Wiktionary lookup of a word.
+type wiktionaryLookup struct {
+	entry   *wiktionary.Entry
+	err     error
+	pending bool
+}
+
+// wiktionaryResult represents a finished Wiktionary lookup.
+type wiktionaryResult struct {
+	key   string
+	entry *wiktionary.Entry
+	err   error
+}
+
+// lookupKey identifies the lookup of a word in a language.
+func lookupKey(lang, word string) string {
+	return lang + "|" + word
+}
+
+// lookupWiktionary creates a tea.Cmd that looks up word in the Wiktionary entries of lang.
+func lookupWiktionary(cfg config.Config, lang, word string) tea.Cmd {
+	return func() tea.Msg {
+		result := wiktionaryResult{key: lookupKey(lang, word)}
+		client, err := translate.NewHTTPClient(cfg.Network)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		result.entry, result.err = translator.RunStep(context.Background(), cfg.Timeout, "Looking up", nil, func(ctx context.Context) (*wiktionary.Entry, error) {
+			return wiktionary.Lookup(ctx, client, cfg.WiktionaryURL, word, translator.LanguageName(lang))
+		})
+		return result
+	}
+}
+
+// selectFirstWord selects the first word of the current analysis and closes the drill-down.
+func (m *model) selectFirstWord() {
+	m.wordSelected = -1
+	m.wordOffset = 0
+	if len(m.analysisView()) > 0 {
+		m.wordSelected = 0
+	}
+	m.wordExpanded = false
+}
+
+// hasWords reports whether the current result has an analysis to select words in.
+func (m model) hasWords() bool {
+	return m.wordSelected >= 0
+}
+
+// selectedWord returns the selected word of the analysis.
+func (m model) selectedWord() (translator.WordInfo, bool) {
+	view := m.analysisView()
+	if m.wordSelected < 0 || m.wordSelected >= len(view) {
+		return translator.WordInfo{}, false
+	}
+	return view[m.wordSelected], true
+}
+
+// moveWordSelection selects the word dir positions from the selected one.
+func (m model) moveWordSelection(dir int) model {
+	if i := m.wordSelected + dir; m.hasWords() && i >= 0 && i < len(m.analysisView()) {
+		m.wordSelected = i
+		m.wordOffset, _ = m.tableWindow(len(m.analysisView()))
+	}
+	return m
+}
+
+// lookUpSelectedWord opens the drill-down of the selected word and looks it up in
+// Wiktionary, unless it already was.
+func (m model) lookUpSelectedWord() (tea.Model, tea.Cmd) {
+	word, ok := m.selectedWord()
+	if !ok {
+		return m, nil
+	}
+	m.wordExpanded = true
+	key := lookupKey(m.targetLang, word.WordInTargetLang)
+	if l, ok := m.lookups[key]; ok && (l.pending || l.err == nil) {
+		return m, nil
+	}
+	if m.lookups == nil {
+		m.lookups = map[string]wiktionaryLookup{}
+	}
+	m.lookups[key] = wiktionaryLookup{pending: true}
+	return m, lookupWiktionary(m.cfg, m.targetLang, word.WordInTargetLang)
+}
+
+// handleWiktionaryResult stores the outcome of a lookup.
+func (m model) handleWiktionaryResult(msg wiktionaryResult) (tea.Model, tea.Cmd) {
+	m.lookups[msg.key] = wiktionaryLookup{entry: msg.entry, err: msg.err}
+	return m, nil
+}
+
+// writeDrillDown renders the details of the selected word below it: the words of a
+// multi-word expression, its Wiktionary entry, example sentences from Tatoeba, its
+// synonyms and antonyms and a hint to play its pronunciation.
+func (m model) writeDrillDown(s *strings.Builder, word translator.WordInfo) {
+	writeParts(s, word.Parts)
+	l, ok := m.lookups[lookupKey(m.targetLang, word.WordInTargetLang)]
+	switch {
+	case !ok:
+		s.WriteString(normalStyle.Render(fmt.Sprintf("      %s: look up in Wiktionary", m.bindingKeys(m.keys.Wiktionary))))
+		s.WriteString("\n")
+	case l.pending:
+		s.WriteString(normalStyle.Render("      Looking up in Wiktionary..."))
+		s.WriteString("\n")
+	case l.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("      Wiktionary: %v", l.err)))
+		s.WriteString("\n")
+	default:
+		writeWiktionaryEntry(s, l.entry)
+	}
+	m.writeExamples(s, word)
+	m.writeRelated(s, word)
+	s.WriteString(normalStyle.Render(fmt.Sprintf("      %s: play pronunciation", m.bindingKeys(m.keys.Pronounce))))
+	s.WriteString("\n")
+}
+
+// writeParts renders the per-word breakdown of a multi-word expression.
+func writeParts(s *strings.Builder, parts []translator.WordPart) {
+	for _, p := range parts {
+		s.WriteString("      · ")
+		s.WriteString(valueStyle.Render(p.Word))
+		if p.Analysis != "" {
+			s.WriteString(" - ")
+			s.WriteString(normalStyle.Render(p.Analysis))
+		}
+		s.WriteString("\n")
+	}
+}
+
+// writeWiktionaryEntry renders the etymology, senses and inflection tables of an entry
+// with a link to its source.
+func writeWiktionaryEntry(s *strings.Builder, e *wiktionary.Entry) {
+	s.WriteString(labelStyle.Render("      Wiktionary: "))
+	s.WriteString(normalStyle.Render(e.URL))
+	s.WriteString("\n")
+	for _, etymology := range e.Etymology {
+		s.WriteString(labelStyle.Render("      Etymology: "))
+		s.WriteString(normalStyle.Render(etymology))
+		s.WriteString("\n")
+	}
+	for i, sense := range e.Senses {
+		if i == maxSenses {
+			s.WriteString(normalStyle.Render(fmt.Sprintf("      ... %d more", len(e.Senses)-maxSenses)))
+			s.WriteString("\n")
+			break
+		}
+		s.WriteString(labelStyle.Render("      " + sense.PartOfSpeech + ": "))
+		s.WriteString(normalStyle.Render(sense.Definition))
+		s.WriteString("\n")
+	}
+	for _, t := range e.Tables {
+		s.WriteString(labelStyle.Render("      " + t.Title))
+		s.WriteString("\n")
+		writeTable(s, t.Rows)
+	}
+}
+
+// writeTable renders the rows of a table with aligned columns.
+func writeTable(s *strings.Builder, rows [][]string) {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+	for _, row := range rows {
+		s.WriteString("        ")
+		for i, cell := range row {
+			s.WriteString(normalStyle.Render(cell + strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2)))
+		}
+		s.WriteString("\n")
+	}
+}
@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"google.golang.org/genai"
+
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// errorCategory classifies a pipeline failure to suggest a fix.
+type errorCategory int
+
+const (
+	errorOther errorCategory = iota
+	errorAuth
+	errorQuota
+	errorNetwork
+	errorTimeout
+	errorParse
+	errorBlocked
+	errorOffline
+)
+
+// title returns the heading shown on the error screen.
+func (c errorCategory) title() string {
+	switch c {
+	case errorAuth:
+		return "Authentication Failed"
+	case errorQuota:
+		return "Quota Exceeded"
+	case errorNetwork:
+		return "Network Error"
+	case errorTimeout:
+		return "Request Timed Out"
+	case errorParse:
+		return "Unreadable Model Response"
+	case errorBlocked:
+		return "Blocked by Safety Filters"
+	case errorOffline:
+		return "Provider Offline"
+	default:
+		return "Translation Failed"
+	}
+}
+
+// suggestions returns actionable hints for fixing the failure.
+func (c errorCategory) suggestions() []string {
+	switch c {
+	case errorAuth:
+		return []string{
+			"Check that GEMINI_API_KEY (or your stored key) is valid and not revoked",
+			"For Vertex AI, run: gcloud auth application-default login",
+		}
+	case errorQuota:
+		return []string{
+			"Wait a minute and retry; free-tier limits reset quickly",
+			"Add more keys via [gemini] api_keys to rotate automatically",
+			"Retry with another model, which has its own quota",
+		}
+	case errorNetwork:
+		return []string{
+			"Check your internet connection",
+			"Behind a corporate network? Configure [network] proxy",
+			"Configure [fallback] libretranslate_url for offline use",
+		}
+	case errorTimeout:
+		return []string{
+			"Retry; the service may be temporarily slow",
+			"Increase timeout in the config",
+			"Retry with a faster model",
+		}
+	case errorParse:
+		return []string{
+			"Retry; model output varies between attempts",
+			"Retry with a more capable model",
+			"Start with --debug and press F12 to inspect the raw response",
+		}
+	case errorBlocked:
+		return []string{
+			"Quotes from novels or news can trip the filters; lower them with [gemini] safety_threshold",
+			"Retry with another model",
+		}
+	case errorOffline:
+		return []string{
+			"The provider is checked again in the background; retrying tries it right away",
+			"History, saved vocabulary and cached results keep working meanwhile",
+			"Configure [fallback] libretranslate_url for basic translations while offline",
+		}
+	default:
+		return []string{"Retry, or start with --debug and press F12 for details"}
+	}
+}
+
+// classifyError determines the category of a pipeline error.
+func classifyError(err error) errorCategory {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case translator.IsQuotaError(err):
+			return errorQuota
+		case apiErr.Code == http.StatusUnauthorized || apiErr.Code == http.StatusForbidden,
+			apiErr.Status == "UNAUTHENTICATED", apiErr.Status == "PERMISSION_DENIED",
+			apiErr.Code == http.StatusBadRequest && strings.Contains(apiErr.Message, "API key"):
+			return errorAuth
+		}
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, translate.ErrOffline):
+		return errorOffline
+	case errors.Is(err, translator.ErrBlocked):
+		return errorBlocked
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorTimeout
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return errorParse
+	case errors.As(err, &netErr):
+		return errorNetwork
+	case strings.Contains(err.Error(), "API key"):
+		return errorAuth
+	}
+	return errorOther
+}
+
+// updateErrorScreen handles key presses on the error screen.
+func (m model) updateErrorScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Retry):
+		translate.ResetCircuit() // Asked for explicitly, so try the provider again
+		m.state = stateInputSentence
+		return m.startTranslation()
+	case key.Matches(msg, m.keys.RetryModel):
+		m.retryAfterPick = true
+		return m.openModelPicker()
+	case key.Matches(msg, m.keys.Edit, m.keys.Back):
+		m.state = stateInputSentence
+		m.failure = nil
+	}
+	return m, nil
+}
+
+// viewErrorScreen renders the failure category, the error and suggested fixes.
+func (m model) viewErrorScreen() string {
+	category := classifyError(m.failure)
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(category.title()))
+	s.WriteString("\n\n")
+	s.WriteString(fmt.Sprintf("%s ↔ %s\n\n", m.getLangName(m.userLang), m.getLangName(m.targetLang)))
+	s.WriteString(labelStyle.Render("Sentence: "))
+	s.WriteString(valueStyle.Render(m.input))
+	s.WriteString("\n\n")
+	s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.failure)))
+	s.WriteString("\n\n")
+	s.WriteString(labelStyle.Render("Suggestions:"))
+	s.WriteString("\n")
+	for _, hint := range category.suggestions() {
+		s.WriteString(normalStyle.Render("  • " + hint))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Retry, "Retry"}, helpEntry{m.keys.RetryModel, "Retry with other model"}, helpEntry{m.keys.Edit, "Edit input"}, helpEntry{m.keys.Help, "Help"}, helpEntry{m.keys.Quit, "Quit"})))
+	return s.String()
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/tatoeba"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Largest number of Tatoeba examples shown for a word
+const maxExamples = 5
+
+// examplesLookup is the state of a Tatoeba search for a word.
+type examplesLookup struct {
+	examples *tatoeba.Examples
+	err      error
+	pending  bool
+}
+
+// examplesResult represents a finished Tatoeba search.
+type examplesResult struct {
+	key      string
+	examples *tatoeba.Examples
+	err      error
+}
+
+// searchExamples creates a tea.Cmd that searches Tatoeba for sentences in lang containing
+// phrase, translated to userLang.
+func searchExamples(cfg config.Config, lang, userLang, phrase string) tea.Cmd {
+	return func() tea.Msg {
+		result := examplesResult{key: lookupKey(lang, phrase)}
+		client, err := translate.NewHTTPClient(cfg.Network)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		result.examples, result.err = translator.RunStep(context.Background(), cfg.Timeout, "Searching examples", nil, func(ctx context.Context) (*tatoeba.Examples, error) {
+			return tatoeba.Search(ctx, client, cfg.TatoebaURL, phrase, lang, userLang)
+		})
+		return result
+	}
+}
+
+// searchSelectedExamples opens the drill-down of the selected word and searches Tatoeba
+// for examples of it, unless it already was.
+func (m model) searchSelectedExamples() (tea.Model, tea.Cmd) {
+	word, ok := m.selectedWord()
+	if !ok {
+		return m, nil
+	}
+	m.wordExpanded = true
+	key := lookupKey(m.targetLang, word.WordInTargetLang)
+	if l, ok := m.examples[key]; ok && (l.pending || l.err == nil) {
+		return m, nil
+	}
+	if m.examples == nil {
+		m.examples = map[string]examplesLookup{}
+	}
+	m.examples[key] = examplesLookup{pending: true}
+	return m, searchExamples(m.cfg, m.targetLang, m.userLang, word.WordInTargetLang)
+}
+
+// handleExamplesResult stores the outcome of a search.
+func (m model) handleExamplesResult(msg examplesResult) (tea.Model, tea.Cmd) {
+	m.examples[msg.key] = examplesLookup{examples: msg.examples, err: msg.err}
+	return m, nil
+}
+
+// writeExamples renders the Tatoeba examples of the selected word in the drill-down.
+func (m model) writeExamples(s *strings.Builder, word translator.WordInfo) {
+	l, ok := m.examples[lookupKey(m.targetLang, word.WordInTargetLang)]
+	switch {
+	case !ok:
+		s.WriteString(normalStyle.Render(fmt.Sprintf("      %s: example sentences from Tatoeba", m.bindingKeys(m.keys.Examples))))
+		s.WriteString("\n")
+	case l.pending:
+		s.WriteString(normalStyle.Render("      Searching Tatoeba..."))
+		s.WriteString("\n")
+	case l.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("      Tatoeba: %v", l.err)))
+		s.WriteString("\n")
+	default:
+		s.WriteString(labelStyle.Render("      Examples (Tatoeba):"))
+		s.WriteString("\n")
+		for i, e := range l.examples.Examples {
+			if i == maxExamples {
+				break
+			}
+			s.WriteString("      · ")
+			s.WriteString(valueStyle.Render(e.Text))
+			s.WriteString("\n        ")
+			s.WriteString(normalStyle.Render(e.Translation + "  " + e.URL))
+			s.WriteString("\n")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// translateFanout creates a tea.Cmd that translates one sentence into several target
+// languages concurrently. Each finished language is reported as a sentenceResult,
+// followed by a final paragraphDone, so the results are shown like a paragraph.
+func translateFanout(cfg config.Config, userLang string, targetLangs []string, sentence string) tea.Cmd {
+	updates := make(chan tea.Msg, len(targetLangs)+1)
+	go func() {
+		defer close(updates)
+		reqs := make([]translator.Request, len(targetLangs))
+		for i, targetLang := range targetLangs {
+			reqs[i] = translator.Request{Sentence: sentence, UserLang: userLang, TargetLang: targetLang}
+		}
+		translate.RunBatch(context.Background(), cfg, reqs, func(i int, result translate.Result, err error) {
+			updates <- sentenceResult{index: i, Result: result, err: err, updates: updates}
+		})
+		updates <- paragraphDone{}
+	}()
+	return waitForPipeline(updates)
+}
+
+// toggleFanout marks or unmarks the highlighted language of the target menu for
+// translating into several languages at once. Documents and watched files keep a
+// single pair.
+func (m model) toggleFanout() (tea.Model, tea.Cmd) {
+	if m.doc != nil || m.watch != nil || len(m.filteredLangs) == 0 {
+		return m, nil
+	}
+	code := m.filteredLangs[m.selectedLang].code
+	if code == detectOption.code {
+		return m, nil
+	}
+	if i := slices.Index(m.fanout, code); i >= 0 {
+		m.fanout = slices.Delete(m.fanout, i, i+1)
+	} else {
+		m.fanout = append(m.fanout, code)
+	}
+	return m, nil
+}
+
+// startFanout starts translating the input into each marked target language.
+func (m model) startFanout() (model, tea.Cmd) {
+	m.paragraph = make([]paragraphItem, len(m.fanout))
+	for i, targetLang := range m.fanout {
+		m.paragraph[i] = paragraphItem{sentence: m.input, targetLang: targetLang}
+	}
+	m.sentenceIndex = 0
+	m.showOverview = false
+	m.loadingStep = fmt.Sprintf("Translating into %d languages", len(m.fanout))
+	return m, tea.Batch(translateFanout(m.cfg, m.userLang, m.fanout, m.input), loadingTick(), m.spinner.Tick)
+}
+
+// isFanout reports whether the results are translations of one sentence into several
+// languages rather than the sentences of a paragraph.
+func (m model) isFanout() bool {
+	return len(m.paragraph) > 0 && m.paragraph[0].targetLang != ""
+}
+
+// fanoutNames lists the names of the marked target languages.
+func (m model) fanoutNames() string {
+	names := make([]string, len(m.fanout))
+	for i, code := range m.fanout {
+		names[i] = m.getLangName(code)
+	}
+	return strings.Join(names, ", ")
+}
+
+// viewFanoutTabs renders the target languages of the results as tabs, highlighting
+// the one shown.
+func (m model) viewFanoutTabs() string {
+	tabs := make([]string, len(m.paragraph))
+	for i, item := range m.paragraph {
+		name := m.getLangName(item.targetLang)
+		if item.err != nil {
+			name += " ✗"
+		}
+		if i == m.sentenceIndex {
+			tabs[i] = selectedStyle.Render("[" + name + "]")
+		} else {
+			tabs[i] = normalStyle.Render(" " + name + " ")
+		}
+	}
+	return strings.Join(tabs, " ")
+}
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// followUp is a question about the current result and its answer.
+type followUp struct {
+	question string
+	answer   string
+	err      error
+	pending  bool
+}
+
+// followUpAnswer represents the model's answer to follow-up question index.
+type followUpAnswer struct {
+	index    int
+	question string
+	text     string
+	err      error
+}
+
+// askFollowUp creates a tea.Cmd that asks the model a question about the current result.
+func askFollowUp(cfg config.Config, system string, history []translator.Message, index int, langs ...string) tea.Cmd {
+	question := history[len(history)-1].Text
+	return func() tea.Msg {
+		text, err := translate.Chat(context.Background(), cfg, system, history, langs...)
+		return followUpAnswer{index: index, question: question, text: text, err: err}
+	}
+}
+
+// followUpHistory returns the answered questions followed by question, as conversation turns.
+func (m model) followUpHistory(question string) []translator.Message {
+	var history []translator.Message
+	for _, f := range m.followUps {
+		if f.answer == "" {
+			continue
+		}
+		history = append(history,
+			translator.Message{Role: translator.RoleUser, Text: f.question},
+			translator.Message{Role: translator.RoleModel, Text: f.answer})
+	}
+	return append(history, translator.Message{Role: translator.RoleUser, Text: question})
+}
+
+// updateFollowUp handles key presses while a follow-up question is typed.
+func (m model) updateFollowUp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if text, ok := typedText(msg); ok {
+		m.input += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.askingFollowUp = false
+		m.input = ""
+	case key.Matches(msg, m.keys.Select):
+		question := strings.TrimSpace(m.input)
+		if question == "" {
+			return m, nil
+		}
+		result := translator.Result{Original: m.originalSentence, Translation: m.translation, Words: m.wordAnalysis, Level: m.sentenceLevel, Tone: m.sentenceTone}
+		system := translator.FollowUpInstruction(m.userLang, m.targetLang, result)
+		history := m.followUpHistory(question)
+		m.followUps = append(m.followUps, followUp{question: question, pending: true})
+		m.askingFollowUp = false
+		m.input = ""
+		return m, askFollowUp(m.cfg, system, history, len(m.followUps)-1, m.userLang, m.targetLang)
+	case msg.Type == tea.KeyBackspace:
+		if len(m.input) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.input)
+			m.input = m.input[:len(m.input)-size]
+		}
+	}
+	return m, nil
+}
+
+// handleFollowUpAnswer stores the answer to a follow-up question.
+func (m model) handleFollowUpAnswer(msg followUpAnswer) (tea.Model, tea.Cmd) {
+	if msg.index >= len(m.followUps) || m.followUps[msg.index].question != msg.question {
+		return m, nil // The result changed while waiting
+	}
+	f := &m.followUps[msg.index]
+	f.pending = false
+	f.answer = msg.text
+	f.err = msg.err
+	return m, nil
+}
+
+// writeFollowUps renders the questions and answers about the current result and the
+// question being typed.
+func (m model) writeFollowUps(s *strings.Builder) {
+	if len(m.followUps) == 0 && !m.askingFollowUp {
+		return
+	}
+	s.WriteString(labelStyle.Render("Questions:"))
+	s.WriteString("\n\n")
+	for _, f := range m.followUps {
+		s.WriteString(labelStyle.Render("  Q: "))
+		s.WriteString(valueStyle.Render(f.question))
+		s.WriteString("\n")
+		switch {
+		case f.pending:
+			s.WriteString(normalStyle.Render("  A: Thinking..."))
+		case f.err != nil:
+			s.WriteString(errorStyle.Render(fmt.Sprintf("  A: Error: %v", f.err)))
+		default:
+			s.WriteString(labelStyle.Render("  A: "))
+			s.WriteString(normalStyle.Render(f.answer))
+		}
+		s.WriteString("\n\n")
+	}
+	if m.askingFollowUp {
+		s.WriteString(fmt.Sprintf("Ask: %s█\n", m.input))
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Ask"}, helpEntry{m.keys.Back, "Cancel"})))
+		s.WriteString("\n\n")
+	}
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/brittaao/translation-tui/internal/frequency"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// wordOrder selects how the word analysis is sorted and filtered by word frequency.
+type wordOrder int
+
+const (
+	orderSentence    wordOrder = iota // As in the sentence
+	orderCommonFirst                  // Most common words first
+	orderRareFirst                    // Rarest words first
+	orderHideCommon                   // As in the sentence, without the 1,000 most common words
+)
+
+// wordOrderNames describe the word orders in the status line.
+var wordOrderNames = map[wordOrder]string{
+	orderSentence:    "sentence order",
+	orderCommonFirst: "most common first",
+	orderRareFirst:   "rarest first",
+	orderHideCommon:  "common words hidden",
+}
+
+// Words up to this rank are hidden by orderHideCommon
+const commonRank = 1000
+
+// orderWords returns the words sorted or filtered by order. Words without a rank are
+// kept last when sorting, and shown when filtering.
+func orderWords(words []translator.WordInfo, order wordOrder) []translator.WordInfo {
+	rank := func(w translator.WordInfo) int {
+		if w.Rank == 0 {
+			return math.MaxInt
+		}
+		return w.Rank
+	}
+	switch order {
+	case orderCommonFirst:
+		sorted := slices.Clone(words)
+		slices.SortStableFunc(sorted, func(a, b translator.WordInfo) int { return cmp.Compare(rank(a), rank(b)) })
+		return sorted
+	case orderRareFirst:
+		sorted := slices.Clone(words)
+		slices.SortStableFunc(sorted, func(a, b translator.WordInfo) int {
+			if a.Rank == 0 || b.Rank == 0 {
+				return cmp.Compare(rank(a), rank(b))
+			}
+			return cmp.Compare(b.Rank, a.Rank)
+		})
+		return sorted
+	case orderHideCommon:
+		return slices.DeleteFunc(slices.Clone(words), func(w translator.WordInfo) bool {
+			return w.Rank > 0 && w.Rank <= commonRank
+		})
+	}
+	return words
+}
+
+// analysisView returns the word analysis of the current result in the selected order,
+// sorted by the selected column and filtered.
+func (m model) analysisView() []translator.WordInfo {
+	words := orderWords(m.wordAnalysis, m.wordOrder)
+	return filterWords(sortWords(words, m.wordSort-1), m.wordFilter, m.cfg.Level)
+}
+
+// cycleWordOrder switches to the next word order of the analysis.
+func (m model) cycleWordOrder() model {
+	if !slices.ContainsFunc(m.wordAnalysis, func(w translator.WordInfo) bool { return w.Rank > 0 }) {
+		m.status = warningStyle.Render("No frequency list installed for " + m.getLangName(m.targetLang) + "; run: translation-tui frequency " + m.targetLang)
+		return m
+	}
+	m.wordOrder = (m.wordOrder + 1) % wordOrder(len(wordOrderNames))
+	m.selectFirstWord()
+	m.status = normalStyle.Render("Words: " + wordOrderNames[m.wordOrder])
+	return m
+}
+
+// frequencyBand renders how common a word is, if it is ranked.
+func frequencyBand(word translator.WordInfo) string {
+	band := frequency.Band(word.Rank)
+	if band == "" {
+		return ""
+	}
+	return labelStyle.Render(" · " + band)
+}
@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// genderOption is a gender to pick in the gender quiz, with its definite article if
+// the nouns saved have one.
+type genderOption struct {
+	gender  string
+	article string
+}
+
+// label names the option, such as "der · masculine", or "neuter" if no noun of that
+// gender is saved.
+func (o genderOption) label() string {
+	if o.article == "" {
+		return o.gender
+	}
+	return o.article + " · " + o.gender
+}
+
+// nounsResult carries the saved nouns of a language with their gender, for the gender
+// quiz, and the genders to pick from.
+type nounsResult struct {
+	nouns   []storage.VocabCard
+	options []genderOption
+	err     error
+}
+
+// loadNouns creates a tea.Cmd that reads the vocab cards of lang saved with a gender,
+// one per dictionary form, those most often missed first and otherwise in random order.
+func loadNouns(lang string) tea.Cmd {
+	return func() tea.Msg {
+		cards, err := storage.LoadVocab()
+		if err != nil {
+			return nounsResult{err: err}
+		}
+		seen := map[string]bool{}
+		articles := map[string]string{}
+		var nouns []storage.VocabCard
+		for _, c := range cards {
+			lemma := strings.ToLower(dictionaryForm(c))
+			if c.Lang != lang || c.Gender == "" || seen[lemma] {
+				continue
+			}
+			seen[lemma] = true
+			nouns = append(nouns, c)
+			if articles[c.Gender] == "" {
+				articles[c.Gender] = strings.ToLower(c.Article)
+			}
+		}
+		rand.Shuffle(len(nouns), func(i, j int) { nouns[i], nouns[j] = nouns[j], nouns[i] })
+		slices.SortStableFunc(nouns, func(a, b storage.VocabCard) int {
+			return cmp.Compare(missRate(b), missRate(a))
+		})
+
+		// The genders of the language, and any other of the nouns saved in the usual order
+		genders := slices.Clone(translator.LanguageGenders[lang])
+		for _, g := range []string{translator.GenderMasculine, translator.GenderFeminine, translator.GenderNeuter, translator.GenderCommon} {
+			if _, ok := articles[g]; ok && !slices.Contains(genders, g) {
+				genders = append(genders, g)
+			}
+		}
+		options := make([]genderOption, len(genders))
+		for i, g := range genders {
+			options[i] = genderOption{gender: g, article: articles[g]}
+		}
+		return nounsResult{nouns: nouns, options: options}
+	}
+}
+
+// missRate returns the share of the times a noun's gender was asked for that it was
+// missed, or 0 if never asked.
+func missRate(c storage.VocabCard) float64 {
+	if c.GenderAsked == 0 {
+		return 0
+	}
+	return float64(c.GenderMissed) / float64(c.GenderAsked)
+}
+
+// openGenders starts the gender quiz on the saved nouns of the language learned.
+func (m model) openGenders() (model, tea.Cmd) {
+	m.navigate(stateGenders)
+	m.nouns = nil
+	m.genderOptions = nil
+	m.practiceIndex = 0
+	m.genderCursor = 0
+	m.genderAnswer = ""
+	m.genderRight, m.genderTotal = 0, 0
+	m.genderMissed = nil
+	m.status = ""
+	return m, loadNouns(m.targetLang)
+}
+
+// handleNouns starts the gender quiz with the first of the saved nouns.
+func (m model) handleNouns(msg nounsResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		return m, nil
+	}
+	m.nouns = msg.nouns
+	if m.nouns == nil {
+		m.nouns = []storage.VocabCard{} // Loaded, but none saved
+	}
+	m.genderOptions = msg.options
+	return m, nil
+}
+
+// updateGenders handles key presses in the gender quiz: a gender is picked and
+// answered with Select, which records the answer on the card and then goes on to the
+// next noun.
+func (m model) updateGenders(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		return m.back()
+	case m.practiceIndex >= len(m.nouns):
+	case key.Matches(msg, m.keys.Select) && m.genderAnswer != "":
+		m.practiceIndex++
+		m.genderAnswer = ""
+		m.genderCursor = 0
+	case key.Matches(msg, m.keys.Select):
+		card := &m.nouns[m.practiceIndex]
+		m.genderAnswer = m.genderOptions[m.genderCursor].gender
+		m.genderTotal++
+		card.GenderAsked++
+		if m.genderAnswer == card.Gender {
+			m.genderRight++
+		} else {
+			card.GenderMissed++
+			m.genderMissed = append(m.genderMissed, genderedNoun(*card))
+		}
+		return m, saveReview(*card)
+	case m.genderAnswer != "":
+	case key.Matches(msg, m.keys.Up):
+		m.genderCursor = max(m.genderCursor-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.genderCursor = min(m.genderCursor+1, len(m.genderOptions)-1)
+	}
+	return m, nil
+}
+
+// genderedNoun returns a noun with its article, such as "der Bahnhof", or its gender
+// if the language has no articles.
+func genderedNoun(c storage.VocabCard) string {
+	if c.Article != "" {
+		return c.Article + " " + dictionaryForm(c)
+	}
+	return fmt.Sprintf("%s (%s)", dictionaryForm(c), c.Gender)
+}
+
+// viewGenders renders the gender quiz: the noun and the genders to pick from, and once
+// answered, the noun with its article, the sentence it was saved from and how often
+// its gender was known.
+func (m model) viewGenders() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Gender Quiz: " + m.getLangName(m.targetLang)))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.nouns == nil:
+	case m.practiceIndex >= len(m.nouns) && len(m.nouns) == 0:
+		s.WriteString(normalStyle.Render("No saved nouns with a gender yet. Save words from the results of sentences in a language with grammatical gender."))
+		s.WriteString("\n\n")
+	case m.practiceIndex >= len(m.nouns):
+		s.WriteString(successStyle.Render(fmt.Sprintf("Done: %d of %d right (%d%%).", m.genderRight, m.genderTotal, percent(m.genderRight, m.genderTotal))))
+		s.WriteString("\n\n")
+		if len(m.genderMissed) > 0 {
+			s.WriteString(labelStyle.Render("To go over: "))
+			s.WriteString(valueStyle.Render(strings.Join(m.genderMissed, ", ")))
+			s.WriteString("\n\n")
+		}
+	default:
+		card := m.nouns[m.practiceIndex]
+		s.WriteString(normalStyle.Render(fmt.Sprintf("Noun %d of %d", m.practiceIndex+1, len(m.nouns))))
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render("Noun: "))
+		s.WriteString(valueStyle.Render(dictionaryForm(card)))
+		s.WriteString("\n\n")
+		for i, o := range m.genderOptions {
+			switch {
+			case m.genderAnswer != "" && o.gender == card.Gender:
+				s.WriteString(successStyle.Render("✓ " + o.label()))
+			case m.genderAnswer != "" && o.gender == m.genderAnswer:
+				s.WriteString(errorStyle.Render("✗ " + o.label()))
+			case m.genderAnswer == "" && i == m.genderCursor:
+				s.WriteString(selectedStyle.Render("> " + o.label()))
+			default:
+				s.WriteString(normalStyle.Render("  " + o.label()))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		if m.genderAnswer == "" {
+			break
+		}
+		if m.genderAnswer == card.Gender {
+			s.WriteString(successStyle.Render("Correct: " + genderedNoun(card)))
+		} else {
+			s.WriteString(errorStyle.Render("Not quite: " + genderedNoun(card)))
+		}
+		s.WriteString("\n")
+		if sentence := strings.TrimSpace(card.ForeignSentence()); sentence != "" {
+			s.WriteString(labelStyle.Render("Saved from: "))
+			s.WriteString(valueStyle.Render(sentence))
+			s.WriteString("\n")
+		}
+		known := card.GenderAsked - card.GenderMissed
+		s.WriteString(normalStyle.Render(fmt.Sprintf("\nThis noun: %d of %d right (%d%%) · session: %d of %d", known, card.GenderAsked, percent(known, card.GenderAsked), m.genderRight, m.genderTotal)))
+		s.WriteString("\n\n")
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	switch {
+	case m.practiceIndex >= len(m.nouns):
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Back"})))
+	case m.genderAnswer == "":
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, "Answer"}, helpEntry{m.keys.Back, "Back"})))
+	default:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Next noun"}, helpEntry{m.keys.Back, "Back"})))
+	}
+	return s.String()
+}
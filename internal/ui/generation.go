@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// generationParam is a sampling parameter that can be adjusted on the generation screen.
+type generationParam struct {
+	name   string
+	step   float64 // Change per key press
+	max    float64
+	params func(*translator.Generation) *translator.StepParams
+	get    func(translator.StepParams) (float64, bool)
+	set    func(*translator.StepParams, float64, bool)
+}
+
+// generationParams lists the adjustable parameters of both steps.
+var generationParams = []generationParam{
+	{"Translation temperature", 0.1, 2, translationParams, temperature, setTemperature},
+	{"Translation top-p", 0.05, 1, translationParams, topP, setTopP},
+	{"Translation max output tokens", 256, 65536, translationParams, maxOutputTokens, setMaxOutputTokens},
+	{"Analysis temperature", 0.1, 2, analysisParams, temperature, setTemperature},
+	{"Analysis top-p", 0.05, 1, analysisParams, topP, setTopP},
+	{"Analysis max output tokens", 256, 65536, analysisParams, maxOutputTokens, setMaxOutputTokens},
+}
+
+func translationParams(g *translator.Generation) *translator.StepParams { return &g.Translation }
+
+func analysisParams(g *translator.Generation) *translator.StepParams { return &g.Analysis }
+
+func temperature(p translator.StepParams) (float64, bool) {
+	if p.Temperature == nil {
+		return 0, false
+	}
+	return *p.Temperature, true
+}
+
+func setTemperature(p *translator.StepParams, v float64, ok bool) {
+	p.Temperature = nil
+	if ok {
+		p.Temperature = &v
+	}
+}
+
+func topP(p translator.StepParams) (float64, bool) {
+	if p.TopP == nil {
+		return 0, false
+	}
+	return *p.TopP, true
+}
+
+func setTopP(p *translator.StepParams, v float64, ok bool) {
+	p.TopP = nil
+	if ok {
+		p.TopP = &v
+	}
+}
+
+func maxOutputTokens(p translator.StepParams) (float64, bool) {
+	return float64(p.MaxOutputTokens), p.MaxOutputTokens > 0
+}
+
+func setMaxOutputTokens(p *translator.StepParams, v float64, ok bool) {
+	p.MaxOutputTokens = 0
+	if ok {
+		p.MaxOutputTokens = int(v)
+	}
+}
+
+// adjust changes the parameter by delta steps, starting at 0 if it is unset. Going below
+// zero unsets it, so that the API default applies again.
+func (p generationParam) adjust(g *translator.Generation, delta int) {
+	params := p.params(g)
+	v, ok := p.get(*params)
+	if !ok && delta < 0 {
+		return
+	}
+	v += float64(delta) * p.step
+	if v < 0 {
+		p.set(params, 0, false)
+		return
+	}
+	// Snap to the step, dividing rather than multiplying by fractions to get 0.3 rather than 0.30000000000000004
+	if n := math.Round(v / p.step); p.step < 1 {
+		v = n / math.Round(1/p.step)
+	} else {
+		v = n * p.step
+	}
+	p.set(params, min(v, p.max), true)
+}
+
+// format renders the value of the parameter, or "default" if it is unset.
+func (p generationParam) format(g translator.Generation) string {
+	v, ok := p.get(*p.params(&g))
+	switch {
+	case !ok:
+		return "default"
+	case p.step >= 1:
+		return fmt.Sprintf("%d", int(v))
+	default:
+		return fmt.Sprintf("%.2f", v)
+	}
+}
+
+// saveGeneration creates a tea.Cmd that persists the sampling parameters for the active profile.
+func saveGeneration(profile string, generation translator.Generation) tea.Cmd {
+	return func() tea.Msg {
+		state, err := storage.LoadProfileState(profile)
+		if err != nil {
+			return storageResult{err: err}
+		}
+		state.Generation = generation
+		if err := storage.SaveProfileState(profile, state); err != nil {
+			return storageResult{err: err}
+		}
+		return storageResult{status: successStyle.Render(fmt.Sprintf("Saved generation parameters for profile %q", profile))}
+	}
+}
+
+// openGeneration switches to the generation screen, remembering the parameters so that
+// leaving without saving restores them.
+func (m model) openGeneration() (model, tea.Cmd) {
+	m.navigate(stateGeneration)
+	m.savedGeneration = m.cfg.Generation
+	m.selectedParam = 0
+	return m, nil
+}
+
+// updateGeneration handles key presses on the generation screen.
+func (m model) updateGeneration(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.cfg.Generation = m.savedGeneration
+		m.pop()
+	case key.Matches(msg, m.keys.Up):
+		if m.selectedParam > 0 {
+			m.selectedParam--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selectedParam < len(generationParams)-1 {
+			m.selectedParam++
+		}
+	case key.Matches(msg, m.keys.PrevSentence):
+		generationParams[m.selectedParam].adjust(&m.cfg.Generation, -1)
+	case key.Matches(msg, m.keys.NextSentence):
+		generationParams[m.selectedParam].adjust(&m.cfg.Generation, 1)
+	case key.Matches(msg, m.keys.Reset):
+		defaults := translator.DefaultGeneration()
+		p := generationParams[m.selectedParam]
+		v, ok := p.get(*p.params(&defaults))
+		p.set(p.params(&m.cfg.Generation), v, ok)
+	case key.Matches(msg, m.keys.Select):
+		m.pop()
+		return m, saveGeneration(m.cfg.Profile, m.cfg.Generation)
+	}
+	return m, nil
+}
+
+// viewGeneration renders the sampling parameters of both steps.
+func (m model) viewGeneration() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Generation Parameters:"))
+	s.WriteString("\n\n")
+	s.WriteString(fmt.Sprintf("Profile: %s\n\n", m.cfg.Profile))
+	for i, p := range generationParams {
+		line := fmt.Sprintf("%-30s %s", p.name, p.format(m.cfg.Generation))
+		if i == m.selectedParam {
+			s.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			s.WriteString(normalStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render("Higher temperatures give freer translations; \"default\" leaves the parameter to the API."))
+	s.WriteString("\n\n")
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.PrevSentence, "Decrease"}, helpEntry{m.keys.NextSentence, "Increase"}, helpEntry{m.keys.Reset, "Reset"}, helpEntry{m.keys.Select, "Save"}, helpEntry{m.keys.Back, "Cancel"})))
+	return s.String()
+}
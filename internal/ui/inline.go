@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// inlineModel is a compact single-lookup UI that renders into the scrollback
+// instead of the alternate screen and quits once the result is shown.
+type inlineModel struct {
+	cfg         config.Config
+	keys        keyMap
+	userLang    string
+	targetLang  string
+	input       string
+	loading     bool
+	loadingStep string
+	deadline    time.Time
+	result      translator.Result
+	degraded    string
+	done        bool
+	err         error
+}
+
+// NewInline creates the inline quick mode model for a language pair. If sentence is
+// not empty it is translated right away, otherwise the user is prompted for one.
+func NewInline(cfg config.Config, userLang, targetLang, sentence string) (tea.Model, error) {
+	if !slices.ContainsFunc(withCustomLanguages(knownLanguages, cfg.Languages), func(l language) bool { return l.code == userLang }) {
+		return nil, fmt.Errorf("unknown language %q to translate from", userLang)
+	}
+	targets := withCustomLanguages(allTargetLanguages, cfg.Languages)
+	if !slices.ContainsFunc(getAvailableTargetLanguages(targets, userLang), func(l language) bool { return l.code == targetLang }) {
+		return nil, fmt.Errorf("unknown language %q to translate to", targetLang)
+	}
+	keys, err := newKeyMap(cfg.Keys)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := applyConfiguredTheme(cfg.Theme); err != nil {
+		return nil, err
+	}
+	m := inlineModel{
+		cfg:        cfg,
+		keys:       keys,
+		userLang:   userLang,
+		targetLang: targetLang,
+		input:      strings.TrimSpace(sentence),
+	}
+	m.loading = m.input != ""
+	return m, nil
+}
+
+// Init starts the translation if the sentence was given up front.
+func (m inlineModel) Init() tea.Cmd {
+	if !m.loading {
+		return nil
+	}
+	return m.translate()
+}
+
+// translate creates a tea.Cmd that translates the input.
+func (m inlineModel) translate() tea.Cmd {
+	return tea.Batch(translateSentence(m.cfg, m.userLang, m.targetLang, m.input), loadingTick())
+}
+
+// Update handles messages for the inline quick mode.
+func (m inlineModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Quit) || msg.Type == tea.KeyEsc {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+		if text, ok := typedText(msg); ok {
+			m.input = appendText(m.input, text)
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.Select):
+			if strings.TrimSpace(m.input) != "" {
+				m.loading = true
+				return m, m.translate()
+			}
+		case msg.Type == tea.KeyBackspace:
+			m.input = deleteLast(m.input)
+		}
+
+	case pipelineProgress:
+		m.loadingStep = msg.step
+		m.deadline = msg.deadline
+		return m, waitForPipeline(msg.updates)
+
+	case loadingTickMsg:
+		if m.loading {
+			return m, loadingTick()
+		}
+
+	case translationResult:
+		m.loading = false
+		m.done = true
+		if msg.err != nil {
+			m.err = msg.err
+			return m, tea.Quit
+		}
+		m.result = msg.Result.Result
+		m.degraded = msg.Degraded
+		return m, tea.Sequence(recordHistory(m.cfg, storage.HistoryEntry{
+			Time:        time.Now(),
+			UserLang:    m.userLang,
+			TargetLang:  m.targetLang,
+			Original:    m.result.Original,
+			Translation: m.result.Translation,
+			Words:       m.result.Words,
+		}), tea.Quit)
+	}
+	return m, nil
+}
+
+// View renders the prompt line and, once done, the compact result.
+func (m inlineModel) View() string {
+	var s strings.Builder
+	s.WriteString(labelStyle.Render(fmt.Sprintf("%s ↔ %s > ", m.userLang, m.targetLang)))
+	s.WriteString(valueStyle.Render(m.input))
+	if !m.loading && !m.done {
+		s.WriteString("█")
+	}
+	s.WriteString("\n")
+
+	switch {
+	case m.loading:
+		s.WriteString(normalStyle.Render("  " + stepView(m.loadingStep, m.deadline)))
+		s.WriteString("\n")
+	case m.err != nil:
+		// The error is reported by the caller after the program exits
+	case m.done:
+		if m.degraded != "" {
+			s.WriteString(warningStyle.Render("  ⚠ " + m.degraded))
+			s.WriteString("\n")
+		}
+		s.WriteString("  ")
+		s.WriteString(successStyle.Render(m.result.Translation))
+		s.WriteString("\n")
+		for _, word := range m.result.Words {
+			s.WriteString(fmt.Sprintf("    %s", valueStyle.Render(word.WordInTargetLang)))
+			if word.GrammaticalExplanation != "" {
+				s.WriteString(" - ")
+				s.WriteString(normalStyle.Render(word.GrammaticalExplanation))
+			}
+			s.WriteString("\n")
+		}
+	}
+	return s.String()
+}
+
+// Err returns the error of a failed inline translation.
+func (m inlineModel) Err() error {
+	return m.err
+}
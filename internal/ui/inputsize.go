@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/brittaao/translation-tui/internal/document"
+	"github.com/brittaao/translation-tui/internal/translate"
+)
+
+// estimateInput roughly estimates translating the sentence input into each target language.
+func (m model) estimateInput() translate.Estimate {
+	return translate.EstimateRequest(m.cfg, m.input, len(m.fanout))
+}
+
+// viewInputSize renders the length of the sentence input with its estimated tokens and
+// cost, warning once it is long.
+func (m model) viewInputSize() string {
+	est := m.estimateInput()
+	text := fmt.Sprintf("%d characters, ~%d tokens", utf8.RuneCountInString(m.input), est.Tokens)
+	if est.Priced {
+		text += fmt.Sprintf(", ~$%.4f", est.Cost)
+	}
+	if warn := m.cfg.Input.WarnTokens; warn > 0 && est.Tokens >= warn {
+		return warningStyle.Render(text + "; long texts are slow and costly to translate, consider splitting them")
+	}
+	return labelStyle.Render(text)
+}
+
+// checkInputSize refuses the sentence input if it is too long for a model, and asks
+// before translating it if its estimated cost exceeds max_cost, which pressing Enter
+// again confirms. It reports whether translating can go ahead.
+func (m *model) checkInputSize() bool {
+	if document.IsURL(m.input) {
+		return true // The size of the article is only known once it is fetched
+	}
+	est := m.estimateInput()
+	if est.Limit > 0 && est.RequestTokens > est.Limit {
+		m.status = errorStyle.Render(fmt.Sprintf("Too long: about %s tokens, but the model takes at most %s; shorten or split the text",
+			formatTokenCount(int32(est.RequestTokens)), formatTokenCount(int32(est.Limit))))
+		return false
+	}
+	if limit := m.cfg.Input.MaxCost; limit > 0 && est.Cost > limit && m.costWarned != m.input {
+		m.costWarned = m.input
+		m.status = warningStyle.Render(fmt.Sprintf("Translating costs about $%.4f, more than max_cost ($%.4f); press %s again to translate anyway",
+			est.Cost, limit, m.bindingKeys(m.keys.Select)))
+		return false
+	}
+	m.costWarned = ""
+	return true
+}
@@ -0,0 +1,409 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyMap holds the bindings of all configurable actions.
+type keyMap struct {
+	Up              key.Binding
+	Down            key.Binding
+	PageUp          key.Binding
+	PageDown        key.Binding
+	Home            key.Binding
+	End             key.Binding
+	Select          key.Binding
+	Back            key.Binding
+	Quit            key.Binding
+	Help            key.Binding
+	Model           key.Binding
+	Debug           key.Binding
+	Save            key.Binding
+	SaveAbove       key.Binding
+	Frequency       key.Binding
+	Wiktionary      key.Binding
+	Examples        key.Binding
+	Pronounce       key.Binding
+	Related         key.Binding
+	AnalyzeRelated  key.Binding
+	SaveRelated     key.Binding
+	Retry           key.Binding
+	RetryModel      key.Binding
+	Edit            key.Binding
+	TranslationOnly key.Binding
+	AnalysisOnly    key.Binding
+	Theme           key.Binding
+	PrevSentence    key.Binding
+	NextSentence    key.Binding
+	Overview        key.Binding
+	Conversation    key.Binding
+	Expand          key.Binding
+	FollowUp        key.Binding
+	Compare         key.Binding
+	Simplify        key.Binding
+	Generation      key.Binding
+	Reset           key.Binding
+	Mark            key.Binding
+	Pairs           key.Binding
+	Settings        key.Binding
+	NextTab         key.Binding
+	PrevTab         key.Binding
+	SortWords       key.Binding
+	Filter          key.Binding
+	Depth           key.Binding
+	Stress          key.Binding
+	Analyze         key.Binding
+	Queue           key.Binding
+	Background      key.Binding
+	Undo            key.Binding
+	Accent          key.Binding
+	Characters      key.Binding
+	Redo            key.Binding
+	Dictionary      key.Binding
+	Copy            key.Binding
+	Export          key.Binding
+	Review          key.Binding
+	Again           key.Binding
+	Hard            key.Binding
+	Good            key.Binding
+	Easy            key.Binding
+	Practice        key.Binding
+	Replay          key.Binding
+	Slower          key.Binding
+	Faster          key.Binding
+}
+
+// keySpec describes a configurable action: its name in the [keys] config table,
+// default keys and help text.
+type keySpec struct {
+	name    string
+	keys    []string
+	help    string
+	binding func(*keyMap) *key.Binding
+}
+
+// keySpecs lists all configurable actions.
+var keySpecs = []keySpec{
+	{"up", []string{"up", "k"}, "Up", func(k *keyMap) *key.Binding { return &k.Up }},
+	{"down", []string{"down", "j"}, "Down", func(k *keyMap) *key.Binding { return &k.Down }},
+	{"page_up", []string{"pgup"}, "Page up", func(k *keyMap) *key.Binding { return &k.PageUp }},
+	{"page_down", []string{"pgdown"}, "Page down", func(k *keyMap) *key.Binding { return &k.PageDown }},
+	{"home", []string{"home"}, "Top", func(k *keyMap) *key.Binding { return &k.Home }},
+	{"end", []string{"end"}, "Bottom", func(k *keyMap) *key.Binding { return &k.End }},
+	{"select", []string{"enter"}, "Select", func(k *keyMap) *key.Binding { return &k.Select }},
+	{"back", []string{"esc", "q"}, "Back", func(k *keyMap) *key.Binding { return &k.Back }},
+	{"quit", []string{"ctrl+c"}, "Quit", func(k *keyMap) *key.Binding { return &k.Quit }},
+	{"help", []string{"?", "f1"}, "Help", func(k *keyMap) *key.Binding { return &k.Help }},
+	{"model", []string{"ctrl+o"}, "Model", func(k *keyMap) *key.Binding { return &k.Model }},
+	{"debug", []string{"f12"}, "Debug view", func(k *keyMap) *key.Binding { return &k.Debug }},
+	{"save", []string{"s"}, "Save words", func(k *keyMap) *key.Binding { return &k.Save }},
+	{"save_above", []string{"S"}, "Save words above my level", func(k *keyMap) *key.Binding { return &k.SaveAbove }},
+	{"frequency", []string{"F"}, "Sort words by frequency", func(k *keyMap) *key.Binding { return &k.Frequency }},
+	{"wiktionary", []string{"w"}, "Look up in Wiktionary", func(k *keyMap) *key.Binding { return &k.Wiktionary }},
+	{"examples", []string{"x"}, "Example sentences", func(k *keyMap) *key.Binding { return &k.Examples }},
+	{"pronounce", []string{"p"}, "Play pronunciation", func(k *keyMap) *key.Binding { return &k.Pronounce }},
+	{"related", []string{"n"}, "Synonyms, antonyms and phrases", func(k *keyMap) *key.Binding { return &k.Related }},
+	{"analyze_related", []string{"g"}, "Analyze the related word", func(k *keyMap) *key.Binding { return &k.AnalyzeRelated }},
+	{"save_related", []string{"v"}, "Save the related word", func(k *keyMap) *key.Binding { return &k.SaveRelated }},
+	{"retry", []string{"r"}, "Retry", func(k *keyMap) *key.Binding { return &k.Retry }},
+	{"retry_model", []string{"m"}, "Retry with other model", func(k *keyMap) *key.Binding { return &k.RetryModel }},
+	{"edit", []string{"e"}, "Edit input", func(k *keyMap) *key.Binding { return &k.Edit }},
+	{"translation_only", []string{"t"}, "Translation only", func(k *keyMap) *key.Binding { return &k.TranslationOnly }},
+	{"analysis_only", []string{"a"}, "Analysis only", func(k *keyMap) *key.Binding { return &k.AnalysisOnly }},
+	{"theme", []string{"ctrl+t"}, "Next theme", func(k *keyMap) *key.Binding { return &k.Theme }},
+	{"prev_sentence", []string{"left", "h"}, "Previous sentence", func(k *keyMap) *key.Binding { return &k.PrevSentence }},
+	{"next_sentence", []string{"right", "l"}, "Next sentence", func(k *keyMap) *key.Binding { return &k.NextSentence }},
+	{"overview", []string{"o"}, "Sentence overview", func(k *keyMap) *key.Binding { return &k.Overview }},
+	{"conversation", []string{"ctrl+n"}, "Conversation practice", func(k *keyMap) *key.Binding { return &k.Conversation }},
+	{"expand", []string{"tab"}, "Translate message", func(k *keyMap) *key.Binding { return &k.Expand }},
+	{"follow_up", []string{"f"}, "Ask a follow-up question", func(k *keyMap) *key.Binding { return &k.FollowUp }},
+	{"compare", []string{"ctrl+k"}, "Explain the difference", func(k *keyMap) *key.Binding { return &k.Compare }},
+	{"simplify", []string{"ctrl+r"}, "Simplify or paraphrase", func(k *keyMap) *key.Binding { return &k.Simplify }},
+	{"generation", []string{"ctrl+g"}, "Generation parameters", func(k *keyMap) *key.Binding { return &k.Generation }},
+	{"reset", []string{"backspace"}, "Reset to default", func(k *keyMap) *key.Binding { return &k.Reset }},
+	{"mark", []string{"tab"}, "Mark language", func(k *keyMap) *key.Binding { return &k.Mark }},
+	{"pairs", []string{"ctrl+p"}, "Recent language pairs", func(k *keyMap) *key.Binding { return &k.Pairs }},
+	{"settings", []string{"ctrl+e"}, "Settings", func(k *keyMap) *key.Binding { return &k.Settings }},
+	{"next_tab", []string{"tab"}, "Next tab", func(k *keyMap) *key.Binding { return &k.NextTab }},
+	{"prev_tab", []string{"shift+tab"}, "Previous tab", func(k *keyMap) *key.Binding { return &k.PrevTab }},
+	{"sort_words", []string{"c"}, "Sort words by column", func(k *keyMap) *key.Binding { return &k.SortWords }},
+	{"filter", []string{"/"}, "Filter words", func(k *keyMap) *key.Binding { return &k.Filter }},
+	{"depth", []string{"ctrl+d"}, "Analysis depth", func(k *keyMap) *key.Binding { return &k.Depth }},
+	{"stress", []string{"'"}, "Stress marks", func(k *keyMap) *key.Binding { return &k.Stress }},
+	{"analyze", []string{"a"}, "Pick words to analyze", func(k *keyMap) *key.Binding { return &k.Analyze }},
+	{"queue", []string{"ctrl+v"}, "Offline queue", func(k *keyMap) *key.Binding { return &k.Queue }},
+	{"background", []string{"ctrl+f"}, "Sentences translated in the background", func(k *keyMap) *key.Binding { return &k.Background }},
+	{"undo", []string{"ctrl+z"}, "Undo", func(k *keyMap) *key.Binding { return &k.Undo }},
+	{"redo", []string{"ctrl+y"}, "Redo", func(k *keyMap) *key.Binding { return &k.Redo }},
+	{"accent", []string{"ctrl+q"}, "Accents of the last letter", func(k *keyMap) *key.Binding { return &k.Accent }},
+	{"characters", []string{"ctrl+s"}, "Special characters", func(k *keyMap) *key.Binding { return &k.Characters }},
+	{"dictionary", []string{"ctrl+w"}, "Personal dictionary", func(k *keyMap) *key.Binding { return &k.Dictionary }},
+	{"copy", []string{"y"}, "Copy result", func(k *keyMap) *key.Binding { return &k.Copy }},
+	{"export", []string{"E"}, "Export results", func(k *keyMap) *key.Binding { return &k.Export }},
+	{"review", []string{"ctrl+l"}, "Review due cards", func(k *keyMap) *key.Binding { return &k.Review }},
+	{"again", []string{"1"}, "Forgotten", func(k *keyMap) *key.Binding { return &k.Again }},
+	{"hard", []string{"2"}, "Recalled with difficulty", func(k *keyMap) *key.Binding { return &k.Hard }},
+	{"good", []string{"3"}, "Recalled", func(k *keyMap) *key.Binding { return &k.Good }},
+	{"easy", []string{"4"}, "Recalled at once", func(k *keyMap) *key.Binding { return &k.Easy }},
+	{"practice", []string{"ctrl+x"}, "Practice", func(k *keyMap) *key.Binding { return &k.Practice }},
+	{"replay", []string{"tab"}, "Play again", func(k *keyMap) *key.Binding { return &k.Replay }},
+	{"slower", []string{"-"}, "Play slower", func(k *keyMap) *key.Binding { return &k.Slower }},
+	{"faster", []string{"+", "="}, "Play faster", func(k *keyMap) *key.Binding { return &k.Faster }},
+}
+
+// newKeyMap builds the key map from the defaults, replacing the keys of actions
+// set in overrides (the [keys] config table).
+func newKeyMap(overrides map[string][]string) (keyMap, error) {
+	var km keyMap
+	for _, spec := range keySpecs {
+		*spec.binding(&km) = newBinding(spec.keys, spec.help)
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		i := slices.IndexFunc(keySpecs, func(s keySpec) bool { return s.name == name })
+		if i < 0 {
+			return keyMap{}, fmt.Errorf("unknown action %q in [keys] (available: %s)", name, strings.Join(keyActionNames(), ", "))
+		}
+		if len(overrides[name]) == 0 {
+			return keyMap{}, fmt.Errorf("no keys given for action %q in [keys]", name)
+		}
+		spec := keySpecs[i]
+		*spec.binding(&km) = newBinding(overrides[name], spec.help)
+	}
+	return km, nil
+}
+
+// newBinding creates a binding for keys with the given help text.
+func newBinding(keys []string, help string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), help))
+}
+
+// keyActionNames returns the names of all configurable actions.
+func keyActionNames() []string {
+	names := make([]string, len(keySpecs))
+	for i, spec := range keySpecs {
+		names[i] = spec.name
+	}
+	return names
+}
+
+// acceptsText reports whether the current screen has a text field, in which case
+// printable keys are typed rather than treated as bindings.
+func (m model) acceptsText() bool {
+	switch m.state {
+	case stateSelectUserLang, stateSelectTargetLang, stateInputSentence, stateSetupAPIKey, stateCompare, stateSimplify:
+		return true
+	case stateListening:
+		return m.listenScores == nil
+	case stateTyping:
+		return m.typingScore == nil
+	case stateNumbers:
+		return m.figureGrade == nil
+	case stateConjugation:
+		return m.conjugation != nil && !m.conjugationChecked
+	case stateCloze:
+		return m.practiceIndex < len(m.cloze) && !m.clozeChecked
+	case stateConversation:
+		return m.chatStarted
+	case stateShowResults:
+		return m.askingFollowUp || m.filteringWords
+	}
+	return false
+}
+
+// typedText returns the text entered by a key press, if it is a printable key.
+func typedText(msg tea.KeyMsg) (string, bool) {
+	switch msg.Type {
+	case tea.KeyRunes:
+		if msg.Alt {
+			return "", false // A shortcut, or a dead key of a terminal sending Option as Meta
+		}
+		return string(msg.Runes), true
+	case tea.KeySpace:
+		return " ", true
+	}
+	return "", false
+}
+
+// keyLabel formats a key name for display, e.g. "ctrl+o" -> "Ctrl+O".
+func keyLabel(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "pgup":
+		return "PgUp"
+	case "pgdown":
+		return "PgDn"
+	}
+	if len([]rune(k)) == 1 {
+		return k
+	}
+	parts := strings.Split(k, "+")
+	for i, p := range parts {
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return strings.Join(parts, "+")
+}
+
+// bindingKeys formats the keys of a binding that work on the current screen.
+// Printable keys are left out where they would be typed into a text field.
+func (m model) bindingKeys(b key.Binding) string {
+	var labels []string
+	for _, k := range b.Keys() {
+		if m.acceptsText() && len([]rune(k)) == 1 {
+			continue
+		}
+		labels = append(labels, keyLabel(k))
+	}
+	return strings.Join(labels, "/")
+}
+
+// helpEntry pairs a binding with the description of what it does on the current screen.
+type helpEntry struct {
+	binding key.Binding
+	desc    string
+}
+
+// helpLine renders a one-line key hint such as "Enter: Translate | Esc: Back".
+func (m model) helpLine(entries ...helpEntry) string {
+	parts := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if keys := m.bindingKeys(e.binding); keys != "" {
+			parts = append(parts, keys+": "+e.desc)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// activeBindings lists the bindings of the current screen for the help overlay.
+func (m model) activeBindings() []helpEntry {
+	k := m.keys
+	common := []helpEntry{{k.Theme, "Next theme"}, {k.Help, "Toggle help"}, {k.Quit, "Quit"}}
+	switch m.state {
+	case stateSelectUserLang:
+		return append([]helpEntry{{k.Up, "Previous language"}, {k.Down, "Next language"}, {k.Select, "Select"}, {k.Pairs, "Recent language pairs"}, {k.Review, "Review due cards"}, {k.Back, "Quit"}, {k.Debug, "Debug view"}}, common...)
+	case stateSelectTargetLang:
+		return append([]helpEntry{{k.Up, "Previous language"}, {k.Down, "Next language"}, {k.Mark, "Mark for translating into several languages"}, {k.Select, "Select"}, {k.Pairs, "Recent language pairs"}, {k.Review, "Review due cards"}, {k.Back, "Back"}, {k.Debug, "Debug view"}}, common...)
+	case stateInputSentence:
+		return append([]helpEntry{{k.Select, "Translate"}, {k.Undo, "Undo"}, {k.Redo, "Redo"}, {k.Accent, "Next accent of the last letter"}, {k.Characters, "Special characters"}, {k.Model, "Model"}, {k.Generation, "Generation parameters"}, {k.Settings, "Settings"}, {k.Depth, "Next analysis depth"}, {k.Queue, "Sentences queued while offline"}, {k.Background, "Sentences translated in the background"}, {k.Dictionary, "Personal dictionary"}, {k.Review, "Review due cards"}, {k.Practice, "Practice"}, {k.Conversation, "Conversation practice"}, {k.Compare, "Explain the difference"}, {k.Simplify, "Simplify or paraphrase"}, {k.Pairs, "Recent language pairs"}, {k.Back, "Back"}, {k.Debug, "Debug view"}}, common...)
+	case stateShowResults:
+		if m.askingFollowUp {
+			return append([]helpEntry{{k.Select, "Ask"}, {k.Back, "Cancel question"}}, common...)
+		}
+		if m.filteringWords {
+			return append([]helpEntry{{k.Up, "Previous word"}, {k.Down, "Next word"}, {k.Select, "Keep filter"}, {k.Back, "Clear filter"}}, common...)
+		}
+		if m.copying {
+			return append([]helpEntry{{k.Up, "Previous format"}, {k.Down, "Next format"}, {k.Select, "Copy to the clipboard"}, {k.Back, "Cancel"}}, common...)
+		}
+		if m.exporting {
+			return append([]helpEntry{{k.Up, "Previous choice"}, {k.Down, "Next choice"}, {k.Select, "Export to a file"}, {k.Back, "Cancel"}}, common...)
+		}
+		if m.pickingWords {
+			return append([]helpEntry{{k.PrevSentence, "Previous word"}, {k.NextSentence, "Next word"}, {k.Mark, "Pick or unpick the word"}, {k.Select, "Analyze the picked words"}, {k.Back, "Cancel"}}, common...)
+		}
+		entries := []helpEntry{{k.NextTab, "Next tab"}, {k.PrevTab, "Previous tab"}, {k.PageUp, "Page up"}, {k.PageDown, "Page down"}, {k.Save, "Save words"}, {k.SaveAbove, "Save words above my level"}, {k.Frequency, "Sort or filter words by frequency"}, {k.FollowUp, "Ask a follow-up question"}, {k.Copy, "Copy to the clipboard"}, {k.Export, "Export the result or session as text, Markdown or HTML"}, {k.Model, "Model"}, {k.Generation, "Generation parameters"}, {k.Settings, "Settings"}, {k.Depth, "Next analysis depth"}, {k.Stress, "Show or hide stress marks"}, {k.Analyze, "Pick words of the sentence to analyze"}, {k.Queue, "Sentences queued while offline"}, {k.Background, "Sentences translated in the background"}, {k.Dictionary, "Personal dictionary"}, {k.Review, "Review due cards"}, {k.Practice, "Practice"}, {k.Pairs, "Recent language pairs"}, {k.Back, "Translate another"}, {k.Debug, "Debug view"}}
+		if m.resultTab == tabAlternatives {
+			entries = append(entries, helpEntry{k.Select, "Paraphrase the sentence"})
+		}
+		if m.resultTab == tabWords && m.hasWords() {
+			entries = append(entries, helpEntry{k.Up, "Previous word"}, helpEntry{k.Down, "Next word"}, helpEntry{k.Select, "Show or hide word details"}, helpEntry{k.SortWords, "Sort words by the next column"}, helpEntry{k.Filter, "Filter words"}, helpEntry{k.Wiktionary, "Look up the word in Wiktionary"}, helpEntry{k.Examples, "Example sentences from Tatoeba"}, helpEntry{k.Pronounce, "Play the pronunciation of the word"}, helpEntry{k.Related, "Synonyms, antonyms and common phrases, then select the next"}, helpEntry{k.AnalyzeRelated, "Analyze the selected synonym or antonym"}, helpEntry{k.SaveRelated, "Save the selected synonym or antonym"})
+		}
+		if m.isFanout() {
+			entries = append(entries, helpEntry{k.PrevSentence, "Previous language"}, helpEntry{k.NextSentence, "Next language"}, helpEntry{k.Overview, "All translations"})
+		} else if len(m.paragraph) > 1 {
+			entries = append(entries, helpEntry{k.PrevSentence, "Previous sentence"}, helpEntry{k.NextSentence, "Next sentence"}, helpEntry{k.Overview, "Sentence overview"})
+		}
+		return append(entries, common...)
+	case stateSelectModel:
+		return append([]helpEntry{{k.Up, "Previous model"}, {k.Down, "Next model"}, {k.Select, "Use for both steps"}, {k.TranslationOnly, "Translation only"}, {k.AnalysisOnly, "Analysis only"}, {k.Back, "Back"}}, common...)
+	case stateGeneration:
+		return append([]helpEntry{{k.Up, "Previous parameter"}, {k.Down, "Next parameter"}, {k.PrevSentence, "Decrease"}, {k.NextSentence, "Increase"}, {k.Reset, "Reset to default"}, {k.Select, "Save for profile"}, {k.Back, "Cancel"}}, common...)
+	case stateSettings:
+		return append([]helpEntry{{k.Up, "Previous setting"}, {k.Down, "Next setting"}, {k.PrevSentence, "Previous value"}, {k.NextSentence, "Next value"}, {k.Select, "Next value or pick models"}, {k.Back, "Back"}}, common...)
+	case statePending:
+		return append([]helpEntry{{k.Up, "Previous sentence"}, {k.Down, "Next sentence"}, {k.Select, "Show the results, or edit the sentence"}, {k.Retry, "Translate the waiting sentences now"}, {k.Back, "Back"}}, common...)
+	case statePractice:
+		return append([]helpEntry{{k.Up, "Previous mode"}, {k.Down, "Next mode"}, {k.Select, "Start"}, {k.Back, "Back"}}, common...)
+	case stateListening:
+		return append([]helpEntry{{k.Select, "Check, then next sentence"}, {k.Replay, "Play the sentence again"}, {k.Back, "Back"}}, common...)
+	case stateTyping:
+		return append([]helpEntry{{k.Select, "Check, then next sentence"}, {k.Back, "Back"}}, common...)
+	case stateNumbers:
+		return append([]helpEntry{{k.Select, "Check, then next figure"}, {k.Replay, "Read the figure again, by ear"}, {k.Back, "Back"}}, common...)
+	case stateConjugation:
+		return append([]helpEntry{{k.Select, "Check, then next verb"}, {k.Back, "Back"}}, common...)
+	case stateCloze:
+		return append([]helpEntry{{k.Select, "Check, then next sentence"}, {k.Back, "Back"}}, common...)
+	case stateStudy:
+		return append([]helpEntry{{k.PrevSentence, "Shorter session"}, {k.NextSentence, "Longer session"}, {k.Select, "Start"}, {k.Back, "Back"}}, common...)
+	case stateGenders:
+		return append([]helpEntry{{k.Up, "Previous gender"}, {k.Down, "Next gender"}, {k.Select, "Answer, then next noun"}, {k.Back, "Back"}}, common...)
+	case stateCases:
+		return append([]helpEntry{{k.Up, "Previous case"}, {k.Down, "Next case"}, {k.Select, "Answer, then next question"}, {k.Back, "Back"}}, common...)
+	case stateShadowing:
+		return append([]helpEntry{{k.Select, "Pause or go on"}, {k.Replay, "Play from the start"}, {k.PrevSentence, "Previous part of the sentence"}, {k.NextSentence, "Next part of the sentence"}, {k.Slower, "Play slower"}, {k.Faster, "Play faster"}, {k.Up, "Previous sentence"}, {k.Down, "Next sentence"}, {k.Back, "Back"}}, common...)
+	case stateReview:
+		return append([]helpEntry{{k.Select, "Show answer"}, {k.Again, "Forgotten: show again at the end"}, {k.Hard, "Recalled with difficulty"}, {k.Good, "Recalled"}, {k.Easy, "Recalled at once"}, {k.Back, "Back"}}, common...)
+	case stateDictionary:
+		return append([]helpEntry{{k.Up, "Previous word"}, {k.Down, "Next word"}, {k.PageUp, "Page up"}, {k.PageDown, "Page down"}, {k.Filter, "Search"}, {k.Back, "Back"}}, common...)
+	case statePairs:
+		return append([]helpEntry{{k.Up, "Previous pair"}, {k.Down, "Next pair"}, {k.Select, "Switch to pair"}, {k.Back, "Back"}}, common...)
+	case stateSetupAPIKey:
+		return append([]helpEntry{{k.Select, "Save"}, {k.Back, "Quit"}}, common...)
+	case stateDebug:
+		return append([]helpEntry{{k.Up, "Scroll up"}, {k.Down, "Scroll down"}, {k.PageUp, "Page up"}, {k.PageDown, "Page down"}, {k.Home, "Top"}, {k.End, "Bottom"}, {k.Debug, "Back"}, {k.Back, "Back"}}, common...)
+	case stateDocument:
+		return append([]helpEntry{{k.PrevSentence, "Previous sentence"}, {k.NextSentence, "Next sentence"}, {k.Home, "First sentence"}, {k.End, "Last sentence"}, {k.Select, "Translate"}, {k.Save, "Save words"}, {k.SaveAbove, "Save words above my level"}, {k.Model, "Model"}, {k.Back, "Back"}, {k.Debug, "Debug view"}}, common...)
+	case stateConversation:
+		if !m.chatStarted {
+			return append([]helpEntry{{k.Up, "Lower level"}, {k.Down, "Higher level"}, {k.Select, "Start"}, {k.Back, "Back"}}, common...)
+		}
+		return append([]helpEntry{{k.Select, "Send"}, {k.Up, "Previous partner message"}, {k.Down, "Next partner message"}, {k.Expand, "Translate & analyze message"}, {k.Back, "Back"}, {k.Debug, "Debug view"}}, common...)
+	case stateCompare:
+		return append([]helpEntry{{k.Select, "Compare"}, {k.Up, "Other field"}, {k.Down, "Other field"}, {k.Back, "Back"}, {k.Debug, "Debug view"}}, common...)
+	case stateSimplify:
+		return append([]helpEntry{{k.Select, "Rewrite"}, {k.PrevSentence, "Easier level or paraphrases"}, {k.NextSentence, "Harder level"}, {k.Back, "Back"}, {k.Debug, "Debug view"}}, common...)
+	case stateWatch:
+		return append([]helpEntry{{k.Up, "Previous line"}, {k.Down, "Next line"}, {k.Home, "First line"}, {k.End, "Latest line"}, {k.Expand, "Show or hide word analysis"}, {k.Save, "Save words"}, {k.SaveAbove, "Save words above my level"}, {k.Back, "Back"}, {k.Debug, "Debug view"}}, common...)
+	case stateError:
+		return append([]helpEntry{{k.Retry, "Retry"}, {k.RetryModel, "Retry with other model"}, {k.Edit, "Edit input"}, {k.Back, "Edit input"}}, common...)
+	}
+	return common
+}
+
+// viewHelp renders the help overlay listing the bindings of the current screen.
+func (m model) viewHelp() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Keybindings"))
+	s.WriteString("\n\n")
+	for _, e := range m.activeBindings() {
+		keys := m.bindingKeys(e.binding)
+		if keys == "" {
+			continue
+		}
+		s.WriteString(labelStyle.Render(fmt.Sprintf("  %-16s", keys)))
+		s.WriteString(normalStyle.Render(e.desc))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render("Keys can be changed in the [keys] table of config.toml. " + m.helpLine(helpEntry{m.keys.Help, "Close"})))
+	return s.String()
+}
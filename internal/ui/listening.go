@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/drill"
+	"github.com/brittaao/translation-tui/internal/pronunciation"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// sentenceAudioResult represents a sentence that was synthesized and played.
+type sentenceAudioResult struct {
+	err error
+}
+
+// playSentence creates a tea.Cmd that synthesizes a sentence in lang and plays it.
+func playSentence(cfg config.Config, lang, sentence string) tea.Cmd {
+	return func() tea.Msg {
+		audio, err := translator.RunStep(context.Background(), cfg.Timeout, "Synthesizing sentence", nil, func(ctx context.Context) (pronunciation.Audio, error) {
+			return pronunciation.Speak(ctx, cfg.Pronunciation.TTSCommand, sentence, lang)
+		})
+		if err != nil {
+			return sentenceAudioResult{err: err}
+		}
+		defer audio.Discard()
+		return sentenceAudioResult{err: pronunciation.Play(context.Background(), cfg.Pronunciation.Player, audio.Path)}
+	}
+}
+
+// openListening starts the listening quiz on the saved sentences of the language learned.
+func (m model) openListening() (model, tea.Cmd) {
+	m.navigate(stateListening)
+	m.practice = nil
+	m.practiceIndex = 0
+	m.practiceInput = ""
+	m.listenScores = nil
+	m.listenCorrect, m.listenTotal = 0, 0
+	m.status = ""
+	return m, loadPracticeSentences(m.targetLang)
+}
+
+// playListening plays the current sentence of the listening quiz.
+func (m model) playListening() (model, tea.Cmd) {
+	if m.practiceIndex >= len(m.practice) {
+		return m, nil
+	}
+	m.status = normalStyle.Render("Playing...")
+	return m, playSentence(m.cfg, m.targetLang, m.practice[m.practiceIndex].text)
+}
+
+// handleSentenceAudio reports a sentence that could not be played.
+func (m model) handleSentenceAudio(msg sentenceAudioResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Audio: %v", msg.err))
+	} else {
+		m.status = ""
+	}
+	return m, nil
+}
+
+// updateListening handles key presses in the listening quiz. What was heard is typed
+// and checked with Select, which then goes on to the next sentence.
+func (m model) updateListening(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	done := m.practiceIndex >= len(m.practice)
+	if text, ok := typedText(msg); ok && !done && m.listenScores == nil {
+		m.practiceInput += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		return m.back()
+	case done:
+	case key.Matches(msg, m.keys.Replay):
+		return m.playListening()
+	case key.Matches(msg, m.keys.Select) && m.listenScores != nil:
+		m.practiceIndex++
+		m.practiceInput = ""
+		m.listenScores = nil
+		if next, cmd, ok := m.studyTurn(m.practiceIndex >= len(m.practice)); ok {
+			return next, cmd
+		}
+		return m.playListening()
+	case key.Matches(msg, m.keys.Select):
+		m.listenScores = drill.CompareWords(m.practice[m.practiceIndex].text, m.practiceInput)
+		correct, total := drill.CountCorrect(m.listenScores)
+		m.listenCorrect += correct
+		m.listenTotal += total
+		m.recordStudy(correct, total)
+	case msg.Type == tea.KeyBackspace && m.listenScores == nil:
+		if len(m.practiceInput) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.practiceInput)
+			m.practiceInput = m.practiceInput[:len(m.practiceInput)-size]
+		}
+	}
+	return m, nil
+}
+
+// viewListening renders the listening quiz: the answer being typed, and once checked,
+// each word of the sentence marked as heard, misheard or missed.
+func (m model) viewListening() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Listening Quiz: " + m.getLangName(m.targetLang)))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.practice == nil:
+	case m.practiceIndex >= len(m.practice) && len(m.practice) == 0:
+		s.WriteString(normalStyle.Render("No saved sentences yet. Save words from the results to practice their sentences."))
+		s.WriteString("\n\n")
+	case m.practiceIndex >= len(m.practice):
+		s.WriteString(successStyle.Render(fmt.Sprintf("Done: %d of %d words heard (%d%%).", m.listenCorrect, m.listenTotal, percent(m.listenCorrect, m.listenTotal))))
+		s.WriteString("\n\n")
+	default:
+		s.WriteString(normalStyle.Render(fmt.Sprintf("Sentence %d of %d", m.practiceIndex+1, len(m.practice))))
+		s.WriteString("\n\n")
+		if m.listenScores == nil {
+			s.WriteString(fmt.Sprintf("Heard: %s█\n\n", m.practiceInput))
+			break
+		}
+		s.WriteString(fmt.Sprintf("Heard: %s\n\n", m.practiceInput))
+		writeWordScores(&s, m.listenScores)
+		current := m.practice[m.practiceIndex]
+		s.WriteString(labelStyle.Render("Sentence: "))
+		s.WriteString(valueStyle.Render(current.text))
+		s.WriteString("\n")
+		if current.translation != "" {
+			s.WriteString(labelStyle.Render("Meaning:  "))
+			s.WriteString(valueStyle.Render(current.translation))
+			s.WriteString("\n")
+		}
+		correct, total := drill.CountCorrect(m.listenScores)
+		s.WriteString(normalStyle.Render(fmt.Sprintf("\n%d of %d words (%d%%) · session: %d of %d", correct, total, percent(correct, total), m.listenCorrect, m.listenTotal)))
+		s.WriteString("\n\n")
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	switch {
+	case m.practiceIndex >= len(m.practice):
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Back"})))
+	case m.listenScores == nil:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Check"}, helpEntry{m.keys.Replay, "Play again"}, helpEntry{m.keys.Back, "Back"}) + " | Type what you hear"))
+	default:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Next sentence"}, helpEntry{m.keys.Replay, "Play again"}, helpEntry{m.keys.Back, "Back"})))
+	}
+	return s.String()
+}
+
+// writeWordScores renders the words of a sentence as scored: those given right as they
+// are, and the others marked with what was given instead.
+func writeWordScores(s *strings.Builder, scores []drill.WordScore) {
+	parts := make([]string, len(scores))
+	for i, score := range scores {
+		switch score.Verdict {
+		case drill.Correct:
+			parts[i] = successStyle.Render(score.Word)
+		case drill.Wrong:
+			parts[i] = errorStyle.Render(fmt.Sprintf("%s (not %s)", score.Word, score.Given))
+		case drill.Missing:
+			parts[i] = errorStyle.Render(fmt.Sprintf("%s (missed)", score.Word))
+		case drill.Extra:
+			parts[i] = warningStyle.Render(fmt.Sprintf("+%s", score.Word))
+		}
+	}
+	s.WriteString(strings.Join(parts, " "))
+	s.WriteString("\n\n")
+}
+
+// percent returns part of whole in percent, rounded down, or 0 of nothing.
+func percent(part, whole int) int {
+	if whole == 0 {
+		return 0
+	}
+	return part * 100 / whole
+}
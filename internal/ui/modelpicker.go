@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// modelOption represents a model that can be selected in the model picker.
+type modelOption struct {
+	name        string
+	displayName string
+	inputLimit  int32
+}
+
+// modelListResult represents the result of listing available models.
+type modelListResult struct {
+	models []modelOption
+	err    error
+}
+
+// listModels creates a tea.Cmd that fetches the models supporting content generation.
+func listModels(cfg config.Config) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		httpClient, err := translate.NewHTTPClient(cfg.Network)
+		if err != nil {
+			return modelListResult{err: err}
+		}
+		clients, err := translate.NewClients(ctx, cfg, httpClient)
+		if err != nil {
+			return modelListResult{err: err}
+		}
+		client := clients[translator.ActiveKey()%len(clients)]
+
+		var models []modelOption
+		for m, err := range client.Models.All(ctx) {
+			if err != nil {
+				return modelListResult{err: fmt.Errorf("failed to list models: %w", err)}
+			}
+			// Vertex AI does not report supported actions, so only filter when they are known
+			if len(m.SupportedActions) > 0 && !slices.Contains(m.SupportedActions, "generateContent") {
+				continue
+			}
+			models = append(models, modelOption{
+				name:        path.Base(m.Name), // Strip "models/" or "publishers/google/models/"
+				displayName: m.DisplayName,
+				inputLimit:  m.InputTokenLimit,
+			})
+		}
+		slices.SortFunc(models, func(a, b modelOption) int { return strings.Compare(a.name, b.name) })
+		return modelListResult{models: models}
+	}
+}
+
+// describe returns the context-window and pricing hints shown next to a model.
+func (o modelOption) describe() string {
+	var hints []string
+	if o.inputLimit > 0 {
+		hints = append(hints, formatTokenCount(o.inputLimit)+" ctx")
+	}
+	if p, ok := translate.LookupPricing(o.name); ok {
+		hints = append(hints, fmt.Sprintf("$%.3g/$%.3g per 1M in/out", p.Input, p.Output))
+	}
+	if len(hints) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(hints, ", ") + "]"
+}
+
+// formatTokenCount formats a token count compactly, e.g. 1048576 -> "1M".
+func formatTokenCount(n int32) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%dM", n/(1<<20))
+	case n >= 1000:
+		return fmt.Sprintf("%dk", n/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+// saveModelChoice creates a tea.Cmd that persists the selected models for the active profile.
+func saveModelChoice(profile string, models translator.Models) tea.Cmd {
+	return func() tea.Msg {
+		state, err := storage.LoadProfileState(profile)
+		if err != nil {
+			return storageResult{err: err}
+		}
+		state.Models = models
+		if err := storage.SaveProfileState(profile, state); err != nil {
+			return storageResult{err: err}
+		}
+		return storageResult{status: successStyle.Render(fmt.Sprintf("Saved model choice for profile %q", profile))}
+	}
+}
+
+// openModelPicker switches to the model picker and starts loading the model list.
+func (m model) openModelPicker() (model, tea.Cmd) {
+	m.navigate(stateSelectModel)
+	m.modelOptions = nil
+	m.selectedModel = 0
+	m.modelsLoading = true
+	m.err = nil
+	return m, listModels(m.cfg)
+}
+
+// updateModelPicker handles key presses while the model picker is shown.
+func (m model) updateModelPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.pop()
+		m.retryAfterPick = false
+		m.err = nil
+		return m, nil
+	case key.Matches(msg, m.keys.Up):
+		if m.selectedModel > 0 {
+			m.selectedModel--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selectedModel < len(m.modelOptions)-1 {
+			m.selectedModel++
+		}
+	case key.Matches(msg, m.keys.Select, m.keys.TranslationOnly, m.keys.AnalysisOnly):
+		if len(m.modelOptions) == 0 {
+			return m, nil
+		}
+		name := m.modelOptions[m.selectedModel].name
+		switch {
+		case key.Matches(msg, m.keys.TranslationOnly):
+			m.cfg.Models.Translation = name
+		case key.Matches(msg, m.keys.AnalysisOnly):
+			m.cfg.Models.Analysis = name
+		default:
+			m.cfg.Models.Translation = name
+			m.cfg.Models.Analysis = name
+		}
+		m.pop()
+		if m.retryAfterPick {
+			m.retryAfterPick = false
+			m.state = stateInputSentence
+			m, cmd := m.startTranslation()
+			return m, tea.Batch(cmd, saveModelChoice(m.cfg.Profile, m.cfg.Models))
+		}
+		return m, saveModelChoice(m.cfg.Profile, m.cfg.Models)
+	}
+	return m, nil
+}
+
+// viewModelPicker renders the model picker.
+func (m model) viewModelPicker() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Select A Model:"))
+	s.WriteString("\n\n")
+	s.WriteString(fmt.Sprintf("Profile: %s\n", m.cfg.Profile))
+	s.WriteString(fmt.Sprintf("Translation: %s | Analysis: %s\n\n", m.cfg.Models.Translation, m.cfg.Models.Analysis))
+
+	switch {
+	case m.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n")
+	case m.modelsLoading:
+		s.WriteString("Loading models...\n")
+	case len(m.modelOptions) == 0:
+		s.WriteString("No models available.\n")
+	}
+
+	for i, opt := range m.modelOptions {
+		line := opt.name + opt.describe()
+		if i == m.selectedModel {
+			s.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			s.WriteString(normalStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, "Use for both steps"}, helpEntry{m.keys.TranslationOnly, "Translation only"}, helpEntry{m.keys.AnalysisOnly, "Analysis only"}, helpEntry{m.keys.Back, "Back"})))
+	return s.String()
+}
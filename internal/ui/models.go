@@ -0,0 +1,1618 @@
+// Package ui implements the terminal user interface.
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/document"
+	"github.com/brittaao/translation-tui/internal/drill"
+	"github.com/brittaao/translation-tui/internal/notify"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// model represents the application state for the TUI.
+type model struct {
+	cfg                 config.Config
+	state               appState
+	userLang            string
+	targetLang          string
+	detect              bool                  // The target language is detected for each sentence
+	detected            *translator.Detection // Most recent detection, naming its language
+	fanout              []string              // Target languages marked for translating into all of them at once
+	knownLangs          []language            // Languages offered as known well, with those of the config file
+	recentPairs         []storage.LanguagePair
+	selectedSetting     int
+	selectedPair        int
+	targetLangs         []language // Languages offered for learning, with those of the config file
+	input               string
+	originalSentence    string
+	translation         string
+	foreign             string // Side of the result in the language being learned, if known
+	wordAnalysis        []translator.WordInfo
+	sentenceLevel       string
+	sentenceTone        *translator.Tone
+	translating         string          // Sentence being translated from the input, which is cleared for the next
+	background          []paragraphItem // Sentences entered while another was being translated, and that one
+	foreground          int             // 1 + the index in background of the sentence being translated from the input, or 0
+	undo                []string        // Earlier versions of the sentence input, the last one most recent
+	redo                []string        // Undone versions of the sentence input, the last one undone first
+	lastEdit            inputEdit
+	draft               string   // Sentence input as last saved, restored when it is shown empty
+	costWarned          string   // Sentence input whose estimated cost was warned about, translated on confirming
+	misspelled          []string // Words of the sentence input the spelling dictionaries do not know
+	spellGen            int      // Incremented as the sentence input changes, to drop outdated spellchecks
+	pickingChar         bool     // Special characters are shown below the sentence input
+	charCursor          int
+	wordSelected        int
+	wordExpanded        bool
+	wordSort            int // 1 + the column the word table is sorted by, or 0
+	wordFilter          string
+	filteringWords      bool
+	wordOffset          int // First row of the word table shown
+	lookups             map[string]wiktionaryLookup
+	examples            map[string]examplesLookup
+	related             map[string]relatedLookup
+	wordOrder           wordOrder
+	err                 error
+	cursor              int
+	selectedLang        int
+	langs               []language
+	langFilter          string
+	filteredLangs       []language
+	status              string
+	usedModels          translator.Models
+	timings             translator.Timings // Step timings of the current result
+	nav                 []appState         // Screens below the current one, returned to by going back
+	savedGeneration     translator.Generation
+	modelFallbackWarned bool
+	selectedParam       int
+	modelOptions        []modelOption
+	selectedModel       int
+	modelsLoading       bool
+	degraded            string
+	loading             bool
+	loadingStep         string
+	deadline            time.Time
+	debugOffset         int
+	width               int
+	height              int
+	retryAfterPick      bool
+	failure             error
+	keys                keyMap
+	showHelp            bool
+	theme               string
+	cached              bool
+	spinner             spinner.Model
+	paragraph           []paragraphItem
+	sentenceIndex       int
+	showOverview        bool
+	doc                 *document.Document
+	docIndex            int
+	docResults          map[int]translate.Result
+	docPending          int
+	watch               *document.Tail
+	watchFeed           []watchItem
+	watchSelected       int
+	watchExpanded       bool
+	source              string
+	chat                []chatMessage
+	chatLevel           int
+	chatStarted         bool
+	chatSelected        int
+	followUps           []followUp
+	askingFollowUp      bool
+	compareInputs       [2]string
+	compareField        int
+	comparison          *translator.Comparison
+	simplifyLevel       int
+	simplification      *translator.Simplification
+	resultTab           resultTab
+	resultScroll        int // First line of the selected tab shown
+	alternatives        *translator.Simplification
+	alternativesErr     error
+	alternativesPending bool
+	pickingWords        bool
+	pickCursor          int
+	picked              []bool // Words of the foreign-language sentence picked for analysis
+	analyzingWords      bool
+	probing             bool      // The provider is offline and checked in the background
+	started             time.Time // When the running translation started, for notifying once it is done
+	queue               []storage.QueuedSentence
+	dictionary          []storage.DictionaryEntry
+	dictionarySearch    string
+	searchingDictionary bool
+	selectedEntry       int // Of the dictionary entries matching the search
+	selectedQueued      int
+	copying             bool // The copy menu is open
+	copyCursor          int
+	exporting           bool // The export menu is open
+	exportCursor        int
+	session             []storage.HistoryEntry // Translations of the session, for exporting
+	terminal            io.Writer              // Of a session on another machine, which copies to its clipboard
+	review              []storage.VocabCard    // Cards of the review, with those forgotten again at the end
+	reviewIndex         int
+	reviewRevealed      bool // The answer of the current card is shown
+	reviewed            int  // Cards recalled in the review
+	practiceCursor      int
+	practice            []practiceSentence // Sentences of the practice mode, or nil while loading
+	practiceIndex       int
+	practiceInput       string
+	listenScores        []drill.WordScore  // Of the current sentence once checked
+	listenCorrect       int                // Words heard right in the quiz
+	listenTotal         int                // Words checked in the quiz
+	typingScore         *drill.TypingScore // Of the current sentence once checked
+	typingStarted       time.Time          // When the first key of the current sentence was typed
+	typingElapsed       time.Duration      // Taken to type the current sentence
+	typingChars         int                // Characters of the sentences checked in the drill
+	typingErrors        int
+	typingTime          time.Duration
+	shadowing           []shadowSentence // Sentences of the shadowing mode, or nil while loading
+	shadowChunk         int              // 1 + the part of the sentence played, or 0 for all of it
+	shadowSpeed         int              // Of shadowSpeeds
+	shadowPaused        bool
+	shadowGen           int                       // Counts the playbacks started, to ignore the end of those stopped
+	shadowStop          context.CancelFunc        // Stops the running playback
+	figure              drill.Figure              // To write out in the numbers drill
+	figureByEar         bool                      // The figure is read aloud rather than shown
+	figureGrade         *translator.NumberGrade   // Of the current figure once checked
+	figureRight         int                       // Figures written out right in the drill
+	figureTotal         int                       // Figures checked in the drill
+	verbs               []storage.VocabCard       // Saved verbs of the conjugation quiz, or nil while loading
+	conjugation         *translator.Conjugation   // Of the current verb, or nil while loading
+	conjugationForm     translator.ConjugatedForm // Asked for of the current verb
+	conjugationChecked  bool
+	conjugationRight    int                       // Forms typed right in the quiz
+	conjugationTotal    int                       // Forms checked in the quiz
+	caseQuestions       []translator.CaseQuestion // Of the case quiz, or nil while loading
+	caseCursor          int                       // Of translator.Cases of the language learned
+	caseAnswer          string                    // Case picked for the current question, once answered
+	caseRight           int                       // Questions answered right in the quiz
+	caseTotal           int
+	nouns               []storage.VocabCard // Saved nouns of the gender quiz, or nil while loading
+	genderOptions       []genderOption
+	genderCursor        int
+	genderAnswer        string // Gender picked for the current noun, once answered
+	genderRight         int    // Nouns answered right in the quiz
+	genderTotal         int
+	genderMissed        []string    // Nouns answered wrong in the quiz, with their articles
+	cloze               []clozeItem // Of the cloze test, or nil while loading
+	clozeChecked        bool
+	clozeRight          int // Words filled in right in the test
+	clozeTotal          int
+	study               studySession
+}
+
+// appState represents the current state of the application.
+type appState int
+
+const (
+	stateSelectUserLang appState = iota
+	stateSelectTargetLang
+	stateInputSentence
+	stateShowResults
+	stateSelectModel
+	stateSetupAPIKey
+	stateDebug
+	stateError
+	stateDocument
+	stateConversation
+	stateCompare
+	stateSimplify
+	stateWatch
+	stateGeneration
+	statePairs
+	stateSettings
+	statePending
+	stateDictionary
+	stateReview
+	statePractice
+	stateListening
+	stateTyping
+	stateShadowing
+	stateNumbers
+	stateConjugation
+	stateCases
+	stateGenders
+	stateCloze
+	stateStudy
+)
+
+// language represents a language with its code and display name.
+type language struct {
+	code string
+	name string
+}
+
+// Languages that can be selected as "known well" for my personal convenience
+var knownLanguages = []language{
+	{"de", "German"},
+	{"sv", "Swedish"},
+	{"en", "English"},
+	{"es", "Spanish"},
+}
+
+// All languages available for learning (includes all current languages + German)
+var allTargetLanguages = []language{
+	{"sr", "Serbian"},
+	{"es", "Spanish"},
+	{"fr", "French"},
+	{"it", "Italian"},
+	{"pt", "Portuguese"},
+	{"en", "English"},
+	{"sv", "Swedish"},
+	{"de", "German"},
+}
+
+// Styles of the UI; their colors are set by the active theme.
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Padding(1, 2)
+
+	selectedStyle = lipgloss.NewStyle().
+			Bold(true)
+
+	normalStyle = lipgloss.NewStyle()
+
+	errorStyle = lipgloss.NewStyle().
+			Bold(true)
+
+	successStyle = lipgloss.NewStyle()
+
+	warningStyle = lipgloss.NewStyle().
+			Bold(true)
+
+	labelStyle = lipgloss.NewStyle().
+			Bold(true)
+
+	valueStyle = lipgloss.NewStyle()
+
+	statusBarStyle = lipgloss.NewStyle().
+			Reverse(true).
+			Padding(0, 1)
+
+	masculineStyle = lipgloss.NewStyle()
+	feminineStyle  = lipgloss.NewStyle()
+	neuterStyle    = lipgloss.NewStyle()
+
+	misspelledStyle = lipgloss.NewStyle().
+			Underline(true)
+)
+
+// New creates the TUI model, starting at the language selection.
+func New(cfg config.Config, opts Options) (tea.Model, error) {
+	m, err := initialModel(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return opts.apply(m)
+}
+
+// NewSetup creates the TUI model, starting at the first-run API key setup screen.
+func NewSetup(cfg config.Config, opts Options) (tea.Model, error) {
+	m, err := initialModel(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m.state = stateSetupAPIKey
+	return opts.apply(m)
+}
+
+func initialModel(cfg config.Config) (model, error) {
+	keys, err := newKeyMap(cfg.Keys)
+	if err != nil {
+		return model{}, err
+	}
+	themeName, err := applyConfiguredTheme(cfg.Theme)
+	if err != nil {
+		return model{}, err
+	}
+	known := withCustomLanguages(knownLanguages, cfg.Languages)
+	targets := withCustomLanguages(allTargetLanguages, cfg.Languages)
+	return model{
+		cfg:           cfg,
+		state:         stateSelectUserLang,
+		langs:         known,
+		filteredLangs: known,
+		knownLangs:    known,
+		targetLangs:   targets,
+		keys:          keys,
+		theme:         themeName,
+		spinner:       spinner.New(spinner.WithSpinner(spinner.Dot)),
+		chatLevel:     defaultChatLevel,
+		simplifyLevel: defaultSimplifyLevel,
+		wordSelected:  -1,
+		shadowSpeed:   defaultShadowSpeed,
+	}, nil
+}
+
+func (m model) Init() tea.Cmd {
+	return tea.Batch(loadQueue(true), loadDueCards(), loadDraft(), draftTick())
+}
+
+// String returns the state name used in logs.
+func (s appState) String() string {
+	switch s {
+	case stateSelectUserLang:
+		return "select_user_lang"
+	case stateSelectTargetLang:
+		return "select_target_lang"
+	case stateInputSentence:
+		return "input_sentence"
+	case stateShowResults:
+		return "show_results"
+	case stateSelectModel:
+		return "select_model"
+	case stateSetupAPIKey:
+		return "setup_api_key"
+	case stateDebug:
+		return "debug"
+	case stateError:
+		return "error"
+	case stateDocument:
+		return "document"
+	case stateConversation:
+		return "conversation"
+	case stateCompare:
+		return "compare"
+	case stateSimplify:
+		return "simplify"
+	case stateWatch:
+		return "watch"
+	case stateGeneration:
+		return "generation"
+	case statePairs:
+		return "pairs"
+	case stateSettings:
+		return "settings"
+	case statePending:
+		return "pending"
+	case stateDictionary:
+		return "dictionary"
+	case stateReview:
+		return "review"
+	case statePractice:
+		return "practice"
+	case stateListening:
+		return "listening"
+	case stateTyping:
+		return "typing"
+	case stateShadowing:
+		return "shadowing"
+	case stateNumbers:
+		return "numbers"
+	case stateConjugation:
+		return "conjugation"
+	case stateCases:
+		return "cases"
+	case stateGenders:
+		return "genders"
+	case stateCloze:
+		return "cloze"
+	case stateStudy:
+		return "study"
+	default:
+		return fmt.Sprintf("state(%d)", int(s))
+	}
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if nm, ok := next.(model); ok {
+		if nm.state != m.state {
+			slog.Debug("state transition", "from", m.state, "to", nm.state)
+			nm, cmd = nm.keepDraftOnTransition(m, cmd)
+		}
+		if nm.input != m.input && nm.state == stateInputSentence {
+			nm, cmd = nm.scheduleSpellcheck(cmd)
+		}
+		next, cmd = nm.watchConnectivity(cmd)
+	}
+	return next, cmd
+}
+
+// update handles a message; Update wraps it to log state transitions.
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case pipelineProgress:
+		m.loadingStep = msg.step
+		m.deadline = msg.deadline
+		return m, waitForPipeline(msg.updates)
+
+	case loadingTickMsg:
+		if m.loading {
+			return m, loadingTick()
+		}
+		return m, nil
+
+	case sentenceResult:
+		return m.handleSentenceResult(msg)
+
+	case backgroundResult:
+		return m.handleBackgroundResult(msg)
+
+	case spellTick:
+		return m.handleSpellTick(msg)
+
+	case spellResult:
+		return m.handleSpellResult(msg)
+
+	case draftTickMsg:
+		return m.handleDraftTick()
+
+	case draftResult:
+		return m.handleDraftResult(msg)
+
+	case backgroundRecorded:
+		if msg.err != nil {
+			m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		}
+		return m, nil
+
+	case paragraphDone:
+		return m.handleParagraphDone()
+
+	case articleResult:
+		return m.handleArticleResult(msg)
+
+	case watchTickMsg:
+		return m.handleWatchTick()
+
+	case watchLines:
+		return m.handleWatchLines(msg)
+
+	case watchResult:
+		return m.handleWatchResult(msg)
+
+	case chatReply:
+		return m.handleChatReply(msg)
+
+	case chatExpansion:
+		return m.handleChatExpansion(msg)
+
+	case wiktionaryResult:
+		return m.handleWiktionaryResult(msg)
+
+	case examplesResult:
+		return m.handleExamplesResult(msg)
+
+	case relatedResult:
+		return m.handleRelatedResult(msg)
+
+	case pronunciationResult:
+		return m.handlePronunciationResult(msg)
+
+	case followUpAnswer:
+		return m.handleFollowUpAnswer(msg)
+
+	case comparisonResult:
+		return m.handleComparisonResult(msg)
+
+	case simplificationResult:
+		return m.handleSimplificationResult(msg)
+
+	case alternativesResult:
+		return m.handleAlternativesResult(msg)
+
+	case pickedWordsResult:
+		return m.handlePickedWordsResult(msg)
+
+	case queueResult:
+		return m.handleQueueResult(msg)
+
+	case dictionaryResult:
+		return m.handleDictionaryResult(msg)
+
+	case dueCardsResult:
+		return m.handleDueCards(msg)
+
+	case practiceSentencesResult:
+		return m.handlePracticeSentences(msg)
+
+	case sentenceAudioResult:
+		return m.handleSentenceAudio(msg)
+
+	case shadowSentencesResult:
+		return m.handleShadowSentences(msg)
+
+	case shadowPlayed:
+		return m.handleShadowPlayed(msg)
+
+	case shadowReplay:
+		return m.handleShadowReplay(msg)
+
+	case numberGradeResult:
+		return m.handleNumberGrade(msg)
+
+	case verbsResult:
+		return m.handleVerbs(msg)
+
+	case conjugationResult:
+		return m.handleConjugation(msg)
+
+	case caseQuizResult:
+		return m.handleCaseQuiz(msg)
+
+	case nounsResult:
+		return m.handleNouns(msg)
+
+	case clozeResult:
+		return m.handleCloze(msg)
+
+	case probeTickMsg:
+		return m.handleProbeTick()
+
+	case probeResult:
+		return m.handleProbeResult(msg)
+
+	case detectionResult:
+		return m.handleDetectionResult(msg)
+
+	case recentPairsResult:
+		return m.handleRecentPairs(msg)
+
+	case spinner.TickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case translationResult:
+		if m.doc != nil {
+			return m.handleDocumentResult(msg)
+		}
+		m.loading = false
+		if m.input != "" || m.foreground > 0 {
+			return m.keepInBackground(msg)
+		}
+		m.input, m.translating = m.translating, ""
+		if msg.err != nil {
+			m.failure = msg.err
+			m.state = stateError
+			return m, notifyFailed(m.cfg, notify.ModeSentence, m.started, msg.err)
+		}
+		m.translation = msg.Translation
+		m.originalSentence = msg.Original
+		m.wordAnalysis = msg.Words
+		m.sentenceLevel = msg.Level
+		m.sentenceTone = msg.Tone
+		m.clearResultTabs()
+		m.foreign = msg.Foreign
+		m.selectFirstWord()
+		m.usedModels = msg.Models
+		m.timings = msg.Timings
+		m.degraded = msg.Degraded
+		m.cached = msg.Cached
+		m.followUps = nil
+		m.showResults()
+		m.input = ""
+		m.err = nil
+		m.status = ""
+		m.warnModelFallback(msg.Result)
+		entry := storage.HistoryEntry{
+			Time:        time.Now(),
+			UserLang:    m.userLang,
+			TargetLang:  m.targetLang,
+			Original:    m.originalSentence,
+			Translation: m.translation,
+			Words:       m.wordAnalysis,
+		}
+		m.session = append(m.session, entry)
+		return m, tea.Batch(recordHistory(m.cfg, entry), notifyDone(m.cfg, notify.ModeSentence, m.started, "Translation ready", m.translation))
+
+	case apiKeyStoredResult:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.state = stateSelectUserLang
+		m.input = ""
+		m.err = nil
+		m.status = successStyle.Render(fmt.Sprintf("API key saved to %s", msg.location))
+		return m, nil
+
+	case modelListResult:
+		m.modelsLoading = false
+		m.err = msg.err
+		m.modelOptions = msg.models
+		for i, opt := range m.modelOptions {
+			if opt.name == m.cfg.Models.Translation {
+				m.selectedModel = i
+			}
+		}
+		return m, nil
+
+	case storageResult:
+		if msg.err != nil {
+			m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		} else {
+			m.status = msg.status
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// handleKey dispatches a key press to the current screen. Printable keys are typed
+// into text fields before any binding is considered.
+func (m model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.showHelp {
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Help), key.Matches(msg, m.keys.Back):
+			m.showHelp = false
+		}
+		return m, nil
+	}
+	_, isText := typedText(msg)
+	isText = isText && m.acceptsText()
+	if key.Matches(msg, m.keys.Help) && !isText {
+		m.showHelp = true
+		return m, nil
+	}
+	if key.Matches(msg, m.keys.Theme) && !isText {
+		return m.switchTheme()
+	}
+
+	switch m.state {
+	case stateSelectModel:
+		return m.updateModelPicker(msg)
+	case stateSetupAPIKey:
+		return m.updateSetup(msg)
+	case stateDebug:
+		return m.updateDebugView(msg)
+	case stateError:
+		return m.updateErrorScreen(msg)
+	case stateDocument:
+		return m.updateDocument(msg)
+	case stateConversation:
+		return m.updateConversation(msg)
+	case stateCompare:
+		return m.updateCompare(msg)
+	case stateSimplify:
+		return m.updateSimplify(msg)
+	case stateWatch:
+		return m.updateWatch(msg)
+	case stateGeneration:
+		return m.updateGeneration(msg)
+	case statePairs:
+		return m.updatePairs(msg)
+	case stateSettings:
+		return m.updateSettings(msg)
+	case statePending:
+		return m.updateQueue(msg)
+	case stateDictionary:
+		return m.updateDictionary(msg)
+	case stateReview:
+		return m.updateReview(msg)
+	case statePractice:
+		return m.updatePractice(msg)
+	case stateListening:
+		return m.updateListening(msg)
+	case stateTyping:
+		return m.updateTyping(msg)
+	case stateShadowing:
+		return m.updateShadowing(msg)
+	case stateNumbers:
+		return m.updateNumbers(msg)
+	case stateConjugation:
+		return m.updateConjugation(msg)
+	case stateCases:
+		return m.updateCases(msg)
+	case stateGenders:
+		return m.updateGenders(msg)
+	case stateCloze:
+		return m.updateCloze(msg)
+	case stateStudy:
+		return m.updateStudy(msg)
+	}
+
+	if m.state == stateShowResults && m.askingFollowUp {
+		return m.updateFollowUp(msg)
+	}
+	if m.state == stateShowResults && m.filteringWords {
+		return m.updateWordFilter(msg)
+	}
+	if m.state == stateShowResults && m.pickingWords {
+		return m.updatePickWords(msg)
+	}
+	if m.state == stateShowResults && m.copying {
+		return m.updateCopyMenu(msg)
+	}
+	if m.state == stateShowResults && m.exporting {
+		return m.updateExportMenu(msg)
+	}
+	if m.state == stateInputSentence && m.pickingChar {
+		return m.updateCharPicker(msg)
+	}
+	if isText {
+		return m.typeText(msg)
+	}
+	if m.state == stateShowResults && m.showOverview {
+		return m.updateOverview(msg)
+	}
+	if m.state == stateShowResults {
+		if next, cmd, ok := m.updateResultTabs(msg); ok {
+			return next, cmd
+		}
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Debug):
+		return m.openDebugView()
+
+	case key.Matches(msg, m.keys.Model):
+		if m.state == stateInputSentence || m.state == stateShowResults {
+			return m.openModelPicker()
+		}
+
+	case key.Matches(msg, m.keys.Generation):
+		if m.state == stateInputSentence || m.state == stateShowResults {
+			return m.openGeneration()
+		}
+
+	case key.Matches(msg, m.keys.Settings):
+		if m.state == stateInputSentence || m.state == stateShowResults {
+			return m.openSettings()
+		}
+
+	case key.Matches(msg, m.keys.Queue):
+		if m.state == stateInputSentence || m.state == stateShowResults {
+			return m.openQueue()
+		}
+
+	case key.Matches(msg, m.keys.Dictionary):
+		if m.state == stateInputSentence || m.state == stateShowResults {
+			return m.openDictionary()
+		}
+
+	case key.Matches(msg, m.keys.Accent):
+		if m.state == stateInputSentence {
+			return m.cycleAccent()
+		}
+
+	case key.Matches(msg, m.keys.Characters):
+		if m.state == stateInputSentence {
+			return m.openCharPicker()
+		}
+
+	case key.Matches(msg, m.keys.Undo):
+		if m.state == stateInputSentence {
+			return m.undoInput()
+		}
+
+	case key.Matches(msg, m.keys.Redo):
+		if m.state == stateInputSentence {
+			return m.redoInput()
+		}
+
+	case key.Matches(msg, m.keys.Background):
+		if m.state == stateInputSentence || m.state == stateShowResults {
+			return m.openBackground()
+		}
+
+	case key.Matches(msg, m.keys.Review):
+		if m.state != stateSetupAPIKey {
+			return m.openReview()
+		}
+
+	case key.Matches(msg, m.keys.Practice):
+		if m.state == stateInputSentence || m.state == stateShowResults {
+			if m.targetLang == "" {
+				return m.needTargetLanguage()
+			}
+			return m.openPractice()
+		}
+
+	case key.Matches(msg, m.keys.Depth):
+		if m.state == stateInputSentence || m.state == stateShowResults {
+			return m.cycleDepth()
+		}
+
+	case key.Matches(msg, m.keys.Conversation):
+		if m.state == stateInputSentence {
+			if m.targetLang == "" {
+				return m.needTargetLanguage()
+			}
+			return m.openConversation()
+		}
+
+	case key.Matches(msg, m.keys.Compare):
+		if m.state == stateInputSentence {
+			if m.targetLang == "" {
+				return m.needTargetLanguage()
+			}
+			return m.openCompare()
+		}
+
+	case key.Matches(msg, m.keys.Simplify):
+		if m.state == stateInputSentence {
+			if m.targetLang == "" {
+				return m.needTargetLanguage()
+			}
+			return m.openSimplify()
+		}
+
+	case key.Matches(msg, m.keys.Back):
+		return m.back()
+
+	case key.Matches(msg, m.keys.Select):
+		return m.selectItem()
+
+	case key.Matches(msg, m.keys.Mark):
+		if m.state == stateSelectTargetLang {
+			return m.toggleFanout()
+		}
+
+	case key.Matches(msg, m.keys.Pairs):
+		if m.canSwitchPair() {
+			return m.openPairs()
+		}
+
+	case key.Matches(msg, m.keys.Up):
+		if m.state == stateSelectUserLang || m.state == stateSelectTargetLang {
+			if m.selectedLang > 0 {
+				m.selectedLang--
+			}
+		}
+		if m.state == stateShowResults {
+			m = m.moveWordSelection(-1)
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.state == stateSelectUserLang || m.state == stateSelectTargetLang {
+			if m.selectedLang < len(m.filteredLangs)-1 {
+				m.selectedLang++
+			}
+		}
+		if m.state == stateShowResults {
+			m = m.moveWordSelection(1)
+		}
+
+	case key.Matches(msg, m.keys.Expand):
+		if m.state == stateShowResults && m.hasWords() {
+			m.wordExpanded = !m.wordExpanded
+		}
+
+	case key.Matches(msg, m.keys.Wiktionary):
+		if m.state == stateShowResults {
+			return m.lookUpSelectedWord()
+		}
+
+	case key.Matches(msg, m.keys.Examples):
+		if m.state == stateShowResults {
+			return m.searchSelectedExamples()
+		}
+
+	case key.Matches(msg, m.keys.Pronounce):
+		if m.state == stateShowResults {
+			return m.pronounceSelectedWord()
+		}
+
+	case key.Matches(msg, m.keys.Related):
+		if m.state == stateShowResults {
+			return m.showSelectedRelated()
+		}
+
+	case key.Matches(msg, m.keys.Stress):
+		if m.state == stateShowResults {
+			return m.toggleStress()
+		}
+
+	case key.Matches(msg, m.keys.AnalyzeRelated):
+		if m.state == stateShowResults {
+			return m.analyzeSelectedRelated()
+		}
+
+	case key.Matches(msg, m.keys.SaveRelated):
+		if m.state == stateShowResults {
+			return m.saveSelectedRelated()
+		}
+
+	case key.Matches(msg, m.keys.Frequency):
+		if m.state == stateShowResults && len(m.wordAnalysis) > 0 {
+			return m.cycleWordOrder(), nil
+		}
+
+	case key.Matches(msg, m.keys.Save):
+		if m.state == stateShowResults {
+			return m, saveWordsToVocab(m.targetLang, m.originalSentence, m.translation, m.wordAnalysis, m.cfg.Level)
+		}
+
+	case key.Matches(msg, m.keys.SaveAbove):
+		if m.state == stateShowResults {
+			return m.saveWordsAboveLevel(m.originalSentence, m.translation, m.wordAnalysis)
+		}
+
+	case key.Matches(msg, m.keys.PrevSentence):
+		if m.state == stateShowResults {
+			m.showSentence(m.sentenceIndex - 1)
+		}
+
+	case key.Matches(msg, m.keys.NextSentence):
+		if m.state == stateShowResults {
+			m.showSentence(m.sentenceIndex + 1)
+		}
+
+	case key.Matches(msg, m.keys.Overview):
+		if m.state == stateShowResults && len(m.paragraph) > 1 {
+			m.showOverview = true
+		}
+
+	case key.Matches(msg, m.keys.FollowUp):
+		if m.state == stateShowResults && m.translation != "" {
+			m.askingFollowUp = true
+			m.input = ""
+			m.switchTab(int(tabTranslation)) // Questions and answers are shown with the translation
+		}
+
+	case key.Matches(msg, m.keys.Copy):
+		if m.state == stateShowResults && m.hasResult() {
+			m.copying = true
+			m.copyCursor = 0
+		}
+
+	case key.Matches(msg, m.keys.Export):
+		if m.state == stateShowResults && m.hasResult() {
+			m.exporting = true
+			m.exportCursor = 0
+		}
+
+	case msg.Type == tea.KeyBackspace:
+		if m.state == stateSelectUserLang || m.state == stateSelectTargetLang {
+			if len(m.langFilter) > 0 {
+				m.langFilter = deleteLast(m.langFilter)
+				m.filterLanguages()
+				if m.selectedLang >= len(m.filteredLangs) {
+					m.selectedLang = len(m.filteredLangs) - 1
+					if m.selectedLang < 0 {
+						m.selectedLang = 0
+					}
+				}
+			}
+		}
+		if m.state == stateInputSentence {
+			if len(m.input) > 0 {
+				m.editInput(deleteLast(m.input), editDeleting)
+			}
+		}
+	}
+	return m, nil
+}
+
+// typeText adds a printable key press to the language filter or the sentence.
+func (m model) typeText(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	text, _ := typedText(msg)
+	switch m.state {
+	case stateSelectUserLang, stateSelectTargetLang:
+		m.langFilter = appendText(m.langFilter, text)
+		m.filterLanguages()
+		m.selectedLang = 0
+	case stateInputSentence:
+		edit := editTyping
+		if msg.Paste {
+			edit = editPasting
+		}
+		m.editInput(appendText(m.input, text), edit)
+	}
+	return m, nil
+}
+
+// selectItem confirms the selected language or starts translating the sentence.
+func (m model) selectItem() (tea.Model, tea.Cmd) {
+	switch m.state {
+	case stateSelectUserLang:
+		if len(m.filteredLangs) > 0 {
+			m.userLang = m.filteredLangs[m.selectedLang].code
+			m.status = ""
+			m.fanout = nil
+			m.navigate(stateSelectTargetLang)
+			m.enter()
+		}
+
+	case stateSelectTargetLang:
+		if len(m.filteredLangs) > 0 {
+			code := m.filteredLangs[m.selectedLang].code
+			m.navigate(stateInputSentence)
+			m.status = ""
+			if code == detectOption.code {
+				m.detect = true
+				m.targetLang = ""
+				m.fanout = nil
+				return m, nil
+			}
+			if len(m.fanout) > 0 {
+				code = m.fanout[0]
+			}
+			if len(m.fanout) < 2 {
+				m.fanout = nil
+			}
+			m.targetLang = code
+			// A sentence whose detected language was ambiguous is translated right away
+			if m.detect && m.input != "" {
+				return m.startTranslation()
+			}
+			m.detect = false
+			if m.doc != nil {
+				return m.openDocument()
+			}
+			if m.watch != nil {
+				return m.openWatch()
+			}
+			if m.fanout == nil {
+				return m, rememberPair(m.userLang, m.targetLang)
+			}
+		}
+
+	case stateInputSentence:
+		if m.input != "" && m.loading && m.translating != "" && !translate.Offline() {
+			if !m.checkInputSize() {
+				return m, nil
+			}
+			return m.submitInBackground()
+		}
+		if m.input != "" && !m.loading {
+			if !m.checkInputSize() {
+				return m, nil
+			}
+			if m.detect {
+				return m.startDetection()
+			}
+			if translate.Offline() && len(m.fanout) < 2 {
+				return m.queueSentence()
+			}
+			return m.startTranslation()
+		}
+	}
+	return m, nil
+}
+
+func (m *model) filterLanguages() {
+	if m.langFilter == "" {
+		m.filteredLangs = m.langs
+		return
+	}
+	filter := strings.ToLower(m.langFilter)
+	m.filteredLangs = []language{}
+	for _, lang := range m.langs {
+		if strings.Contains(strings.ToLower(lang.name), filter) ||
+			strings.Contains(strings.ToLower(lang.code), filter) {
+			m.filteredLangs = append(m.filteredLangs, lang)
+		}
+	}
+}
+
+func (m model) View() string {
+	if m.showHelp {
+		return m.withStatusBar(m.viewHelp())
+	}
+
+	var s strings.Builder
+
+	switch m.state {
+	case stateSelectUserLang:
+		s.WriteString(titleStyle.Render("Select A Language You Know Well:"))
+		s.WriteString("\n\n")
+		if m.langFilter != "" {
+			s.WriteString(fmt.Sprintf("Filter: %s\n\n", m.langFilter))
+		}
+		for i, lang := range m.filteredLangs {
+			if i == m.selectedLang {
+				s.WriteString(selectedStyle.Render(fmt.Sprintf("> %s (%s)", lang.name, lang.code)))
+			} else {
+				s.WriteString(normalStyle.Render(fmt.Sprintf("  %s (%s)", lang.name, lang.code)))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		if m.status != "" {
+			s.WriteString(m.status)
+			s.WriteString("\n\n")
+		}
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, "Select"}, helpEntry{m.keys.Pairs, "Recent pairs"}, helpEntry{m.keys.Back, "Quit"}, helpEntry{m.keys.Help, "Help"}) + " | Type to filter"))
+
+	case stateSelectTargetLang:
+		s.WriteString(titleStyle.Render("Select The Language You Want To Learn:"))
+		s.WriteString("\n\n")
+		s.WriteString(fmt.Sprintf("From: %s\n\n", m.getLangName(m.userLang)))
+		if m.status != "" {
+			s.WriteString(m.status)
+			s.WriteString("\n\n")
+		}
+		if m.langFilter != "" {
+			s.WriteString(fmt.Sprintf("Filter: %s\n\n", m.langFilter))
+		}
+		for i, lang := range m.filteredLangs {
+			line := fmt.Sprintf("%s (%s)", lang.name, lang.code)
+			if slices.Contains(m.fanout, lang.code) {
+				line += " ✓"
+			}
+			if i == m.selectedLang {
+				s.WriteString(selectedStyle.Render("> " + line))
+			} else {
+				s.WriteString(normalStyle.Render("  " + line))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Mark, "Mark several"}, helpEntry{m.keys.Select, "Select"}, helpEntry{m.keys.Back, "Back"}, helpEntry{m.keys.Help, "Help"}) + " | Type to filter"))
+
+	case stateInputSentence:
+		if len(m.fanout) > 1 {
+			s.WriteString(titleStyle.Render("Enter Sentence to Translate Into Several Languages:"))
+			s.WriteString("\n\n")
+			s.WriteString(fmt.Sprintf("%s → %s\n\n", m.getLangName(m.userLang), m.fanoutNames()))
+		} else {
+			s.WriteString(titleStyle.Render("Enter Sentence in Either Language:"))
+			s.WriteString("\n\n")
+			s.WriteString(fmt.Sprintf("%s ↔ %s\n\n", m.getLangName(m.userLang), m.getLangName(m.targetLang)))
+		}
+		if rtlBase(m.input) {
+			s.WriteString("Sentence:\n")
+			s.WriteString(m.viewInputRTL())
+		} else {
+			s.WriteString(fmt.Sprintf("Sentence: %s", m.viewSpelling()))
+			if m.cursor%2 == 0 {
+				s.WriteString("█")
+			}
+		}
+		if m.input != "" {
+			s.WriteString("\n")
+			s.WriteString(m.viewInputSize())
+		}
+		if misspelled := m.viewMisspelled(); misspelled != "" {
+			s.WriteString("\n")
+			s.WriteString(misspelled)
+		}
+		if m.pickingChar {
+			s.WriteString("\n\n")
+			s.WriteString(m.viewCharPicker())
+		}
+		s.WriteString("\n\n")
+		if m.loading {
+			if m.translating != "" {
+				s.WriteString(labelStyle.Render("Translating: "))
+				s.WriteString(valueStyle.Render(m.translating))
+				s.WriteString("\n")
+			}
+			s.WriteString(labelStyle.Render(m.loadingView()))
+			s.WriteString("\n\n")
+		}
+		if m.status != "" {
+			s.WriteString(m.status)
+			s.WriteString("\n\n")
+		}
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Translate"}, helpEntry{m.keys.Model, "Model"}, helpEntry{m.keys.Conversation, "Conversation"}, helpEntry{m.keys.Compare, "Compare"}, helpEntry{m.keys.Simplify, "Simplify"}, helpEntry{m.keys.Back, "Back"}, helpEntry{m.keys.Help, "Help"}, helpEntry{m.keys.Quit, "Quit"})))
+
+	case stateShowResults:
+		if m.showOverview {
+			s.WriteString(m.viewOverview())
+			break
+		}
+		s.WriteString(m.viewResults())
+
+	case stateSelectModel:
+		s.WriteString(m.viewModelPicker())
+
+	case stateSetupAPIKey:
+		s.WriteString(m.viewSetup())
+
+	case stateDebug:
+		s.WriteString(m.viewDebug())
+
+	case stateError:
+		s.WriteString(m.viewErrorScreen())
+
+	case stateDocument:
+		s.WriteString(m.viewDocument())
+
+	case stateConversation:
+		s.WriteString(m.viewConversation())
+
+	case stateCompare:
+		s.WriteString(m.viewCompare())
+
+	case stateSimplify:
+		s.WriteString(m.viewSimplify())
+
+	case stateWatch:
+		s.WriteString(m.viewWatch())
+
+	case stateGeneration:
+		s.WriteString(m.viewGeneration())
+
+	case statePairs:
+		s.WriteString(m.viewPairs())
+
+	case stateSettings:
+		s.WriteString(m.viewSettings())
+
+	case statePending:
+		s.WriteString(m.viewQueue())
+
+	case stateDictionary:
+		s.WriteString(m.viewDictionary())
+
+	case stateReview:
+		s.WriteString(m.viewReview())
+
+	case statePractice:
+		s.WriteString(m.viewPractice())
+
+	case stateListening:
+		s.WriteString(m.viewListening())
+
+	case stateTyping:
+		s.WriteString(m.viewTyping())
+
+	case stateShadowing:
+		s.WriteString(m.viewShadowing())
+
+	case stateNumbers:
+		s.WriteString(m.viewNumbers())
+
+	case stateConjugation:
+		s.WriteString(m.viewConjugation())
+
+	case stateCases:
+		s.WriteString(m.viewCases())
+
+	case stateGenders:
+		s.WriteString(m.viewGenders())
+
+	case stateCloze:
+		s.WriteString(m.viewCloze())
+
+	case stateStudy:
+		s.WriteString(m.viewStudy())
+
+	default:
+		s.WriteString("Unknown state")
+	}
+
+	return m.withStatusBar(s.String())
+}
+
+// writeWordAnalysis renders the word-by-word analysis, if there is one. Words above the
+// user's known level are marked with ▲; idioms and false friends are shown as warnings.
+// Multi-word expressions are bracketed. The word at index selected is marked with > and,
+// if drill is not nil, followed by the details drill renders. Pass -1 to select none.
+func writeWordAnalysis(s *strings.Builder, words []translator.WordInfo, known string, selected int, drill func(*strings.Builder, translator.WordInfo)) {
+	if len(words) == 0 {
+		return
+	}
+	s.WriteString(labelStyle.Render("Word-by-Word Analysis:\n"))
+	s.WriteString("\n")
+	for i, word := range words {
+		writeWordLine(s, word, known, i == selected)
+		if drill != nil && i == selected {
+			drill(s, word)
+		}
+	}
+}
+
+// writeWordLine renders one word of the analysis with its level, frequency, noun forms
+// and explanation, marked with > if it is selected, followed by any false-friend note.
+func writeWordLine(s *strings.Builder, word translator.WordInfo, known string, selected bool) {
+	if selected {
+		s.WriteString(selectedStyle.Render(">"))
+	} else {
+		s.WriteString(" ")
+	}
+	if translator.AboveLevel(word.Level, known) {
+		s.WriteString(warningStyle.Render("▲ "))
+	} else {
+		s.WriteString("  ")
+	}
+	text := word.WordInTargetLang
+	if word.Grouped() {
+		text = "[" + text + "]"
+	}
+	s.WriteString(genderStyle(word.Gender, valueStyle).Render(bidiText(text)))
+	if word.Level != "" {
+		s.WriteString(" ")
+		s.WriteString(levelBadge(word.Level, known))
+	}
+	s.WriteString(frequencyBand(word))
+	if word.Idiom {
+		s.WriteString(warningStyle.Render(" (idiom)"))
+	}
+	if noun := nounForms(word); noun != "" {
+		s.WriteString(genderStyle(word.Gender, normalStyle).Render(" (" + noun + ")"))
+	}
+	if word.GrammaticalExplanation != "" {
+		s.WriteString(" - ")
+		s.WriteString(normalStyle.Render(word.GrammaticalExplanation))
+	}
+	s.WriteString("\n")
+	if word.FalseFriend != "" {
+		s.WriteString(warningStyle.Render("     ⚠ False friend: " + word.FalseFriend))
+		s.WriteString("\n")
+	}
+	if len(word.Morphemes) > 0 {
+		s.WriteString(labelStyle.Render("     Morphemes: "))
+		s.WriteString(normalStyle.Render(morphemes(word.Morphemes)))
+		s.WriteString("\n")
+	}
+	if word.Stress != "" {
+		s.WriteString(labelStyle.Render("     Stress: "))
+		s.WriteString(normalStyle.Render(word.Stress))
+		s.WriteString("\n")
+	}
+}
+
+// morphemes formats the breakdown of a word, such as "Haus (house) + tür (door)".
+func morphemes(parts []translator.Morpheme) string {
+	var texts []string
+	for _, p := range parts {
+		text := p.Text
+		if p.Gloss != "" {
+			text += " (" + p.Gloss + ")"
+		}
+		texts = append(texts, text)
+	}
+	return strings.Join(texts, " + ")
+}
+
+// genderStyle returns the style of nouns of the given gender, or fallback for other words.
+func genderStyle(gender string, fallback lipgloss.Style) lipgloss.Style {
+	switch gender {
+	case translator.GenderMasculine, translator.GenderCommon:
+		return masculineStyle
+	case translator.GenderFeminine:
+		return feminineStyle
+	case translator.GenderNeuter:
+		return neuterStyle
+	}
+	return fallback
+}
+
+// genderAbbreviations abbreviate genders of nouns in languages without articles.
+var genderAbbreviations = map[string]string{
+	translator.GenderMasculine: "m.",
+	translator.GenderFeminine:  "f.",
+	translator.GenderNeuter:    "n.",
+	translator.GenderCommon:    "c.",
+}
+
+// nounForms describes the article, or gender, and plural of a noun, e.g. "der, pl. Bahnhöfe".
+func nounForms(word translator.WordInfo) string {
+	var forms []string
+	switch {
+	case word.Article != "":
+		forms = append(forms, word.Article)
+	case word.Gender != "":
+		forms = append(forms, genderAbbreviations[word.Gender])
+	}
+	if word.Plural != "" {
+		forms = append(forms, "pl. "+word.Plural)
+	}
+	return strings.Join(forms, ", ")
+}
+
+// levelBadge renders a CEFR level as a badge, highlighted if it is above the known level.
+func levelBadge(level, known string) string {
+	if translator.AboveLevel(level, known) {
+		return warningStyle.Render("[" + level + "]")
+	}
+	return labelStyle.Render("[" + level + "]")
+}
+
+// modelFooter describes the models that produced the current result and how long
+// each step took.
+func (m model) modelFooter() string {
+	var footer string
+	switch {
+	case m.usedModels.Analysis == "", m.usedModels.Translation == m.usedModels.Analysis:
+		footer = fmt.Sprintf("Model: %s", m.usedModels.Translation)
+	default:
+		footer = fmt.Sprintf("Models: %s (translation) | %s (analysis)", m.usedModels.Translation, m.usedModels.Analysis)
+	}
+	if m.timings.Total() > 0 {
+		footer += " | " + m.timings.String()
+	}
+	return footer
+}
+
+func (m model) getLangName(code string) string {
+	if code == "" && m.detect {
+		return detectOption.name
+	}
+	if m.detected != nil && code == m.detected.Language {
+		return displayName(m.detected)
+	}
+	// Check in all possible languages, not just current langs
+	allLangs := append(slices.Clone(m.knownLangs), m.targetLangs...)
+	for _, lang := range allLangs {
+		if lang.code == code {
+			return lang.name
+		}
+	}
+	return code
+}
+
+// withCustomLanguages returns langs with the languages of the config file appended, or
+// renamed if their code is already listed.
+func withCustomLanguages(langs []language, custom []translator.Language) []language {
+	langs = slices.Clone(langs)
+	for _, c := range custom {
+		if i := slices.IndexFunc(langs, func(l language) bool { return l.code == c.Code }); i >= 0 {
+			langs[i].name = c.Name
+			continue
+		}
+		langs = append(langs, language{c.Code, c.Name})
+	}
+	return langs
+}
+
+// getAvailableTargetLanguages returns the target languages except the selected known language
+func getAvailableTargetLanguages(targets []language, userLang string) []language {
+	available := []language{}
+	for _, lang := range targets {
+		if lang.code != userLang {
+			available = append(available, lang)
+		}
+	}
+	return available
+}
+
+// startTranslation starts the pipeline for the current input and shows the loading view.
+func (m model) startTranslation() (model, tea.Cmd) {
+	m.loading = true
+	m.loadingStep = ""
+	m.deadline = time.Time{}
+	m.err = nil
+	m.started = time.Now()
+	m.translating = ""
+	m.forgetEdits()
+	if document.IsURL(m.input) {
+		return m.startArticle()
+	}
+	m.source = ""
+	// Markdown and HTML are translated as a whole, so their structure is kept
+	if sentences := translator.SplitSentences(m.input); len(sentences) > 1 && !translator.HasMarkup(m.input) {
+		return m.startParagraph(sentences)
+	}
+	if len(m.fanout) > 1 {
+		return m.startFanout()
+	}
+	m.paragraph = nil
+	// The input is cleared so that the next sentence can be typed meanwhile
+	m.translating, m.input = m.input, ""
+	return m, tea.Batch(translateSentence(m.cfg, m.userLang, m.targetLang, m.translating), loadingTick(), m.spinner.Tick)
+}
+
+// loadingTickMsg refreshes the remaining time shown while a translation is pending.
+type loadingTickMsg struct{}
+
+// loadingTick creates a tea.Cmd that emits a loadingTickMsg after one second.
+func loadingTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return loadingTickMsg{}
+	})
+}
+
+// loadingView describes the pending pipeline step and the time left before it times out.
+func (m model) loadingView() string {
+	return stepView(m.loadingStep, m.deadline)
+}
+
+// stepView describes a pipeline step and the time left before its deadline.
+func stepView(step string, deadline time.Time) string {
+	if step == "" {
+		step = "Starting"
+	}
+	if deadline.IsZero() {
+		return step + "..."
+	}
+	remaining := time.Until(deadline).Round(time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return fmt.Sprintf("%s... (times out in %s)", step, remaining)
+}
+
+// storageResult represents the outcome of a background storage operation.
+type storageResult struct {
+	status string
+	err    error
+}
+
+// warnModelFallback shows in the status line that a fallback model replaced an unavailable
+// one, once per session.
+func (m *model) warnModelFallback(result translate.Result) {
+	if result.ModelFallback == "" || m.modelFallbackWarned {
+		return
+	}
+	m.modelFallbackWarned = true
+	m.status = warningStyle.Render("⚠ " + result.ModelFallback + " (see [fallback] models)")
+}
+
+// recordHistory creates a tea.Cmd that appends a completed translation to the history file
+// and runs the configured hooks.
+func recordHistory(cfg config.Config, entry storage.HistoryEntry) tea.Cmd {
+	return func() tea.Msg {
+		return storageResult{err: translate.Record(context.Background(), cfg, entry)}
+	}
+}
+
+// saveWordsAboveLevel saves only the words above the configured level to the vocab deck.
+func (m model) saveWordsAboveLevel(sentence, translation string, words []translator.WordInfo) (tea.Model, tea.Cmd) {
+	if m.cfg.Level == "" {
+		m.status = warningStyle.Render("Set level in config.toml to save the words above your level")
+		return m, nil
+	}
+	var above []translator.WordInfo
+	for _, w := range words {
+		if translator.AboveLevel(w.Level, m.cfg.Level) {
+			above = append(above, w)
+		}
+	}
+	if len(above) == 0 {
+		m.status = normalStyle.Render(fmt.Sprintf("No words above level %s", m.cfg.Level))
+		return m, nil
+	}
+	return m, saveWordsToVocab(m.targetLang, sentence, translation, above, m.cfg.Level)
+}
+
+// saveWordsToVocab creates a tea.Cmd that adds the analyzed words to the vocab deck.
+// Words above the known level are saved as priority vocabulary.
+func saveWordsToVocab(lang, sentence, translation string, words []translator.WordInfo, known string) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		cards := make([]storage.VocabCard, 0, len(words))
+		for _, w := range words {
+			cards = append(cards, storage.VocabCard{
+				Word:         w.WordInTargetLang,
+				Analysis:     w.GrammaticalExplanation,
+				Sentence:     sentence,
+				Translation:  translation,
+				Lang:         lang,
+				Added:        now,
+				Level:        w.Level,
+				Priority:     translator.AboveLevel(w.Level, known),
+				Lemma:        w.Lemma,
+				PartOfSpeech: w.PartOfSpeech,
+				Gender:       w.Gender,
+				Article:      w.Article,
+			})
+		}
+		if storage.Private() {
+			return storageResult{status: warningStyle.Render("Private session: words are not saved")}
+		}
+		added, err := storage.AddToVocab(cards)
+		if err != nil {
+			return storageResult{err: err}
+		}
+		return storageResult{status: successStyle.Render(fmt.Sprintf("Saved %d new word(s) to vocab deck", added))}
+	}
+}
@@ -0,0 +1,1449 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/muesli/termenv"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/export"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+func init() {
+	// Render without colors so assertions match plain text
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// newTestServer serves canned OpenAI-compatible chat completions for both pipeline steps
+// and conversations.
+// A non-zero status makes every request fail with that status instead.
+func newTestServer(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != 0 {
+			http.Error(w, "server unavailable", status)
+			return
+		}
+		var req struct {
+			ResponseFormat struct {
+				JSONSchema struct {
+					Name string `json:"name"`
+				} `json:"json_schema"`
+			} `json:"response_format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var content string
+		switch req.ResponseFormat.JSONSchema.Name {
+		case "": // Free-text conversation
+			content = "Hallo! Wie geht es dir?"
+		case "word_analysis":
+			content = loadFixture(t, "analysis_valid.json")
+		case "simplification":
+			content = `{"rewrites": [{"text": "Ich bin froh.", "level": "A1", "changes": "Simpler word"}]}`
+		case "detection":
+			content = `{"language": "de", "name": "German", "ambiguous": false}`
+		case "conjugation":
+			content = `{"lemma": "sein", "forms": [{"tense": "present", "person": "1st person singular", "pronoun": "ich", "form": "bin"}, {"tense": "present", "person": "2nd person singular", "pronoun": "du", "form": "bist"}, {"tense": "past", "person": "1st person singular", "pronoun": "ich", "form": "war"}]}`
+		case "case_quiz":
+			content = `{"questions": [{"word": "mit", "phrase": "mit [der Bus]", "answer": "dative", "example": "mit dem Bus", "explanation": "Mit always takes the dative."}, {"word": "ohne", "phrase": "ohne [ich]", "answer": "ablative", "example": "ohne mich", "explanation": ""}]}`
+		case "word_details":
+			content = `{"synonyms": [{"word": "froh", "gloss": "glad", "register": ""}, {"word": "glücklich", "gloss": "happy", "register": ""}], "antonyms": [{"word": "traurig", "gloss": "sad", "register": "also of things"}], "phrases": [{"text": "glücklich sein", "translation": "to be happy"}, {"text": " ", "translation": ""}]}`
+		case "number_grade":
+			content = `{"correct": false, "expected": "dreiundzwanzig", "explanation": "The ones come before the tens."}`
+		case "comparison":
+			content = `{"meaning": "Same meaning", "register": "The second is casual", "grammar": "No difference", "use_first": "In writing", "use_second": "With friends"}`
+		default:
+			content = loadFixture(t, "translation_valid.json")
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]string{"role": "assistant", "content": content}},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestProgram starts the TUI against the test server with an isolated data directory,
+// with the config changed by configure.
+func newTestProgram(t *testing.T, srv *httptest.Server, keys map[string][]string, configure ...func(*config.Config)) *teatest.TestModel {
+	t.Helper()
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	translate.ResetCircuit()
+	t.Cleanup(translate.ResetCircuit)
+
+	cfg := config.Default()
+	cfg.Provider = translator.ProviderOpenAI
+	cfg.OpenAI = config.OpenAIConfig{BaseURL: srv.URL, Model: "test-model"}
+	cfg.Timeout = 5 * time.Second
+	cfg.Keys = keys
+	for _, f := range configure {
+		f(&cfg)
+	}
+	m, err := initialModel(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return teatest.NewTestModel(t, m, teatest.WithInitialTermSize(120, 40))
+}
+
+// loadFixture reads a file from testdata.
+func loadFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return string(data)
+}
+
+// waitForText waits until the rendered output contains all of the given strings.
+func waitForText(t *testing.T, tm *teatest.TestModel, texts ...string) {
+	t.Helper()
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		for _, text := range texts {
+			if !bytes.Contains(out, []byte(text)) {
+				return false
+			}
+		}
+		return true
+	}, teatest.WithDuration(5*time.Second))
+}
+
+// selectLanguages picks Swedish as the known language and German as the target.
+func selectLanguages(t *testing.T, tm *teatest.TestModel) {
+	t.Helper()
+	waitForText(t, tm, "Select A Language You Know Well:")
+	tm.Type("swe")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Select The Language You Want To Learn:", "From: Swedish")
+	tm.Type("ger")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Enter Sentence in Either Language:", "Swedish ↔ German")
+}
+
+func TestTranslationFlow(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Type("jag är glad")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation Results", "Ich bin glücklich.", "Level: [A1]", "Model: test-model")
+	tm.Type("2")
+	waitForText(t, tm, "Word-by-Word Analysis:", "1st person singular of sein", "glücklich [A2]", "Model: test-model")
+
+	// q returns to the input screen for the next sentence
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	waitForText(t, tm, "Enter: Translate")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.state != stateInputSentence {
+		t.Errorf("final state = %v, want %v", final.state, stateInputSentence)
+	}
+	if final.translation != "" || final.wordAnalysis != nil {
+		t.Errorf("results were not cleared: %q, %v", final.translation, final.wordAnalysis)
+	}
+}
+
+func TestErrorScreen(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, http.StatusInternalServerError), nil)
+	selectLanguages(t, tm)
+
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation Failed", "server unavailable")
+
+	// Esc goes back to editing the sentence
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	waitForText(t, tm, "Sentence: hej")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.state != stateInputSentence {
+		t.Errorf("final state = %v, want %v", final.state, stateInputSentence)
+	}
+	if final.failure != nil {
+		t.Errorf("failure was not cleared: %v", final.failure)
+	}
+}
+
+func TestOffline(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	upstream := newTestServer(t, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "server unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		upstream.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	tm := newTestProgram(t, srv, nil)
+	selectLanguages(t, tm)
+
+	cfg := config.Default()
+	cfg.Provider = translator.ProviderOpenAI
+	cfg.OpenAI = config.OpenAIConfig{BaseURL: srv.URL, Model: "test-model"}
+	req := translator.Request{Sentence: "hej", UserLang: "sv", TargetLang: "de"}
+	for range 3 {
+		if _, err := translate.Run(context.Background(), cfg, req, nil); err == nil {
+			t.Fatal("translation against a failing server succeeded")
+		}
+	}
+
+	// Sentences entered while offline are queued
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Offline: the provider failed repeatedly", "○ offline", "queued for translation when back online (1 waiting)", "1 queued")
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlV})
+	waitForText(t, tm, "Offline Queue:", "sv → de: hej (waiting)")
+
+	// and translated once the provider is back
+	failing.Store(false)
+	translate.ResetCircuit()
+	tm.Type("r")
+	waitForText(t, tm, "Translated 1 queued sentences (0 failed)", "✓ sv → de: hej → Ich bin glücklich.")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.state != stateShowResults || len(final.queue) != 0 {
+		t.Errorf("state = %v with %d queued, want results and an empty queue", final.state, len(final.queue))
+	}
+}
+
+func TestBackgroundTranslations(t *testing.T) {
+	release := make(chan struct{})
+	var held atomic.Bool
+	upstream := newTestServer(t, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if held.CompareAndSwap(false, true) {
+			<-release // Hold the first translation until the next sentences are entered
+		}
+		upstream.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	unblock := sync.OnceFunc(func() { close(release) })
+	t.Cleanup(unblock) // Before the server closes, which waits for the held request
+	tm := newTestProgram(t, srv, nil)
+	selectLanguages(t, tm)
+
+	tm.Type("god morgon")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translating: god morgon")
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translated in the background: 1 of 2 ready")
+
+	// The first result joins the list rather than interrupting the next sentence
+	tm.Type("tack")
+	unblock()
+	waitForText(t, tm, "Translated in the background: 2 of 2 ready", "Sentence: tack")
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlF})
+	waitForText(t, tm, "Translated in the Background", "1. god morgon", "2. hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Sentence 2 of 2")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if len(final.background) != 2 || !final.background[0].done || final.input != "tack" {
+		t.Errorf("background = %+v, input = %q, want 2 translated and the next sentence kept", final.background, final.input)
+	}
+}
+
+func TestUndoInput(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Type("hej du")
+	waitForText(t, tm, "Sentence: hej du")
+	tm.Send(tea.KeyMsg{Type: tea.KeyBackspace})
+	tm.Send(tea.KeyMsg{Type: tea.KeyBackspace})
+	tm.Type("ni")
+	waitForText(t, tm, "Sentence: hej ni")
+
+	// Undo takes back the typed word, then both deletions at once
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	waitForText(t, tm, "Sentence: hej du")
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlY})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.input != "hej " {
+		t.Errorf("input = %q, want %q", final.input, "hej ")
+	}
+	if len(final.undo) != 3 || len(final.redo) != 1 {
+		t.Errorf("undo = %q, redo = %q, want 3 and 1 steps", final.undo, final.redo)
+	}
+}
+
+func TestDraftRestored(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	// Going back to the language menu saves the sentence, and coming back restores it
+	tm.Type("hej du")
+	waitForText(t, tm, "Sentence: hej du")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	waitForText(t, tm, "Select The Language You Want To Learn:")
+	tm.Type("ger")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Sentence: hej du", "Restored the sentence you were typing")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+	draft, err := storage.LoadDraft()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if draft.Sentence != "hej du" {
+		t.Errorf("draft = %q, want %q", draft.Sentence, "hej du")
+	}
+}
+
+func TestInputCostConfirmation(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil, func(cfg *config.Config) {
+		cfg.OpenAI.Model = "gemini-2.5-pro" // Known pricing
+		cfg.Input.MaxCost = 0.001
+	})
+	selectLanguages(t, tm)
+
+	tm.Type("jag ar glad")
+	waitForText(t, tm, "11 characters, ~3 tokens, ~$0.0028")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "more than max_cost ($0.0010); press Enter again to translate anyway")
+
+	// Confirming translates it anyway
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation Results", "Ich bin glücklich.")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestInputTooLong(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil, func(cfg *config.Config) {
+		cfg.Input.MaxInputTokens = 510
+	})
+	selectLanguages(t, tm)
+
+	tm.Type("jag ar glad och du ar glad och vi ar alla glada idag")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Too long: about 517 tokens, but the model takes at most 510")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.state != stateInputSentence {
+		t.Errorf("state = %v, want %v", final.state, stateInputSentence)
+	}
+}
+
+func TestSpellcheck(t *testing.T) {
+	// A stand-in for hunspell that knows every word but "gald"
+	bin := t.TempDir()
+	script := "#!/bin/sh\ntr -s ' ' '\\n' | grep -x gald\nexit 0\n"
+	if err := os.WriteFile(filepath.Join(bin, "hunspell"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Type("jag ar gald")
+	waitForText(t, tm, "Check the spelling of: gald")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if !slices.Equal(final.misspelled, []string{"gald"}) {
+		t.Errorf("misspelled = %q, want [gald]", final.misspelled)
+	}
+}
+
+func TestAccents(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	// German letters come first, then Swedish ones
+	tm.Type("a")
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	waitForText(t, tm, "Sentence: ä")
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	waitForText(t, tm, "Sentence: å")
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	tm.Type("C")
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	waitForText(t, tm, "Sentence: aÇ")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlS})
+	waitForText(t, tm, "Characters: [ä] ö ü ß „ “ ‚ ‘ Ä Ö Ü")
+	tm.Send(tea.KeyMsg{Type: tea.KeyRight})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Sentence: aÇö")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.input != "aÇö" || final.pickingChar {
+		t.Errorf("input = %q, picking = %v, want the picked character typed", final.input, final.pickingChar)
+	}
+}
+
+func TestComposedInput(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	// An input method commits several characters at once, a dead key sends the accent
+	// after its letter, and Alt combinations are no text
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("你好")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("\u0301")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x"), Alt: true})
+	waitForText(t, tm, "Sentence: 你好é")
+	tm.Send(tea.KeyMsg{Type: tea.KeyBackspace})
+	tm.Send(tea.KeyMsg{Type: tea.KeyBackspace})
+	waitForText(t, tm, "Sentence: 你")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.input != "你" {
+		t.Errorf("input = %q, want %q", final.input, "你")
+	}
+}
+
+func TestDeleteLast(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"café", "caf"},
+		{"cafe\u0301", "caf"},
+		{"한국", "한"},
+		{"hi 👋🏽", "hi "},
+	}
+	for _, tt := range tests {
+		if got := deleteLast(tt.in); got != tt.want {
+			t.Errorf("deleteLast(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRightToLeftInput(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("שלום עולם")})
+	waitForText(t, tm, "Sentence:", "םלוע םולש")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.input != "שלום עולם" {
+		t.Errorf("input = %q, want it kept in logical order", final.input)
+	}
+	// Shown against the right edge, in the order it is read
+	line := final.viewInputRTL()
+	if !strings.HasPrefix(line, "   ") || !strings.HasSuffix(line, "םלוע םולש") || lipgloss.Width(line) != final.width {
+		t.Errorf("input shown as %q, want it right-aligned to %d columns", line, final.width)
+	}
+}
+
+func TestVisualOrder(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"hello world", "hello world"},
+		{"שלום עולם", "םלוע םולש"},
+		{"אני בן 25 שנים.", ".םינש 25 ןב ינא"},
+		{"שלום hello world עולם", "םלוע hello world םולש"},
+		{"hello שלום עולם 123 bye", "hello 123 םלוע םולש bye"},
+		{"مرحبا (بك)", "(كب) ابحرم"},
+		{"كَتَبَ", "بَتَكَ"},
+	}
+	for _, tt := range tests {
+		if got := bidiText(tt.in); got != tt.want {
+			t.Errorf("bidiText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLanguageNavigation(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	// Esc walks back through the language menus
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	waitForText(t, tm, "Esc: Back | F1: Help | Type to filter")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.state != stateSelectUserLang {
+		t.Errorf("final state = %v, want %v", final.state, stateSelectUserLang)
+	}
+	if final.userLang != "sv" || final.targetLang != "de" {
+		t.Errorf("languages = %s/%s, want sv/de", final.userLang, final.targetLang)
+	}
+}
+
+func TestNavigationStack(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+	tm.Type("jag är glad")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Language › Learn › Sentence › Results")
+
+	// Screens opened on top of the results return to them
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlG})
+	waitForText(t, tm, "Language › Learn › Sentence › Results › Generation")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	waitForText(t, tm, "Language › Learn | sv")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.state != stateSelectTargetLang || len(final.nav) != 1 {
+		t.Errorf("state = %v with %d screens below, want %v with 1", final.state, len(final.nav), stateSelectTargetLang)
+	}
+	if final.translation != "" {
+		t.Errorf("results were not cleared: %q", final.translation)
+	}
+}
+
+func TestScreenTitles(t *testing.T) {
+	titles := map[appState]string{
+		stateSelectUserLang:   "Language",
+		stateSelectTargetLang: "Learn",
+		stateInputSentence:    "Sentence",
+		stateShowResults:      "Results",
+		stateSelectModel:      "Model",
+		stateSetupAPIKey:      "Setup",
+		stateDebug:            "Debug",
+		stateError:            "Error",
+		stateDocument:         "Document",
+		stateConversation:     "Conversation",
+		stateCompare:          "Compare",
+		stateSimplify:         "Simplify",
+		stateWatch:            "Watch",
+		stateGeneration:       "Generation",
+		statePairs:            "Pairs",
+		stateSettings:         "Settings",
+		statePending:          "Queue",
+		stateDictionary:       "Dictionary",
+		stateReview:           "Review",
+		statePractice:         "Practice",
+		stateListening:        "Listening",
+		stateTyping:           "Typing",
+		stateShadowing:        "Shadowing",
+		stateNumbers:          "Numbers",
+		stateConjugation:      "Conjugation",
+		stateCases:            "Cases",
+		stateGenders:          "Genders",
+		stateCloze:            "Cloze",
+		stateStudy:            "Study",
+	}
+	for state, want := range titles {
+		if got := state.title(); got != want {
+			t.Errorf("%v.title() = %q, want %q", state, got, want)
+		}
+	}
+
+	m := model{nav: []appState{stateSelectUserLang, stateSelectTargetLang, stateInputSentence}, state: stateReview}
+	if got, want := m.breadcrumb(), "Language › Learn › Sentence › Review"; got != want {
+		t.Errorf("breadcrumb = %q, want %q", got, want)
+	}
+}
+
+func TestHelpOverlay(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), map[string][]string{"help": {"f2"}, "save": {"ctrl+s"}})
+	selectLanguages(t, tm)
+
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation Results", "Ctrl+S: Save words")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyF2})
+	waitForText(t, tm, "Keybindings", "Translate another")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.showHelp {
+		t.Error("help overlay was not closed")
+	}
+	if final.state != stateShowResults {
+		t.Errorf("final state = %v, want %v", final.state, stateShowResults)
+	}
+}
+
+func TestUnknownKeyAction(t *testing.T) {
+	cfg := config.Default()
+	cfg.Keys = map[string][]string{"jump": {"x"}}
+	if _, err := initialModel(cfg); err == nil {
+		t.Error("expected an error for an unknown action")
+	}
+}
+
+func TestInlineMode(t *testing.T) {
+	srv := newTestServer(t, 0)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	cfg := config.Default()
+	cfg.Provider = translator.ProviderOpenAI
+	cfg.OpenAI = config.OpenAIConfig{BaseURL: srv.URL, Model: "test-model"}
+	m, err := NewInline(cfg, "sv", "de", "jag är glad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(120, 40))
+
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(inlineModel)
+	if final.Err() != nil {
+		t.Fatalf("unexpected error: %v", final.Err())
+	}
+	if view := final.View(); !strings.Contains(view, "Ich bin glücklich.") {
+		t.Errorf("result missing from view:\n%s", view)
+	}
+}
+
+func TestParagraphMode(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Type("Jag heter Anna. Du heter Bo.")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Sentence 1 of 2", "Ich bin glücklich.")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRight})
+	waitForText(t, tm, "Sentence 2 of 2")
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	waitForText(t, tm, "Paragraph Overview", "2. Du heter Bo.")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if len(final.paragraph) != 2 || final.sentenceIndex != 1 {
+		t.Errorf("paragraph = %d sentences at %d, want 2 at 1", len(final.paragraph), final.sentenceIndex)
+	}
+}
+
+func TestConversation(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlN})
+	waitForText(t, tm, "Conversation Practice", "Choose your level:")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Partner: Hallo! Wie geht es dir?")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	waitForText(t, tm, "Translation: Ich bin glücklich.", "Word-by-Word Analysis:")
+
+	tm.Type("gut")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "You: gut")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if len(final.chat) < 2 || final.chat[1].Text != "gut" || final.chat[0].result == nil {
+		t.Errorf("unexpected conversation %+v", final.chat)
+	}
+}
+
+func TestFollowUpQuestion(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Type("jag är glad")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation Results", "f: Ask")
+
+	tm.Type("f")
+	waitForText(t, tm, "Ask: ")
+	tm.Type("why sein?")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Q: why sein?", "A: Hallo! Wie geht es dir?")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if len(final.followUps) != 1 || final.followUps[0].answer == "" {
+		t.Errorf("unexpected follow-ups %+v", final.followUps)
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	waitForText(t, tm, "Select A Language You Know Well:")
+	tm.Type("swe")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Detect automatically (auto)")
+	tm.Type("auto")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Swedish ↔ Detect automatically")
+
+	tm.Type("Ich bin glücklich.")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation Results", "Swedish ↔ German")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.targetLang != "de" || !final.detect {
+		t.Errorf("target = %q, detect = %v, want de, true", final.targetLang, final.detect)
+	}
+}
+
+func TestFanout(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	waitForText(t, tm, "Select A Language You Know Well:")
+	tm.Type("swe")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Select The Language You Want To Learn:")
+
+	// Mark Spanish and French, below Detect and Serbian
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	waitForText(t, tm, "Spanish (es) ✓", "French (fr) ✓")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Swedish → Spanish, French")
+
+	tm.Type("jag är glad")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "[Spanish]", "Swedish ↔ Spanish")
+	tm.Send(tea.KeyMsg{Type: tea.KeyRight})
+	waitForText(t, tm, "[French]", "Swedish ↔ French")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if len(final.paragraph) != 2 || final.targetLang != "fr" {
+		t.Errorf("results = %d, target = %q, want 2, fr", len(final.paragraph), final.targetLang)
+	}
+}
+
+func TestRecentPairs(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	// Pick a second pair through the menus
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	waitForText(t, tm, "Ctrl+P: Recent pairs")
+	tm.Type("eng")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "From: English")
+	tm.Type("spa")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "English ↔ Spanish")
+
+	// The switcher preselects the previous pair
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlP})
+	waitForText(t, tm, "Recent Language Pairs:", "> Swedish ↔ German")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.state != stateInputSentence || final.userLang != "sv" || final.targetLang != "de" {
+		t.Errorf("state = %v, pair = %s/%s, want input with sv/de", final.state, final.userLang, final.targetLang)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlK})
+	waitForText(t, tm, "Explain The Difference:")
+	tm.Type("Guten Tag")
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Type("Hallo")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Register: The second is casual", "Use the second: With friends")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.compareInputs != [2]string{"Guten Tag", "Hallo"} {
+		t.Errorf("inputs = %q", final.compareInputs)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Type("Ich bin hocherfreut.")
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlR})
+	waitForText(t, tm, "Simplify Or Paraphrase:", "Simplify to A2")
+	tm.Send(tea.KeyMsg{Type: tea.KeyLeft})
+	waitForText(t, tm, "Simplify to A1")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "1. [A1] Ich bin froh.", "Simpler word")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.simplification == nil || len(final.simplification.Rewrites) != 1 {
+		t.Errorf("simplification = %+v", final.simplification)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlE})
+	waitForText(t, tm, "Settings:", "Formality      none")
+	for range 4 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyRight})
+	waitForText(t, tm, "> Formality      formal", "changed for this session")
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRight})
+	waitForText(t, tm, "> Analysis depth deep")
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "> Result cache   off")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.cfg.Formality != "formal" || final.cfg.Depth != "deep" || final.cfg.Fallback.Cache {
+		t.Errorf("formality = %q, depth = %q, cache = %v, want formal, deep, false", final.cfg.Formality, final.cfg.Depth, final.cfg.Fallback.Cache)
+	}
+}
+
+func TestAnalyzeOnDemand(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlE})
+	for range 6 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "> Word analysis  on demand")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+
+	tm.Type("jag är glad")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+	tm.Type("2")
+	waitForText(t, tm, "No word analysis for this sentence. Press a to pick words")
+	tm.Type("a")
+	waitForText(t, tm, "Pick words to analyze:", "Tab: Pick")
+	tm.Send(tea.KeyMsg{Type: tea.KeyRight})
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	waitForText(t, tm, "[bin]")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Analyzed 3 words", "glücklich")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if len(final.wordAnalysis) != 3 || final.foreign != "Ich bin glücklich." {
+		t.Errorf("analysis = %+v of %q", final.wordAnalysis, final.foreign)
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlE})
+	for range 7 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "> Dictionary     collect words")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		entries, err := storage.LoadDictionary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dictionary = %+v, want the 3 analyzed words", entries)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlW})
+	waitForText(t, tm, "Dictionary: German", "> glücklich — happy", "sein — am", "First seen")
+	tm.Type("/am")
+	waitForText(t, tm, "Search: am", "> sein — am", "Forms: bin")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestGenerationParameters(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlG})
+	waitForText(t, tm, "Generation Parameters:", "0.30")
+	tm.Send(tea.KeyMsg{Type: tea.KeyRight})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRight})
+	waitForText(t, tm, "0.40", "0.05")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, `Saved generation parameters for profile "default"`)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	g := final.cfg.Generation.Translation
+	if g.Temperature == nil || *g.Temperature != 0.4 || g.TopP == nil || *g.TopP != 0.05 {
+		t.Errorf("translation parameters = %+v", g)
+	}
+	state, err := storage.LoadProfileState(config.DefaultProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Generation.Translation.Temperature == nil || *state.Generation.Translation.Temperature != 0.4 {
+		t.Errorf("saved parameters = %+v", state.Generation)
+	}
+}
+
+func TestResultTabs(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Type("jag är glad")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "[1 Translation]", "Translation: Ich bin glücklich.")
+	tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+	waitForText(t, tm, "[2 Words]", "Word-by-Word Analysis:")
+	tm.Send(tea.KeyMsg{Type: tea.KeyShiftTab})
+	waitForText(t, tm, "[1 Translation]")
+	tm.Type("5")
+	waitForText(t, tm, "[5 Raw]", `"Translation": "Ich bin glücklich."`)
+	tm.Type("4")
+	waitForText(t, tm, "[4 Alternatives]", "Sentence: Ich bin glücklich.", "Enter: Paraphrase the sentence")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "1. Ich bin froh.", "Simpler word")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	if final.resultTab != tabAlternatives || final.alternatives == nil {
+		t.Errorf("tab = %v, alternatives = %+v", final.resultTab, final.alternatives)
+	}
+}
+
+func TestWordTable(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Type("jag är glad")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation Results")
+	tm.Type("2")
+	waitForText(t, tm, "Word", "Lemma", "POS", "Gloss", "Grammar", "sein", "adjective")
+	tm.Type("cc")
+	waitForText(t, tm, "Words sorted by lemma")
+	tm.Type("/verb")
+	waitForText(t, tm, "Filter: verb█")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	view := final.analysisView()
+	if len(view) != 1 || view[0].Lemma != "sein" || final.filteringWords {
+		t.Errorf("filtered words = %+v, still filtering = %v", view, final.filteringWords)
+	}
+	if sorted := sortWords(final.wordAnalysis, 1); sorted[0].Lemma != "glücklich" || sorted[2].Lemma != "sein" {
+		t.Errorf("sorted by lemma = %+v", sorted)
+	}
+}
+
+func TestCopyMenu(t *testing.T) {
+	// A pbcopy of the test takes the copied text
+	bin, clip := t.TempDir(), filepath.Join(t.TempDir(), "clipboard.txt")
+	if err := os.WriteFile(filepath.Join(bin, "pbcopy"), []byte("#!/bin/sh\ncat > "+clip+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	for _, env := range []string{"WAYLAND_DISPLAY", "DISPLAY", "SSH_TTY"} {
+		t.Setenv(env, "")
+	}
+
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+	tm.Type("jag är glad")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation Results")
+	tm.Type("y")
+	waitForText(t, tm, "Copy as:", "> Translation only", "Anki TSV row", "Full JSON")
+	for range 3 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Copied anki tsv row to the clipboard")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	data, err := os.ReadFile(clip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields := strings.Split(string(data), "\t"); len(fields) != 3 || fields[0] != "Ich bin glücklich." || fields[1] != "I am happy." || !strings.Contains(fields[2], "<b>bin</b>") {
+		t.Errorf("copied row = %q", data)
+	}
+	if table := final.markdownTable(); !strings.Contains(table, "| German | Swedish |\n| --- | --- |\n| Ich bin glücklich. | I am happy. |") {
+		t.Errorf("markdown table = %q", table)
+	}
+	if final.copying {
+		t.Error("copy menu still open")
+	}
+}
+
+func TestExportMenu(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+	tm.Type("jag är glad")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation Results")
+
+	// Without an export directory, files are written to the current directory
+	dir := t.TempDir()
+	t.Chdir(dir)
+	tm.Type("E")
+	waitForText(t, tm, "Export:", "> Result as plain text", "Session as Markdown")
+	for range 4 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Exported 1 translation(s) to session-")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second))
+	files, err := filepath.Glob(filepath.Join(dir, "session-*.md"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("exported files = %v, %v", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := string(data); !strings.Contains(text, "> I am happy.\n\n**Ich bin glücklich.**") || !strings.Contains(text, "| bin | am |") {
+		t.Errorf("exported session = %q", text)
+	}
+}
+
+func TestReview(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+	tm.Type("s")
+	waitForText(t, tm, "Saved 3 new word(s) to vocab deck")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlL})
+	waitForText(t, tm, "Review: 3 left", "Ich", "Enter: Show answer")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "1: Again (soon)", "3: Good (1 day)", "4: Easy (4 days)")
+	tm.Type("1")
+	waitForText(t, tm, "bin  (German)")
+	for range 3 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+		tm.Type("3")
+	}
+	waitForText(t, tm, "Done: 3 card(s) reviewed.")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		cards, err := storage.LoadVocab()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cards) == 3 && cards[0].Reps == 1 && cards[0].Lapses == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("vocab = %+v, want reviewed cards", cards)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEsc})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestDueCardsBanner(t *testing.T) {
+	m, err := initialModel(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, _ := m.Update(dueCardsResult{cards: make([]storage.VocabCard, 14)})
+	if view := next.View(); !strings.Contains(view, "14 cards due — press Ctrl+L to review") {
+		t.Errorf("view lacks the banner:\n%s", view)
+	}
+}
+
+func TestListeningQuiz(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil, func(cfg *config.Config) {
+		cfg.Pronunciation.TTSCommand = []string{"sh", "-c", `printf '%s' "$1" > "$0"`, "{file}", "{word}"}
+		cfg.Pronunciation.Player = []string{"true"}
+	})
+	selectLanguages(t, tm)
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+	tm.Type("s")
+	waitForText(t, tm, "Saved 3 new word(s) to vocab deck")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlX})
+	waitForText(t, tm, "Practice German:", "> Review due cards", "Listening quiz")
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Listening Quiz: German", "Sentence 1 of 1", "Type what you hear")
+	tm.Type("ich bim")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Ich bin (not bim) glücklich (missed)", "Meaning:  I am happy.", "1 of 3 words (33%)")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Done: 1 of 3 words heard (33%).")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestTypingDrill(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+	tm.Type("s")
+	waitForText(t, tm, "Saved 3 new word(s) to vocab deck")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlX})
+	waitForText(t, tm, "Typing drill")
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Typing Drill: German", "Copy:  Ich bin glücklich.", "Special characters: ü")
+	tm.Type("Ich bin glucklich.")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "94% accuracy", `Mistakes: "ü" typed as "u"`)
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Done: 94% accuracy")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestShadowing(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil, func(cfg *config.Config) {
+		cfg.Pronunciation.TTSCommand = []string{"sh", "-c", `printf '%s' "$1" > "$0"`, "{file}", "{word}"}
+		cfg.Pronunciation.Player = []string{"true"}
+	})
+	selectLanguages(t, tm)
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlX})
+	waitForText(t, tm, "Shadowing")
+	for range 3 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Shadowing: German", "Sentence 1 of 1 · whole sentence · 1× · looping", "Ich bin glücklich.", "I   am  happy", "Meaning: I am happy.")
+	tm.Type("-")
+	waitForText(t, tm, "0.9× · paused", "true cannot change the speed of audio")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestNumbersDrill(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlX})
+	waitForText(t, tm, "Numbers and dates")
+	for range 4 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Numbers And Dates: German", "Number: ")
+	tm.Type("zwanzigdrei")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Not quite", "Written out: dreiundzwanzig", "The ones come before the tens.", "Session: 0 of 1 right (0%)")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Date:   ")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestConjugationQuiz(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+	tm.Type("s")
+	waitForText(t, tm, "Saved 3 new word(s) to vocab deck")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlX})
+	waitForText(t, tm, "Conjugation")
+	for range 6 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Conjugation: German", "Verb 1 of 1", "Verb:   sein", "Form:   ")
+	tm.Type("sind")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Not quite", "Session: 0 of 1 right (0%)")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Done: 0 of 1 forms right (0%).", "come up first in the review")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		cards, err := storage.LoadVocab()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i := slices.IndexFunc(cards, func(c storage.VocabCard) bool { return c.Word == "bin" }); i >= 0 && cards[i].Priority && cards[i].Lemma == "sein" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("verb conjugated wrong not made a priority: %+v", cards)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok := storage.LookupConjugation("sv", "de", "sein"); !ok {
+		t.Error("conjugation of sein not cached")
+	}
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestCaseQuiz(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlX})
+	waitForText(t, tm, "Cases")
+	for range 7 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	// The question whose answer is not a German case is left out
+	waitForText(t, tm, "Case Quiz: German", "Question 1 of 1", "Phrase: mit [der Bus]", "> Nominative", "Genitive")
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "✗ Accusative", "✓ Dative", "Not quite: dative, mit dem Bus", "Mit always takes the dative.", "Session: 0 of 1 right (0%)")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Done: 0 of 1 right (0%).")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestGenderQuiz(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	_, err := storage.AddToVocab([]storage.VocabCard{
+		{Word: "Bahnhof", Lang: "de", Sentence: "Der Bahnhof ist groß.", Lemma: "Bahnhof", PartOfSpeech: "noun", Gender: "masculine", Article: "der"},
+		{Word: "Städte", Lang: "de", Lemma: "Stadt", PartOfSpeech: "noun", Gender: "feminine", Article: "die", GenderAsked: 2, GenderMissed: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlX})
+	waitForText(t, tm, "Genders")
+	for range 8 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	// The noun missed before comes first
+	waitForText(t, tm, "Gender Quiz: German", "Noun 1 of 2", "Noun: Stadt", "> der · masculine", "die · feminine", "neuter")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Not quite: die Stadt", "This noun: 1 of 3 right (33%) · session: 0 of 1")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Noun: Bahnhof")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Correct: der Bahnhof", "Saved from: Der Bahnhof ist groß.", "This noun: 1 of 1 right (100%)")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Done: 1 of 2 right (50%).", "To go over: die Stadt")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		cards, err := storage.LoadVocab()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cards) == 2 && cards[0].GenderAsked == 1 && cards[1].GenderAsked == 3 && cards[1].GenderMissed == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("answers not recorded: %+v", cards)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestStudySession(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil, func(cfg *config.Config) {
+		cfg.Pronunciation.TTSCommand = []string{"sh", "-c", `printf '%s' "$1" > "$0"`, "{file}", "{word}"}
+		cfg.Pronunciation.Player = []string{"true"}
+	})
+	_, err := storage.AddToVocab([]storage.VocabCard{
+		{Word: "Bahnhof", Lang: "de", Sentence: "Der Bahnhof ist groß.", Translation: "The station is big.", Due: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	selectLanguages(t, tm)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlX})
+	waitForText(t, tm, "Study session")
+	for range 10 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Study Session: German", "◀ 15 minutes ▶")
+	tm.Send(tea.KeyMsg{Type: tea.KeyLeft})
+	waitForText(t, tm, "◀ 10 minutes ▶")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+
+	waitForText(t, tm, "Review: 1 left", "Bahnhof", "study: 10 min left")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	tm.Type("3")
+	waitForText(t, tm, "Cloze: German", "Der ____ ist groß.", "Meaning: The station is big.")
+	tm.Type("bahnhof")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Correct")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	// No verbs saved, so the conjugation quiz is left out
+	waitForText(t, tm, "Listening Quiz: German", "Type what you hear")
+	tm.Type("der bahnhof ist klein")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "3 of 4 words (75%)")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+
+	waitForText(t, tm, "Session over after 0 minutes.", "1 of 1 right (100%)", "not practiced", "3 of 4 right (75%)", "5 of 6 right (83%)")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(5*time.Second))
+}
+
+func TestRelatedWords(t *testing.T) {
+	tm := newTestProgram(t, newTestServer(t, 0), nil)
+	selectLanguages(t, tm)
+	tm.Type("hej")
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	waitForText(t, tm, "Translation: Ich bin glücklich.")
+	tm.Type("2")
+	waitForText(t, tm, "Word-by-Word Analysis:")
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+	tm.Type("n")
+	// The word itself is left out of its synonyms
+	waitForText(t, tm, "Synonyms:", "froh · glad", "Antonyms:", "traurig · sad (also of things)", "Phrases:", "glücklich sein · to be happy")
+	tm.Type("n")
+	waitForText(t, tm, "> froh · glad")
+	tm.Type("n")
+	waitForText(t, tm, "> traurig · sad")
+	tm.Type("v")
+	waitForText(t, tm, "Saved traurig to vocab deck")
+	cards, err := storage.LoadVocab()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].Word != "traurig" || cards[0].Lang != "de" || cards[0].Analysis != "sad (also of things) · related to glücklich" {
+		t.Errorf("related word not saved: %+v", cards)
+	}
+
+	tm.Type("g")
+	waitForText(t, tm, "Ich [A1] - I - personal pronoun")
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(5*time.Second)).(model)
+	// The result of the related word replaces the one it was found in
+	if want := []appState{stateSelectUserLang, stateSelectTargetLang, stateInputSentence}; final.state != stateShowResults || !slices.Equal(final.nav, want) {
+		t.Errorf("related word not analyzed in place: %v over %v", final.state, final.nav)
+	}
+}
+
+func TestMarkStress(t *testing.T) {
+	tests := []struct {
+		sentence string
+		words    []translator.WordInfo
+		want     string
+	}{
+		{
+			"Она пьёт молоко на кухне.",
+			[]translator.WordInfo{{WordInTargetLang: "Она", Stress: "Она́"}, {WordInTargetLang: "молоко", Stress: "молоко́"}, {WordInTargetLang: "на"}, {WordInTargetLang: "кухне", Stress: "ку́хне"}},
+			"Она́ пьёт молоко́ на ку́хне.",
+		},
+		{
+			"箸を使う",
+			[]translator.WordInfo{{WordInTargetLang: "箸", Stress: "はしꜜ"}, {WordInTargetLang: "使う", Stress: "つかう"}},
+			"はしꜜをつかう",
+		},
+	}
+	for _, tt := range tests {
+		if got := markStress(tt.sentence, tt.words); got != tt.want {
+			t.Errorf("markStress(%q) = %q, want %q", tt.sentence, got, tt.want)
+		}
+	}
+}
+
+func TestShadowChunks(t *testing.T) {
+	tokens := export.Annotate("Wenn es regnet, bleiben wir heute den ganzen Tag zu Hause.", nil)
+	var parts []string
+	for _, c := range shadowChunks(tokens) {
+		var s strings.Builder
+		for _, tok := range tokens[c[0]:c[1]] {
+			s.WriteString(tok.Text)
+		}
+		parts = append(parts, s.String())
+	}
+	want := []string{"Wenn es regnet", "bleiben wir heute den", "ganzen Tag zu Hause"}
+	if !slices.Equal(parts, want) {
+		t.Errorf("chunks = %q, want %q", parts, want)
+	}
+}
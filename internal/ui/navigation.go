@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Screens are kept on a navigation stack: navigate shows a screen on top of the current
+// one and pop returns to it. Assigning m.state directly replaces the current screen, so
+// that going back skips it, as the error screen does for the sentence input.
+
+// navigate shows state on top of the current screen, which going back returns to.
+func (m *model) navigate(state appState) {
+	// Clipped so that copies of the model never share a stack that is appended to
+	m.nav = append(slices.Clip(m.nav), m.state)
+	m.state = state
+}
+
+// pop returns to the screen below the current one, reporting false if there is none.
+func (m *model) pop() bool {
+	if len(m.nav) == 0 {
+		return false
+	}
+	m.state = m.nav[len(m.nav)-1]
+	m.nav = m.nav[:len(m.nav)-1]
+	return true
+}
+
+// returnTo pops screens until state is shown, and prepares it afresh.
+func (m *model) returnTo(state appState) {
+	for m.state != state && m.pop() {
+	}
+	m.enter()
+}
+
+// resetNavigation shows state as if the current language pair had been picked in the
+// menus, so that going back leads through them.
+func (m *model) resetNavigation(state appState) {
+	m.nav = []appState{stateSelectUserLang, stateSelectTargetLang}
+	m.state = state
+}
+
+// showResults shows the results screen above the sentence input, whichever screen the
+// translation was started from, such as the error screen when retrying.
+func (m *model) showResults() {
+	m.state = stateInputSentence
+	m.navigate(stateShowResults)
+}
+
+// back leaves the current screen, discarding its input or results, and returns to the
+// screen below it. It quits from the first screen.
+func (m model) back() (tea.Model, tea.Cmd) {
+	m.leave()
+	if !m.pop() {
+		return m, tea.Quit
+	}
+	m.enter()
+	return m, nil
+}
+
+// leave discards what the current screen showed when going back from it.
+func (m *model) leave() {
+	if m.studying() {
+		m.study.current = len(studyActivities) // Cut short: on to the summary
+	}
+	switch m.state {
+	case stateInputSentence, stateDocument, stateWatch:
+		m.input = ""
+		m.status = ""
+	case stateShowResults:
+		m.translation = ""
+		m.wordAnalysis = nil
+		m.sentenceLevel = ""
+		m.sentenceTone = nil
+		m.paragraph = nil
+		m.showOverview = false
+		m.source = ""
+		m.followUps = nil
+		m.clearResultTabs()
+	}
+}
+
+// enter prepares a language menu when it is shown, with the first language highlighted
+// and no filter.
+func (m *model) enter() {
+	switch m.state {
+	case stateSelectUserLang:
+		m.langs = m.knownLangs
+	case stateSelectTargetLang:
+		m.langs = m.targetMenu()
+	default:
+		return
+	}
+	m.filteredLangs = m.langs
+	m.selectedLang = 0
+	m.langFilter = ""
+}
+
+// breadcrumb names the screens on the navigation stack down to the current one,
+// e.g. "Language › Learn › Sentence".
+func (m model) breadcrumb() string {
+	names := make([]string, 0, len(m.nav)+1)
+	for _, state := range m.nav {
+		names = append(names, state.title())
+	}
+	return strings.Join(append(names, m.state.title()), " › ")
+}
+
+// title is the short name of a screen shown in the breadcrumb.
+func (s appState) title() string {
+	switch s {
+	case stateSelectUserLang:
+		return "Language"
+	case stateSelectTargetLang:
+		return "Learn"
+	case stateInputSentence:
+		return "Sentence"
+	case stateShowResults:
+		return "Results"
+	case stateSelectModel:
+		return "Model"
+	case stateSetupAPIKey:
+		return "Setup"
+	case stateDebug:
+		return "Debug"
+	case stateError:
+		return "Error"
+	case stateDocument:
+		return "Document"
+	case stateConversation:
+		return "Conversation"
+	case stateCompare:
+		return "Compare"
+	case stateSimplify:
+		return "Simplify"
+	case stateWatch:
+		return "Watch"
+	case stateGeneration:
+		return "Generation"
+	case statePairs:
+		return "Pairs"
+	case stateSettings:
+		return "Settings"
+	case statePending:
+		return "Queue"
+	case stateDictionary:
+		return "Dictionary"
+	case stateReview:
+		return "Review"
+	case statePractice:
+		return "Practice"
+	case stateListening:
+		return "Listening"
+	case stateTyping:
+		return "Typing"
+	case stateShadowing:
+		return "Shadowing"
+	case stateNumbers:
+		return "Numbers"
+	case stateConjugation:
+		return "Conjugation"
+	case stateCases:
+		return "Cases"
+	case stateGenders:
+		return "Genders"
+	case stateCloze:
+		return "Cloze"
+	case stateStudy:
+		return "Study"
+	}
+	return s.String()
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/notify"
+)
+
+// notifyDone creates a tea.Cmd that notifies on the desktop that work of the mode
+// started at started has finished, if the config asks for it. Failing to notify is
+// only logged, since the result is on the screen anyway.
+func notifyDone(cfg config.Config, mode string, started time.Time, title, body string) tea.Cmd {
+	if started.IsZero() {
+		return nil
+	}
+	elapsed := time.Since(started)
+	return func() tea.Msg {
+		if err := notify.Done(context.Background(), cfg.Notify, mode, elapsed, title, body, os.Stderr); err != nil {
+			slog.Warn("desktop notification failed", "mode", mode, "err", err)
+		}
+		return nil
+	}
+}
+
+// notifyFailed is notifyDone for work that failed with err.
+func notifyFailed(cfg config.Config, mode string, started time.Time, err error) tea.Cmd {
+	return notifyDone(cfg, mode, started, "Translation failed", err.Error())
+}
@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/drill"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// numberGradeResult represents the model's verdict on a figure written out in words.
+type numberGradeResult struct {
+	grade *translator.NumberGrade
+	err   error
+}
+
+// gradeNumber creates a tea.Cmd that asks the model whether answer is the figure
+// written out in the target language.
+func gradeNumber(cfg config.Config, userLang, targetLang string, figure drill.Figure, answer string) tea.Cmd {
+	return func() tea.Msg {
+		req := translator.Request{UserLang: userLang, TargetLang: targetLang}
+		grade, err := translate.GradeNumber(context.Background(), cfg, figure.Digits, figure.Kind, answer, req)
+		return numberGradeResult{grade: grade, err: err}
+	}
+}
+
+// openNumbers starts the drill of writing out numbers, dates, prices and times shown
+// in digits.
+func (m model) openNumbers() (model, tea.Cmd) {
+	return m.startNumbers(false)
+}
+
+// openNumbersByEar starts the drill of writing out numbers, dates, prices and times
+// that are read aloud rather than shown.
+func (m model) openNumbersByEar() (model, tea.Cmd) {
+	return m.startNumbers(true)
+}
+
+// startNumbers starts the numbers drill, by ear or not, with its first figure.
+func (m model) startNumbers(byEar bool) (model, tea.Cmd) {
+	m.navigate(stateNumbers)
+	m.figureByEar = byEar
+	m.figureRight, m.figureTotal = 0, 0
+	m.status = ""
+	return m.nextFigure()
+}
+
+// nextFigure makes up the next figure of the drill, of the kinds in turn, and reads it
+// aloud if the drill is by ear.
+func (m model) nextFigure() (model, tea.Cmd) {
+	m.figure = drill.RandomFigure(drill.FigureKinds[m.figureTotal%len(drill.FigureKinds)])
+	m.figureGrade = nil
+	m.practiceInput = ""
+	m.err = nil
+	return m.playFigure()
+}
+
+// playFigure reads the current figure aloud if the drill is by ear.
+func (m model) playFigure() (model, tea.Cmd) {
+	if !m.figureByEar {
+		return m, nil
+	}
+	m.status = normalStyle.Render("Playing...")
+	return m, playSentence(m.cfg, m.targetLang, m.figure.Digits)
+}
+
+// updateNumbers handles key presses in the numbers drill. The figure is written out
+// and checked by the model with Select, which then goes on to the next.
+func (m model) updateNumbers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if text, ok := typedText(msg); ok && m.figureGrade == nil {
+		m.practiceInput += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.loading = false
+		return m.back()
+	case key.Matches(msg, m.keys.Replay) && m.figureByEar:
+		return m.playFigure()
+	case m.loading:
+	case key.Matches(msg, m.keys.Select) && m.figureGrade != nil:
+		return m.nextFigure()
+	case key.Matches(msg, m.keys.Select) && strings.TrimSpace(m.practiceInput) != "":
+		m.loading = true
+		m.loadingStep = "Grading"
+		m.deadline = time.Time{}
+		m.err = nil
+		return m, tea.Batch(gradeNumber(m.cfg, m.userLang, m.targetLang, m.figure, strings.TrimSpace(m.practiceInput)), m.spinner.Tick)
+	case msg.Type == tea.KeyBackspace && m.figureGrade == nil:
+		if len(m.practiceInput) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.practiceInput)
+			m.practiceInput = m.practiceInput[:len(m.practiceInput)-size]
+		}
+	}
+	return m, nil
+}
+
+// handleNumberGrade shows the model's verdict on the figure written out and counts it.
+func (m model) handleNumberGrade(msg numberGradeResult) (tea.Model, tea.Cmd) {
+	if !m.loading {
+		return m, nil // Left the drill while grading
+	}
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	m.figureGrade = msg.grade
+	m.figureTotal++
+	if msg.grade.Correct {
+		m.figureRight++
+	}
+	return m, nil
+}
+
+// viewNumbers renders the numbers drill: the figure in digits, unless it is read aloud,
+// the answer being typed, and once graded, the verdict with the figure written out.
+func (m model) viewNumbers() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Numbers And Dates: " + m.getLangName(m.targetLang)))
+	s.WriteString("\n\n")
+
+	digits := m.figure.Digits
+	if m.figureByEar && m.figureGrade == nil {
+		digits = "(listen)"
+	}
+	s.WriteString(labelStyle.Render(fmt.Sprintf("%-8s", capitalize(m.figure.Kind)+":")))
+	s.WriteString(valueStyle.Render(digits))
+	s.WriteString("\n")
+	s.WriteString(labelStyle.Render("Words:  "))
+	s.WriteString(m.practiceInput)
+	if m.figureGrade == nil {
+		s.WriteString("█")
+	}
+	s.WriteString("\n\n")
+
+	switch {
+	case m.loading:
+		s.WriteString(labelStyle.Render(m.loadingView()))
+		s.WriteString("\n\n")
+	case m.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	case m.figureGrade != nil:
+		grade := m.figureGrade
+		if grade.Correct {
+			s.WriteString(successStyle.Render("Correct"))
+		} else {
+			s.WriteString(errorStyle.Render("Not quite"))
+		}
+		s.WriteString("\n")
+		s.WriteString(labelStyle.Render("Written out: "))
+		s.WriteString(valueStyle.Render(grade.Expected))
+		s.WriteString("\n")
+		if !grade.Correct && grade.Explanation != "" {
+			s.WriteString(normalStyle.Render(grade.Explanation))
+			s.WriteString("\n")
+		}
+		s.WriteString(normalStyle.Render(fmt.Sprintf("\nSession: %d of %d right (%d%%)", m.figureRight, m.figureTotal, percent(m.figureRight, m.figureTotal))))
+		s.WriteString("\n\n")
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	entries := []helpEntry{{m.keys.Select, "Check"}}
+	if m.figureGrade != nil {
+		entries[0].desc = "Next"
+	}
+	if m.figureByEar {
+		entries = append(entries, helpEntry{m.keys.Replay, "Play again"})
+	}
+	entries = append(entries, helpEntry{m.keys.Back, "Back"})
+	line := m.helpLine(entries...)
+	if m.figureGrade == nil {
+		line += " | Write it out in words"
+	}
+	s.WriteString(normalStyle.Render(line))
+	return s.String()
+}
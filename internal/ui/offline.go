@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+)
+
+// probeInterval is how often the provider is checked while it is offline.
+const probeInterval = 30 * time.Second
+
+// probeTickMsg is sent when it is time to check an offline provider again.
+type probeTickMsg struct{}
+
+// probeResult represents the outcome of checking whether the provider is back.
+type probeResult struct {
+	err error
+}
+
+// probeTick creates a tea.Cmd that waits for the next check of the provider.
+func probeTick() tea.Cmd {
+	return tea.Tick(probeInterval, func(time.Time) tea.Msg { return probeTickMsg{} })
+}
+
+// probeProvider creates a tea.Cmd that checks whether the provider is reachable again.
+func probeProvider(cfg config.Config) tea.Cmd {
+	return func() tea.Msg {
+		return probeResult{err: translate.Probe(context.Background(), cfg)}
+	}
+}
+
+// watchConnectivity starts checking the provider in the background once it went
+// offline, unless that already happens.
+func (m model) watchConnectivity(cmd tea.Cmd) (model, tea.Cmd) {
+	if !translate.Offline() || m.probing {
+		return m, cmd
+	}
+	m.probing = true
+	return m, tea.Batch(cmd, probeTick())
+}
+
+// handleProbeTick checks the provider, unless a request brought it back meanwhile.
+func (m model) handleProbeTick() (tea.Model, tea.Cmd) {
+	if !translate.Offline() {
+		m.probing = false
+		return m, nil
+	}
+	return m, probeProvider(m.cfg)
+}
+
+// handleProbeResult reports that the provider is back, or waits for the next check.
+func (m model) handleProbeResult(msg probeResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m, probeTick()
+	}
+	m.probing = false
+	m.status = successStyle.Render("Back online")
+	if countPending(m.queue) > 0 {
+		return m, processQueue(m.cfg, m.bindingKeys(m.keys.Queue))
+	}
+	return m, nil
+}
+
+// viewOfflineBanner renders the notice shown on every screen while the provider is
+// offline, or "" while it is not.
+func (m model) viewOfflineBanner() string {
+	if !translate.Offline() {
+		return ""
+	}
+	return errorStyle.Render(fmt.Sprintf("Offline: the provider failed repeatedly. History, vocabulary and cached results still work; checking again every %v.", probeInterval))
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// recentPairsResult represents the outcome of loading the recently used language pairs.
+type recentPairsResult struct {
+	pairs []storage.LanguagePair
+	err   error
+}
+
+// loadRecentPairs creates a tea.Cmd that reads the recently used language pairs.
+func loadRecentPairs() tea.Cmd {
+	return func() tea.Msg {
+		pairs, err := storage.LoadRecentPairs()
+		return recentPairsResult{pairs: pairs, err: err}
+	}
+}
+
+// rememberPair creates a tea.Cmd that moves a language pair to the front of the
+// recently used ones. Only failures are reported.
+func rememberPair(userLang, targetLang string) tea.Cmd {
+	return func() tea.Msg {
+		if err := storage.AddRecentPair(storage.LanguagePair{UserLang: userLang, TargetLang: targetLang}); err != nil {
+			return storageResult{err: err}
+		}
+		return nil
+	}
+}
+
+// canSwitchPair reports whether the pair switcher can be opened from the current
+// screen. Documents and watched files keep the pair they were opened with.
+func (m model) canSwitchPair() bool {
+	if m.doc != nil || m.watch != nil {
+		return false
+	}
+	switch m.state {
+	case stateSelectUserLang, stateSelectTargetLang, stateInputSentence, stateShowResults:
+		return true
+	}
+	return false
+}
+
+// openPairs shows the recently used language pairs.
+func (m model) openPairs() (model, tea.Cmd) {
+	m.navigate(statePairs)
+	m.recentPairs = nil
+	m.selectedPair = 0
+	m.err = nil
+	return m, loadRecentPairs()
+}
+
+// handleRecentPairs shows the loaded language pairs, skipping the current one so that
+// Enter flips to the previous pair.
+func (m model) handleRecentPairs(msg recentPairsResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	m.recentPairs = msg.pairs
+	if len(m.recentPairs) > 1 && m.recentPairs[0] == (storage.LanguagePair{UserLang: m.userLang, TargetLang: m.targetLang}) {
+		m.selectedPair = 1
+	}
+	return m, nil
+}
+
+// updatePairs handles key presses in the pair switcher.
+func (m model) updatePairs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Pairs):
+		m.pop()
+		m.err = nil
+	case key.Matches(msg, m.keys.Up):
+		if m.selectedPair > 0 {
+			m.selectedPair--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selectedPair < len(m.recentPairs)-1 {
+			m.selectedPair++
+		}
+	case key.Matches(msg, m.keys.Select):
+		if len(m.recentPairs) == 0 {
+			return m, nil
+		}
+		return m.switchPair(m.recentPairs[m.selectedPair])
+	}
+	return m, nil
+}
+
+// switchPair makes pair the current language pair and goes to the sentence input.
+func (m model) switchPair(pair storage.LanguagePair) (tea.Model, tea.Cmd) {
+	m.userLang = pair.UserLang
+	m.targetLang = pair.TargetLang
+	m.detect = false
+	m.fanout = nil
+	m.resetNavigation(stateInputSentence)
+	m.paragraph = nil
+	m.translation = ""
+	m.wordAnalysis = nil
+	m.sentenceLevel = ""
+	m.sentenceTone = nil
+	m.showOverview = false
+	m.followUps = nil
+	m.clearResultTabs()
+	m.status = ""
+	return m, rememberPair(pair.UserLang, pair.TargetLang)
+}
+
+// viewPairs renders the recently used language pairs.
+func (m model) viewPairs() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Recent Language Pairs:"))
+	s.WriteString("\n\n")
+	switch {
+	case m.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	case len(m.recentPairs) == 0:
+		s.WriteString(normalStyle.Render("No language pairs used yet."))
+		s.WriteString("\n\n")
+	}
+	for i, pair := range m.recentPairs {
+		line := fmt.Sprintf("%s ↔ %s", m.getLangName(pair.UserLang), m.getLangName(pair.TargetLang))
+		if i == m.selectedPair {
+			s.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			s.WriteString(normalStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, "Switch"}, helpEntry{m.keys.Back, "Back"})))
+	return s.String()
+}
@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/document"
+	"github.com/brittaao/translation-tui/internal/notify"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// paragraphItem holds the outcome of translating one sentence of a paragraph.
+type paragraphItem struct {
+	sentence   string
+	targetLang string // Set when the sentence is translated into several languages
+	result     translate.Result
+	err        error
+	done       bool
+	queued     bool // Entered while another sentence was being translated
+}
+
+// startParagraph starts translating each sentence of a multi-sentence input.
+func (m model) startParagraph(sentences []string) (model, tea.Cmd) {
+	m.paragraph = make([]paragraphItem, len(sentences))
+	for i, sentence := range sentences {
+		m.paragraph[i].sentence = sentence
+	}
+	m.sentenceIndex = 0
+	m.showOverview = false
+	m.loadingStep = fmt.Sprintf("Translating %d sentences", len(sentences))
+	return m, tea.Batch(translateParagraph(m.cfg, m.userLang, m.targetLang, sentences), loadingTick(), m.spinner.Tick)
+}
+
+// startArticle fetches the article at the URL in the input.
+func (m model) startArticle() (model, tea.Cmd) {
+	m.paragraph = nil
+	m.source = ""
+	m.loadingStep = "Fetching article"
+	return m, tea.Batch(fetchArticle(m.cfg, m.input), loadingTick(), m.spinner.Tick)
+}
+
+// Most sentences of a fetched article that are translated
+const maxArticleSentences = 50
+
+// articleResult represents the outcome of fetching an article.
+type articleResult struct {
+	doc document.Document
+	err error
+}
+
+// fetchArticle creates a tea.Cmd that downloads and extracts the article at url.
+func fetchArticle(cfg config.Config, url string) tea.Cmd {
+	return func() tea.Msg {
+		httpClient, err := translate.NewHTTPClient(cfg.Network)
+		if err != nil {
+			return articleResult{err: err}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+		defer cancel()
+		doc, err := document.FetchArticle(ctx, httpClient, url)
+		return articleResult{doc: doc, err: err}
+	}
+}
+
+// handleArticleResult translates the sentences of a fetched article in paragraph mode.
+func (m model) handleArticleResult(msg articleResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.loading = false
+		m.failure = msg.err
+		m.state = stateError
+		return m, nil
+	}
+	sentences := msg.doc.Sentences
+	m.source = msg.doc.Title
+	if len(sentences) > maxArticleSentences {
+		m.source += fmt.Sprintf(" (first %d of %d sentences)", maxArticleSentences, len(sentences))
+		sentences = sentences[:maxArticleSentences]
+	}
+	return m.startParagraph(sentences)
+}
+
+// handleSentenceResult stores the result of one sentence and waits for the next.
+func (m model) handleSentenceResult(msg sentenceResult) (tea.Model, tea.Cmd) {
+	item := &m.paragraph[msg.index]
+	item.result = msg.Result
+	item.err = msg.err
+	item.done = true
+	finished := 0
+	for _, item := range m.paragraph {
+		if item.done {
+			finished++
+		}
+	}
+	unit := "sentences"
+	if m.isFanout() {
+		unit = "languages"
+	}
+	m.loadingStep = fmt.Sprintf("Translated %d of %d %s", finished, len(m.paragraph), unit)
+	return m, waitForPipeline(msg.updates)
+}
+
+// handleParagraphDone shows the first sentence once all are translated, or the error
+// screen if none succeeded.
+func (m model) handleParagraphDone() (tea.Model, tea.Cmd) {
+	m.loading = false
+	var cmds []tea.Cmd
+	var firstErr error
+	for _, item := range m.paragraph {
+		if item.err != nil {
+			if firstErr == nil {
+				firstErr = item.err
+			}
+			continue
+		}
+		targetLang := m.targetLang
+		if item.targetLang != "" {
+			targetLang = item.targetLang
+		}
+		entry := storage.HistoryEntry{
+			Time:        time.Now(),
+			UserLang:    m.userLang,
+			TargetLang:  targetLang,
+			Original:    item.result.Original,
+			Translation: item.result.Translation,
+			Words:       item.result.Words,
+		}
+		m.session = append(m.session, entry)
+		cmds = append(cmds, recordHistory(m.cfg, entry))
+	}
+	if len(cmds) == 0 {
+		m.failure = firstErr
+		m.state = stateError
+		return m, notifyFailed(m.cfg, notify.ModeParagraph, m.started, firstErr)
+	}
+	cmds = append(cmds, notifyDone(m.cfg, notify.ModeParagraph, m.started, "Translation ready",
+		fmt.Sprintf("%d of %d translated", len(cmds), len(m.paragraph))))
+	m.showResults()
+	m.input = ""
+	m.err = nil
+	m.status = ""
+	m.showSentence(0)
+	return m, tea.Batch(cmds...)
+}
+
+// showSentence makes sentence i of the paragraph the current result.
+func (m *model) showSentence(i int) {
+	if i < 0 || i >= len(m.paragraph) {
+		return
+	}
+	item := m.paragraph[i]
+	m.sentenceIndex = i
+	m.followUps = nil
+	m.clearResultTabs()
+	m.originalSentence = item.sentence
+	m.translation = ""
+	m.wordAnalysis = nil
+	m.sentenceLevel = ""
+	m.sentenceTone = nil
+	m.degraded = ""
+	m.cached = false
+	m.timings = translator.Timings{}
+	if item.err == nil {
+		m.originalSentence = item.result.Original
+		m.translation = item.result.Translation
+		m.foreign = item.result.Foreign
+		m.wordAnalysis = item.result.Words
+		m.sentenceLevel = item.result.Level
+		m.sentenceTone = item.result.Tone
+		m.usedModels = item.result.Models
+		m.timings = item.result.Timings
+		m.degraded = item.result.Degraded
+		m.cached = item.result.Cached
+	}
+	if item.targetLang != "" {
+		m.targetLang = item.targetLang
+	}
+	m.selectFirstWord()
+}
+
+// currentSentence returns the paragraph sentence shown in the results, if the input was a paragraph.
+func (m model) currentSentence() (paragraphItem, bool) {
+	if m.sentenceIndex >= len(m.paragraph) {
+		return paragraphItem{}, false
+	}
+	return m.paragraph[m.sentenceIndex], true
+}
+
+// updateOverview handles key presses while the sentence overview is shown.
+func (m model) updateOverview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Overview):
+		m.showOverview = false
+	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.PrevSentence):
+		if m.sentenceIndex > 0 {
+			m.sentenceIndex--
+		}
+	case key.Matches(msg, m.keys.Down), key.Matches(msg, m.keys.NextSentence):
+		if m.sentenceIndex < len(m.paragraph)-1 {
+			m.sentenceIndex++
+		}
+	case key.Matches(msg, m.keys.Select):
+		m.showSentence(m.sentenceIndex)
+		m.showOverview = false
+	}
+	return m, nil
+}
+
+// viewOverview renders the list of all sentences of the paragraph, or of those translated
+// in the background, with their translations.
+func (m model) viewOverview() string {
+	var s strings.Builder
+	if m.isFanout() {
+		s.WriteString(titleStyle.Render("All Translations"))
+		s.WriteString("\n\n")
+		s.WriteString(valueStyle.Render(m.paragraph[0].sentence))
+		s.WriteString("\n\n")
+	} else if m.isBackgroundList() {
+		s.WriteString(titleStyle.Render("Translated in the Background"))
+		s.WriteString("\n\n")
+	} else {
+		s.WriteString(titleStyle.Render("Paragraph Overview"))
+		s.WriteString("\n\n")
+	}
+	for i, item := range m.paragraph {
+		translation := successStyle.Render(item.result.Translation)
+		if item.err != nil {
+			translation = errorStyle.Render(fmt.Sprintf("Error: %v", item.err))
+		} else if !item.done {
+			translation = normalStyle.Render("Translating...")
+		}
+		line := fmt.Sprintf("%d. %s", i+1, item.sentence)
+		if item.targetLang != "" {
+			line = fmt.Sprintf("%d. %s", i+1, m.getLangName(item.targetLang))
+		}
+		if i == m.sentenceIndex {
+			s.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			s.WriteString(normalStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+		s.WriteString("     ")
+		s.WriteString(translation)
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, "Show sentence"}, helpEntry{m.keys.Back, "Back"})))
+	return s.String()
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// analyzeOnDemandSetting is the settings row that skips the automatic word analysis.
+var analyzeOnDemandSetting = setting{
+	name: "Word analysis", key: "analyze_on_demand",
+	value: func(m model) string {
+		if m.cfg.AnalyzeOnDemand {
+			return "on demand"
+		}
+		return "automatic"
+	},
+	change: func(m *model, dir int) any {
+		m.cfg.AnalyzeOnDemand = !m.cfg.AnalyzeOnDemand
+		return m.cfg.AnalyzeOnDemand
+	},
+}
+
+// pickedWordsResult represents the outcome of analyzing the words picked from a sentence.
+type pickedWordsResult struct {
+	sentence string
+	words    []translator.WordInfo
+	err      error
+}
+
+// analyzePickedWords creates a tea.Cmd that analyzes the chosen words of a
+// foreign-language sentence.
+func analyzePickedWords(cfg config.Config, userLang, targetLang, sentence string, words []string) tea.Cmd {
+	return func() tea.Msg {
+		req := translator.Request{Sentence: sentence, UserLang: userLang, TargetLang: targetLang, Words: words}
+		analysis, err := translate.AnalyzeWords(context.Background(), cfg, sentence, req)
+		return pickedWordsResult{sentence: sentence, words: analysis, err: err}
+	}
+}
+
+// sentenceTokens returns the words of a sentence that can be picked, without the
+// punctuation around them.
+func sentenceTokens(sentence string) []string {
+	var tokens []string
+	for _, field := range strings.Fields(sentence) {
+		if token := strings.TrimFunc(field, unicode.IsPunct); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// startPickingWords shows the words of the foreign-language sentence on the Words tab
+// for choosing which to analyze.
+func (m model) startPickingWords() model {
+	tokens := sentenceTokens(m.foreignSentence())
+	if len(tokens) == 0 || m.analyzingWords {
+		return m
+	}
+	m.switchTab(int(tabWords))
+	m.pickingWords = true
+	m.pickCursor = 0
+	m.picked = make([]bool, len(tokens))
+	return m
+}
+
+// updatePickWords handles key presses while words are picked for analysis. Mark picks
+// the word under the cursor, Select analyzes the picked words, or that word if none are.
+func (m model) updatePickWords(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	tokens := sentenceTokens(m.foreignSentence())
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.pickingWords = false
+	case key.Matches(msg, m.keys.PrevSentence):
+		m.pickCursor = max(m.pickCursor-1, 0)
+	case key.Matches(msg, m.keys.NextSentence):
+		m.pickCursor = min(m.pickCursor+1, len(tokens)-1)
+	case key.Matches(msg, m.keys.Mark):
+		m.picked[m.pickCursor] = !m.picked[m.pickCursor]
+	case key.Matches(msg, m.keys.Select):
+		var words []string
+		for i, token := range tokens {
+			if m.picked[i] {
+				words = append(words, token)
+			}
+		}
+		if len(words) == 0 {
+			words = []string{tokens[m.pickCursor]}
+		}
+		m.pickingWords = false
+		m.analyzingWords = true
+		m.status = ""
+		return m, analyzePickedWords(m.cfg, m.userLang, m.targetLang, m.foreignSentence(), words)
+	}
+	return m, nil
+}
+
+// handlePickedWordsResult adds the analyzed words to the analysis, replacing earlier
+// analyses of the same words, unless another sentence is shown by now.
+func (m model) handlePickedWordsResult(msg pickedWordsResult) (tea.Model, tea.Cmd) {
+	if !m.analyzingWords || msg.sentence != m.foreignSentence() {
+		return m, nil
+	}
+	m.analyzingWords = false
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		return m, nil
+	}
+	for _, word := range msg.words {
+		i := slices.IndexFunc(m.wordAnalysis, func(w translator.WordInfo) bool {
+			return strings.EqualFold(w.WordInTargetLang, word.WordInTargetLang)
+		})
+		if i >= 0 {
+			m.wordAnalysis[i] = word
+		} else {
+			m.wordAnalysis = append(m.wordAnalysis, word)
+		}
+	}
+	if item, ok := m.currentSentence(); ok && item.err == nil {
+		m.paragraph[m.sentenceIndex].result.Words = m.wordAnalysis
+	}
+	m.selectFirstWord()
+	m.status = successStyle.Render(fmt.Sprintf("Analyzed %d words", len(msg.words)))
+	return m, m.addToDictionary(msg.sentence, msg.words)
+}
+
+// writeWordPicker renders the words of the sentence with the picked ones marked and
+// the cursor on one of them.
+func (m model) writeWordPicker(s *strings.Builder) {
+	s.WriteString(labelStyle.Render("Pick words to analyze:"))
+	s.WriteString("\n\n")
+	for i, token := range sentenceTokens(m.foreignSentence()) {
+		if i > 0 {
+			s.WriteString(" ")
+		}
+		if m.picked[i] {
+			token = "[" + token + "]"
+		}
+		if i == m.pickCursor {
+			s.WriteString(selectedStyle.Render(token))
+		} else {
+			s.WriteString(normalStyle.Render(token))
+		}
+	}
+	s.WriteString("\n\n")
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// translationResult represents the result of a translation operation.
+type translationResult struct {
+	translate.Result
+	err error
+}
+
+// pipelineProgress reports that a pipeline step has started and when it will time out.
+// It carries the update channel so the UI can keep listening for the next message.
+type pipelineProgress struct {
+	step     string
+	deadline time.Time
+	updates  <-chan tea.Msg
+}
+
+// translateSentence creates a tea.Cmd that performs translation and word analysis.
+// Progress is reported as pipelineProgress messages, followed by a final translationResult.
+func translateSentence(cfg config.Config, userLang, targetLang, sentence string) tea.Cmd {
+	updates := make(chan tea.Msg, 8)
+	go func() {
+		defer close(updates)
+		req := translator.Request{Sentence: sentence, UserLang: userLang, TargetLang: targetLang}
+		progress := func(step string, deadline time.Time) {
+			updates <- pipelineProgress{step: step, deadline: deadline, updates: updates}
+		}
+		result, err := translate.Run(context.Background(), cfg, req, progress)
+		updates <- translationResult{Result: result, err: err}
+	}()
+	return waitForPipeline(updates)
+}
+
+// waitForPipeline creates a tea.Cmd that waits for the next pipeline message.
+func waitForPipeline(updates <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// sentenceResult represents the result of one sentence of a paragraph.
+// It carries the update channel so the UI can keep listening for the next message.
+type sentenceResult struct {
+	index int
+	translate.Result
+	err     error
+	updates <-chan tea.Msg
+}
+
+// paragraphDone reports that all sentences of a paragraph have been translated.
+type paragraphDone struct{}
+
+// translateParagraph creates a tea.Cmd that translates the sentences of a paragraph concurrently.
+// Each finished sentence is reported as a sentenceResult, followed by a final paragraphDone.
+func translateParagraph(cfg config.Config, userLang, targetLang string, sentences []string) tea.Cmd {
+	updates := make(chan tea.Msg, len(sentences)+1)
+	go func() {
+		defer close(updates)
+		reqs := make([]translator.Request, len(sentences))
+		for i, sentence := range sentences {
+			reqs[i] = translator.Request{Sentence: sentence, UserLang: userLang, TargetLang: targetLang}
+		}
+		translate.RunBatch(context.Background(), cfg, reqs, func(i int, result translate.Result, err error) {
+			updates <- sentenceResult{index: i, Result: result, err: err, updates: updates}
+		})
+		updates <- paragraphDone{}
+	}()
+	return waitForPipeline(updates)
+}
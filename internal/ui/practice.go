@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// practiceMode is a mode of the practice menu.
+type practiceMode struct {
+	name string
+	desc string
+	open func(model) (model, tea.Cmd)
+}
+
+// practiceModes lists the modes of the practice menu.
+var practiceModes = []practiceMode{
+	{"Review due cards", "Recall saved words, spaced out by how well you know them", model.openReview},
+	{"Listening quiz", "Type what you hear of saved sentences, scored word by word", model.openListening},
+	{"Typing drill", "Copy saved sentences, accents and all, for accuracy and speed", model.openTyping},
+	{"Shadowing", "Speak along with recent sentences on a loop, at the speed you choose", model.openShadowing},
+	{"Numbers and dates", "Write out numbers, dates, prices and times shown in digits", model.openNumbers},
+	{"Numbers by ear", "Write out numbers, dates, prices and times read aloud", model.openNumbersByEar},
+	{"Conjugation", "Type the form of a saved verb for a person and tense", model.openConjugation},
+	{"Cases", "Pick the case prepositions and verbs of recent sentences take", model.openCases},
+	{"Genders", "Pick the gender and article of saved nouns", model.openGenders},
+	{"Cloze", "Fill saved words back into the sentences they came from", model.openCloze},
+	{"Study session", "A few minutes each of review, cloze, conjugation and listening, with a summary", model.openStudy},
+}
+
+// practiceSentence is a saved sentence in the language learned, with its translation.
+type practiceSentence struct {
+	text        string
+	translation string
+}
+
+// practiceSentencesResult carries the saved sentences of a language, for practicing.
+type practiceSentencesResult struct {
+	sentences []practiceSentence
+	err       error
+}
+
+// loadPracticeSentences creates a tea.Cmd that reads the sentences of the vocab cards
+// of lang, each once, in random order.
+func loadPracticeSentences(lang string) tea.Cmd {
+	return func() tea.Msg {
+		cards, err := storage.LoadVocab()
+		if err != nil {
+			return practiceSentencesResult{err: err}
+		}
+		seen := map[string]bool{}
+		var sentences []practiceSentence
+		for _, c := range cards {
+			text := strings.TrimSpace(c.ForeignSentence())
+			if c.Lang != lang || text == "" || seen[text] {
+				continue
+			}
+			seen[text] = true
+			translation := c.Translation
+			if text == strings.TrimSpace(c.Translation) {
+				translation = c.Sentence
+			}
+			sentences = append(sentences, practiceSentence{text: text, translation: strings.TrimSpace(translation)})
+		}
+		rand.Shuffle(len(sentences), func(i, j int) { sentences[i], sentences[j] = sentences[j], sentences[i] })
+		return practiceSentencesResult{sentences: sentences}
+	}
+}
+
+// handlePracticeSentences starts the practice mode that asked for the sentences with
+// the first of them.
+func (m model) handlePracticeSentences(msg practiceSentencesResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		return m, nil
+	}
+	m.practice = msg.sentences
+	if m.practice == nil {
+		m.practice = []practiceSentence{} // Loaded, but none saved
+	}
+	if next, cmd, ok := m.studyTurn(len(m.practice) == 0); ok {
+		return next, cmd
+	}
+	if m.state == stateListening {
+		return m.playListening()
+	}
+	return m, nil
+}
+
+// openPractice shows the practice menu.
+func (m model) openPractice() (model, tea.Cmd) {
+	m.navigate(statePractice)
+	m.status = ""
+	return m, nil
+}
+
+// updatePractice handles key presses in the practice menu.
+func (m model) updatePractice(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Practice):
+		m.pop()
+	case key.Matches(msg, m.keys.Up):
+		m.practiceCursor = max(m.practiceCursor-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.practiceCursor = min(m.practiceCursor+1, len(practiceModes)-1)
+	case key.Matches(msg, m.keys.Select):
+		return practiceModes[m.practiceCursor].open(m)
+	}
+	return m, nil
+}
+
+// viewPractice renders the practice menu.
+func (m model) viewPractice() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Practice " + m.getLangName(m.targetLang) + ":"))
+	s.WriteString("\n\n")
+	for i, mode := range practiceModes {
+		if i == m.practiceCursor {
+			s.WriteString(selectedStyle.Render("> " + mode.name))
+		} else {
+			s.WriteString(normalStyle.Render("  " + mode.name))
+		}
+		s.WriteString(normalStyle.Render("  " + mode.desc))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, "Start"}, helpEntry{m.keys.Back, "Back"})))
+	return s.String()
+}
+
+// capitalize returns s with its first letter in upper case, as for a label.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
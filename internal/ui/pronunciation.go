@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/pronunciation"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Environment variable holding the Forvo API key if the config file has none
+const envForvoAPIKey = "FORVO_API_KEY"
+
+// pronunciationResult represents a pronunciation that was fetched and played.
+type pronunciationResult struct {
+	word   string
+	source string
+	err    error
+}
+
+// playPronunciation creates a tea.Cmd that fetches the pronunciation of word in lang and
+// plays it.
+func playPronunciation(cfg config.Config, lang, word string) tea.Cmd {
+	return func() tea.Msg {
+		result := pronunciationResult{word: word}
+		client, err := translate.NewHTTPClient(cfg.Network)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		opts := pronunciation.Options{
+			ForvoAPIKey: cfg.Pronunciation.ForvoAPIKey,
+			ForvoURL:    cfg.Pronunciation.ForvoURL,
+			TTSCommand:  cfg.Pronunciation.TTSCommand,
+		}
+		if opts.ForvoAPIKey == "" {
+			opts.ForvoAPIKey = os.Getenv(envForvoAPIKey)
+		}
+		audio, err := translator.RunStep(context.Background(), cfg.Timeout, "Fetching pronunciation", nil, func(ctx context.Context) (pronunciation.Audio, error) {
+			return pronunciation.Fetch(ctx, client, opts, word, lang)
+		})
+		if err != nil {
+			result.err = err
+			return result
+		}
+		result.source = audio.Source
+		result.err = pronunciation.Play(context.Background(), cfg.Pronunciation.Player, audio.Path)
+		return result
+	}
+}
+
+// pronounceSelectedWord opens the drill-down of the selected word and plays its pronunciation.
+func (m model) pronounceSelectedWord() (tea.Model, tea.Cmd) {
+	word, ok := m.selectedWord()
+	if !ok {
+		return m, nil
+	}
+	m.wordExpanded = true
+	m.status = normalStyle.Render(fmt.Sprintf("Playing %s...", word.WordInTargetLang))
+	return m, playPronunciation(m.cfg, m.targetLang, word.WordInTargetLang)
+}
+
+// handlePronunciationResult reports the outcome of playing a pronunciation.
+func (m model) handlePronunciationResult(msg pronunciationResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Pronunciation: %v", msg.err))
+		return m, nil
+	}
+	m.status = successStyle.Render(fmt.Sprintf("Played %s (%s)", msg.word, msg.source))
+	return m, nil
+}
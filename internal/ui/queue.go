@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/notify"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+)
+
+// queueResult carries the offline queue after loading, adding to or processing it.
+type queueResult struct {
+	queue   []storage.QueuedSentence
+	status  string
+	err     error
+	process bool // Translate the pending sentences if the provider is online
+}
+
+// loadQueue creates a tea.Cmd that reads the offline queue. With process set, its
+// pending sentences are translated afterwards, as when the app starts.
+func loadQueue(process bool) tea.Cmd {
+	return func() tea.Msg {
+		queue, err := storage.LoadQueue()
+		return queueResult{queue: queue, err: err, process: process}
+	}
+}
+
+// enqueueSentence creates a tea.Cmd that adds a sentence to the offline queue.
+func enqueueSentence(item storage.QueuedSentence) tea.Cmd {
+	return func() tea.Msg {
+		if err := storage.AddToQueue(item); err != nil {
+			return queueResult{err: err}
+		}
+		queue, err := storage.LoadQueue()
+		status := warningStyle.Render(fmt.Sprintf("Offline: queued for translation when back online (%d waiting)", countPending(queue)))
+		return queueResult{queue: queue, status: status, err: err}
+	}
+}
+
+// processQueue creates a tea.Cmd that translates the pending sentences of the offline
+// queue and reports how it went.
+func processQueue(cfg config.Config, queueKeys string) tea.Cmd {
+	return func() tea.Msg {
+		started := time.Now()
+		done, failed, err := translate.ProcessQueue(context.Background(), cfg)
+		if done+failed > 0 {
+			// Run here rather than batched, since this already is in the background
+			notifyDone(cfg, notify.ModeQueue, started, "Queued sentences translated",
+				fmt.Sprintf("%d translated, %d failed", done, failed))()
+		}
+		queue, loadErr := storage.LoadQueue()
+		var status string
+		if done+failed > 0 {
+			status = successStyle.Render(fmt.Sprintf("Translated %d queued sentences (%d failed); %s shows them", done, failed, queueKeys))
+		}
+		return queueResult{queue: queue, status: status, err: errors.Join(err, loadErr)}
+	}
+}
+
+// countPending returns the number of queued sentences waiting to be translated.
+func countPending(queue []storage.QueuedSentence) int {
+	n := 0
+	for _, item := range queue {
+		if item.Pending() {
+			n++
+		}
+	}
+	return n
+}
+
+// queueSentence keeps the entered sentence for translating once the provider is back.
+// Private sessions do not keep it, since the queue is saved.
+func (m model) queueSentence() (tea.Model, tea.Cmd) {
+	if m.cfg.Private {
+		m.status = errorStyle.Render("Offline: private sessions do not queue sentences; try again when back online")
+		return m, nil
+	}
+	item := storage.QueuedSentence{Queued: time.Now(), UserLang: m.userLang, TargetLang: m.targetLang, Sentence: m.input}
+	m.input = ""
+	m.forgetEdits()
+	return m, enqueueSentence(item)
+}
+
+// handleQueueResult shows the offline queue as loaded, translating its pending
+// sentences if asked to and the provider is online.
+func (m model) handleQueueResult(msg queueResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+	} else if msg.status != "" {
+		m.status = msg.status
+	}
+	m.queue = msg.queue
+	m.selectedQueued = min(m.selectedQueued, max(len(m.queue)-1, 0))
+	if msg.process && countPending(m.queue) > 0 && !translate.Offline() {
+		return m, processQueue(m.cfg, m.bindingKeys(m.keys.Queue))
+	}
+	return m, nil
+}
+
+// openQueue shows the offline queue.
+func (m model) openQueue() (model, tea.Cmd) {
+	m.navigate(statePending)
+	m.selectedQueued = 0
+	return m, loadQueue(false)
+}
+
+// updateQueue handles key presses in the offline queue. Select takes a translated
+// sentence to its results, and any other back to the input; either leaves the queue.
+func (m model) updateQueue(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Queue):
+		m.pop()
+	case key.Matches(msg, m.keys.Up):
+		m.selectedQueued = max(m.selectedQueued-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.selectedQueued = min(m.selectedQueued+1, max(len(m.queue)-1, 0))
+	case key.Matches(msg, m.keys.Retry):
+		if translate.Offline() {
+			m.status = warningStyle.Render("Still offline")
+			return m, nil
+		}
+		m.status = normalStyle.Render("Translating the queued sentences...")
+		return m, processQueue(m.cfg, m.bindingKeys(m.keys.Queue))
+	case key.Matches(msg, m.keys.Select):
+		if len(m.queue) == 0 {
+			return m, nil
+		}
+		return m.takeQueued(m.queue[m.selectedQueued])
+	}
+	return m, nil
+}
+
+// takeQueued removes a sentence from the offline queue and shows its results, or puts
+// it back into the input if it has none.
+func (m model) takeQueued(item storage.QueuedSentence) (tea.Model, tea.Cmd) {
+	m.userLang = item.UserLang
+	m.targetLang = item.TargetLang
+	m.detect = false
+	m.fanout = nil
+	m.resetNavigation(stateInputSentence)
+	m.paragraph = nil
+	m.showOverview = false
+	m.source = ""
+	m.followUps = nil
+	m.clearResultTabs()
+	m.status = ""
+	if item.Result == nil {
+		m.input = item.Sentence
+		m.translation = ""
+		m.wordAnalysis = nil
+	} else {
+		m.input = ""
+		m.translation = item.Result.Translation
+		m.originalSentence = item.Result.Original
+		m.foreign = item.Result.Foreign
+		m.wordAnalysis = item.Result.Words
+		m.sentenceLevel = item.Result.Level
+		m.sentenceTone = item.Result.Tone
+		m.usedModels = item.Result.Models
+		m.timings = item.Result.Timings
+		m.degraded = ""
+		m.cached = false
+		m.selectFirstWord()
+		m.showResults()
+	}
+	remove := func() tea.Msg {
+		err := storage.UpdateQueue(func(queue []storage.QueuedSentence) []storage.QueuedSentence {
+			var kept []storage.QueuedSentence
+			for _, q := range queue {
+				if !q.Same(item) {
+					kept = append(kept, q)
+				}
+			}
+			return kept
+		})
+		if err != nil {
+			return queueResult{err: err}
+		}
+		queue, err := storage.LoadQueue()
+		return queueResult{queue: queue, err: err}
+	}
+	return m, remove
+}
+
+// viewQueue renders the offline queue: each sentence with its language pair and
+// whether it is waiting, translated or failed.
+func (m model) viewQueue() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Offline Queue:"))
+	s.WriteString("\n\n")
+	if len(m.queue) == 0 {
+		s.WriteString(normalStyle.Render("No queued sentences. Sentences entered while offline are kept here."))
+		s.WriteString("\n\n")
+	}
+	for i, item := range m.queue {
+		var line string
+		switch {
+		case item.Result != nil:
+			line = fmt.Sprintf("✓ %s → %s: %s → %s", item.UserLang, item.TargetLang, item.Sentence, item.Result.Translation)
+		case item.Err != "":
+			line = fmt.Sprintf("✗ %s → %s: %s (%s)", item.UserLang, item.TargetLang, item.Sentence, item.Err)
+		default:
+			line = fmt.Sprintf("… %s → %s: %s (waiting)", item.UserLang, item.TargetLang, item.Sentence)
+		}
+		if i == m.selectedQueued {
+			s.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			s.WriteString(normalStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Select, "Open"}, helpEntry{m.keys.Retry, "Translate now"}, helpEntry{m.keys.Back, "Back"})))
+	return s.String()
+}
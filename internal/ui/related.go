@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// relatedLookup is the state of the search for the synonyms, antonyms and common phrases
+// of a word.
+type relatedLookup struct {
+	details  *translator.WordDetails
+	err      error
+	pending  bool
+	selected int // Of relatedWords, or -1 before one is selected
+}
+
+// relatedResult represents a finished search for synonyms, antonyms and phrases.
+type relatedResult struct {
+	key     string
+	details *translator.WordDetails
+	err     error
+}
+
+// relatedKey identifies the search for the related words of a word in a sentence, whose
+// sense they depend on.
+func relatedKey(lang, word, sentence string) string {
+	return lookupKey(lang, word) + "|" + sentence
+}
+
+// findRelated creates a tea.Cmd that asks the model for the synonyms, antonyms and common
+// phrases of word as used in sentence.
+func findRelated(cfg config.Config, userLang, targetLang, word, sentence string) tea.Cmd {
+	return func() tea.Msg {
+		req := translator.Request{UserLang: userLang, TargetLang: targetLang}
+		details, err := translate.WordDetails(context.Background(), cfg, word, sentence, req)
+		return relatedResult{key: relatedKey(targetLang, word, sentence), details: details, err: err}
+	}
+}
+
+// relatedWords returns the synonyms of a word followed by its antonyms.
+func relatedWords(details *translator.WordDetails) []translator.RelatedWord {
+	return append(append([]translator.RelatedWord(nil), details.Synonyms...), details.Antonyms...)
+}
+
+// relatedSentence returns the sentence the related words of the selected word are
+// searched for in: the side of the result in the language being learned.
+func (m model) relatedSentence() string {
+	if m.foreign != "" {
+		return m.foreign
+	}
+	return m.originalSentence
+}
+
+// showSelectedRelated opens the drill-down of the selected word and asks for its
+// synonyms, antonyms and phrases, unless it already was; once they are shown, it selects
+// the next synonym or antonym.
+func (m model) showSelectedRelated() (tea.Model, tea.Cmd) {
+	word, ok := m.selectedWord()
+	if !ok {
+		return m, nil
+	}
+	m.wordExpanded = true
+	key := relatedKey(m.targetLang, word.WordInTargetLang, m.relatedSentence())
+	l, ok := m.related[key]
+	switch {
+	case ok && l.pending:
+		return m, nil
+	case ok && l.err == nil:
+		if n := len(relatedWords(l.details)); n > 0 {
+			l.selected = (l.selected + 1) % n
+			m.related[key] = l
+		}
+		return m, nil
+	}
+	if m.related == nil {
+		m.related = map[string]relatedLookup{}
+	}
+	m.related[key] = relatedLookup{pending: true, selected: -1}
+	return m, findRelated(m.cfg, m.userLang, m.targetLang, word.WordInTargetLang, m.relatedSentence())
+}
+
+// handleRelatedResult stores the outcome of a search for related words.
+func (m model) handleRelatedResult(msg relatedResult) (tea.Model, tea.Cmd) {
+	m.related[msg.key] = relatedLookup{details: msg.details, err: msg.err, selected: -1}
+	return m, nil
+}
+
+// selectedRelated returns the related word selected in the drill-down of the selected word.
+func (m model) selectedRelated() (translator.RelatedWord, bool) {
+	word, ok := m.selectedWord()
+	if !ok || !m.wordExpanded {
+		return translator.RelatedWord{}, false
+	}
+	l, ok := m.related[relatedKey(m.targetLang, word.WordInTargetLang, m.relatedSentence())]
+	if !ok || l.details == nil || l.selected < 0 {
+		return translator.RelatedWord{}, false
+	}
+	words := relatedWords(l.details)
+	if l.selected >= len(words) {
+		return translator.RelatedWord{}, false
+	}
+	return words[l.selected], true
+}
+
+// analyzeSelectedRelated translates and analyzes the selected related word as if it had
+// been typed as the next sentence.
+func (m model) analyzeSelectedRelated() (tea.Model, tea.Cmd) {
+	related, ok := m.selectedRelated()
+	if !ok || m.loading {
+		return m, nil
+	}
+	m.leave()
+	m.pop()
+	m.input = related.Word
+	return m.startTranslation()
+}
+
+// saveSelectedRelated adds the selected related word to the vocab deck, with its gloss
+// and register note as its analysis.
+func (m model) saveSelectedRelated() (tea.Model, tea.Cmd) {
+	related, ok := m.selectedRelated()
+	if !ok {
+		return m, nil
+	}
+	word, _ := m.selectedWord()
+	return m, saveRelatedToVocab(m.targetLang, word.WordInTargetLang, related)
+}
+
+// saveRelatedToVocab creates a tea.Cmd that adds a synonym or antonym of word to the
+// vocab deck.
+func saveRelatedToVocab(lang, word string, related translator.RelatedWord) tea.Cmd {
+	return func() tea.Msg {
+		if storage.Private() {
+			return storageResult{status: warningStyle.Render("Private session: words are not saved")}
+		}
+		analysis := related.Gloss
+		if related.Register != "" {
+			analysis += " (" + related.Register + ")"
+		}
+		card := storage.VocabCard{
+			Word:     related.Word,
+			Lemma:    related.Word,
+			Analysis: fmt.Sprintf("%s · related to %s", analysis, word),
+			Lang:     lang,
+			Added:    time.Now(),
+		}
+		added, err := storage.AddToVocab([]storage.VocabCard{card})
+		if err != nil {
+			return storageResult{err: err}
+		}
+		if added == 0 {
+			return storageResult{status: normalStyle.Render(fmt.Sprintf("%s is already in the vocab deck", related.Word))}
+		}
+		return storageResult{status: successStyle.Render(fmt.Sprintf("Saved %s to vocab deck", related.Word))}
+	}
+}
+
+// writeRelated renders the synonyms and antonyms of the selected word in the drill-down,
+// each with its gloss and register note and the selected one highlighted, followed by
+// its most common phrases with their translations.
+func (m model) writeRelated(s *strings.Builder, word translator.WordInfo) {
+	l, ok := m.related[relatedKey(m.targetLang, word.WordInTargetLang, m.relatedSentence())]
+	switch {
+	case !ok:
+		s.WriteString(normalStyle.Render(fmt.Sprintf("      %s: synonyms, antonyms and phrases", m.bindingKeys(m.keys.Related))))
+		s.WriteString("\n")
+		return
+	case l.pending:
+		s.WriteString(normalStyle.Render("      Finding synonyms, antonyms and phrases..."))
+		s.WriteString("\n")
+		return
+	case l.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("      Synonyms, antonyms and phrases: %v", l.err)))
+		s.WriteString("\n")
+		return
+	case len(relatedWords(l.details)) == 0 && len(l.details.Phrases) == 0:
+		s.WriteString(normalStyle.Render("      No synonyms, antonyms or phrases"))
+		s.WriteString("\n")
+		return
+	}
+	i := 0
+	for _, group := range []struct {
+		label string
+		words []translator.RelatedWord
+	}{{"Synonyms:", l.details.Synonyms}, {"Antonyms:", l.details.Antonyms}} {
+		if len(group.words) == 0 {
+			continue
+		}
+		s.WriteString(labelStyle.Render("      " + group.label))
+		s.WriteString("\n")
+		for _, r := range group.words {
+			line := r.Word
+			if r.Gloss != "" {
+				line += " · " + r.Gloss
+			}
+			if r.Register != "" {
+				line += " (" + r.Register + ")"
+			}
+			if i == l.selected {
+				s.WriteString(selectedStyle.Render("      > " + line))
+			} else {
+				s.WriteString(normalStyle.Render("        " + line))
+			}
+			s.WriteString("\n")
+			i++
+		}
+	}
+	if len(l.details.Phrases) > 0 {
+		s.WriteString(labelStyle.Render("      Phrases:"))
+		s.WriteString("\n")
+		for _, p := range l.details.Phrases {
+			s.WriteString("        ")
+			s.WriteString(valueStyle.Render(p.Text))
+			if p.Translation != "" {
+				s.WriteString(normalStyle.Render(" · " + p.Translation))
+			}
+			s.WriteString("\n")
+		}
+	}
+	if len(relatedWords(l.details)) == 0 {
+		return
+	}
+	help := m.helpLine(helpEntry{m.keys.Related, "Select next"})
+	if l.selected >= 0 {
+		help = m.helpLine(helpEntry{m.keys.Related, "Select next"}, helpEntry{m.keys.AnalyzeRelated, "Analyze it"}, helpEntry{m.keys.SaveRelated, "Save it"})
+	}
+	s.WriteString(normalStyle.Render("      " + help))
+	s.WriteString("\n")
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/export"
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// exportChoice is a way of exporting results to a file.
+type exportChoice struct {
+	name    string
+	format  string // One of the export formats
+	session bool   // All translations of the session rather than the current result
+}
+
+// exportChoices lists the choices of the export menu.
+var exportChoices = []exportChoice{
+	{"Result as plain text", export.FormatText, false},
+	{"Result as Markdown", export.FormatMarkdown, false},
+	{"Result as HTML study page", export.FormatHTML, false},
+	{"Session as plain text", export.FormatText, true},
+	{"Session as Markdown", export.FormatMarkdown, true},
+	{"Session as HTML study page", export.FormatHTML, true},
+}
+
+// exportChoiceNames lists the names of the export choices for the menu.
+func exportChoiceNames() []string {
+	names := make([]string, len(exportChoices))
+	for i, choice := range exportChoices {
+		names[i] = choice.name
+	}
+	return names
+}
+
+// updateExportMenu handles key presses in the export menu.
+func (m model) updateExportMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Export):
+		m.exporting = false
+	case key.Matches(msg, m.keys.Up):
+		m.exportCursor = max(m.exportCursor-1, 0)
+	case key.Matches(msg, m.keys.Down):
+		m.exportCursor = min(m.exportCursor+1, len(exportChoices)-1)
+	case key.Matches(msg, m.keys.Select):
+		choice := exportChoices[m.exportCursor]
+		m.exporting = false
+		m.status = ""
+		entries := []storage.HistoryEntry{m.currentEntry()}
+		name := "translation"
+		if choice.session {
+			entries = m.session
+			name = "session"
+		}
+		return m, exportResults(m.cfg.ExportDir, name, choice.format, entries)
+	}
+	return m, nil
+}
+
+// currentEntry returns the current result as it is exported.
+func (m model) currentEntry() storage.HistoryEntry {
+	targetLang := m.targetLang
+	if item, ok := m.currentSentence(); ok && item.targetLang != "" {
+		targetLang = item.targetLang // Translating into several languages
+	}
+	return storage.HistoryEntry{
+		UserLang:    m.userLang,
+		TargetLang:  targetLang,
+		Original:    m.originalSentence,
+		Translation: m.translation,
+		Words:       m.wordAnalysis,
+	}
+}
+
+// exportResults creates a tea.Cmd that writes translations in the format to a new file
+// in dir, named after what is exported and the time.
+func exportResults(dir, name, format string, entries []storage.HistoryEntry) tea.Cmd {
+	return func() tea.Msg {
+		var buf bytes.Buffer
+		if err := export.Write(&buf, format, entries); err != nil {
+			return storageResult{err: err}
+		}
+		path := filepath.Join(dir, name+"-"+time.Now().Format("2006-01-02-150405")+export.Extension(format))
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			return storageResult{err: fmt.Errorf("failed to write export: %w", err)}
+		}
+		return storageResult{status: successStyle.Render(fmt.Sprintf("Exported %d translation(s) to %s", len(entries), path))}
+	}
+}
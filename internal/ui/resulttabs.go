@@ -0,0 +1,418 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/export"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// resultTab is a tab of the results screen. Each tab shows one part of the result so
+// that the screen fits small terminals.
+type resultTab int
+
+const (
+	tabTranslation resultTab = iota
+	tabWords
+	tabGrammar
+	tabAlternatives
+	tabRaw
+)
+
+// resultTabNames are the titles of the tabs, in the order of the number keys.
+var resultTabNames = []string{"Translation", "Words", "Grammar", "Alternatives", "Raw"}
+
+// Fewest lines of a tab shown per page; smaller terminals show the whole tab
+const minPageHeight = 3
+
+// alternativesResult represents the outcome of paraphrasing the foreign-language
+// sentence of the results.
+type alternativesResult struct {
+	sentence       string
+	simplification *translator.Simplification
+	err            error
+}
+
+// paraphraseSentence creates a tea.Cmd that asks for paraphrases of a foreign-language
+// sentence.
+func paraphraseSentence(cfg config.Config, userLang, targetLang, sentence string) tea.Cmd {
+	return func() tea.Msg {
+		req := translator.Request{Sentence: sentence, UserLang: userLang, TargetLang: targetLang}
+		simplification, err := translate.Simplify(context.Background(), cfg, sentence, "", req)
+		return alternativesResult{sentence: sentence, simplification: simplification, err: err}
+	}
+}
+
+// foreignSentence returns the side of the result in the language being learned, if the
+// pipeline did not say: the one containing more of the analyzed words, or the
+// translation if neither does.
+func (m model) foreignSentence() string {
+	if m.foreign != "" {
+		return m.foreign
+	}
+	return export.ForeignSentence(m.currentEntry())
+}
+
+// switchTab shows tab i of the results, wrapping around, from its first line.
+func (m *model) switchTab(i int) {
+	n := len(resultTabNames)
+	m.resultTab = resultTab((i%n + n) % n)
+	m.resultScroll = 0
+}
+
+// clearResultTabs forgets what the tabs loaded for the previous result.
+func (m *model) clearResultTabs() {
+	m.resultScroll = 0
+	m.alternatives = nil
+	m.alternativesErr = nil
+	m.alternativesPending = false
+	m.wordFilter = ""
+	m.filteringWords = false
+	m.foreign = ""
+	m.pickingWords = false
+	m.analyzingWords = false
+	m.copying = false
+	m.exporting = false
+}
+
+// updateResultTabs handles the keys that switch, page and act within the tabs of the
+// results screen, reporting false for keys it leaves to the other results bindings.
+func (m model) updateResultTabs(msg tea.KeyMsg) (model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, m.keys.NextTab):
+		m.switchTab(int(m.resultTab) + 1)
+	case key.Matches(msg, m.keys.PrevTab):
+		m.switchTab(int(m.resultTab) - 1)
+	case key.Matches(msg, m.keys.PageUp):
+		m.resultScroll = max(m.resultScroll-m.pageHeight(), 0)
+	case key.Matches(msg, m.keys.PageDown):
+		m.resultScroll = min(m.resultScroll+m.pageHeight(), m.maxResultScroll())
+	case key.Matches(msg, m.keys.Select) && m.resultTab == tabWords:
+		if m.hasWords() {
+			m.wordExpanded = !m.wordExpanded
+		}
+	case key.Matches(msg, m.keys.Select) && m.resultTab == tabAlternatives:
+		return m.loadAlternatives()
+	case key.Matches(msg, m.keys.SortWords) && len(m.wordAnalysis) > 0:
+		m = m.cycleWordSort()
+	case key.Matches(msg, m.keys.Filter) && len(m.wordAnalysis) > 0:
+		m = m.startWordFilter()
+	case key.Matches(msg, m.keys.Analyze):
+		m = m.startPickingWords()
+	default:
+		text, ok := typedText(msg)
+		if !ok || len(text) != 1 || text[0] < '1' || int(text[0]-'1') >= len(resultTabNames) {
+			return m, nil, false
+		}
+		m.switchTab(int(text[0] - '1'))
+	}
+	return m, nil, true
+}
+
+// loadAlternatives asks for paraphrases of the foreign-language sentence, unless they
+// are already shown or on their way.
+func (m model) loadAlternatives() (model, tea.Cmd, bool) {
+	sentence := m.foreignSentence()
+	if sentence == "" || m.alternativesPending || m.alternatives != nil {
+		return m, nil, true
+	}
+	m.alternativesPending = true
+	m.alternativesErr = nil
+	return m, paraphraseSentence(m.cfg, m.userLang, m.targetLang, sentence), true
+}
+
+// handleAlternativesResult shows the paraphrases, unless another sentence is shown by now.
+func (m model) handleAlternativesResult(msg alternativesResult) (tea.Model, tea.Cmd) {
+	if !m.alternativesPending || msg.sentence != m.foreignSentence() {
+		return m, nil
+	}
+	m.alternativesPending = false
+	m.alternatives = msg.simplification
+	m.alternativesErr = msg.err
+	return m, nil
+}
+
+// viewResults renders the results screen: the sentence being shown, the tab bar, a page
+// of the selected tab and the key hints.
+func (m model) viewResults() string {
+	var header strings.Builder
+	header.WriteString(titleStyle.Render("Translation Results"))
+	header.WriteString("\n\n")
+	if m.source != "" {
+		header.WriteString(labelStyle.Render("Article: "))
+		header.WriteString(valueStyle.Render(m.source))
+		header.WriteString("\n\n")
+	}
+	if m.isFanout() {
+		header.WriteString(m.viewFanoutTabs())
+		header.WriteString("\n\n")
+	} else if len(m.paragraph) > 1 {
+		header.WriteString(labelStyle.Render(fmt.Sprintf("Sentence %d of %d", m.sentenceIndex+1, len(m.paragraph))))
+		header.WriteString("\n\n")
+	}
+	if m.degraded != "" {
+		header.WriteString(warningStyle.Render("⚠ Degraded output: " + m.degraded))
+		header.WriteString("\n\n")
+	}
+	header.WriteString(m.viewTabBar())
+	header.WriteString("\n\n")
+
+	footer := m.resultsFooter()
+	lines := strings.Split(strings.TrimRight(m.tabBody(), "\n"), "\n")
+	page := m.pageHeight()
+	if page > 0 && len(lines) > page {
+		first := min(m.resultScroll, len(lines)-page)
+		last := first + page
+		lines = append(lines[first:last], "", labelStyle.Render(fmt.Sprintf("Lines %d–%d of %d", first+1, last, len(lines)))+normalStyle.Render(" | "+m.helpLine(helpEntry{m.keys.PageUp, "Page up"}, helpEntry{m.keys.PageDown, "Page down"})))
+	}
+	return header.String() + strings.Join(lines, "\n") + "\n\n" + footer
+}
+
+// viewTabBar renders the titles of the tabs with their number keys, highlighting the
+// selected one.
+func (m model) viewTabBar() string {
+	tabs := make([]string, len(resultTabNames))
+	for i, name := range resultTabNames {
+		title := fmt.Sprintf("%d %s", i+1, name)
+		if resultTab(i) == m.resultTab {
+			tabs[i] = selectedStyle.Render("[" + title + "]")
+		} else {
+			tabs[i] = normalStyle.Render(" " + title + " ")
+		}
+	}
+	return strings.Join(tabs, " ")
+}
+
+// tabBody renders the whole content of the selected tab, which is paged if it does not
+// fit the terminal.
+func (m model) tabBody() string {
+	var s strings.Builder
+	switch m.resultTab {
+	case tabTranslation:
+		s.WriteString(fmt.Sprintf("%s ↔ %s\n\n", m.getLangName(m.userLang), m.getLangName(m.targetLang)))
+		s.WriteString(labelStyle.Render("Original: "))
+		m.writeDirected(&s, m.withStress(m.originalSentence), valueStyle)
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render("Translation: "))
+		if item, ok := m.currentSentence(); ok && item.err != nil {
+			s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", item.err)))
+		} else {
+			m.writeDirected(&s, m.withStress(m.translation), successStyle)
+		}
+		s.WriteString("\n\n")
+		if m.sentenceLevel != "" {
+			s.WriteString(labelStyle.Render("Level: "))
+			s.WriteString(levelBadge(m.sentenceLevel, m.cfg.Level))
+			s.WriteString("\n\n")
+		}
+		m.writeFollowUps(&s)
+
+	case tabWords:
+		switch {
+		case m.pickingWords:
+			m.writeWordPicker(&s)
+		case m.analyzingWords:
+			s.WriteString(labelStyle.Render("Analyzing words..."))
+			s.WriteString("\n\n")
+		}
+		if len(m.wordAnalysis) == 0 {
+			s.WriteString(normalStyle.Render("No word analysis for this sentence. Press " + m.bindingKeys(m.keys.Analyze) + " to pick words to analyze."))
+			break
+		}
+		m.writeWordTable(&s)
+
+	case tabGrammar:
+		m.writeGrammar(&s)
+
+	case tabAlternatives:
+		s.WriteString(labelStyle.Render("Sentence: "))
+		m.writeDirected(&s, m.foreignSentence(), valueStyle)
+		s.WriteString("\n\n")
+		switch {
+		case m.alternativesPending:
+			s.WriteString(labelStyle.Render("Finding other ways to say it..."))
+		case m.alternativesErr != nil:
+			s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.alternativesErr)))
+		case m.alternatives != nil:
+			for i, r := range m.alternatives.Rewrites {
+				s.WriteString(labelStyle.Render(fmt.Sprintf("%d. ", i+1)))
+				m.writeDirected(&s, r.Text, successStyle)
+				s.WriteString("\n")
+				s.WriteString(normalStyle.Render("   " + r.Changes))
+				s.WriteString("\n\n")
+			}
+		default:
+			s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Paraphrase the sentence"})))
+		}
+
+	case tabRaw:
+		raw, err := json.MarshalIndent(translator.Result{
+			Original:    m.originalSentence,
+			Translation: m.translation,
+			Words:       m.wordAnalysis,
+			Level:       m.sentenceLevel,
+			Tone:        m.sentenceTone,
+			Models:      m.usedModels,
+		}, "", "  ")
+		if err != nil {
+			s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			break
+		}
+		s.WriteString(normalStyle.Render(string(raw)))
+	}
+	return s.String()
+}
+
+// writeGrammar lists the grammar notes of the analysis: the tone of the sentence, the
+// forms of nouns, multi-word expressions and false friends.
+func (m model) writeGrammar(s *strings.Builder) {
+	var nouns, expressions, falseFriends []translator.WordInfo
+	for _, word := range m.wordAnalysis {
+		if nounForms(word) != "" {
+			nouns = append(nouns, word)
+		}
+		if word.Grouped() || word.Idiom {
+			expressions = append(expressions, word)
+		}
+		if word.FalseFriend != "" {
+			falseFriends = append(falseFriends, word)
+		}
+	}
+	if len(nouns)+len(expressions)+len(falseFriends) == 0 && m.sentenceTone == nil {
+		s.WriteString(normalStyle.Render("No grammar notes for this sentence."))
+		return
+	}
+	if tone := m.sentenceTone; tone != nil {
+		s.WriteString(labelStyle.Render("Tone:"))
+		s.WriteString("\n")
+		for _, row := range [][2]string{{"Register", tone.Register}, {"Politeness", tone.Politeness}, {"Emotion", tone.Emotion}} {
+			if row[1] == "" {
+				continue
+			}
+			s.WriteString(normalStyle.Render(fmt.Sprintf("  %-11s ", row[0]+":")))
+			s.WriteString(valueStyle.Render(row[1]))
+			s.WriteString("\n")
+		}
+		for _, shift := range tone.Shifts {
+			lead := "  Or say: "
+			if shift.Toward != "" {
+				lead = fmt.Sprintf("  To make it %s, say: ", shift.Toward)
+			}
+			s.WriteString(normalStyle.Render(lead))
+			s.WriteString(successStyle.Render(shift.Sentence))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	}
+	if len(nouns) > 0 {
+		s.WriteString(labelStyle.Render("Nouns:"))
+		s.WriteString("\n")
+		for _, word := range nouns {
+			s.WriteString("  ")
+			s.WriteString(genderStyle(word.Gender, valueStyle).Render(word.WordInTargetLang))
+			s.WriteString(normalStyle.Render(" (" + nounForms(word) + ")"))
+			if word.Gender != "" {
+				s.WriteString(normalStyle.Render(" - " + word.Gender))
+			}
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	}
+	if len(expressions) > 0 {
+		s.WriteString(labelStyle.Render("Expressions:"))
+		s.WriteString("\n")
+		for _, word := range expressions {
+			s.WriteString("  ")
+			s.WriteString(valueStyle.Render(word.WordInTargetLang))
+			s.WriteString(normalStyle.Render(" - " + word.GrammaticalExplanation))
+			s.WriteString("\n")
+			for _, part := range word.Parts {
+				s.WriteString(normalStyle.Render(fmt.Sprintf("      %s: %s", part.Word, part.Analysis)))
+				s.WriteString("\n")
+			}
+		}
+		s.WriteString("\n")
+	}
+	if len(falseFriends) > 0 {
+		s.WriteString(labelStyle.Render("False friends:"))
+		s.WriteString("\n")
+		for _, word := range falseFriends {
+			s.WriteString("  ")
+			s.WriteString(valueStyle.Render(word.WordInTargetLang))
+			s.WriteString(warningStyle.Render(" - " + word.FalseFriend))
+			s.WriteString("\n")
+		}
+	}
+}
+
+// resultsFooter renders the status and the key hints below the tabs.
+func (m model) resultsFooter() string {
+	var s strings.Builder
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	switch {
+	case m.copying:
+		m.writeMenu(&s, "Copy as:", copyFormatNames(), m.copyCursor, "Copy")
+	case m.exporting:
+		m.writeMenu(&s, "Export:", exportChoiceNames(), m.exportCursor, "Export")
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Translate another"}, helpEntry{m.keys.Save, "Save words"}, helpEntry{m.keys.SaveAbove, "Save above level"}, helpEntry{m.keys.FollowUp, "Ask"}, helpEntry{m.keys.Copy, "Copy"}, helpEntry{m.keys.Export, "Export"}, helpEntry{m.keys.Model, "Model"}, helpEntry{m.keys.Help, "Help"}, helpEntry{m.keys.Quit, "Quit"})))
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.NextTab, "Next tab"}, helpEntry{m.keys.PrevTab, "Previous tab"}) + fmt.Sprintf(" | 1-%d: Go to tab", len(resultTabNames))))
+	if m.resultTab == tabWords && m.pickingWords {
+		s.WriteString("\n")
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.PrevSentence, "Previous word"}, helpEntry{m.keys.NextSentence, "Next word"}, helpEntry{m.keys.Mark, "Pick"}, helpEntry{m.keys.Select, "Analyze"}, helpEntry{m.keys.Back, "Cancel"})))
+	} else if m.resultTab == tabWords && m.filteringWords {
+		s.WriteString("\n")
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Keep filter"}, helpEntry{m.keys.Back, "Clear filter"}) + " | Type to filter"))
+	} else if m.resultTab == tabWords && len(m.wordAnalysis) > 0 {
+		s.WriteString("\n")
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Previous word"}, helpEntry{m.keys.Down, "Next word"}, helpEntry{m.keys.Select, "Details"}, helpEntry{m.keys.SortWords, "Sort"}, helpEntry{m.keys.Filter, "Filter"}, helpEntry{m.keys.Analyze, "Analyze more"}, helpEntry{m.keys.Wiktionary, "Wiktionary"}, helpEntry{m.keys.Examples, "Examples"}, helpEntry{m.keys.Pronounce, "Pronounce"}, helpEntry{m.keys.Related, "Related"})))
+	}
+	if m.isFanout() {
+		s.WriteString("\n")
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.PrevSentence, "Previous language"}, helpEntry{m.keys.NextSentence, "Next language"}, helpEntry{m.keys.Overview, "All languages"})))
+	} else if len(m.paragraph) > 1 {
+		s.WriteString("\n")
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.PrevSentence, "Previous sentence"}, helpEntry{m.keys.NextSentence, "Next sentence"}, helpEntry{m.keys.Overview, "Overview"})))
+	}
+	s.WriteString("\n")
+	s.WriteString(normalStyle.Render(m.modelFooter()))
+	return s.String()
+}
+
+// pageHeight returns the number of lines of a tab shown at once, leaving room for the
+// header, the footer and the status bar, or 0 if the whole tab is shown.
+func (m model) pageHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	// Title, tab bar, pager line and status bar, with the blank lines between them
+	chrome := 9 + lipgloss.Height(m.resultsFooter())
+	for _, shown := range []bool{len(m.paragraph) > 1, m.degraded != "", m.source != ""} {
+		if shown {
+			chrome += 2
+		}
+	}
+	if page := m.height - chrome; page >= minPageHeight {
+		return page
+	}
+	return 0
+}
+
+// maxResultScroll returns the first line of the last page of the selected tab.
+func (m model) maxResultScroll() int {
+	lines := strings.Count(strings.TrimRight(m.tabBody(), "\n"), "\n") + 1
+	return max(lines-m.pageHeight(), 0)
+}
@@ -0,0 +1,189 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/srs"
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// dueCardsResult carries the vocab cards due for review.
+type dueCardsResult struct {
+	cards []storage.VocabCard
+	err   error
+}
+
+// loadDueCards creates a tea.Cmd that reads the vocab cards due for review.
+func loadDueCards() tea.Cmd {
+	return func() tea.Msg {
+		cards, err := storage.LoadVocab()
+		return dueCardsResult{cards: srs.Due(cards, time.Now()), err: err}
+	}
+}
+
+// saveReview creates a tea.Cmd that saves the schedule of a reviewed card.
+func saveReview(card storage.VocabCard) tea.Cmd {
+	return func() tea.Msg {
+		return storageResult{err: storage.UpdateVocab([]storage.VocabCard{card})}
+	}
+}
+
+// handleDueCards starts reviewing the due cards if the review is open, and otherwise
+// tells how many are due, as when the app starts.
+func (m model) handleDueCards(msg dueCardsResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		return m, nil
+	}
+	if m.state == stateReview {
+		m.review = msg.cards
+		m.reviewIndex = 0
+		m.reviewRevealed = false
+		if next, cmd, ok := m.studyTurn(len(m.review) == 0); ok {
+			return next, cmd
+		}
+		return m, nil
+	}
+	if n := len(msg.cards); n > 0 {
+		noun := "cards"
+		if n == 1 {
+			noun = "card"
+		}
+		m.status = warningStyle.Render(fmt.Sprintf("%d %s due — press %s to review", n, noun, m.bindingKeys(m.keys.Review)))
+	}
+	return m, nil
+}
+
+// openReview shows the review of the vocab cards due.
+func (m model) openReview() (model, tea.Cmd) {
+	m.navigate(stateReview)
+	m.review = nil
+	m.reviewIndex = 0
+	m.reviewRevealed = false
+	m.reviewed = 0
+	m.status = ""
+	return m, loadDueCards()
+}
+
+// reviewGrade is a grade given by a key in the review.
+type reviewGrade struct {
+	binding key.Binding
+	grade   srs.Grade
+	name    string
+}
+
+// reviewGrades lists the grades of the review, from forgotten to recalled at once.
+func (m model) reviewGrades() []reviewGrade {
+	return []reviewGrade{
+		{m.keys.Again, srs.Again, "Again"},
+		{m.keys.Hard, srs.Hard, "Hard"},
+		{m.keys.Good, srs.Good, "Good"},
+		{m.keys.Easy, srs.Easy, "Easy"},
+	}
+}
+
+// updateReview handles key presses in the review. Select shows the answer, which is
+// then graded; cards forgotten come back at the end of the review.
+func (m model) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Review):
+		return m.back()
+	case m.reviewIndex >= len(m.review):
+		return m, nil
+	case !m.reviewRevealed:
+		if key.Matches(msg, m.keys.Select) {
+			m.reviewRevealed = true
+		}
+		return m, nil
+	}
+	for _, g := range m.reviewGrades() {
+		if !key.Matches(msg, g.binding) {
+			continue
+		}
+		card := srs.Review(m.review[m.reviewIndex], g.grade, time.Now())
+		if g.grade == srs.Again {
+			m.review = append(slices.Clip(m.review), card)
+			m.recordStudy(0, 1)
+		} else {
+			m.reviewed++
+			m.recordStudy(1, 1)
+		}
+		m.reviewIndex++
+		m.reviewRevealed = false
+		if next, cmd, ok := m.studyTurn(m.reviewIndex >= len(m.review)); ok {
+			return next, tea.Batch(saveReview(card), cmd)
+		}
+		return m, saveReview(card)
+	}
+	return m, nil
+}
+
+// viewReview renders the review: the word of the current card, and once shown, its
+// analysis, sentence and translation with the grades and when each would bring it back.
+func (m model) viewReview() string {
+	var s strings.Builder
+	left := len(m.review) - m.reviewIndex
+	s.WriteString(titleStyle.Render(fmt.Sprintf("Review: %d left", left)))
+	s.WriteString("\n\n")
+
+	if left == 0 {
+		if m.reviewed == 0 {
+			s.WriteString(normalStyle.Render("No cards are due. Words saved from the results are reviewed here."))
+		} else {
+			s.WriteString(successStyle.Render(fmt.Sprintf("Done: %d card(s) reviewed. Come back when the next are due.", m.reviewed)))
+		}
+		s.WriteString("\n\n")
+		if m.status != "" {
+			s.WriteString(m.status)
+			s.WriteString("\n\n")
+		}
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Back"})))
+		return s.String()
+	}
+
+	card := m.review[m.reviewIndex]
+	s.WriteString(labelStyle.Render(card.Word))
+	s.WriteString(normalStyle.Render(fmt.Sprintf("  (%s)", m.getLangName(card.Lang))))
+	s.WriteString("\n\n")
+	if m.reviewRevealed {
+		for _, part := range []string{card.Analysis, card.Sentence, card.Translation} {
+			if part = strings.TrimSpace(part); part != "" {
+				s.WriteString(normalStyle.Render(part))
+				s.WriteString("\n\n")
+			}
+		}
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	if !m.reviewRevealed {
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Show answer"}, helpEntry{m.keys.Back, "Back"})))
+		return s.String()
+	}
+	var entries []helpEntry
+	for _, g := range m.reviewGrades() {
+		entries = append(entries, helpEntry{g.binding, fmt.Sprintf("%s (%s)", g.name, reviewInterval(srs.Interval(card, g.grade)))})
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(append(entries, helpEntry{m.keys.Back, "Back"})...)))
+	return s.String()
+}
+
+// reviewInterval formats the days until a card comes back.
+func reviewInterval(days int) string {
+	switch days {
+	case 0:
+		return "soon"
+	case 1:
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
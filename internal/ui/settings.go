@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// setting is a row of the settings screen, changed with left/right and saved to a key
+// of the config file.
+type setting struct {
+	name  string
+	table string // Table of the key in config.toml; empty for top-level keys
+	key   string
+	// value formats the current value for display.
+	value func(m model) string
+	// change moves the setting dir steps and returns the new value to save, or nil if
+	// the setting is not changed with left/right.
+	change func(m *model, dir int) any
+}
+
+// Values the settings cycle through
+var (
+	providerOptions  = []string{translator.ProviderGemini, translator.ProviderOpenAI, translator.ProviderDeepL, translator.ProviderLibreTranslate}
+	levelOptions     = append([]string{""}, cefrLevels...)
+	formalityOptions = []string{"", config.FormalityFormal, config.FormalityInformal}
+)
+
+// settings lists the rows of the settings screen.
+var settings = []setting{
+	{
+		name: "Provider", key: "provider",
+		value: func(m model) string { return m.cfg.Provider },
+		change: func(m *model, dir int) any {
+			m.cfg.Provider = cycle(providerOptions, m.cfg.Provider, dir)
+			return m.cfg.Provider
+		},
+	},
+	{
+		name: "Models",
+		value: func(m model) string {
+			models := translate.ConfiguredModels(m.cfg)
+			return models.Translation + " / " + models.Analysis + " (Enter to pick)"
+		},
+	},
+	{
+		name: "Theme", table: "theme", key: "name",
+		value: func(m model) string { return m.theme },
+		change: func(m *model, dir int) any {
+			name := cycle(themeNames, m.theme, dir)
+			t, err := newTheme(name, m.cfg.Theme)
+			if err != nil {
+				return nil
+			}
+			t.apply()
+			m.theme = name
+			m.cfg.Theme.Name = name
+			return name
+		},
+	},
+	{
+		name: "Level", key: "level",
+		value: func(m model) string { return orNone(m.cfg.Level) },
+		change: func(m *model, dir int) any {
+			m.cfg.Level = cycle(levelOptions, m.cfg.Level, dir)
+			return m.cfg.Level
+		},
+	},
+	{
+		name: "Formality", key: "formality",
+		value: func(m model) string { return orNone(m.cfg.Formality) },
+		change: func(m *model, dir int) any {
+			m.cfg.Formality = cycle(formalityOptions, m.cfg.Formality, dir)
+			return m.cfg.Formality
+		},
+	},
+	depthSetting,
+	analyzeOnDemandSetting,
+	dictionarySetting,
+	{
+		name: "Result cache", table: "fallback", key: "cache",
+		value: func(m model) string { return onOff(m.cfg.Fallback.Cache) },
+		change: func(m *model, dir int) any {
+			m.cfg.Fallback.Cache = !m.cfg.Fallback.Cache
+			return m.cfg.Fallback.Cache
+		},
+	},
+	{
+		name: "Redact", key: "redact",
+		value: func(m model) string {
+			if m.cfg.Redact {
+				return "names, e-mails, phones"
+			}
+			return "off"
+		},
+		change: func(m *model, dir int) any {
+			m.cfg.Redact = !m.cfg.Redact
+			return m.cfg.Redact
+		},
+	},
+	{
+		name: "Transcript", key: "transcript",
+		value: func(m model) string {
+			if m.cfg.Transcript {
+				return "daily Markdown"
+			}
+			return "off"
+		},
+		change: func(m *model, dir int) any {
+			m.cfg.Transcript = !m.cfg.Transcript
+			return m.cfg.Transcript
+		},
+	},
+	{
+		name: "Etymology", key: "etymology",
+		value: func(m model) string { return onOff(m.cfg.Etymology) },
+		change: func(m *model, dir int) any {
+			m.cfg.Etymology = !m.cfg.Etymology
+			return m.cfg.Etymology
+		},
+	},
+	stressSetting,
+	{
+		name: "Spellcheck", key: "spellcheck",
+		value: func(m model) string { return onOff(m.cfg.Spellcheck) },
+		change: func(m *model, dir int) any {
+			m.cfg.Spellcheck = !m.cfg.Spellcheck
+			return m.cfg.Spellcheck
+		},
+	},
+}
+
+// cycle returns the option dir steps from current, wrapping around.
+func cycle(options []string, current string, dir int) string {
+	i := max(slices.Index(options, current), 0)
+	return options[(i+dir+len(options))%len(options)]
+}
+
+// orNone formats an optional value for display.
+func orNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
+
+// onOff formats a boolean setting for display.
+func onOff(v bool) string {
+	if v {
+		return "on"
+	}
+	return "off"
+}
+
+// saveSetting creates a tea.Cmd that writes a changed setting to the config file.
+func saveSetting(path string, s setting, value any) tea.Cmd {
+	return func() tea.Msg {
+		if path == "" {
+			return storageResult{status: warningStyle.Render(s.name + " changed for this session; there is no config file to save it to")}
+		}
+		if err := config.SaveSetting(path, s.table, s.key, value); err != nil {
+			return storageResult{err: err}
+		}
+		return storageResult{status: successStyle.Render(fmt.Sprintf("Saved %s to %s", strings.ToLower(s.name), path))}
+	}
+}
+
+// openSettings shows the settings screen.
+func (m model) openSettings() (model, tea.Cmd) {
+	m.navigate(stateSettings)
+	m.selectedSetting = 0
+	m.status = ""
+	return m, nil
+}
+
+// updateSettings handles key presses on the settings screen. Changes take effect right
+// away and are saved to the config file.
+func (m model) updateSettings(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := settings[m.selectedSetting]
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Settings):
+		m.pop()
+	case key.Matches(msg, m.keys.Up):
+		if m.selectedSetting > 0 {
+			m.selectedSetting--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.selectedSetting < len(settings)-1 {
+			m.selectedSetting++
+		}
+	case key.Matches(msg, m.keys.Select) && s.change == nil:
+		return m.openModelPicker()
+	case key.Matches(msg, m.keys.PrevSentence), key.Matches(msg, m.keys.NextSentence), key.Matches(msg, m.keys.Select):
+		if s.change == nil {
+			return m, nil
+		}
+		dir := 1
+		if key.Matches(msg, m.keys.PrevSentence) {
+			dir = -1
+		}
+		if value := s.change(&m, dir); value != nil {
+			return m, saveSetting(m.cfg.Path, s, value)
+		}
+	}
+	return m, nil
+}
+
+// viewSettings renders the settings with their current values.
+func (m model) viewSettings() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Settings:"))
+	s.WriteString("\n\n")
+	for i, setting := range settings {
+		line := fmt.Sprintf("%-14s %s", setting.name, setting.value(m))
+		if i == m.selectedSetting {
+			s.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			s.WriteString(normalStyle.Render("  " + line))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.PrevSentence, "Previous value"}, helpEntry{m.keys.NextSentence, "Next value"}, helpEntry{m.keys.Back, "Back"})))
+	return s.String()
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/translate"
+)
+
+// apiKeyStoredResult represents the outcome of storing the API key from the setup screen.
+type apiKeyStoredResult struct {
+	location string
+	err      error
+}
+
+// saveAPIKey creates a tea.Cmd that stores the API key in the keyring or key file.
+func saveAPIKey(key string) tea.Cmd {
+	return func() tea.Msg {
+		location, err := translate.StoreAPIKey(key)
+		return apiKeyStoredResult{location: location, err: err}
+	}
+}
+
+// updateSetup handles key presses on the first-run API key setup screen.
+func (m model) updateSetup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if text, ok := typedText(msg); ok {
+		m.input += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit, m.keys.Back):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Select):
+		apiKey := strings.TrimSpace(m.input)
+		if apiKey == "" {
+			return m, nil
+		}
+		return m, saveAPIKey(apiKey)
+	case msg.Type == tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	}
+	return m, nil
+}
+
+// viewSetup renders the first-run API key setup screen with the key masked.
+func (m model) viewSetup() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Welcome! Set Up Your Gemini API Key:"))
+	s.WriteString("\n\n")
+	s.WriteString("No API key was found. Get one at https://aistudio.google.com/apikey\n")
+	s.WriteString("It will be stored in your OS keyring, or in a private key file if no keyring is available.\n\n")
+	s.WriteString(fmt.Sprintf("API key: %s█\n\n", strings.Repeat("•", len(m.input))))
+	if m.err != nil {
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Save"}, helpEntry{m.keys.Back, "Quit"}, helpEntry{m.keys.Help, "Help"})))
+	return s.String()
+}
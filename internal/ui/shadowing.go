@@ -0,0 +1,340 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/export"
+	"github.com/brittaao/translation-tui/internal/pronunciation"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// Sentences of the history offered for shadowing, most recent first
+const maxShadowSentences = 50
+
+// Most words in a chunk of a sentence
+const maxChunkWords = 4
+
+// shadowPause is the silence between two playbacks, to catch up.
+const shadowPause = time.Second
+
+// shadowSpeeds lists the speeds of playback, as a share of the normal speed.
+var shadowSpeeds = []float64{0.5, 0.6, 0.7, 0.8, 0.9, 1, 1.1, 1.25, 1.5}
+
+// defaultShadowSpeed is the speed of playback until changed: the normal speed.
+var defaultShadowSpeed = slices.Index(shadowSpeeds, 1)
+
+// shadowSentence is a sentence of the history in the language learned, with the
+// glosses of its words.
+type shadowSentence struct {
+	tokens      []export.Token
+	chunks      [][2]int // Tokens of the parts of the sentence, if it has several
+	translation string
+}
+
+// shadowSentencesResult carries the sentences of the history offered for shadowing.
+type shadowSentencesResult struct {
+	sentences []shadowSentence
+	err       error
+}
+
+// shadowPlayed represents a playback of the shadowing mode that ended.
+type shadowPlayed struct {
+	gen int
+	err error
+}
+
+// shadowReplay asks for the next playback of the loop, after the pause.
+type shadowReplay struct {
+	gen int
+}
+
+// loadShadowSentences creates a tea.Cmd that reads the analyzed sentences of the
+// history in lang, each once, most recent first.
+func loadShadowSentences(lang string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := storage.LoadHistory()
+		if err != nil {
+			return shadowSentencesResult{err: err}
+		}
+		seen := map[string]bool{}
+		var sentences []shadowSentence
+		for i := len(entries) - 1; i >= 0 && len(sentences) < maxShadowSentences; i-- {
+			e := entries[i]
+			text := strings.Join(strings.Fields(export.ForeignSentence(e)), " ")
+			if e.TargetLang != lang || len(e.Words) == 0 || seen[text] {
+				continue
+			}
+			seen[text] = true
+			translation := e.Translation
+			if text == strings.Join(strings.Fields(e.Translation), " ") {
+				translation = e.Original
+			}
+			tokens := export.Annotate(text, e.Words)
+			sentences = append(sentences, shadowSentence{tokens: tokens, chunks: shadowChunks(tokens), translation: strings.TrimSpace(translation)})
+		}
+		return shadowSentencesResult{sentences: sentences}
+	}
+}
+
+// shadowChunks splits a sentence into parts at punctuation, of at most maxChunkWords
+// words each. A sentence of one part has none.
+func shadowChunks(tokens []export.Token) [][2]int {
+	var chunks [][2]int
+	start, end, words := -1, 0, 0
+	for i, tok := range tokens {
+		switch {
+		case tok.Word:
+			if start < 0 {
+				start = i
+			}
+			end = i + 1
+			words++
+		case start >= 0 && strings.ContainsAny(tok.Text, ",;:.!?…–—"):
+			words = maxChunkWords // Ends the part
+		}
+		if start >= 0 && words >= maxChunkWords {
+			chunks = append(chunks, [2]int{start, end})
+			start, words = -1, 0
+		}
+	}
+	if start >= 0 {
+		chunks = append(chunks, [2]int{start, end})
+	}
+	if len(chunks) < 2 {
+		return nil
+	}
+	return chunks
+}
+
+// playShadow creates a tea.Cmd that synthesizes text in lang and plays it at the
+// speed, until done or ctx is canceled.
+func playShadow(ctx context.Context, cfg config.Config, lang, text string, speed float64, gen int) tea.Cmd {
+	return func() tea.Msg {
+		audio, err := translator.RunStep(ctx, cfg.Timeout, "Synthesizing sentence", nil, func(ctx context.Context) (pronunciation.Audio, error) {
+			return pronunciation.Speak(ctx, cfg.Pronunciation.TTSCommand, text, lang)
+		})
+		if err == nil {
+			err = pronunciation.PlayAt(ctx, cfg.Pronunciation.Player, audio.Path, speed)
+			audio.Discard()
+		}
+		return shadowPlayed{gen: gen, err: err}
+	}
+}
+
+// openShadowing starts the shadowing mode on the recent sentences of the language learned.
+func (m model) openShadowing() (model, tea.Cmd) {
+	m.navigate(stateShadowing)
+	m.shadowing = nil
+	m.practiceIndex = 0
+	m.shadowChunk = 0
+	m.shadowPaused = false
+	m.status = ""
+	return m, loadShadowSentences(m.targetLang)
+}
+
+// handleShadowSentences starts looping the first of the sentences.
+func (m model) handleShadowSentences(msg shadowSentencesResult) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = errorStyle.Render(fmt.Sprintf("Error: %v", msg.err))
+		return m, nil
+	}
+	if m.state != stateShadowing {
+		return m, nil
+	}
+	m.shadowing = msg.sentences
+	if m.shadowing == nil {
+		m.shadowing = []shadowSentence{} // Loaded, but none analyzed
+	}
+	return m.playShadowing()
+}
+
+// playShadowing stops what is playing and plays the current sentence or part of it,
+// unless paused.
+func (m model) playShadowing() (model, tea.Cmd) {
+	m.stopShadowing()
+	m.shadowGen++
+	if m.shadowPaused || m.practiceIndex >= len(m.shadowing) {
+		return m, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.shadowStop = cancel
+	m.status = ""
+	return m, playShadow(ctx, m.cfg, m.targetLang, m.shadowText(), shadowSpeeds[m.shadowSpeed], m.shadowGen)
+}
+
+// stopShadowing stops the playback of the shadowing mode, if any.
+func (m *model) stopShadowing() {
+	if m.shadowStop != nil {
+		m.shadowStop()
+		m.shadowStop = nil
+	}
+}
+
+// shadowText returns the text played: the current sentence, or the selected part of it.
+func (m model) shadowText() string {
+	sentence := m.shadowing[m.practiceIndex]
+	from, to := 0, len(sentence.tokens)
+	if m.shadowChunk > 0 {
+		from, to = sentence.chunks[m.shadowChunk-1][0], sentence.chunks[m.shadowChunk-1][1]
+	}
+	var s strings.Builder
+	for _, tok := range sentence.tokens[from:to] {
+		s.WriteString(tok.Text)
+	}
+	return s.String()
+}
+
+// handleShadowPlayed plays the text again after a pause, or stops the loop if it could
+// not be played. Playbacks of text no longer shown are ignored.
+func (m model) handleShadowPlayed(msg shadowPlayed) (tea.Model, tea.Cmd) {
+	if msg.gen != m.shadowGen || errors.Is(msg.err, context.Canceled) {
+		return m, nil
+	}
+	m.shadowStop = nil
+	if msg.err != nil {
+		m.shadowPaused = true
+		m.status = errorStyle.Render(fmt.Sprintf("Audio: %v", msg.err))
+		return m, nil
+	}
+	gen := m.shadowGen
+	return m, tea.Tick(shadowPause, func(time.Time) tea.Msg { return shadowReplay{gen: gen} })
+}
+
+// handleShadowReplay plays the text again, unless the loop was changed meanwhile.
+func (m model) handleShadowReplay(msg shadowReplay) (tea.Model, tea.Cmd) {
+	if msg.gen != m.shadowGen || m.state != stateShadowing {
+		return m, nil
+	}
+	return m.playShadowing()
+}
+
+// updateShadowing handles key presses in the shadowing mode. Every change of the
+// sentence, part or speed starts the loop afresh.
+func (m model) updateShadowing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		m.stopShadowing()
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.stopShadowing()
+		m.shadowGen++
+		return m.back()
+	case m.practiceIndex >= len(m.shadowing):
+		return m, nil
+	case key.Matches(msg, m.keys.Select):
+		m.shadowPaused = !m.shadowPaused
+	case key.Matches(msg, m.keys.Replay):
+		m.shadowPaused = false
+	case key.Matches(msg, m.keys.Up):
+		m.practiceIndex = max(m.practiceIndex-1, 0)
+		m.shadowChunk = 0
+	case key.Matches(msg, m.keys.Down):
+		m.practiceIndex = min(m.practiceIndex+1, len(m.shadowing)-1)
+		m.shadowChunk = 0
+	case key.Matches(msg, m.keys.PrevSentence):
+		m.shadowChunk = max(m.shadowChunk-1, 0)
+	case key.Matches(msg, m.keys.NextSentence):
+		m.shadowChunk = min(m.shadowChunk+1, len(m.shadowing[m.practiceIndex].chunks))
+	case key.Matches(msg, m.keys.Slower):
+		m.shadowSpeed = max(m.shadowSpeed-1, 0)
+	case key.Matches(msg, m.keys.Faster):
+		m.shadowSpeed = min(m.shadowSpeed+1, len(shadowSpeeds)-1)
+	default:
+		return m, nil
+	}
+	return m.playShadowing()
+}
+
+// viewShadowing renders the shadowing mode: the sentence with the glosses below its
+// words and the part played marked, its meaning, and how it is played.
+func (m model) viewShadowing() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Shadowing: " + m.getLangName(m.targetLang)))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.shadowing == nil:
+	case len(m.shadowing) == 0:
+		s.WriteString(normalStyle.Render("No analyzed sentences yet. Translate some sentences to shadow them."))
+		s.WriteString("\n\n")
+	default:
+		sentence := m.shadowing[m.practiceIndex]
+		state := "looping"
+		if m.shadowPaused {
+			state = "paused"
+		}
+		part := "whole sentence"
+		from, to := 0, len(sentence.tokens)
+		if m.shadowChunk > 0 {
+			part = fmt.Sprintf("part %d of %d", m.shadowChunk, len(sentence.chunks))
+			from, to = sentence.chunks[m.shadowChunk-1][0], sentence.chunks[m.shadowChunk-1][1]
+		}
+		s.WriteString(normalStyle.Render(fmt.Sprintf("Sentence %d of %d · %s · %s× · %s", m.practiceIndex+1, len(m.shadowing), part, strconv.FormatFloat(shadowSpeeds[m.shadowSpeed], 'f', -1, 64), state)))
+		s.WriteString("\n\n")
+		s.WriteString(interlinear(sentence.tokens, from, to, m.width-4))
+		s.WriteString("\n")
+		if sentence.translation != "" {
+			s.WriteString(labelStyle.Render("Meaning: "))
+			s.WriteString(valueStyle.Render(sentence.translation))
+			s.WriteString("\n\n")
+		}
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	if len(m.shadowing) == 0 {
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Back"})))
+		return s.String()
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Pause"}, helpEntry{m.keys.PrevSentence, "Previous part"}, helpEntry{m.keys.NextSentence, "Next part"}, helpEntry{m.keys.Slower, "Slower"}, helpEntry{m.keys.Faster, "Faster"}, helpEntry{m.keys.Up, "Previous sentence"}, helpEntry{m.keys.Down, "Next sentence"}, helpEntry{m.keys.Back, "Back"})))
+	return s.String()
+}
+
+// interlinear renders a sentence on one line, or several lines of at most width, with
+// the glosses of its words on the line below. The words of tokens from to to are marked.
+func interlinear(tokens []export.Token, from, to, width int) string {
+	var s, top, bottom strings.Builder
+	lineWidth := 0
+	flush := func() {
+		s.WriteString(strings.TrimRight(top.String(), " "))
+		s.WriteString("\n")
+		s.WriteString(strings.TrimRight(bottom.String(), " "))
+		s.WriteString("\n")
+		top.Reset()
+		bottom.Reset()
+		lineWidth = 0
+	}
+	for i, tok := range tokens {
+		text := strings.ReplaceAll(tok.Text, "\n", " ")
+		w := max(lipgloss.Width(text), lipgloss.Width(tok.Gloss))
+		if width > 0 && lineWidth > 0 && lineWidth+w > width {
+			flush()
+			text = strings.TrimLeft(text, " ")
+			w = max(lipgloss.Width(text), lipgloss.Width(tok.Gloss))
+		}
+		style := normalStyle
+		if tok.Word && i >= from && i < to {
+			style = selectedStyle
+		}
+		top.WriteString(style.Render(text))
+		top.WriteString(strings.Repeat(" ", w-lipgloss.Width(text)))
+		bottom.WriteString(labelStyle.Render(tok.Gloss))
+		bottom.WriteString(strings.Repeat(" ", w-lipgloss.Width(tok.Gloss)))
+		lineWidth += w
+	}
+	flush()
+	return s.String()
+}
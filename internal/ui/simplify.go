@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// simplifyLevels are the targets of the simplification mode: three paraphrases (empty)
+// or a rewrite at one of the CEFR levels below C2.
+var simplifyLevels = append([]string{""}, cefrLevels[:len(cefrLevels)-1]...)
+
+// Target selected when the simplification mode is first opened: A2
+const defaultSimplifyLevel = 2
+
+// simplificationResult represents the outcome of simplifying a sentence.
+type simplificationResult struct {
+	simplification *translator.Simplification
+	err            error
+}
+
+// simplifySentence creates a tea.Cmd that rewrites a target-language sentence.
+func simplifySentence(cfg config.Config, userLang, targetLang, sentence, level string) tea.Cmd {
+	return func() tea.Msg {
+		req := translator.Request{Sentence: sentence, UserLang: userLang, TargetLang: targetLang}
+		simplification, err := translate.Simplify(context.Background(), cfg, sentence, level, req)
+		return simplificationResult{simplification: simplification, err: err}
+	}
+}
+
+// simplifyTarget describes a simplification target for display.
+func simplifyTarget(level string) string {
+	if level == "" {
+		return "3 paraphrases"
+	}
+	return "Simplify to " + level
+}
+
+// openSimplify shows the simplification mode for the sentence typed so far.
+func (m model) openSimplify() (tea.Model, tea.Cmd) {
+	m.navigate(stateSimplify)
+	m.simplification = nil
+	m.err = nil
+	m.status = ""
+	return m, nil
+}
+
+// updateSimplify handles key presses in the simplification mode.
+func (m model) updateSimplify(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if text, ok := typedText(msg); ok {
+		m.input += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Debug):
+		return m.openDebugView()
+	case key.Matches(msg, m.keys.Back):
+		m.pop()
+		m.loading = false
+		return m, nil
+	case key.Matches(msg, m.keys.PrevSentence):
+		m.simplifyLevel = (m.simplifyLevel + len(simplifyLevels) - 1) % len(simplifyLevels)
+	case key.Matches(msg, m.keys.NextSentence):
+		m.simplifyLevel = (m.simplifyLevel + 1) % len(simplifyLevels)
+	case key.Matches(msg, m.keys.Select):
+		sentence := strings.TrimSpace(m.input)
+		if sentence == "" || m.loading {
+			return m, nil
+		}
+		m.loading = true
+		m.loadingStep = "Simplifying"
+		m.deadline = time.Time{}
+		m.err = nil
+		return m, tea.Batch(simplifySentence(m.cfg, m.userLang, m.targetLang, sentence, simplifyLevels[m.simplifyLevel]), m.spinner.Tick)
+	case msg.Type == tea.KeyBackspace:
+		if len(m.input) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.input)
+			m.input = m.input[:len(m.input)-size]
+		}
+	}
+	return m, nil
+}
+
+// handleSimplificationResult shows the rewrites of the sentence.
+func (m model) handleSimplificationResult(msg simplificationResult) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	m.simplification = msg.simplification
+	return m, nil
+}
+
+// viewSimplify renders the sentence, the selected target and the rewrites.
+func (m model) viewSimplify() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Simplify Or Paraphrase:"))
+	s.WriteString("\n\n")
+	s.WriteString(fmt.Sprintf("%s sentence: %s█\n\n", m.getLangName(m.targetLang), m.input))
+	s.WriteString(labelStyle.Render("Target: "))
+	s.WriteString(selectedStyle.Render("◀ " + simplifyTarget(simplifyLevels[m.simplifyLevel]) + " ▶"))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.loading:
+		s.WriteString(labelStyle.Render(m.loadingView()))
+		s.WriteString("\n\n")
+	case m.err != nil:
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	case m.simplification != nil:
+		for i, r := range m.simplification.Rewrites {
+			s.WriteString(labelStyle.Render(fmt.Sprintf("%d. [%s] ", i+1, r.Level)))
+			s.WriteString(successStyle.Render(r.Text))
+			s.WriteString("\n")
+			s.WriteString(normalStyle.Render("   " + r.Changes))
+			s.WriteString("\n\n")
+		}
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Rewrite"}, helpEntry{m.keys.PrevSentence, "Easier/paraphrase"}, helpEntry{m.keys.NextSentence, "Harder"}, helpEntry{m.keys.Back, "Back"}, helpEntry{m.keys.Help, "Help"})))
+	return s.String()
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/spellcheck"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// spellDelay is how long typing pauses before the sentence input is spellchecked.
+const spellDelay = 400 * time.Millisecond
+
+// spellTick spellchecks the sentence input unless it changed again meanwhile.
+type spellTick struct {
+	gen int
+}
+
+// spellResult carries the misspelled words of the sentence input.
+type spellResult struct {
+	gen   int
+	words []string
+	err   error
+}
+
+// checkSpelling creates a tea.Cmd that finds the words of text in none of the
+// dictionaries of langs.
+func checkSpelling(text string, langs []string, gen int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		words, err := spellcheck.Misspelled(ctx, text, langs...)
+		return spellResult{gen: gen, words: words, err: err}
+	}
+}
+
+// scheduleSpellcheck spellchecks the sentence input once typing pauses.
+func (m model) scheduleSpellcheck(cmd tea.Cmd) (model, tea.Cmd) {
+	m.spellGen++
+	if !m.cfg.Spellcheck || m.input == "" {
+		m.misspelled = nil
+		return m, cmd
+	}
+	gen := m.spellGen
+	return m, tea.Batch(cmd, tea.Tick(spellDelay, func(time.Time) tea.Msg { return spellTick{gen: gen} }))
+}
+
+// handleSpellTick spellchecks the sentence input in the language it is guessed to be
+// in, or in both languages of the pair if that is unclear.
+func (m model) handleSpellTick(msg spellTick) (tea.Model, tea.Cmd) {
+	if msg.gen != m.spellGen || m.state != stateInputSentence || m.input == "" {
+		return m, nil
+	}
+	langs := []string{m.userLang, m.targetLang}
+	if lang := translator.GuessLanguage(m.input, langs...); lang != "" {
+		langs = []string{lang}
+	}
+	return m, checkSpelling(m.input, langs, m.spellGen)
+}
+
+// handleSpellResult underlines the misspelled words. Without a dictionary there are
+// simply no hints.
+func (m model) handleSpellResult(msg spellResult) (tea.Model, tea.Cmd) {
+	if msg.gen != m.spellGen {
+		return m, nil
+	}
+	if msg.err != nil {
+		slog.Debug("spellcheck skipped", "error", msg.err)
+		m.misspelled = nil
+		return m, nil
+	}
+	m.misspelled = msg.words
+	return m, nil
+}
+
+// viewSpelling renders the sentence input with its misspelled words underlined.
+func (m model) viewSpelling() string {
+	if len(m.misspelled) == 0 {
+		return m.input
+	}
+	var s strings.Builder
+	word := func(w string) {
+		if slices.Contains(m.misspelled, w) {
+			s.WriteString(misspelledStyle.Render(w))
+		} else {
+			s.WriteString(w)
+		}
+	}
+	start := 0
+	for i, r := range m.input {
+		if isWordRune(r) {
+			continue
+		}
+		word(m.input[start:i])
+		s.WriteRune(r)
+		start = i + len(string(r))
+	}
+	word(m.input[start:])
+	return s.String()
+}
+
+// viewMisspelled lists the misspelled words of the sentence input, for terminals that
+// do not show underlines.
+func (m model) viewMisspelled() string {
+	if len(m.misspelled) == 0 {
+		return ""
+	}
+	return warningStyle.Render("Check the spelling of: " + strings.Join(m.misspelled, ", "))
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/brittaao/translation-tui/internal/translate"
+)
+
+// withStatusBar appends the status bar to a screen, at the bottom of the terminal if its height is known.
+func (m model) withStatusBar(content string) string {
+	if banner := m.viewOfflineBanner(); banner != "" {
+		content += "\n\n" + banner
+	}
+	bar := m.viewStatusBar()
+	if gap := m.height - lipgloss.Height(content) - lipgloss.Height(bar); gap > 0 {
+		content += strings.Repeat("\n", gap)
+	}
+	return content + "\n" + bar
+}
+
+// viewStatusBar renders the breadcrumb of screens, the language pair, active provider
+// and model, connectivity, cache hit, pending request, time left of a study session and the estimated cost of the session.
+func (m model) viewStatusBar() string {
+	parts := []string{m.breadcrumb()}
+	if m.userLang != "" {
+		pair := m.userLang
+		if m.targetLang != "" {
+			pair += " ↔ " + m.targetLang
+		}
+		parts = append(parts, pair)
+	}
+
+	models := translate.ConfiguredModels(m.cfg)
+	modelInfo := models.Translation
+	if models.Analysis != "" && models.Analysis != models.Translation {
+		modelInfo += " / " + models.Analysis
+	}
+	parts = append(parts, fmt.Sprintf("%s: %s", m.cfg.Provider, modelInfo))
+
+	switch {
+	case m.cfg.Network.ReplayDir != "":
+		parts = append(parts, "○ replay")
+	case m.degraded != "", translate.Offline():
+		parts = append(parts, "○ offline")
+	default:
+		parts = append(parts, "● online")
+	}
+	if m.cached {
+		parts = append(parts, "cache hit")
+	}
+	if m.cfg.Private {
+		parts = append(parts, "private")
+	}
+	if n := countPending(m.queue); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d queued", n))
+	}
+	if left := m.studyTimeLeft(); left != "" {
+		parts = append(parts, left)
+	}
+	if m.loading {
+		parts = append(parts, m.spinner.View()+" "+stepView(m.loadingStep, m.deadline))
+	}
+
+	usage := translate.SessionUsage()
+	parts = append(parts, fmt.Sprintf("$%.4f (%d calls)", usage.Cost, usage.Calls))
+
+	style := statusBarStyle
+	if m.width > 0 {
+		style = style.Width(m.width)
+	}
+	return style.Render(strings.Join(parts, " | "))
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// stressSetting is the settings row of the stress marks, also toggled with their own key.
+var stressSetting = setting{
+	name: "Stress marks", key: "stress_marks",
+	value: func(m model) string { return onOff(m.cfg.StressMarks) },
+	change: func(m *model, dir int) any {
+		m.cfg.StressMarks = !m.cfg.StressMarks
+		return m.cfg.StressMarks
+	},
+}
+
+// toggleStress shows or hides the stress marks on the sentence of the results.
+func (m model) toggleStress() (tea.Model, tea.Cmd) {
+	value := stressSetting.change(&m, 1)
+	m.status = normalStyle.Render("Stress marks: " + onOff(m.cfg.StressMarks))
+	return m, saveSetting(m.cfg.Path, stressSetting, value)
+}
+
+// withStress returns sentence with the stress marks of the analyzed words if it is the
+// foreign-language side of the result and they are shown.
+func (m model) withStress(sentence string) string {
+	if !m.cfg.StressMarks || sentence == "" || sentence != m.foreignSentence() {
+		return sentence
+	}
+	return markStress(sentence, m.wordAnalysis)
+}
+
+// markStress replaces the words of sentence with their stressed forms, in the order they
+// were analyzed. Words are only matched whole, unless the sentence is written without
+// spaces as in Japanese, and those not found are skipped.
+func markStress(sentence string, words []translator.WordInfo) string {
+	var b strings.Builder
+	whole := strings.ContainsRune(sentence, ' ')
+	rest := sentence
+	for _, w := range words {
+		if w.Stress == "" || w.Grouped() {
+			continue
+		}
+		i := strings.Index(rest, w.WordInTargetLang)
+		if whole {
+			i = indexWord(rest, w.WordInTargetLang)
+		}
+		if i < 0 || w.WordInTargetLang == "" {
+			continue
+		}
+		b.WriteString(rest[:i])
+		b.WriteString(w.Stress)
+		rest = rest[i+len(w.WordInTargetLang):]
+	}
+	b.WriteString(rest)
+	return b.String()
+}
+
+// indexWord returns the index of the first occurrence of word in s that is not part of
+// a longer word, or -1 if there is none.
+func indexWord(s, word string) int {
+	if word == "" {
+		return -1
+	}
+	for start := 0; ; {
+		i := strings.Index(s[start:], word)
+		if i < 0 {
+			return -1
+		}
+		i += start
+		end := i + len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return i
+		}
+		start = i + 1
+	}
+}
+
+// isWordRune reports whether r belongs to a word, as letters, digits and combining marks do.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r)
+}
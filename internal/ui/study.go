@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Length of a study session in minutes: the default, the bounds and the step it is
+// changed by
+const (
+	defaultStudyMinutes = 15
+	minStudyMinutes     = 5
+	maxStudyMinutes     = 60
+	studyMinutesStep    = 5
+)
+
+// studyActivity is an activity of a study session, one of the practice modes.
+type studyActivity struct {
+	name  string
+	state appState
+	open  func(model) (model, tea.Cmd)
+}
+
+// studyActivities lists the activities of a study session in the order they are done.
+var studyActivities = []studyActivity{
+	{"Review due cards", stateReview, model.openReview},
+	{"Cloze", stateCloze, model.openCloze},
+	{"Conjugation", stateConjugation, model.openConjugation},
+	{"Listening quiz", stateListening, model.openListening},
+}
+
+// studyScore counts the answers of an activity of a study session.
+type studyScore struct {
+	right, total int
+}
+
+// studySession is a study session being set up, under way or over. It is under way
+// from its start until the time is up, its activities are done or one is left with Back.
+type studySession struct {
+	minutes     int
+	started     time.Time
+	end         time.Time // Zero until the session starts
+	current     int       // Of studyActivities; len(studyActivities) once over
+	activityEnd time.Time // When the current activity gives way to the next
+	scores      []studyScore
+}
+
+// openStudy shows the setup of a study session.
+func (m model) openStudy() (model, tea.Cmd) {
+	m.navigate(stateStudy)
+	m.study = studySession{minutes: defaultStudyMinutes}
+	m.status = ""
+	return m, nil
+}
+
+// studying reports whether a study session is under way on the current screen.
+func (m model) studying() bool {
+	s := m.study
+	return !s.end.IsZero() && s.current < len(studyActivities) && m.state == studyActivities[s.current].state
+}
+
+// recordStudy counts answers of the current activity of a study session, if under way.
+func (m *model) recordStudy(right, total int) {
+	if !m.studying() {
+		return
+	}
+	// Cloned so that copies of the model never share the scores
+	m.study.scores = slices.Clone(m.study.scores)
+	m.study.scores[m.study.current].right += right
+	m.study.scores[m.study.current].total += total
+}
+
+// studyTurn moves a study session on to its next activity if the current one is done,
+// having run out of items, or its time is up. The activities call it before their
+// next item, reporting whether it replaced them.
+func (m model) studyTurn(done bool) (model, tea.Cmd, bool) {
+	if !m.studying() || (!done && time.Now().Before(m.study.activityEnd)) {
+		return m, nil, false
+	}
+	m.pop()
+	next, cmd := m.nextStudyActivity()
+	return next, cmd, true
+}
+
+// nextStudyActivity opens the next activity of the study session with an even share of
+// the time left, or shows the summary if the time is up or all were done.
+func (m model) nextStudyActivity() (model, tea.Cmd) {
+	m.study.current++
+	left := len(studyActivities) - m.study.current
+	remaining := time.Until(m.study.end)
+	if left <= 0 || remaining <= 0 {
+		m.study.current = len(studyActivities)
+		m.status = ""
+		return m, nil
+	}
+	m.study.activityEnd = time.Now().Add(remaining / time.Duration(left))
+	return studyActivities[m.study.current].open(m)
+}
+
+// updateStudy handles key presses in the setup of a study session, which sets its
+// length and starts it, and in its summary.
+func (m model) updateStudy(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		m.study = studySession{}
+		m.pop()
+	case !m.study.end.IsZero():
+	case key.Matches(msg, m.keys.PrevSentence):
+		m.study.minutes = max(m.study.minutes-studyMinutesStep, minStudyMinutes)
+	case key.Matches(msg, m.keys.NextSentence):
+		m.study.minutes = min(m.study.minutes+studyMinutesStep, maxStudyMinutes)
+	case key.Matches(msg, m.keys.Select):
+		m.study.started = time.Now()
+		m.study.end = m.study.started.Add(time.Duration(m.study.minutes) * time.Minute)
+		m.study.current = -1
+		m.study.scores = make([]studyScore, len(studyActivities))
+		return m.nextStudyActivity()
+	}
+	return m, nil
+}
+
+// studyTimeLeft describes the time left of a study session under way, such as
+// "study: 12 min left", or is empty if none is.
+func (m model) studyTimeLeft() string {
+	if !m.studying() {
+		return ""
+	}
+	return fmt.Sprintf("study: %d min left", int(max(time.Until(m.study.end), 0).Round(time.Minute).Minutes()))
+}
+
+// viewStudy renders the setup of a study session, or its summary with the accuracy of
+// each activity once it is over.
+func (m model) viewStudy() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Study Session: " + m.getLangName(m.targetLang)))
+	s.WriteString("\n\n")
+
+	if m.study.end.IsZero() {
+		s.WriteString(labelStyle.Render("Length: "))
+		s.WriteString(valueStyle.Render(fmt.Sprintf("◀ %d minutes ▶", m.study.minutes)))
+		s.WriteString("\n\n")
+		s.WriteString(normalStyle.Render("The time is shared among these activities, in turn, leaving out those with nothing to practice:"))
+		s.WriteString("\n")
+		for _, a := range studyActivities {
+			s.WriteString(normalStyle.Render("  " + a.name))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		if m.status != "" {
+			s.WriteString(m.status)
+			s.WriteString("\n\n")
+		}
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.PrevSentence, "Shorter"}, helpEntry{m.keys.NextSentence, "Longer"}, helpEntry{m.keys.Select, "Start"}, helpEntry{m.keys.Back, "Back"})))
+		return s.String()
+	}
+
+	elapsed := min(time.Since(m.study.started), m.study.end.Sub(m.study.started))
+	s.WriteString(successStyle.Render(fmt.Sprintf("Session over after %d minutes.", int(elapsed.Round(time.Minute).Minutes()))))
+	s.WriteString("\n\n")
+	var right, total int
+	for i, a := range studyActivities {
+		score := m.study.scores[i]
+		s.WriteString(labelStyle.Render(fmt.Sprintf("%-18s", a.name)))
+		if score.total == 0 {
+			s.WriteString(normalStyle.Render("not practiced"))
+		} else {
+			s.WriteString(valueStyle.Render(fmt.Sprintf("%d of %d right (%d%%)", score.right, score.total, percent(score.right, score.total))))
+		}
+		s.WriteString("\n")
+		right += score.right
+		total += score.total
+	}
+	s.WriteString("\n")
+	s.WriteString(labelStyle.Render(fmt.Sprintf("%-18s", "All")))
+	s.WriteString(valueStyle.Render(fmt.Sprintf("%d of %d right (%d%%)", right, total, percent(right, total))))
+	s.WriteString("\n\n")
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Back"})))
+	return s.String()
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/brittaao/translation-tui/internal/config"
+)
+
+// theme holds the colors of all styles.
+type theme struct {
+	title      lipgloss.TerminalColor
+	selected   lipgloss.TerminalColor
+	selectedBg lipgloss.TerminalColor
+	normal     lipgloss.TerminalColor
+	err        lipgloss.TerminalColor
+	success    lipgloss.TerminalColor
+	warning    lipgloss.TerminalColor
+	label      lipgloss.TerminalColor
+	value      lipgloss.TerminalColor
+
+	// Colors of nouns by grammatical gender
+	masculine lipgloss.TerminalColor
+	feminine  lipgloss.TerminalColor
+	neuter    lipgloss.TerminalColor
+}
+
+// Theme used when none is configured
+const defaultTheme = "auto"
+
+// darkTheme is the original palette, made for dark terminal backgrounds.
+var darkTheme = theme{
+	title:      lipgloss.Color("51"),
+	selected:   lipgloss.Color("15"),
+	selectedBg: lipgloss.Color("39"),
+	normal:     lipgloss.Color("231"),
+	err:        lipgloss.Color("196"),
+	success:    lipgloss.Color("46"),
+	warning:    lipgloss.Color("214"),
+	label:      lipgloss.Color("87"),
+	value:      lipgloss.Color("231"),
+	masculine:  lipgloss.Color("75"),
+	feminine:   lipgloss.Color("211"),
+	neuter:     lipgloss.Color("114"),
+}
+
+// lightTheme uses darker colors that stay readable on light backgrounds.
+var lightTheme = theme{
+	title:      lipgloss.Color("25"),
+	selected:   lipgloss.Color("15"),
+	selectedBg: lipgloss.Color("25"),
+	normal:     lipgloss.Color("235"),
+	err:        lipgloss.Color("160"),
+	success:    lipgloss.Color("28"),
+	warning:    lipgloss.Color("130"),
+	label:      lipgloss.Color("31"),
+	value:      lipgloss.Color("16"),
+	masculine:  lipgloss.Color("26"),
+	feminine:   lipgloss.Color("162"),
+	neuter:     lipgloss.Color("28"),
+}
+
+// themeNames lists the built-in themes in the order they are cycled through.
+var themeNames = []string{"auto", "dark", "light", "solarized", "high-contrast"}
+
+// builtinTheme returns the built-in theme with the given name.
+func builtinTheme(name string) (theme, bool) {
+	switch name {
+	case "auto":
+		return adaptiveTheme(lightTheme, darkTheme), true
+	case "dark":
+		return darkTheme, true
+	case "light":
+		return lightTheme, true
+	case "solarized":
+		return theme{
+			title:      lipgloss.Color("#268bd2"),
+			selected:   lipgloss.Color("#fdf6e3"),
+			selectedBg: lipgloss.Color("#268bd2"),
+			normal:     lipgloss.AdaptiveColor{Light: "#657b83", Dark: "#839496"},
+			err:        lipgloss.Color("#dc322f"),
+			success:    lipgloss.Color("#859900"),
+			warning:    lipgloss.Color("#b58900"),
+			label:      lipgloss.Color("#2aa198"),
+			value:      lipgloss.AdaptiveColor{Light: "#586e75", Dark: "#93a1a1"},
+			masculine:  lipgloss.Color("#268bd2"),
+			feminine:   lipgloss.Color("#d33682"),
+			neuter:     lipgloss.Color("#859900"),
+		}, true
+	case "high-contrast":
+		fg := lipgloss.AdaptiveColor{Light: "0", Dark: "15"}
+		return theme{
+			title:      fg,
+			selected:   lipgloss.Color("0"),
+			selectedBg: lipgloss.Color("11"),
+			normal:     fg,
+			err:        lipgloss.AdaptiveColor{Light: "1", Dark: "9"},
+			success:    lipgloss.AdaptiveColor{Light: "2", Dark: "10"},
+			warning:    lipgloss.AdaptiveColor{Light: "5", Dark: "13"},
+			label:      fg,
+			value:      fg,
+			masculine:  lipgloss.AdaptiveColor{Light: "4", Dark: "12"},
+			feminine:   lipgloss.AdaptiveColor{Light: "1", Dark: "9"},
+			neuter:     lipgloss.AdaptiveColor{Light: "2", Dark: "10"},
+		}, true
+	}
+	return theme{}, false
+}
+
+// adaptiveTheme picks each color from light or dark depending on the terminal background.
+func adaptiveTheme(light, dark theme) theme {
+	pick := func(l, d lipgloss.TerminalColor) lipgloss.TerminalColor {
+		return lipgloss.AdaptiveColor{Light: string(l.(lipgloss.Color)), Dark: string(d.(lipgloss.Color))}
+	}
+	return theme{
+		title:      pick(light.title, dark.title),
+		selected:   pick(light.selected, dark.selected),
+		selectedBg: pick(light.selectedBg, dark.selectedBg),
+		normal:     pick(light.normal, dark.normal),
+		err:        pick(light.err, dark.err),
+		success:    pick(light.success, dark.success),
+		warning:    pick(light.warning, dark.warning),
+		label:      pick(light.label, dark.label),
+		value:      pick(light.value, dark.value),
+		masculine:  pick(light.masculine, dark.masculine),
+		feminine:   pick(light.feminine, dark.feminine),
+		neuter:     pick(light.neuter, dark.neuter),
+	}
+}
+
+// newTheme builds the named theme with the color overrides of the [theme] config section.
+func newTheme(name string, cfg config.ThemeConfig) (theme, error) {
+	t, ok := builtinTheme(name)
+	if !ok {
+		return theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(themeNames, ", "))
+	}
+	overrides := []struct {
+		color string
+		field *lipgloss.TerminalColor
+	}{
+		{cfg.Title, &t.title},
+		{cfg.Selected, &t.selected},
+		{cfg.SelectedBackground, &t.selectedBg},
+		{cfg.Normal, &t.normal},
+		{cfg.Error, &t.err},
+		{cfg.Success, &t.success},
+		{cfg.Warning, &t.warning},
+		{cfg.Label, &t.label},
+		{cfg.Value, &t.value},
+		{cfg.Masculine, &t.masculine},
+		{cfg.Feminine, &t.feminine},
+		{cfg.Neuter, &t.neuter},
+	}
+	for _, o := range overrides {
+		if o.color != "" {
+			*o.field = lipgloss.Color(o.color)
+		}
+	}
+	return t, nil
+}
+
+// applyConfiguredTheme applies the theme selected in the config and returns its name.
+func applyConfiguredTheme(cfg config.ThemeConfig) (string, error) {
+	name := cfg.Name
+	if name == "" {
+		name = defaultTheme
+	}
+	t, err := newTheme(name, cfg)
+	if err != nil {
+		return "", err
+	}
+	t.apply()
+	return name, nil
+}
+
+// nextTheme returns the name of the built-in theme after name.
+func nextTheme(name string) string {
+	i := slices.Index(themeNames, name)
+	return themeNames[(i+1)%len(themeNames)]
+}
+
+// switchTheme changes to the next built-in theme, keeping the configured color overrides.
+func (m model) switchTheme() (tea.Model, tea.Cmd) {
+	name := nextTheme(m.theme)
+	t, err := newTheme(name, m.cfg.Theme)
+	if err != nil {
+		return m, nil
+	}
+	t.apply()
+	m.theme = name
+	m.status = normalStyle.Render("Theme: " + name)
+	return m, nil
+}
+
+// apply sets the colors of all styles.
+func (t theme) apply() {
+	titleStyle = titleStyle.Foreground(t.title)
+	selectedStyle = selectedStyle.Foreground(t.selected).Background(t.selectedBg)
+	normalStyle = normalStyle.Foreground(t.normal)
+	errorStyle = errorStyle.Foreground(t.err)
+	successStyle = successStyle.Foreground(t.success)
+	warningStyle = warningStyle.Foreground(t.warning)
+	labelStyle = labelStyle.Foreground(t.label)
+	valueStyle = valueStyle.Foreground(t.value)
+	statusBarStyle = statusBarStyle.Foreground(t.label)
+	masculineStyle = masculineStyle.Foreground(t.masculine)
+	feminineStyle = feminineStyle.Foreground(t.feminine)
+	neuterStyle = neuterStyle.Foreground(t.neuter)
+	misspelledStyle = misspelledStyle.Foreground(t.err)
+}
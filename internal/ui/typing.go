@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/drill"
+)
+
+// openTyping starts the typing drill on the saved sentences of the language learned.
+func (m model) openTyping() (model, tea.Cmd) {
+	m.navigate(stateTyping)
+	m.practice = nil
+	m.practiceIndex = 0
+	m.practiceInput = ""
+	m.typingScore = nil
+	m.typingStarted = time.Time{}
+	m.typingChars, m.typingErrors, m.typingTime = 0, 0, 0
+	m.status = ""
+	return m, loadPracticeSentences(m.targetLang)
+}
+
+// updateTyping handles key presses in the typing drill. The clock starts with the first
+// key typed and stops when Select checks the sentence, which then goes on to the next.
+func (m model) updateTyping(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	done := m.practiceIndex >= len(m.practice)
+	if text, ok := typedText(msg); ok && !done && m.typingScore == nil {
+		if m.typingStarted.IsZero() {
+			m.typingStarted = time.Now()
+		}
+		m.practiceInput += text
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Back):
+		return m.back()
+	case done:
+	case key.Matches(msg, m.keys.Select) && m.typingScore != nil:
+		m.practiceIndex++
+		m.practiceInput = ""
+		m.typingScore = nil
+		m.typingStarted = time.Time{}
+	case key.Matches(msg, m.keys.Select) && m.practiceInput != "":
+		score := drill.CompareChars(m.practice[m.practiceIndex].text, m.practiceInput)
+		m.typingScore = &score
+		m.typingElapsed = time.Since(m.typingStarted)
+		m.typingChars += score.Chars
+		m.typingErrors += score.Errors
+		m.typingTime += m.typingElapsed
+	case msg.Type == tea.KeyBackspace && m.typingScore == nil:
+		if len(m.practiceInput) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.practiceInput)
+			m.practiceInput = m.practiceInput[:len(m.practiceInput)-size]
+		}
+	}
+	return m, nil
+}
+
+// viewTyping renders the typing drill: the sentence to copy with its special characters,
+// what was typed, and once checked, the accuracy, speed and characters typed wrong.
+func (m model) viewTyping() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Typing Drill: " + m.getLangName(m.targetLang)))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.practice == nil:
+	case m.practiceIndex >= len(m.practice) && len(m.practice) == 0:
+		s.WriteString(normalStyle.Render("No saved sentences yet. Save words from the results to practice their sentences."))
+		s.WriteString("\n\n")
+	case m.practiceIndex >= len(m.practice):
+		accuracy := drill.TypingScore{Chars: m.typingChars, Errors: m.typingErrors}.Accuracy()
+		s.WriteString(successStyle.Render(fmt.Sprintf("Done: %d%% accuracy at %d words per minute.", accuracy, wordsPerMinute(m.typingChars, m.typingTime))))
+		s.WriteString("\n\n")
+	default:
+		current := m.practice[m.practiceIndex]
+		s.WriteString(normalStyle.Render(fmt.Sprintf("Sentence %d of %d", m.practiceIndex+1, len(m.practice))))
+		s.WriteString("\n\n")
+		s.WriteString(labelStyle.Render("Copy:  "))
+		s.WriteString(valueStyle.Render(current.text))
+		s.WriteString("\n")
+		s.WriteString(labelStyle.Render("Typed: "))
+		s.WriteString(typedAgainst(current.text, m.practiceInput))
+		if m.typingScore == nil {
+			s.WriteString("█")
+		}
+		s.WriteString("\n\n")
+		if special := drill.SpecialChars(current.text); len(special) > 0 && m.typingScore == nil {
+			s.WriteString(normalStyle.Render("Special characters: " + strings.Join(special, " ")))
+			s.WriteString("\n\n")
+		}
+		if score := m.typingScore; score != nil {
+			s.WriteString(normalStyle.Render(fmt.Sprintf("%d%% accuracy · %d words per minute · %.1f s", score.Accuracy(), wordsPerMinute(score.Chars, m.typingElapsed), m.typingElapsed.Seconds())))
+			s.WriteString("\n")
+			if len(score.Mistakes) > 0 {
+				s.WriteString(errorStyle.Render("Mistakes: " + describeMistakes(score.Mistakes)))
+				s.WriteString("\n")
+			}
+			if current.translation != "" {
+				s.WriteString(labelStyle.Render("Meaning: "))
+				s.WriteString(valueStyle.Render(current.translation))
+				s.WriteString("\n")
+			}
+			s.WriteString("\n")
+		}
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	switch {
+	case m.practiceIndex >= len(m.practice):
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Back, "Back"})))
+	case m.typingScore == nil:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Check"}, helpEntry{m.keys.Back, "Back"}) + " | Type the sentence"))
+	default:
+		s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Select, "Next sentence"}, helpEntry{m.keys.Back, "Back"})))
+	}
+	return s.String()
+}
+
+// typedAgainst renders typed text with each character that differs from the one
+// expected at its place marked as an error.
+func typedAgainst(expected, typed string) string {
+	want := []rune(expected)
+	var s strings.Builder
+	for i, r := range []rune(typed) {
+		if i < len(want) && want[i] == r {
+			s.WriteString(successStyle.Render(string(r)))
+		} else {
+			s.WriteString(errorStyle.Render(string(r)))
+		}
+	}
+	return s.String()
+}
+
+// describeMistakes lists characters typed wrong, such as "ß typed as s" or "é left out".
+func describeMistakes(mistakes []drill.CharMistake) string {
+	parts := make([]string, len(mistakes))
+	for i, mistake := range mistakes {
+		switch {
+		case mistake.Got == "":
+			parts[i] = fmt.Sprintf("%q left out", mistake.Want)
+		case mistake.Want == "":
+			parts[i] = fmt.Sprintf("%q in excess", mistake.Got)
+		default:
+			parts[i] = fmt.Sprintf("%q typed as %q", mistake.Want, mistake.Got)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// wordsPerMinute returns the typing speed of chars characters in the time, counting
+// five characters as a word.
+func wordsPerMinute(chars int, elapsed time.Duration) int {
+	if elapsed <= 0 {
+		return 0
+	}
+	return int(float64(chars) / 5 / elapsed.Minutes())
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndo is the number of edits of the sentence input that can be undone.
+const maxUndo = 100
+
+// inputEdit is the kind of the last edit of the sentence input, which decides whether the
+// next one continues its undo step.
+type inputEdit int
+
+const (
+	editNone inputEdit = iota
+	editTyping
+	editDeleting
+	editPasting
+)
+
+// editInput changes the sentence being typed, saving it for undo first. Typing continues
+// the last undo step until a word ends and deleting continues it too, so that undo takes
+// back a word or a run of deletions at a time; a paste is a step of its own.
+func (m *model) editInput(input string, edit inputEdit) {
+	if input == m.input {
+		return
+	}
+	if edit != m.lastEdit || edit == editPasting || (edit == editTyping && strings.HasSuffix(m.input, " ")) {
+		m.undo = append(m.undo, m.input)
+		if len(m.undo) > maxUndo {
+			m.undo = m.undo[1:]
+		}
+	}
+	m.lastEdit = edit
+	m.redo = nil
+	m.input = input
+}
+
+// forgetEdits clears the undo and redo steps once the sentence is submitted.
+func (m *model) forgetEdits() {
+	m.undo = nil
+	m.redo = nil
+	m.lastEdit = editNone
+}
+
+// undoInput takes back the last edit of the sentence input.
+func (m model) undoInput() (tea.Model, tea.Cmd) {
+	if len(m.undo) == 0 {
+		m.status = normalStyle.Render("Nothing to undo")
+		return m, nil
+	}
+	m.redo = append(m.redo, m.input)
+	m.input = m.undo[len(m.undo)-1]
+	m.undo = m.undo[:len(m.undo)-1]
+	m.lastEdit = editNone
+	m.status = ""
+	return m, nil
+}
+
+// redoInput makes the last undone edit of the sentence input again.
+func (m model) redoInput() (tea.Model, tea.Cmd) {
+	if len(m.redo) == 0 {
+		m.status = normalStyle.Render("Nothing to redo")
+		return m, nil
+	}
+	m.undo = append(m.undo, m.input)
+	m.input = m.redo[len(m.redo)-1]
+	m.redo = m.redo[:len(m.redo)-1]
+	m.lastEdit = editNone
+	m.status = ""
+	return m, nil
+}
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/brittaao/translation-tui/internal/config"
+	"github.com/brittaao/translation-tui/internal/document"
+	"github.com/brittaao/translation-tui/internal/storage"
+	"github.com/brittaao/translation-tui/internal/translate"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// How often a watched file is checked for new lines
+const watchInterval = time.Second
+
+// watchItem is a line appended to a watched file with its translation.
+type watchItem struct {
+	line   string
+	result translate.Result
+	err    error
+	done   bool
+}
+
+// watchTickMsg triggers checking the watched file for new lines.
+type watchTickMsg struct{}
+
+// watchLines carries the lines appended to the watched file since the last check.
+type watchLines struct {
+	lines []string
+	err   error
+}
+
+// watchResult represents the translation of one line of the feed.
+// It carries the update channel so the UI can keep listening for the next message.
+type watchResult struct {
+	index int
+	translate.Result
+	err     error
+	updates <-chan tea.Msg
+}
+
+// watchTick creates a tea.Cmd that emits a watchTickMsg after watchInterval.
+func watchTick() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{}
+	})
+}
+
+// readWatchedFile creates a tea.Cmd that reads the lines appended to the file.
+func readWatchedFile(tail *document.Tail) tea.Cmd {
+	return func() tea.Msg {
+		lines, err := tail.Read()
+		return watchLines{lines: lines, err: err}
+	}
+}
+
+// translateWatchLines creates a tea.Cmd that translates lines of the feed concurrently,
+// starting at feed index first. Each finished line is reported as a watchResult.
+func translateWatchLines(cfg config.Config, userLang, targetLang string, first int, lines []string) tea.Cmd {
+	updates := make(chan tea.Msg, len(lines))
+	go func() {
+		defer close(updates)
+		reqs := make([]translator.Request, len(lines))
+		for i, line := range lines {
+			reqs[i] = translator.Request{Sentence: line, UserLang: userLang, TargetLang: targetLang}
+		}
+		translate.RunBatch(context.Background(), cfg, reqs, func(i int, result translate.Result, err error) {
+			updates <- watchResult{index: first + i, Result: result, err: err, updates: updates}
+		})
+	}()
+	return waitForPipeline(updates)
+}
+
+// openWatch shows the feed of the watched file and starts checking it for new lines.
+func (m model) openWatch() (tea.Model, tea.Cmd) {
+	m.state = stateWatch
+	m.err = nil
+	return m, watchTick()
+}
+
+// handleWatchTick checks the watched file while the feed is shown.
+func (m model) handleWatchTick() (tea.Model, tea.Cmd) {
+	if m.state != stateWatch {
+		return m, nil
+	}
+	return m, readWatchedFile(m.watch)
+}
+
+// handleWatchLines adds new lines to the feed and translates them.
+func (m model) handleWatchLines(msg watchLines) (tea.Model, tea.Cmd) {
+	m.err = msg.err
+	if len(msg.lines) == 0 {
+		return m, watchTick()
+	}
+	follow := m.watchSelected >= len(m.watchFeed)-1
+	first := len(m.watchFeed)
+	for _, line := range msg.lines {
+		m.watchFeed = append(m.watchFeed, watchItem{line: line})
+	}
+	if follow {
+		m.watchSelected = len(m.watchFeed) - 1
+	}
+	return m, tea.Batch(watchTick(), translateWatchLines(m.cfg, m.userLang, m.targetLang, first, msg.lines))
+}
+
+// handleWatchResult stores the translation of a line and records it in the history.
+func (m model) handleWatchResult(msg watchResult) (tea.Model, tea.Cmd) {
+	m.watchFeed[msg.index] = watchItem{line: m.watchFeed[msg.index].line, result: msg.Result, err: msg.err, done: true}
+	if msg.err != nil {
+		return m, waitForPipeline(msg.updates)
+	}
+	m.usedModels = msg.Models
+	m.timings = msg.Timings
+	m.degraded = msg.Degraded
+	m.cached = msg.Cached
+	m.warnModelFallback(msg.Result)
+	entry := storage.HistoryEntry{
+		Time:        time.Now(),
+		UserLang:    m.userLang,
+		TargetLang:  m.targetLang,
+		Original:    msg.Original,
+		Translation: msg.Translation,
+		Words:       msg.Words,
+	}
+	m.session = append(m.session, entry)
+	return m, tea.Batch(waitForPipeline(msg.updates), recordHistory(m.cfg, entry))
+}
+
+// updateWatch handles key presses while the feed is shown.
+func (m model) updateWatch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Debug):
+		return m.openDebugView()
+	case key.Matches(msg, m.keys.Back):
+		return m.back()
+	case key.Matches(msg, m.keys.Up):
+		if m.watchSelected > 0 {
+			m.watchSelected--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.watchSelected < len(m.watchFeed)-1 {
+			m.watchSelected++
+		}
+	case key.Matches(msg, m.keys.Home):
+		m.watchSelected = 0
+	case key.Matches(msg, m.keys.End):
+		m.watchSelected = len(m.watchFeed) - 1
+	case key.Matches(msg, m.keys.Expand), key.Matches(msg, m.keys.Select):
+		m.watchExpanded = !m.watchExpanded
+	case key.Matches(msg, m.keys.Save):
+		if item, ok := m.selectedWatchItem(); ok {
+			return m, saveWordsToVocab(m.targetLang, item.result.Original, item.result.Translation, item.result.Words, m.cfg.Level)
+		}
+	case key.Matches(msg, m.keys.SaveAbove):
+		if item, ok := m.selectedWatchItem(); ok {
+			return m.saveWordsAboveLevel(item.result.Original, item.result.Translation, item.result.Words)
+		}
+	}
+	return m, nil
+}
+
+// selectedWatchItem returns the selected line of the feed if it was translated.
+func (m model) selectedWatchItem() (watchItem, bool) {
+	if m.watchSelected < 0 || m.watchSelected >= len(m.watchFeed) {
+		return watchItem{}, false
+	}
+	item := m.watchFeed[m.watchSelected]
+	return item, item.done && item.err == nil
+}
+
+// viewWatch renders the feed, scrolled to keep the selected line on the screen. The
+// selected line is followed by its word analysis when expanded.
+func (m model) viewWatch() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("Watching: " + m.watch.Path))
+	s.WriteString("\n\n")
+	s.WriteString(fmt.Sprintf("%s ↔ %s\n\n", m.getLangName(m.userLang), m.getLangName(m.targetLang)))
+	if len(m.watchFeed) == 0 {
+		s.WriteString(normalStyle.Render("Waiting for lines to be appended..."))
+		s.WriteString("\n\n")
+	}
+
+	// Each line takes about three rows; keep room for the header and the help
+	visible := max(3, (m.height-12)/3)
+	end := min(len(m.watchFeed), max(m.watchSelected+1, visible))
+	for i := max(0, end-visible); i < end; i++ {
+		item := m.watchFeed[i]
+		if i == m.watchSelected {
+			s.WriteString(selectedStyle.Render("> " + item.line))
+		} else {
+			s.WriteString(normalStyle.Render("  " + item.line))
+		}
+		s.WriteString("\n    ")
+		switch {
+		case !item.done:
+			s.WriteString(normalStyle.Render("Translating..."))
+		case item.err != nil:
+			s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", item.err)))
+		default:
+			s.WriteString(successStyle.Render(item.result.Translation))
+			if item.result.Degraded != "" {
+				s.WriteString(warningStyle.Render(" ⚠ " + item.result.Degraded))
+			}
+		}
+		s.WriteString("\n")
+		if i == m.watchSelected && m.watchExpanded && item.done && item.err == nil {
+			s.WriteString("\n")
+			writeWordAnalysis(&s, item.result.Words, m.cfg.Level, -1, nil)
+		}
+		s.WriteString("\n")
+	}
+	if m.err != nil {
+		s.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		s.WriteString("\n\n")
+	}
+	if m.status != "" {
+		s.WriteString(m.status)
+		s.WriteString("\n\n")
+	}
+	s.WriteString(normalStyle.Render(m.helpLine(helpEntry{m.keys.Up, "Up"}, helpEntry{m.keys.Down, "Down"}, helpEntry{m.keys.Expand, "Word analysis"}, helpEntry{m.keys.Save, "Save words"}, helpEntry{m.keys.Back, "Back"}, helpEntry{m.keys.Help, "Help"})))
+	return s.String()
+}
@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/brittaao/translation-tui/internal/frequency"
+	"github.com/brittaao/translation-tui/pkg/translator"
+)
+
+// wordColumns are the titles of the columns of the word analysis table.
+var wordColumns = []string{"Word", "Lemma", "POS", "Gloss", "Grammar"}
+
+// Widths of the word analysis table: the widest of the columns before Grammar, which
+// takes the rest of the line but at least minGrammarWidth, and the line width assumed
+// before the terminal size is known
+const (
+	maxColumnWidth   = 24
+	minGrammarWidth  = 20
+	defaultLineWidth = 100
+)
+
+// Lines of the Words tab around the table: the title, filter line and the details of
+// the selected word
+const wordTableChrome = 6
+
+// wordCells returns the row of a word in the analysis table. The word carries its level
+// and frequency band; the grammar its idiom mark, noun forms and false-friend note.
+func wordCells(word translator.WordInfo, known string) table.Row {
+	text := word.WordInTargetLang
+	if word.Grouped() {
+		text = "[" + text + "]"
+	}
+	if translator.AboveLevel(word.Level, known) {
+		text = "▲ " + text
+	}
+	if word.Level != "" {
+		text += " [" + word.Level + "]"
+	}
+	if band := frequency.Band(word.Rank); band != "" {
+		text += " · " + band
+	}
+
+	var grammar []string
+	if word.Idiom {
+		grammar = append(grammar, "(idiom)")
+	}
+	if noun := nounForms(word); noun != "" {
+		grammar = append(grammar, "("+noun+")")
+	}
+	if word.GrammaticalExplanation != "" {
+		grammar = append(grammar, word.GrammaticalExplanation)
+	}
+	if word.FalseFriend != "" {
+		grammar = append(grammar, "⚠ False friend: "+word.FalseFriend)
+	}
+	return table.Row{text, word.Lemma, word.PartOfSpeech, word.Gloss, strings.Join(grammar, " ")}
+}
+
+// sortKey returns the value a word is sorted by in column col.
+func sortKey(word translator.WordInfo, col int) string {
+	switch col {
+	case 0:
+		return word.WordInTargetLang
+	case 1:
+		return word.Lemma
+	case 2:
+		return word.PartOfSpeech
+	case 3:
+		return word.Gloss
+	}
+	return word.GrammaticalExplanation
+}
+
+// sortWords returns the words sorted alphabetically by column col, with empty values
+// last. A negative col keeps the order.
+func sortWords(words []translator.WordInfo, col int) []translator.WordInfo {
+	if col < 0 {
+		return words
+	}
+	sorted := slices.Clone(words)
+	slices.SortStableFunc(sorted, func(a, b translator.WordInfo) int {
+		x, y := strings.ToLower(sortKey(a, col)), strings.ToLower(sortKey(b, col))
+		if (x == "") != (y == "") {
+			return cmp.Compare(y, x) // The empty one last
+		}
+		return cmp.Compare(x, y)
+	})
+	return sorted
+}
+
+// filterWords returns the words with a cell containing filter, ignoring case.
+func filterWords(words []translator.WordInfo, filter, known string) []translator.WordInfo {
+	if filter == "" {
+		return words
+	}
+	filter = strings.ToLower(filter)
+	return slices.DeleteFunc(slices.Clone(words), func(w translator.WordInfo) bool {
+		return !slices.ContainsFunc(wordCells(w, known), func(cell string) bool {
+			return strings.Contains(strings.ToLower(cell), filter)
+		})
+	})
+}
+
+// cycleWordSort sorts the analysis by the next column, or back in sentence order after
+// the last one.
+func (m model) cycleWordSort() model {
+	m.wordSort = (m.wordSort + 1) % (len(wordColumns) + 1)
+	m.switchTab(int(tabWords))
+	m.selectFirstWord()
+	if m.wordSort == 0 {
+		m.status = normalStyle.Render("Words: " + wordOrderNames[m.wordOrder])
+	} else {
+		m.status = normalStyle.Render("Words sorted by " + strings.ToLower(wordColumns[m.wordSort-1]))
+	}
+	return m
+}
+
+// startWordFilter shows the Words tab with the filter ready for typing.
+func (m model) startWordFilter() model {
+	m.switchTab(int(tabWords))
+	m.filteringWords = true
+	return m
+}
+
+// updateWordFilter handles key presses while the filter of the word analysis is typed.
+// Enter keeps the filter, Back clears it.
+func (m model) updateWordFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if text, ok := typedText(msg); ok {
+		m.wordFilter += text
+		m.selectFirstWord()
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Select):
+		m.filteringWords = false
+	case key.Matches(msg, m.keys.Back):
+		m.filteringWords = false
+		m.wordFilter = ""
+		m.selectFirstWord()
+	case key.Matches(msg, m.keys.Up):
+		m = m.moveWordSelection(-1)
+	case key.Matches(msg, m.keys.Down):
+		m = m.moveWordSelection(1)
+	case msg.Type == tea.KeyBackspace:
+		if len(m.wordFilter) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.wordFilter)
+			m.wordFilter = m.wordFilter[:len(m.wordFilter)-size]
+			m.selectFirstWord()
+		}
+	}
+	return m, nil
+}
+
+// tableWindow returns the first of n rows of the table and how many are shown, so that
+// the selected word is visible and the table fits a page of the tab.
+func (m model) tableWindow(n int) (first, visible int) {
+	visible = n
+	if page := m.pageHeight(); page > 0 {
+		visible = min(n, max(page-wordTableChrome, minPageHeight))
+	}
+	first = min(m.wordOffset, max(n-visible, 0))
+	switch {
+	case m.wordSelected < 0:
+	case m.wordSelected < first:
+		first = m.wordSelected
+	case m.wordSelected >= first+visible:
+		first = m.wordSelected - visible + 1
+	}
+	return first, visible
+}
+
+// writeWordTable renders the word analysis as a table with the selected word
+// highlighted, followed by its full analysis and, if expanded, its details.
+func (m model) writeWordTable(s *strings.Builder) {
+	s.WriteString(labelStyle.Render("Word-by-Word Analysis:"))
+	if m.wordSort > 0 {
+		s.WriteString(normalStyle.Render(" sorted by " + strings.ToLower(wordColumns[m.wordSort-1])))
+	}
+	s.WriteString("\n")
+	if m.filteringWords || m.wordFilter != "" {
+		cursor := ""
+		if m.filteringWords {
+			cursor = "█"
+		}
+		s.WriteString(fmt.Sprintf("Filter: %s%s\n", m.wordFilter, cursor))
+	}
+	s.WriteString("\n")
+
+	words := m.analysisView()
+	if len(words) == 0 {
+		s.WriteString(normalStyle.Render("No words match the filter."))
+		s.WriteString("\n")
+		return
+	}
+	rows := make([]table.Row, len(words))
+	for i, word := range words {
+		// Right-to-left words are reordered on their own, leaving the marks around them be
+		word.WordInTargetLang, word.Lemma, word.Gloss = bidiText(word.WordInTargetLang), bidiText(word.Lemma), bidiText(word.Gloss)
+		rows[i] = wordCells(word, m.cfg.Level)
+	}
+	first, visible := m.tableWindow(len(rows))
+
+	// Cells are padded by one space on either side
+	widths := make([]int, len(wordColumns))
+	for i, title := range wordColumns {
+		widths[i] = lipgloss.Width(title)
+	}
+	for _, row := range rows {
+		for i, cell := range row[:len(row)-1] {
+			widths[i] = max(widths[i], min(lipgloss.Width(cell), maxColumnWidth))
+		}
+	}
+	lineWidth := m.width
+	if lineWidth == 0 {
+		lineWidth = defaultLineWidth
+	}
+	used := 2 * len(widths)
+	for _, w := range widths[:len(widths)-1] {
+		used += w
+	}
+	widths[len(widths)-1] = max(lineWidth-used, minGrammarWidth)
+	columns := make([]table.Column, len(wordColumns))
+	for i, title := range wordColumns {
+		columns[i] = table.Column{Title: title, Width: widths[i]}
+	}
+
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithRows(rows[first:first+visible]),
+		table.WithHeight(visible+1),
+		table.WithWidth(used+widths[len(widths)-1]),
+		table.WithStyles(table.Styles{
+			Header:   labelStyle.Padding(0, 1),
+			Cell:     lipgloss.NewStyle().Padding(0, 1),
+			Selected: selectedStyle,
+		}),
+	)
+	t.SetCursor(m.wordSelected - first)
+	s.WriteString(t.View())
+	s.WriteString("\n")
+	if len(rows) > visible {
+		s.WriteString(normalStyle.Render(fmt.Sprintf("Words %d–%d of %d", first+1, first+visible, len(rows))))
+		s.WriteString("\n")
+	}
+
+	if word, ok := m.selectedWord(); ok {
+		s.WriteString("\n")
+		writeWordLine(s, word, m.cfg.Level, true)
+		writeDeepAnalysis(s, word)
+		if m.wordExpanded {
+			m.writeDrillDown(s, word)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package wiktionary
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Largest number of rows kept of an inflection table
+const maxTableRows = 24
+
+// spaces matches runs of white space, including non-breaking spaces, collapsed in extracted text.
+var spaces = regexp.MustCompile(`[\s\x{00a0}]+`)
+
+// ParsePage extracts the entry of a language from the HTML of a Wiktionary page: the
+// paragraphs under Etymology headings, the numbered definitions under part-of-speech
+// headings and the inflection tables.
+func ParsePage(r io.Reader, language string) (*Entry, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Wiktionary page: %w", err)
+	}
+
+	e := &Entry{Language: language}
+	inLanguage, found := false, false
+	heading, navHead := "", ""
+	var walk func(n *html.Node) bool
+	walk = func(n *html.Node) bool {
+		if n.Type == html.ElementNode {
+			if hasClass(n, "mw-editsection") || n.DataAtom == atom.Style || n.DataAtom == atom.Script {
+				return true
+			}
+			switch n.DataAtom {
+			case atom.H2:
+				if inLanguage {
+					return false // The next language starts
+				}
+				inLanguage = text(n) == language
+				found = found || inLanguage
+				return true
+			case atom.H3, atom.H4, atom.H5:
+				heading = text(n)
+				return true
+			}
+			if inLanguage {
+				switch {
+				case hasClass(n, "NavHead"):
+					navHead = text(n)
+					return true
+				case n.DataAtom == atom.P && strings.HasPrefix(heading, "Etymology"):
+					if t := text(n); t != "" {
+						e.Etymology = append(e.Etymology, t)
+					}
+					return true
+				case n.DataAtom == atom.Ol:
+					for li := n.FirstChild; li != nil; li = li.NextSibling {
+						if li.DataAtom == atom.Li {
+							if t := text(li); t != "" {
+								e.Senses = append(e.Senses, Sense{PartOfSpeech: heading, Definition: t})
+							}
+						}
+					}
+					return true
+				case n.DataAtom == atom.Table && (hasClass(n, "inflection-table") || navHead != "" || isInflectionHeading(heading)):
+					if t := parseTable(n, navHead, heading); len(t.Rows) > 0 {
+						e.Tables = append(e.Tables, t)
+					}
+					navHead = ""
+					return true
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if !walk(c) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(doc)
+
+	if !found {
+		return nil, fmt.Errorf("%w in %s", ErrNotFound, language)
+	}
+	return e, nil
+}
+
+// isInflectionHeading reports whether a section heading introduces inflection tables.
+func isInflectionHeading(heading string) bool {
+	for _, h := range []string{"Declension", "Conjugation", "Inflection"} {
+		if strings.HasPrefix(heading, h) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTable returns the text of the cells of a table, titled by its caption, the
+// collapsible frame around it or else the section heading.
+func parseTable(n *html.Node, navHead, heading string) Table {
+	t := Table{Title: navHead}
+	if t.Title == "" {
+		t.Title = heading
+	}
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.Caption:
+			t.Title = text(n)
+			return
+		case atom.Tr:
+			var row []string
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.DataAtom == atom.Th || c.DataAtom == atom.Td {
+					row = append(row, text(c))
+				}
+			}
+			if len(row) > 0 && len(t.Rows) < maxTableRows {
+				t.Rows = append(t.Rows, row)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return t
+}
+
+// text returns the collapsed text content of n, without edit links, nested lists of
+// examples and quotations, and citation markers.
+func text(n *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			return
+		}
+		if n.Type == html.ElementNode {
+			switch {
+			case hasClass(n, "mw-editsection"), hasClass(n, "reference"):
+				return
+			case n.DataAtom == atom.Ul, n.DataAtom == atom.Ol, n.DataAtom == atom.Dl, n.DataAtom == atom.Style:
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.TrimSpace(spaces.ReplaceAllString(b.String(), " "))
+}
+
+// hasClass reports whether the element has the CSS class.
+func hasClass(n *html.Node, class string) bool {
+	for _, a := range n.Attr {
+		if a.Key == "class" {
+			for _, c := range strings.Fields(a.Val) {
+				if c == class {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+// Package wiktionary looks up words in Wiktionary: their etymology, senses and
+// inflection tables. Entries are cached in the data directory, so that words looked up
+// once are available offline.
+package wiktionary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/brittaao/translation-tui/internal/storage"
+)
+
+// DefaultURL is the Wiktionary edition that is queried. The English edition describes
+// the words of all languages in English.
+const DefaultURL = "https://en.wiktionary.org"
+
+// Directory inside the data directory holding cached entries
+const cacheDirName = "wiktionary"
+
+// Largest page that is read, in bytes
+const maxPageSize = 8 << 20
+
+// userAgent identifies requests, as the Wikimedia API policy asks for.
+const userAgent = "translation-tui (https://github.com/brittaao/translation-tui)"
+
+// ErrNotFound is returned when Wiktionary has no entry for the word in the language.
+var ErrNotFound = errors.New("no Wiktionary entry")
+
+// Entry is what Wiktionary says about a word in one language.
+type Entry struct {
+	Word      string    `json:"word"`
+	Language  string    `json:"language"`
+	URL       string    `json:"url"` // Page the entry was taken from, for citing it
+	Etymology []string  `json:"etymology,omitempty"`
+	Senses    []Sense   `json:"senses,omitempty"`
+	Tables    []Table   `json:"tables,omitempty"`
+	Fetched   time.Time `json:"fetched"`
+}
+
+// Sense is one meaning of the word.
+type Sense struct {
+	PartOfSpeech string `json:"part_of_speech"`
+	Definition   string `json:"definition"`
+}
+
+// Table is an inflection table, such as a declension or conjugation.
+type Table struct {
+	Title string     `json:"title"`
+	Rows  [][]string `json:"rows"`
+}
+
+// sectionNames maps language names to the name of their section where they differ.
+var sectionNames = map[string]string{
+	"Serbian":  "Serbo-Croatian",
+	"Croatian": "Serbo-Croatian",
+	"Bosnian":  "Serbo-Croatian",
+}
+
+// Lookup returns the entry of word in the language (an English language name such as
+// "German") from the cache, or fetches it from the Wiktionary at baseURL (DefaultURL if
+// empty). If the word has no entry, its lowercase form is tried as well.
+func Lookup(ctx context.Context, client *http.Client, baseURL, word, language string) (*Entry, error) {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	if name, ok := sectionNames[language]; ok {
+		language = name
+	}
+	word = strings.TrimSpace(word)
+	if e, ok := loadCached(word, language); ok {
+		return e, nil
+	}
+
+	e, err := fetch(ctx, client, baseURL, word, language)
+	if errors.Is(err, ErrNotFound) && strings.ToLower(word) != word {
+		e, err = fetch(ctx, client, baseURL, strings.ToLower(word), language)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = storeCached(word, e) // The cache is best-effort
+	return e, nil
+}
+
+// fetch queries the parse API for the rendered page of word and extracts its entry.
+func fetch(ctx context.Context, client *http.Client, baseURL, word, language string) (*Entry, error) {
+	q := url.Values{
+		"action":        {"parse"},
+		"page":          {word},
+		"prop":          {"text"},
+		"format":        {"json"},
+		"formatversion": {"2"},
+		"redirects":     {"1"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/w/api.php?"+q.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Wiktionary URL: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Wiktionary: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query Wiktionary: %s", resp.Status)
+	}
+
+	var page struct {
+		Parse struct {
+			Title string `json:"title"`
+			Text  string `json:"text"`
+		} `json:"parse"`
+		Error *struct {
+			Code string `json:"code"`
+			Info string `json:"info"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxPageSize)).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to parse Wiktionary response: %w", err)
+	}
+	if page.Error != nil {
+		if page.Error.Code == "missingtitle" {
+			return nil, fmt.Errorf("%w for %q", ErrNotFound, word)
+		}
+		return nil, fmt.Errorf("Wiktionary error: %s", page.Error.Info)
+	}
+
+	e, err := ParsePage(strings.NewReader(page.Parse.Text), language)
+	if err != nil {
+		return nil, err
+	}
+	e.Word = page.Parse.Title
+	e.URL = strings.TrimSuffix(baseURL, "/") + "/wiki/" + url.PathEscape(strings.ReplaceAll(page.Parse.Title, " ", "_")) + "#" + url.PathEscape(strings.ReplaceAll(language, " ", "_"))
+	e.Fetched = time.Now()
+	return e, nil
+}
+
+// cachePath returns the cache file of word in the language.
+func cachePath(word, language string) (string, error) {
+	dir, err := storage.DataFile(filepath.Join(cacheDirName, language))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, url.PathEscape(word)+".json"), nil
+}
+
+// loadCached returns the cached entry of word, if any.
+func loadCached(word, language string) (*Entry, bool) {
+	path, err := cachePath(word, language)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+// storeCached caches the entry under the word it was looked up as.
+func storeCached(word string, e *Entry) error {
+	if storage.Private() {
+		return nil
+	}
+	path, err := cachePath(word, e.Language)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
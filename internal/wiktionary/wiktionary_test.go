@@ -0,0 +1,88 @@
+package wiktionary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePage(t *testing.T) {
+	f, err := os.Open("testdata/bahnhof.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	e, err := ParsePage(f, "German")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"From Bahn (“track”) + Hof (“yard”)."}; !reflect.DeepEqual(e.Etymology, want) {
+		t.Errorf("etymology = %q, want %q", e.Etymology, want)
+	}
+	wantSenses := []Sense{
+		{PartOfSpeech: "Noun", Definition: "train station, railway station"},
+		{PartOfSpeech: "Noun", Definition: "(figuratively) nothing (in nur Bahnhof verstehen)"},
+	}
+	if !reflect.DeepEqual(e.Senses, wantSenses) {
+		t.Errorf("senses = %+v, want %+v", e.Senses, wantSenses)
+	}
+	if len(e.Tables) != 1 {
+		t.Fatalf("got %d tables, want 1", len(e.Tables))
+	}
+	table := e.Tables[0]
+	if table.Title != "Declension of Bahnhof [masculine, strong]" || len(table.Rows) != 3 {
+		t.Errorf("table = %+v", table)
+	}
+	if want := []string{"nominative", "der Bahnhof", "die Bahnhöfe"}; !reflect.DeepEqual(table.Rows[1], want) {
+		t.Errorf("row = %q, want %q", table.Rows[1], want)
+	}
+
+	if _, err := ParsePage(strings.NewReader("<h2>English</h2><ol><li>x</li></ol>"), "German"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing language: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	page, err := os.ReadFile("testdata/bahnhof.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("page") != "Bahnhof" {
+			w.Write([]byte(`{"error": {"code": "missingtitle", "info": "The page you specified doesn't exist."}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"parse": map[string]string{"title": "Bahnhof", "text": string(page)}})
+	}))
+	defer srv.Close()
+
+	e, err := Lookup(context.Background(), srv.Client(), srv.URL, "Bahnhof", "German")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.URL != srv.URL+"/wiki/Bahnhof#German" || len(e.Senses) != 2 {
+		t.Errorf("entry = %+v", e)
+	}
+
+	// The second lookup is served from the cache
+	if _, err := Lookup(context.Background(), srv.Client(), srv.URL, "Bahnhof", "German"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests, want 1", requests)
+	}
+
+	if _, err := Lookup(context.Background(), srv.Client(), srv.URL, "Xyz", "German"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing page: got %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,174 @@
+// Package wordlist reads word lists exported from spreadsheets or flashcard programs:
+// CSV and TSV files, including Anki's "Notes in Plain Text" exports with their header
+// lines and HTML fields.
+package wordlist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Table is a word list as read: an optional header naming the columns, and the rows.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Columns returns the number of columns of the widest row or the header.
+func (t Table) Columns() int {
+	n := len(t.Header)
+	for _, row := range t.Rows {
+		n = max(n, len(row))
+	}
+	return n
+}
+
+// Cell returns column i of row, or "" if the row is shorter.
+func Cell(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+// Options selects how a word list is read.
+type Options struct {
+	// Comma separates the fields; 0 guesses it from the first line, preferring tabs.
+	// An Anki "#separator" header line overrides it.
+	Comma rune
+
+	// Header takes the first row as the names of the columns. An Anki "#columns"
+	// header line names them as well.
+	Header bool
+}
+
+// ankiSeparators maps the separators named in Anki header lines to their characters.
+var ankiSeparators = map[string]rune{
+	"tab":       '\t',
+	"comma":     ',',
+	"semicolon": ';',
+	"pipe":      '|',
+	"space":     ' ',
+	"colon":     ':',
+}
+
+// Read reads a word list. Anki header lines ("#separator:tab", "#html:true",
+// "#columns:Front\tBack") are applied and removed, and HTML fields are turned into
+// plain text.
+func Read(r io.Reader, opts Options) (Table, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Table{}, fmt.Errorf("failed to read word list: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	var table Table
+	isHTML := false
+	var columns string
+	var body bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	inHeader := true
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inHeader && strings.HasPrefix(line, "#") {
+			name, value, ok := strings.Cut(strings.TrimRight(line[1:], "\r"), ":")
+			if !ok {
+				continue
+			}
+			switch strings.ToLower(name) {
+			case "separator":
+				if sep, ok := ankiSeparators[strings.ToLower(value)]; ok {
+					opts.Comma = sep
+				} else if len([]rune(value)) == 1 {
+					opts.Comma = []rune(value)[0]
+				}
+			case "html":
+				isHTML = value == "true"
+			case "columns":
+				columns = value
+			}
+			continue
+		}
+		inHeader = false
+		body.WriteString(line)
+		body.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return Table{}, fmt.Errorf("failed to read word list: %w", err)
+	}
+
+	if opts.Comma == 0 {
+		opts.Comma = guessComma(body.String())
+	}
+	cr := csv.NewReader(&body)
+	cr.Comma = opts.Comma
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return Table{}, fmt.Errorf("failed to parse word list: %w", err)
+	}
+	switch {
+	case columns != "":
+		table.Header = strings.Split(columns, string(opts.Comma))
+	case opts.Header && len(rows) > 0:
+		table.Header, rows = rows[0], rows[1:]
+	}
+	for _, row := range rows {
+		if isHTML {
+			for i := range row {
+				row[i] = PlainText(row[i])
+			}
+		}
+		if strings.TrimSpace(strings.Join(row, "")) != "" {
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return table, nil
+}
+
+// guessComma returns the separator of the first line: a tab if it has one, otherwise
+// a semicolon if it has more of them than commas, otherwise a comma.
+func guessComma(text string) rune {
+	line, _, _ := strings.Cut(text, "\n")
+	switch {
+	case strings.Contains(line, "\t"):
+		return '\t'
+	case strings.Count(line, ";") > strings.Count(line, ","):
+		return ';'
+	default:
+		return ','
+	}
+}
+
+var (
+	breakTag = regexp.MustCompile(`(?i)<br\s*/?>|</(div|p|li)>`)
+	soundTag = regexp.MustCompile(`\[sound:[^\]]*\]`)
+	anyTag   = regexp.MustCompile(`<[^>]*>`)
+	spaces   = regexp.MustCompile(`[ \t]+`)
+)
+
+// PlainText turns an HTML field into plain text, with line breaks for breaks and
+// blocks and Anki's sound tags removed.
+func PlainText(s string) string {
+	s = breakTag.ReplaceAllString(s, "\n")
+	s = anyTag.ReplaceAllString(s, "")
+	s = soundTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = strings.ReplaceAll(s, "\u00a0", " ")
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(spaces.ReplaceAllString(line, " "))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
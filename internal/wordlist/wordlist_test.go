@@ -0,0 +1,48 @@
+package wordlist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		opts   Options
+		header []string
+		rows   [][]string
+	}{
+		{
+			name:   "csv with header",
+			input:  "word,meaning\nHund,dog\n\"Haus, das\",house\n",
+			opts:   Options{Header: true},
+			header: []string{"word", "meaning"},
+			rows:   [][]string{{"Hund", "dog"}, {"Haus, das", "house"}},
+		},
+		{
+			name: "anki export",
+			input: "#separator:tab\r\n#html:true\r\n#columns:Front\tBack\r\n" +
+				"der Hund\tdog<br>[sound:hund.mp3]&nbsp;<b>noun</b>\r\n",
+			header: []string{"Front", "Back"},
+			rows:   [][]string{{"der Hund", "dog\nnoun"}},
+		},
+		{
+			name:  "semicolons guessed",
+			input: "gato;cat;el gato duerme\n",
+			rows:  [][]string{{"gato", "cat", "el gato duerme"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := Read(strings.NewReader(tt.input), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(table.Header, tt.header) || !reflect.DeepEqual(table.Rows, tt.rows) {
+				t.Errorf("Read = %q, %q; want %q, %q", table.Header, table.Rows, tt.header, tt.rows)
+			}
+		})
+	}
+}
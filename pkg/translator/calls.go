@@ -0,0 +1,71 @@
+package translator
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// Provider names, as reported in Call records
+	ProviderGemini         = "gemini"
+	ProviderDeepL          = "deepl"
+	ProviderOpenAI         = "openai"
+	ProviderLibreTranslate = "libretranslate"
+)
+
+// Call captures a single model API call: the exact prompt, schema, raw response and timings.
+type Call struct {
+	Time         time.Time     `json:"time"`
+	Step         string        `json:"step"`
+	Provider     string        `json:"provider"`
+	Model        string        `json:"model"`
+	Prompt       string        `json:"prompt"`
+	Schema       any           `json:"schema,omitempty"`
+	Response     string        `json:"response"`
+	Latency      time.Duration `json:"latency"`
+	InputTokens  int           `json:"input_tokens"`
+	OutputTokens int           `json:"output_tokens"`
+	Err          string        `json:"error,omitempty"`
+}
+
+var (
+	hooksMu   sync.Mutex
+	callHooks []func(Call)
+)
+
+// OnCall registers fn to be called after every model API call.
+// Calls happen on the goroutine running the pipeline, so fn must be safe for concurrent use.
+func OnCall(fn func(Call)) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	callHooks = append(callHooks, fn)
+}
+
+// reportCall logs a completed call and passes it to the registered hooks.
+func reportCall(c Call) {
+	logCall(c)
+	hooksMu.Lock()
+	hooks := callHooks
+	hooksMu.Unlock()
+	for _, fn := range hooks {
+		fn(c)
+	}
+}
+
+// logCall logs a completed API call at info level, or warn level if it failed.
+func logCall(c Call) {
+	attrs := []any{
+		"provider", c.Provider,
+		"model", c.Model,
+		"step", c.Step,
+		"latency", c.Latency,
+		"input_tokens", c.InputTokens,
+		"output_tokens", c.OutputTokens,
+	}
+	if c.Err != "" {
+		slog.Warn("api call failed", append(attrs, "error", c.Err)...)
+		return
+	}
+	slog.Info("api call", attrs...)
+}
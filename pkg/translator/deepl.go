@@ -0,0 +1,123 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DeepL API endpoints; keys ending in ":fx" belong to the free plan
+	deeplFreeURL = "https://api-free.deepl.com/v2/translate"
+	deeplProURL  = "https://api.deepl.com/v2/translate"
+)
+
+// deeplTargetCodes maps language codes to DeepL target codes where they differ from the plain code.
+var deeplTargetCodes = map[string]string{
+	"en": "EN-US",
+	"pt": "PT-PT",
+}
+
+// DeepLProvider implements the translation step using the DeepL API.
+type DeepLProvider struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+// deeplResponse represents the response of the DeepL translate endpoint.
+type deeplResponse struct {
+	Translations []struct {
+		DetectedSourceLanguage string `json:"detected_source_language"`
+		Text                   string `json:"text"`
+	} `json:"translations"`
+}
+
+// NewDeepLProvider creates a DeepL provider. An empty endpoint selects the free or
+// pro API depending on the key.
+func NewDeepLProvider(apiKey, endpoint string, httpClient *http.Client) (*DeepLProvider, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("DeepL API key not set")
+	}
+	if endpoint == "" {
+		endpoint = deeplProURL
+		if strings.HasSuffix(apiKey, ":fx") {
+			endpoint = deeplFreeURL
+		}
+	}
+	return &DeepLProvider{apiKey: apiKey, endpoint: endpoint, client: httpClient}, nil
+}
+
+func (p *DeepLProvider) TranslationModel() string { return ProviderDeepL }
+
+// Translate translates the sentence to the target language, or to the user's language
+// if DeepL detects that the sentence is already in the target language.
+// DeepL does not clean the input, so the sentence is returned unchanged.
+func (p *DeepLProvider) Translate(ctx context.Context, req Request) (*TranslationStep, error) {
+	text, detected, err := p.call(ctx, req.Sentence, req.TargetLang)
+	if err != nil {
+		return nil, err
+	}
+	result := &TranslationStep{
+		InputLanguage:       LanguageName(req.UserLang),
+		CleanedSentence:     req.Sentence,
+		Translation:         text,
+		TranslationLanguage: LanguageName(req.TargetLang),
+	}
+
+	if strings.EqualFold(detected, req.TargetLang) {
+		text, _, err = p.call(ctx, req.Sentence, req.UserLang)
+		if err != nil {
+			return nil, err
+		}
+		result.InputLanguage = LanguageName(req.TargetLang)
+		result.Translation = text
+		result.TranslationLanguage = LanguageName(req.UserLang)
+	}
+	return result, nil
+}
+
+// call sends a single translate request and returns the translation and detected source language.
+func (p *DeepLProvider) call(ctx context.Context, text, targetLang string) (string, string, error) {
+	target, ok := deeplTargetCodes[targetLang]
+	if !ok {
+		target = strings.ToUpper(targetLang)
+	}
+	body, err := json.Marshal(map[string]any{
+		"text":        []string{text},
+		"target_lang": target,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode DeepL request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create DeepL request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "DeepL-Auth-Key "+p.apiKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return "", "", fmt.Errorf("DeepL API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("DeepL API error: %s", resp.Status)
+	}
+
+	var result deeplResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", "", fmt.Errorf("failed to parse DeepL response: %w", err)
+	}
+	if len(result.Translations) == 0 {
+		return "", "", fmt.Errorf("no response from DeepL API")
+	}
+	t := result.Translations[0]
+	return t.Text, t.DetectedSourceLanguage, nil
+}
@@ -0,0 +1,482 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// ContentGenerator is the part of the Gemini client used by the pipeline.
+// It is satisfied by (*genai.Client).Models and can be replaced by a fake in tests.
+type ContentGenerator interface {
+	GenerateContent(ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error)
+}
+
+// GeminiProvider implements both pipeline steps using the Gemini API.
+// Calls rotate through the clients of all configured API keys when one hits its quota.
+type GeminiProvider struct {
+	clients   []ContentGenerator
+	models    Models
+	fallbacks []string // Models tried in order when a model is unavailable
+	prompts   Prompts
+	settings  GeminiSettings
+}
+
+// GeminiSettings tunes the Gemini API calls of the pipeline steps.
+type GeminiSettings struct {
+	Generation Generation
+
+	// SafetyThreshold blocks content of every harm category from this probability on,
+	// e.g. genai.HarmBlockThresholdBlockOnlyHigh. Empty means the API default.
+	SafetyThreshold genai.HarmBlockThreshold
+
+	// Thinking budgets of the steps in tokens. nil means the model default, 0 disables
+	// thinking and -1 lets the model decide.
+	TranslationThinkingBudget *int32
+	AnalysisThinkingBudget    *int32
+}
+
+// safetyCategories are the harm categories SafetyThreshold applies to.
+var safetyCategories = []genai.HarmCategory{
+	genai.HarmCategoryHarassment,
+	genai.HarmCategoryHateSpeech,
+	genai.HarmCategorySexuallyExplicit,
+	genai.HarmCategoryDangerousContent,
+}
+
+// safetyThresholds are the valid values of SafetyThreshold.
+var safetyThresholds = []genai.HarmBlockThreshold{
+	genai.HarmBlockThresholdBlockLowAndAbove,
+	genai.HarmBlockThresholdBlockMediumAndAbove,
+	genai.HarmBlockThresholdBlockOnlyHigh,
+	genai.HarmBlockThresholdBlockNone,
+	genai.HarmBlockThresholdOff,
+}
+
+// ParseSafetyThreshold parses a safety threshold such as "block_only_high", ignoring case.
+func ParseSafetyThreshold(s string) (genai.HarmBlockThreshold, error) {
+	threshold := genai.HarmBlockThreshold(strings.ToUpper(strings.TrimSpace(s)))
+	if threshold == "" || slices.Contains(safetyThresholds, threshold) {
+		return threshold, nil
+	}
+	names := make([]string, len(safetyThresholds))
+	for i, t := range safetyThresholds {
+		names[i] = strings.ToLower(string(t))
+	}
+	return "", fmt.Errorf("unknown safety threshold %q (use %s)", s, strings.Join(names, ", "))
+}
+
+// apply sets the sampling parameters, the safety settings and the thinking budget of a
+// step on config.
+func (s GeminiSettings) apply(config *genai.GenerateContentConfig, params StepParams, thinkingBudget *int32) {
+	if params.Temperature != nil {
+		config.Temperature = genai.Ptr(float32(*params.Temperature))
+	}
+	if params.TopP != nil {
+		config.TopP = genai.Ptr(float32(*params.TopP))
+	}
+	if params.MaxOutputTokens > 0 {
+		config.MaxOutputTokens = int32(params.MaxOutputTokens)
+	}
+	if s.SafetyThreshold != "" {
+		for _, category := range safetyCategories {
+			config.SafetySettings = append(config.SafetySettings, &genai.SafetySetting{Category: category, Threshold: s.SafetyThreshold})
+		}
+	}
+	if thinkingBudget != nil {
+		config.ThinkingConfig = &genai.ThinkingConfig{ThinkingBudget: thinkingBudget}
+	}
+}
+
+// NewGeminiProvider creates a Gemini provider using one client per API key.
+func NewGeminiProvider(clients []ContentGenerator, models Models) *GeminiProvider {
+	return &GeminiProvider{clients: clients, models: models, settings: GeminiSettings{Generation: DefaultGeneration()}}
+}
+
+// SetPrompts replaces the built-in prompts of the pipeline steps.
+func (p *GeminiProvider) SetPrompts(prompts Prompts) {
+	p.prompts = prompts
+}
+
+// SetSettings changes the sampling parameters, safety settings and thinking budgets of
+// the pipeline steps.
+func (p *GeminiProvider) SetSettings(settings GeminiSettings) {
+	p.settings = settings
+}
+
+// SetFallbackModels sets the models tried in order when a model of a step is not found
+// or has exhausted its quota.
+func (p *GeminiProvider) SetFallbackModels(models []string) {
+	p.fallbacks = models
+}
+
+// TranslationModel returns the model translations are sent to: the configured one, or
+// the first fallback model if it turned out to be unavailable.
+func (p *GeminiProvider) TranslationModel() string {
+	return activeModel(p.models.Translation, p.fallbacks)
+}
+
+// AnalysisModel returns the model analyses are sent to: the configured one, or the
+// first fallback model if it turned out to be unavailable.
+func (p *GeminiProvider) AnalysisModel() string { return activeModel(p.models.Analysis, p.fallbacks) }
+
+func (p *GeminiProvider) Translate(ctx context.Context, req Request) (*TranslationStep, error) {
+	return withModelFallback(p, p.models.Translation, func(client ContentGenerator, model string) (*TranslationStep, error) {
+		return performTranslation(ctx, client, model, p.prompts, p.settings, req)
+	})
+}
+
+func (p *GeminiProvider) AnalyzeWords(ctx context.Context, foreignSentence string, req Request) (*AnalysisStep, error) {
+	return withModelFallback(p, p.models.Analysis, func(client ContentGenerator, model string) (*AnalysisStep, error) {
+		return performWordAnalysis(ctx, client, model, p.prompts, p.settings, foreignSentence, req.Words, req)
+	})
+}
+
+func (p *GeminiProvider) Compare(ctx context.Context, first, second string, req Request) (*Comparison, error) {
+	prompt := buildComparisonPrompt(first, second, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+	return withModelFallback(p, p.models.Analysis, func(client ContentGenerator, model string) (*Comparison, error) {
+		var result Comparison
+		if err := generateJSON(ctx, client, "comparison", model, nil, prompt, buildComparisonConfig(), &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	})
+}
+
+func (p *GeminiProvider) Simplify(ctx context.Context, sentence, level string, req Request) (*Simplification, error) {
+	prompt := buildSimplificationPrompt(sentence, level, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+	return withModelFallback(p, p.models.Analysis, func(client ContentGenerator, model string) (*Simplification, error) {
+		var result Simplification
+		if err := generateJSON(ctx, client, "simplification", model, nil, prompt, buildSimplificationConfig(), &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	})
+}
+
+func (p *GeminiProvider) DetectLanguage(ctx context.Context, text string) (*Detection, error) {
+	prompt := buildDetectionPrompt(text)
+	return withModelFallback(p, p.models.Analysis, func(client ContentGenerator, model string) (*Detection, error) {
+		var result Detection
+		if err := generateJSON(ctx, client, "detection", model, nil, prompt, buildDetectionConfig(), &result); err != nil {
+			return nil, err
+		}
+		result.Language = strings.ToLower(strings.TrimSpace(result.Language))
+		return &result, nil
+	})
+}
+
+func (p *GeminiProvider) Conjugate(ctx context.Context, verb string, req Request) (*Conjugation, error) {
+	prompt := buildConjugationPrompt(verb, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+	return withModelFallback(p, p.models.Analysis, func(client ContentGenerator, model string) (*Conjugation, error) {
+		var result Conjugation
+		if err := generateJSON(ctx, client, "conjugation", model, nil, prompt, buildConjugationConfig(), &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	})
+}
+
+func (p *GeminiProvider) CaseQuiz(ctx context.Context, sentences []string, req Request) (*CaseQuiz, error) {
+	prompt := buildCaseQuizPrompt(sentences, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+	return withModelFallback(p, p.models.Analysis, func(client ContentGenerator, model string) (*CaseQuiz, error) {
+		var result CaseQuiz
+		if err := generateJSON(ctx, client, "case quiz", model, nil, prompt, buildCaseQuizConfig(Cases[req.TargetLang]), &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	})
+}
+
+func (p *GeminiProvider) WordDetails(ctx context.Context, word, sentence string, req Request) (*WordDetails, error) {
+	prompt := buildWordDetailsPrompt(word, sentence, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+	return withModelFallback(p, p.models.Analysis, func(client ContentGenerator, model string) (*WordDetails, error) {
+		var result WordDetails
+		if err := generateJSON(ctx, client, "word details", model, nil, prompt, buildWordDetailsConfig(), &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	})
+}
+
+func (p *GeminiProvider) GradeNumber(ctx context.Context, figure, kind, answer string, req Request) (*NumberGrade, error) {
+	prompt := buildNumberGradePrompt(figure, kind, answer, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+	return withModelFallback(p, p.models.Analysis, func(client ContentGenerator, model string) (*NumberGrade, error) {
+		var result NumberGrade
+		if err := generateJSON(ctx, client, "number grading", model, nil, prompt, buildNumberGradeConfig(), &result); err != nil {
+			return nil, err
+		}
+		return &result, nil
+	})
+}
+
+func (p *GeminiProvider) ChatModel() string { return p.AnalysisModel() }
+
+func (p *GeminiProvider) Chat(ctx context.Context, system string, history []Message) (string, error) {
+	history = withOpening(history)
+	contents := make([]*genai.Content, len(history))
+	for i, msg := range history {
+		role := genai.RoleUser
+		if msg.Role == RoleModel {
+			role = genai.RoleModel
+		}
+		contents[i] = genai.NewContentFromText(msg.Text, genai.Role(role))
+	}
+	config := &genai.GenerateContentConfig{
+		SystemInstruction: genai.NewContentFromText(system, genai.RoleUser),
+		Temperature:       genai.Ptr(float32(chatTemperature)),
+	}
+	return withModelFallback(p, p.models.Analysis, func(client ContentGenerator, model string) (string, error) {
+		return generateContents(ctx, client, "conversation", model, contents, transcript(system, history), config)
+	})
+}
+
+// ErrBlocked is returned when the safety filters of the API blocked the prompt or the response.
+var ErrBlocked = errors.New("blocked by the safety filters")
+
+// activeKey is the index of the API key currently in use; it advances when a key hits its quota.
+var activeKey atomic.Int64
+
+// ActiveKey returns the index of the API key currently in use.
+func ActiveKey() int {
+	return int(activeKey.Load())
+}
+
+// IsQuotaError reports whether err means the API key has exhausted its quota.
+func IsQuotaError(err error) bool {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Status == "RESOURCE_EXHAUSTED"
+	}
+	return false
+}
+
+// unavailableModels holds the models that were not found or out of quota during this
+// session, so that later calls go straight to a fallback model.
+var unavailableModels sync.Map
+
+// IsModelUnavailable reports whether err means that the model does not exist (anymore),
+// as happens when preview models expire, or has exhausted its quota.
+func IsModelUnavailable(err error) bool {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusNotFound || apiErr.Status == "NOT_FOUND" || IsQuotaError(err)
+	}
+	return false
+}
+
+// activeModel returns the first of model and the fallbacks that is not known to be
+// unavailable, or model if none is left.
+func activeModel(model string, fallbacks []string) string {
+	for _, m := range append([]string{model}, fallbacks...) {
+		if _, unavailable := unavailableModels.Load(m); !unavailable {
+			return m
+		}
+	}
+	return model
+}
+
+// withModelFallback calls fn with the active model of a step, rotating through the API
+// keys, and moves on to the next fallback model whenever the model is unavailable.
+func withModelFallback[T any](p *GeminiProvider, model string, fn func(client ContentGenerator, model string) (T, error)) (T, error) {
+	var (
+		result T
+		err    error
+	)
+	candidates := append([]string{model}, p.fallbacks...)
+	for i := slices.Index(candidates, activeModel(model, p.fallbacks)); i < len(candidates); i++ {
+		result, err = withKeyRotation(p.clients, func(client ContentGenerator) (T, error) {
+			return fn(client, candidates[i])
+		})
+		if err == nil || !IsModelUnavailable(err) || len(p.fallbacks) == 0 {
+			return result, err
+		}
+		unavailableModels.Store(candidates[i], true)
+		if i+1 < len(candidates) {
+			slog.Warn("model unavailable, falling back", "model", candidates[i], "fallback", candidates[i+1], "error", err)
+		}
+	}
+	return result, err
+}
+
+// withKeyRotation calls fn with each client in turn, starting at the active key,
+// and moves on to the next key whenever fn fails with a quota error.
+func withKeyRotation[C, T any](clients []C, fn func(C) (T, error)) (T, error) {
+	var (
+		result T
+		err    error
+	)
+	start := int(activeKey.Load())
+	for i := range clients {
+		idx := (start + i) % len(clients)
+		result, err = fn(clients[idx])
+		if err == nil || !IsQuotaError(err) {
+			activeKey.Store(int64(idx))
+			return result, err
+		}
+	}
+	return result, err
+}
+
+// performTranslation handles the translation step of the process.
+func performTranslation(ctx context.Context, client ContentGenerator, modelName string, prompts Prompts, settings GeminiSettings, req Request) (*TranslationStep, error) {
+	prompt, err := buildTranslationPrompt(prompts.Translation, req)
+	if err != nil {
+		return nil, err
+	}
+	shots, err := prompts.translationShots(req)
+	if err != nil {
+		return nil, err
+	}
+	config := buildTranslationConfig(LanguageName(req.UserLang), LanguageName(req.TargetLang), settings)
+	withSystemInstruction(config, prompts.System)
+
+	var result TranslationStep
+	if err := generateJSON(ctx, client, "translation", modelName, shots, prompt, config, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// performWordAnalysis handles the word analysis step of the process.
+func performWordAnalysis(ctx context.Context, client ContentGenerator, modelName string, prompts Prompts, settings GeminiSettings, foreignSentence string, words []string, req Request) (*AnalysisStep, error) {
+	prompt, err := buildAnalysisPrompt(prompts.Analysis, foreignSentence, words, req)
+	if err != nil {
+		return nil, err
+	}
+	shots, err := prompts.analysisShots(req)
+	if err != nil {
+		return nil, err
+	}
+	config := buildAnalysisConfig(req, settings)
+	withSystemInstruction(config, prompts.System)
+
+	var result AnalysisStep
+	if err := generateJSON(ctx, client, "word analysis", modelName, shots, prompt, config, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// withSystemInstruction sets the system instruction of config unless it is empty.
+func withSystemInstruction(config *genai.GenerateContentConfig, system string) {
+	if system != "" {
+		config.SystemInstruction = genai.NewContentFromText(system, genai.RoleUser)
+	}
+}
+
+// generateJSON calls the model with a structured-output config and decodes the JSON response into out.
+// The few-shot examples, if any, precede the prompt.
+// Malformed JSON is repaired locally if possible, otherwise the model is asked once to fix it.
+func generateJSON(ctx context.Context, client ContentGenerator, step, modelName string, shots []shot, prompt string, config *genai.GenerateContentConfig, out any) error {
+	contents := make([]*genai.Content, 0, 2*len(shots)+1)
+	for _, s := range shots {
+		contents = append(contents, genai.NewContentFromText(s.prompt, genai.RoleUser), genai.NewContentFromText(s.answer, genai.RoleModel))
+	}
+	contents = append(contents, genai.NewContentFromText(prompt, genai.RoleUser))
+	text, err := generateContents(ctx, client, step, modelName, contents, prompt, config)
+	if err != nil {
+		return err
+	}
+	parseErr := timeParse(ctx, func() error { return parseModelJSON(text, out) })
+	if parseErr == nil {
+		return nil
+	}
+
+	slog.Warn("malformed JSON from model, asking it to repair", "step", step, "model", modelName, "error", parseErr)
+	fixed, err := generateText(ctx, client, step+" (repair)", modelName, buildRepairPrompt(text, parseErr), config)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s JSON: %w", step, parseErr)
+	}
+	if err := timeParse(ctx, func() error { return parseModelJSON(fixed, out) }); err != nil {
+		return fmt.Errorf("failed to parse %s JSON: %w", step, err)
+	}
+	return nil
+}
+
+// generateText performs a single API call with a one-turn prompt and returns the response text.
+func generateText(ctx context.Context, client ContentGenerator, step, modelName, prompt string, config *genai.GenerateContentConfig) (string, error) {
+	return generateContents(ctx, client, step, modelName, genai.Text(prompt), prompt, config)
+}
+
+// generateContents performs a single API call and returns the response text. prompt is
+// the text form of contents recorded for the call.
+// Each call waits for the rate limit, and is logged and reported to the registered call hooks.
+func generateContents(ctx context.Context, client ContentGenerator, step, modelName string, contents []*genai.Content, prompt string, config *genai.GenerateContentConfig) (string, error) {
+	release, err := limiter.Acquire(ctx, EstimateTokens(prompt))
+	if err != nil {
+		return "", fmt.Errorf("%s: waiting for the rate limit: %w", step, err)
+	}
+	call := Call{
+		Time:     time.Now(),
+		Step:     step,
+		Provider: ProviderGemini,
+		Model:    modelName,
+		Prompt:   prompt,
+		Schema:   config.ResponseJsonSchema,
+	}
+	defer func() {
+		release(call.InputTokens + call.OutputTokens)
+		reportCall(call)
+	}()
+
+	resp, err := client.GenerateContent(ctx, modelName, contents, config)
+	call.Latency = time.Since(call.Time)
+	addAPITime(ctx, call.Latency)
+	if err != nil {
+		call.Err = err.Error()
+		return "", fmt.Errorf("%s API error: %w", step, err)
+	}
+	if resp.UsageMetadata != nil {
+		call.InputTokens = int(resp.UsageMetadata.PromptTokenCount)
+		call.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount + resp.UsageMetadata.ThoughtsTokenCount)
+	}
+
+	if reason := blockReason(resp); reason != "" {
+		call.Err = "blocked: " + reason
+		return "", fmt.Errorf("%w: %s was blocked (%s)", ErrBlocked, step, reason)
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		call.Err = "no candidates"
+		return "", fmt.Errorf("no response from %s API", step)
+	}
+
+	call.Response = extractTextFromResponse(resp)
+	return call.Response, nil
+}
+
+// blockReason returns why the prompt or the response was blocked, or "" if it was not.
+func blockReason(resp *genai.GenerateContentResponse) string {
+	if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != "" {
+		return strings.ToLower(string(resp.PromptFeedback.BlockReason))
+	}
+	if len(resp.Candidates) > 0 {
+		switch reason := resp.Candidates[0].FinishReason; reason {
+		case genai.FinishReasonSafety, genai.FinishReasonProhibitedContent, genai.FinishReasonBlocklist:
+			return strings.ToLower(string(reason))
+		}
+	}
+	return ""
+}
+
+// extractTextFromResponse extracts text content from the API response.
+func extractTextFromResponse(resp *genai.GenerateContentResponse) string {
+	var text strings.Builder
+	if len(resp.Candidates) > 0 && len(resp.Candidates[0].Content.Parts) > 0 {
+		for _, part := range resp.Candidates[0].Content.Parts {
+			if part.Text != "" {
+				text.WriteString(part.Text)
+			}
+		}
+	}
+	return strings.TrimSpace(text.String())
+}
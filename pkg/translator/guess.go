@@ -0,0 +1,80 @@
+package translator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// languageHints are the common short words and the letters that tell a built-in
+// language apart from the others, for guessing the language of a sentence without
+// asking the model.
+var languageHints = map[string]struct {
+	words   []string
+	letters string
+}{
+	"en": {strings.Fields("the is are and of to you it not what this have with i"), ""},
+	"es": {strings.Fields("el la los las es y de que no en un una por con está estoy"), "ñ¿¡"},
+	"fr": {strings.Fields("le la les est et de des que ne pas un une je vous dans suis"), "çœêèù"},
+	"it": {strings.Fields("il la lo gli è e di che non un una per sono sei"), "ì"},
+	"pt": {strings.Fields("o a os as é e de que não um uma em do da está estou"), "ãõ"},
+	"sr": {strings.Fields("je i da u na se ne sam su to što kako"), "đćčžš"},
+	"sv": {strings.Fields("är och att det en ett jag inte på som har du vad"), "å"},
+	"de": {strings.Fields("der die das ist und ich nicht ein eine zu mit du bin sind"), "ßü"},
+}
+
+// GuessLanguage returns whichever of the language codes the sentence is most likely
+// in, judging from its common words, distinctive letters and, for registered languages,
+// its script; or "" if that is unclear. It is cheap but rough, so a wrong guess must
+// only cost time.
+func GuessLanguage(sentence string, codes ...string) string {
+	best, bestScore, tie := "", 0, false
+	for _, code := range codes {
+		score := languageScore(sentence, code)
+		switch {
+		case score > bestScore:
+			best, bestScore, tie = code, score, false
+		case score == bestScore:
+			tie = true
+		}
+	}
+	if bestScore == 0 || tie {
+		return ""
+	}
+	return best
+}
+
+// languageScore counts the signs that the sentence is in the language.
+func languageScore(sentence, code string) int {
+	score := 0
+	lower := strings.ToLower(sentence)
+	if hints, ok := languageHints[code]; ok {
+		for _, word := range strings.FieldsFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) && r != '\'' }) {
+			for _, w := range hints.words {
+				if word == w {
+					score++
+					break
+				}
+			}
+		}
+		for _, r := range hints.letters {
+			score += 2 * strings.Count(lower, string(r))
+		}
+	}
+	if lang, ok := customLanguage(code); ok && lang.Script != "" {
+		if table, ok := unicode.Scripts[lang.Script]; ok {
+			letters, inScript := 0, 0
+			for _, r := range sentence {
+				if unicode.IsLetter(r) {
+					letters++
+					if unicode.Is(table, r) {
+						inScript++
+					}
+				}
+			}
+			if letters > 0 && 2*inScript > letters {
+				score += letters // Outweighs any common words
+			}
+		}
+	}
+	return score
+}
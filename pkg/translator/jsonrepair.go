@@ -0,0 +1,73 @@
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// trailingComma matches a comma directly before a closing bracket or brace.
+var trailingComma = regexp.MustCompile(`,(\s*[}\]])`)
+
+// parseModelJSON decodes model output into out, first as-is and then after
+// repairing common defects such as code fences, surrounding prose and trailing commas.
+func parseModelJSON(text string, out any) error {
+	err := json.Unmarshal([]byte(text), out)
+	if err == nil {
+		return nil
+	}
+	repaired := repairJSON(text)
+	if repaired == text {
+		return err
+	}
+	if err := json.Unmarshal([]byte(repaired), out); err != nil {
+		return err
+	}
+	return nil
+}
+
+// repairJSON applies lenient fixes to almost-JSON text.
+func repairJSON(text string) string {
+	text = stripCodeFences(text)
+	text = extractJSONObject(text)
+	text = trailingComma.ReplaceAllString(text, "$1")
+	return text
+}
+
+// stripCodeFences removes a surrounding Markdown code fence such as ```json ... ```.
+func stripCodeFences(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	text = strings.TrimPrefix(text, "```")
+	if i := strings.Index(text, "\n"); i >= 0 {
+		text = text[i+1:] // Drop the language tag line
+	}
+	text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	return strings.TrimSpace(text)
+}
+
+// extractJSONObject returns the outermost JSON object in text, dropping any
+// surrounding prose that models tend to add.
+func extractJSONObject(text string) string {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return strings.TrimSpace(text)
+	}
+	return text[start : end+1]
+}
+
+// buildRepairPrompt asks the model to fix its own malformed JSON output.
+func buildRepairPrompt(text string, parseErr error) string {
+	return fmt.Sprintf(`Your previous response was supposed to be valid JSON matching the requested schema, but it could not be parsed.
+
+Parse error: %v
+
+Previous response:
+%s
+
+Return ONLY the corrected JSON, with the same content, and nothing else.`, parseErr, text)
+}
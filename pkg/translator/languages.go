@@ -0,0 +1,54 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Language describes a language beyond the built-in ones, such as a dialect or a
+// constructed language, or replaces the name of a built-in one.
+type Language struct {
+	Code   string `toml:"code"`
+	Name   string `toml:"name"`
+	Script string `toml:"script"` // Writing system, such as "Latin" or "Cyrillic"
+	Notes  string `toml:"notes"`  // Told to the model whenever the language is translated
+}
+
+// customLanguages holds the languages added with RegisterLanguage, by code.
+var customLanguages sync.Map
+
+// RegisterLanguage makes a language known to LanguageName and describes it to the model
+// in the prompts of both pipeline steps. Registering a code again replaces it.
+func RegisterLanguage(lang Language) {
+	customLanguages.Store(lang.Code, lang)
+}
+
+// customLanguage returns the registered language with the code, if any.
+func customLanguage(code string) (Language, bool) {
+	v, ok := customLanguages.Load(code)
+	if !ok {
+		return Language{}, false
+	}
+	return v.(Language), true
+}
+
+// languageNotes describes the registered languages of req with a script or notes, to be
+// appended to a prompt. It is empty if there are none.
+func languageNotes(req Request) string {
+	var b strings.Builder
+	for _, code := range []string{req.UserLang, req.TargetLang} {
+		lang, ok := customLanguage(code)
+		if !ok || (lang.Script == "" && lang.Notes == "") {
+			continue
+		}
+		fmt.Fprintf(&b, "\n- About %s:", lang.Name)
+		if lang.Script != "" {
+			fmt.Fprintf(&b, " write it in %s script.", lang.Script)
+		}
+		if lang.Notes != "" {
+			b.WriteString(" " + lang.Notes)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// LibreTranslateProvider implements a basic translation step using a LibreTranslate instance.
+type LibreTranslateProvider struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// libreTranslateResponse represents the response of the /translate endpoint.
+type libreTranslateResponse struct {
+	TranslatedText   string `json:"translatedText"`
+	DetectedLanguage struct {
+		Language string `json:"language"`
+	} `json:"detectedLanguage"`
+	Error string `json:"error"`
+}
+
+// NewLibreTranslateProvider creates a LibreTranslate provider for the given instance.
+func NewLibreTranslateProvider(url, apiKey string, httpClient *http.Client) *LibreTranslateProvider {
+	return &LibreTranslateProvider{
+		url:    strings.TrimSuffix(url, "/"),
+		apiKey: apiKey,
+		client: httpClient,
+	}
+}
+
+func (p *LibreTranslateProvider) TranslationModel() string { return ProviderLibreTranslate }
+
+// Translate translates the sentence to the opposite language of the detected input language.
+func (p *LibreTranslateProvider) Translate(ctx context.Context, req Request) (*TranslationStep, error) {
+	text, detected, err := p.call(ctx, req.Sentence, req.TargetLang)
+	if err != nil {
+		return nil, err
+	}
+	result := &TranslationStep{
+		InputLanguage:       LanguageName(req.UserLang),
+		CleanedSentence:     req.Sentence,
+		Translation:         text,
+		TranslationLanguage: LanguageName(req.TargetLang),
+	}
+
+	if detected == req.TargetLang {
+		text, _, err = p.call(ctx, req.Sentence, req.UserLang)
+		if err != nil {
+			return nil, err
+		}
+		result.InputLanguage = LanguageName(req.TargetLang)
+		result.Translation = text
+		result.TranslationLanguage = LanguageName(req.UserLang)
+	}
+	return result, nil
+}
+
+// call sends a single translate request and returns the translation and detected source language.
+func (p *LibreTranslateProvider) call(ctx context.Context, text, targetLang string) (string, string, error) {
+	body := map[string]string{
+		"q":      text,
+		"source": "auto",
+		"target": targetLang,
+		"format": "text",
+	}
+	if p.apiKey != "" {
+		body["api_key"] = p.apiKey
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode LibreTranslate request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+"/translate", bytes.NewReader(data))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create LibreTranslate request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return "", "", fmt.Errorf("LibreTranslate error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result libreTranslateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", "", fmt.Errorf("failed to parse LibreTranslate response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("LibreTranslate error: %s %s", resp.Status, result.Error)
+	}
+	return result.TranslatedText, result.DetectedLanguage.Language, nil
+}
@@ -0,0 +1,62 @@
+package translator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// markupHint matches syntax that marks a text as Markdown or HTML.
+var markupHint = regexp.MustCompile("```|</?[a-zA-Z][^<>]*>|\\*\\*[^*\\n]+\\*\\*|__[^_\\n]+__|\\[[^\\]\\n]*\\]\\([^)\\n]*\\)|(?m:^[ \\t]*(?:#{1,6}|[-*+]|>)[ \\t])")
+
+// markupPattern matches the Markdown and HTML syntax kept out of translation: code,
+// comments, tags, entities, links, line markers of headings, lists and quotes, and
+// emphasis markers.
+var markupPattern = regexp.MustCompile(strings.Join([]string{
+	"(?s:```.*?```)",
+	"`[^`\\n]+`",
+	`(?is:<!--.*?-->|<(?:pre|code|script|style)\b.*?</(?:pre|code|script|style)>)`,
+	`</?[a-zA-Z][^<>]*>`,
+	`&(?:[a-zA-Z]+|#\d+);`,
+	`!?\[[^\]\n]*\]\([^)\n]*\)`,
+	`(?m:^[ \t]*(?:#{1,6}[ \t]+|[-*+][ \t]+|\d+[.)][ \t]+|>[ \t]?))`,
+	`\*{1,3}|~~|\b_{1,3}|_{1,3}\b`,
+}, "|"))
+
+// linkPattern splits a Markdown link or image into its text and target.
+var linkPattern = regexp.MustCompile(`^(!?\[)([^\]\n]*)(\]\([^)\n]*\))$`)
+
+// tokenPattern matches the tokens of masked spans.
+var tokenPattern = regexp.MustCompile(`⟦\d+⟧`)
+
+// HasMarkup reports whether text looks like Markdown or HTML.
+func HasMarkup(text string) bool {
+	return markupHint.MatchString(text)
+}
+
+// maskMarkup replaces Markdown and HTML syntax in text with tokens, appending the
+// masked spans. Only the text of links stays translatable; their brackets and
+// targets are masked.
+func maskMarkup(text string, spans *[]string) string {
+	return markupPattern.ReplaceAllStringFunc(text, func(span string) string {
+		if m := linkPattern.FindStringSubmatch(span); m != nil {
+			open := maskSpan(m[1], spans)
+			inner := maskMarkup(m[2], spans)
+			return open + inner + maskSpan(m[3], spans)
+		}
+		return maskSpan(span, spans)
+	})
+}
+
+// maskSpan appends span and returns its token.
+func maskSpan(span string, spans *[]string) string {
+	*spans = append(*spans, span)
+	return maskToken(len(*spans) - 1)
+}
+
+// StripMarkup returns the readable text of Markdown or HTML: code, tags and markers
+// are dropped and link texts kept.
+func StripMarkup(text string) string {
+	var spans []string
+	plain := tokenPattern.ReplaceAllString(maskMarkup(text, &spans), " ")
+	return strings.Join(strings.Fields(plain), " ")
+}
@@ -0,0 +1,385 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Default base URL of a local Ollama server
+	defaultOpenAIBaseURL = "http://localhost:11434/v1"
+
+	// Structured output modes, since support varies between servers
+	structuredJSONSchema = "json_schema" // response_format with a JSON schema
+	structuredJSONObject = "json_object" // JSON mode, schema described in the prompt
+	structuredNone       = "none"        // schema described in the prompt only
+)
+
+// OpenAIOptions configures an OpenAIProvider.
+type OpenAIOptions struct {
+	BaseURL          string // Defaults to a local Ollama server
+	APIKey           string // Optional for local servers
+	Model            string
+	AnalysisModel    string // Defaults to Model
+	StructuredOutput string // json_schema (default), json_object or none
+	Prompts          Prompts
+	Generation       *Generation // Defaults to DefaultGeneration
+}
+
+// OpenAIProvider implements both pipeline steps against an OpenAI-compatible
+// chat-completions endpoint such as Ollama, llama.cpp or LM Studio.
+type OpenAIProvider struct {
+	baseURL          string
+	apiKey           string
+	model            string
+	analysisModelID  string
+	structuredOutput string
+	prompts          Prompts
+	generation       Generation
+	client           *http.Client
+}
+
+// openaiMessage represents a single chat message.
+type openaiMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openaiChatResponse represents the relevant part of a chat-completions response.
+type openaiChatResponse struct {
+	Choices []struct {
+		Message openaiMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// NewOpenAIProvider creates an OpenAI-compatible provider.
+func NewOpenAIProvider(opts OpenAIOptions, httpClient *http.Client) (*OpenAIProvider, error) {
+	if opts.Model == "" {
+		return nil, fmt.Errorf("no model configured for the openai provider")
+	}
+	p := &OpenAIProvider{
+		baseURL:          strings.TrimSuffix(opts.BaseURL, "/"),
+		apiKey:           opts.APIKey,
+		model:            opts.Model,
+		analysisModelID:  opts.AnalysisModel,
+		structuredOutput: opts.StructuredOutput,
+		prompts:          opts.Prompts,
+		generation:       DefaultGeneration(),
+		client:           httpClient,
+	}
+	if opts.Generation != nil {
+		p.generation = *opts.Generation
+	}
+	if p.baseURL == "" {
+		p.baseURL = defaultOpenAIBaseURL
+	}
+	if p.analysisModelID == "" {
+		p.analysisModelID = p.model
+	}
+	switch p.structuredOutput {
+	case "":
+		p.structuredOutput = structuredJSONSchema
+	case structuredJSONSchema, structuredJSONObject, structuredNone:
+	default:
+		return nil, fmt.Errorf("unknown structured_output mode %q", p.structuredOutput)
+	}
+	return p, nil
+}
+
+func (p *OpenAIProvider) TranslationModel() string { return p.model }
+
+func (p *OpenAIProvider) AnalysisModel() string { return p.analysisModelID }
+
+func (p *OpenAIProvider) Translate(ctx context.Context, req Request) (*TranslationStep, error) {
+	userLangName := LanguageName(req.UserLang)
+	targetLangName := LanguageName(req.TargetLang)
+	prompt, err := buildTranslationPrompt(p.prompts.Translation, req)
+	if err != nil {
+		return nil, err
+	}
+	shots, err := p.prompts.translationShots(req)
+	if err != nil {
+		return nil, err
+	}
+	schema := buildTranslationSchema(userLangName, targetLangName)
+
+	var result TranslationStep
+	if err := p.complete(ctx, p.model, p.history(shots), prompt, "translation", schema, p.generation.Translation, &result); err != nil {
+		return nil, fmt.Errorf("translation API error: %w", err)
+	}
+	return &result, nil
+}
+
+func (p *OpenAIProvider) AnalyzeWords(ctx context.Context, foreignSentence string, req Request) (*AnalysisStep, error) {
+	prompt, err := buildAnalysisPrompt(p.prompts.Analysis, foreignSentence, req.Words, req)
+	if err != nil {
+		return nil, err
+	}
+	shots, err := p.prompts.analysisShots(req)
+	if err != nil {
+		return nil, err
+	}
+	schema := buildAnalysisSchema(req)
+
+	var result AnalysisStep
+	if err := p.complete(ctx, p.analysisModelID, p.history(shots), prompt, "word_analysis", schema, p.generation.Analysis, &result); err != nil {
+		return nil, fmt.Errorf("word analysis API error: %w", err)
+	}
+	return &result, nil
+}
+
+func (p *OpenAIProvider) Compare(ctx context.Context, first, second string, req Request) (*Comparison, error) {
+	prompt := buildComparisonPrompt(first, second, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+
+	var result Comparison
+	if err := p.complete(ctx, p.analysisModelID, nil, prompt, "comparison", buildComparisonSchema(), fixedTemperature(analysisTemperature), &result); err != nil {
+		return nil, fmt.Errorf("comparison API error: %w", err)
+	}
+	return &result, nil
+}
+
+func (p *OpenAIProvider) Simplify(ctx context.Context, sentence, level string, req Request) (*Simplification, error) {
+	prompt := buildSimplificationPrompt(sentence, level, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+
+	var result Simplification
+	if err := p.complete(ctx, p.analysisModelID, nil, prompt, "simplification", buildSimplificationSchema(), fixedTemperature(translationTemperature), &result); err != nil {
+		return nil, fmt.Errorf("simplification API error: %w", err)
+	}
+	return &result, nil
+}
+
+func (p *OpenAIProvider) DetectLanguage(ctx context.Context, text string) (*Detection, error) {
+	prompt := buildDetectionPrompt(text)
+
+	var result Detection
+	if err := p.complete(ctx, p.analysisModelID, nil, prompt, "detection", buildDetectionSchema(), fixedTemperature(analysisTemperature), &result); err != nil {
+		return nil, fmt.Errorf("detection API error: %w", err)
+	}
+	result.Language = strings.ToLower(strings.TrimSpace(result.Language))
+	return &result, nil
+}
+
+func (p *OpenAIProvider) Conjugate(ctx context.Context, verb string, req Request) (*Conjugation, error) {
+	prompt := buildConjugationPrompt(verb, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+
+	var result Conjugation
+	if err := p.complete(ctx, p.analysisModelID, nil, prompt, "conjugation", buildConjugationSchema(), fixedTemperature(analysisTemperature), &result); err != nil {
+		return nil, fmt.Errorf("conjugation API error: %w", err)
+	}
+	return &result, nil
+}
+
+func (p *OpenAIProvider) CaseQuiz(ctx context.Context, sentences []string, req Request) (*CaseQuiz, error) {
+	prompt := buildCaseQuizPrompt(sentences, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+
+	var result CaseQuiz
+	if err := p.complete(ctx, p.analysisModelID, nil, prompt, "case_quiz", buildCaseQuizSchema(Cases[req.TargetLang]), fixedTemperature(analysisTemperature), &result); err != nil {
+		return nil, fmt.Errorf("case quiz API error: %w", err)
+	}
+	return &result, nil
+}
+
+func (p *OpenAIProvider) WordDetails(ctx context.Context, word, sentence string, req Request) (*WordDetails, error) {
+	prompt := buildWordDetailsPrompt(word, sentence, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+
+	var result WordDetails
+	if err := p.complete(ctx, p.analysisModelID, nil, prompt, "word_details", buildWordDetailsSchema(), fixedTemperature(analysisTemperature), &result); err != nil {
+		return nil, fmt.Errorf("word details API error: %w", err)
+	}
+	return &result, nil
+}
+
+func (p *OpenAIProvider) GradeNumber(ctx context.Context, figure, kind, answer string, req Request) (*NumberGrade, error) {
+	prompt := buildNumberGradePrompt(figure, kind, answer, LanguageName(req.UserLang), LanguageName(req.TargetLang))
+
+	var result NumberGrade
+	if err := p.complete(ctx, p.analysisModelID, nil, prompt, "number_grade", buildNumberGradeSchema(), fixedTemperature(analysisTemperature), &result); err != nil {
+		return nil, fmt.Errorf("number grading API error: %w", err)
+	}
+	return &result, nil
+}
+
+// complete sends a single-turn chat completion and decodes the JSON answer into out.
+// Malformed JSON is repaired locally if possible, otherwise the model is asked once to fix it.
+func (p *OpenAIProvider) complete(ctx context.Context, modelName string, history []openaiMessage, prompt, schemaName string, schema map[string]any, params StepParams, out any) error {
+	text, err := p.completeText(ctx, modelName, history, prompt, schemaName, schema, params)
+	if err != nil {
+		return err
+	}
+	parseErr := timeParse(ctx, func() error { return parseModelJSON(text, out) })
+	if parseErr == nil {
+		return nil
+	}
+
+	slog.Warn("malformed JSON from model, asking it to repair", "step", schemaName, "model", modelName, "error", parseErr)
+	fixed, err := p.completeText(ctx, modelName, nil, buildRepairPrompt(text, parseErr), schemaName+" (repair)", schema, params)
+	if err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", parseErr)
+	}
+	if err := timeParse(ctx, func() error { return parseModelJSON(fixed, out) }); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return nil
+}
+
+// completeText sends a chat completion of the prompt after the history and returns the
+// answer text. Servers without schema support get the schema appended to the prompt instead.
+func (p *OpenAIProvider) completeText(ctx context.Context, modelName string, history []openaiMessage, prompt, schemaName string, schema map[string]any, params StepParams) (text string, err error) {
+	call := Call{
+		Time:     time.Now(),
+		Step:     schemaName,
+		Provider: ProviderOpenAI,
+		Model:    modelName,
+		Schema:   schema,
+	}
+	defer func() {
+		if err != nil {
+			call.Err = err.Error()
+		}
+		reportCall(call)
+	}()
+
+	body := map[string]any{"model": modelName}
+	if params.Temperature != nil {
+		body["temperature"] = *params.Temperature
+	}
+	if params.TopP != nil {
+		body["top_p"] = *params.TopP
+	}
+	if params.MaxOutputTokens > 0 {
+		body["max_tokens"] = params.MaxOutputTokens
+	}
+	switch p.structuredOutput {
+	case structuredJSONSchema:
+		body["response_format"] = map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   schemaName,
+				"schema": schema,
+			},
+		}
+	case structuredJSONObject:
+		body["response_format"] = map[string]any{"type": "json_object"}
+		prompt += schemaInstructions(schema)
+	default:
+		prompt += schemaInstructions(schema)
+	}
+	body["messages"] = append(history, openaiMessage{Role: "user", Content: prompt})
+	call.Prompt = prompt
+	return p.post(ctx, body, &call)
+}
+
+// fixedTemperature returns parameters with only the temperature set.
+func fixedTemperature(temperature float64) StepParams {
+	return StepParams{Temperature: &temperature}
+}
+
+// history returns the system instruction and the few-shot examples as chat messages.
+func (p *OpenAIProvider) history(shots []shot) []openaiMessage {
+	var messages []openaiMessage
+	if p.prompts.System != "" {
+		messages = append(messages, openaiMessage{Role: "system", Content: p.prompts.System})
+	}
+	for _, s := range shots {
+		messages = append(messages, openaiMessage{Role: "user", Content: s.prompt}, openaiMessage{Role: "assistant", Content: s.answer})
+	}
+	return messages
+}
+
+func (p *OpenAIProvider) ChatModel() string { return p.analysisModelID }
+
+func (p *OpenAIProvider) Chat(ctx context.Context, system string, history []Message) (text string, err error) {
+	history = withOpening(history)
+	call := Call{
+		Time:     time.Now(),
+		Step:     "conversation",
+		Provider: ProviderOpenAI,
+		Model:    p.analysisModelID,
+		Prompt:   transcript(system, history),
+	}
+	defer func() {
+		if err != nil {
+			call.Err = err.Error()
+		}
+		reportCall(call)
+	}()
+
+	messages := []openaiMessage{{Role: "system", Content: system}}
+	for _, msg := range history {
+		role := "user"
+		if msg.Role == RoleModel {
+			role = "assistant"
+		}
+		messages = append(messages, openaiMessage{Role: role, Content: msg.Text})
+	}
+	return p.post(ctx, map[string]any{
+		"model":       p.analysisModelID,
+		"temperature": chatTemperature,
+		"messages":    messages,
+	}, &call)
+}
+
+// post sends a chat-completions request and returns the answer text, recording
+// latency, token usage and the response in call.
+func (p *OpenAIProvider) post(ctx context.Context, body map[string]any, call *Call) (string, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	release, err := limiter.Acquire(ctx, EstimateTokens(string(data)))
+	if err != nil {
+		return "", fmt.Errorf("waiting for the rate limit: %w", err)
+	}
+	defer func() { release(call.InputTokens + call.OutputTokens) }()
+	call.Time = time.Now() // Not counting the wait
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	call.Latency = time.Since(call.Time)
+	addAPITime(ctx, call.Latency)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var chat openaiChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chat); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	call.InputTokens = chat.Usage.PromptTokens
+	call.OutputTokens = chat.Usage.CompletionTokens
+	if len(chat.Choices) == 0 || chat.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("empty response")
+	}
+	call.Response = chat.Choices[0].Message.Content
+	return call.Response, nil
+}
+
+// schemaInstructions describes the expected JSON schema inside the prompt.
+func schemaInstructions(schema map[string]any) string {
+	data, _ := json.MarshalIndent(schema, "", "  ")
+	return "\n\nRespond ONLY with a JSON object (no prose, no code fences) matching this JSON schema:\n" + string(data)
+}
@@ -0,0 +1,93 @@
+package translator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// placeholderPattern matches printf verbs (%s, %d, %1$s, %(name)s), brace placeholders
+// ({name}, {0}, {{count}}, {{.Name}}) and i18n interpolations (%{name}, ${name}, $t(key)).
+var placeholderPattern = regexp.MustCompile(`%(?:\d+\$)?(?:\([A-Za-z_][\w.]*\))?[-+#0]*\d*(?:\.\d+)?[sdfiuxXoegEGqvtcbp%]|%\{[\w.]+\}|\$\{[\w.]+\}|\$t\([^)]*\)|\{\{\s*[\w.-]+\s*\}\}|\{[\w.]*\}`)
+
+// protectedPattern matches spans that are never translated besides placeholders: inline
+// code, spans marked with double brackets ([[Anna]]), URLs and e-mail addresses.
+var protectedPattern = regexp.MustCompile("`[^`\\n]+`" + `|\[\[[^\]\n]+\]\]|https?://[^\s<>"]*[^\s<>".,;:!?)]|[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+
+// Masked is a text with protected spans replaced by numbered tokens that models leave alone.
+type Masked struct {
+	Text  string
+	Spans []string
+}
+
+// maskToken returns the token standing in for span i.
+func maskToken(i int) string {
+	return "⟦" + strconv.Itoa(i) + "⟧"
+}
+
+// MaskPlaceholders replaces the placeholders in text with tokens, so that they pass
+// through translation untouched.
+func MaskPlaceholders(text string) Masked {
+	var spans []string
+	masked := placeholderPattern.ReplaceAllStringFunc(text, func(span string) string {
+		if span == "%%" {
+			return span
+		}
+		spans = append(spans, span)
+		return maskToken(len(spans) - 1)
+	})
+	return Masked{Text: masked, Spans: spans}
+}
+
+// Protect replaces everything that must pass through translation untouched with tokens:
+// inline code, URLs, e-mail addresses, placeholders, spans marked as [[do not translate]]
+// and the given terms, such as names. The brackets of marked spans are dropped on Restore.
+// In Markdown or HTML, the syntax is masked as well, so that only text gets translated.
+func Protect(text string, terms []string) Masked {
+	var spans []string
+	if HasMarkup(text) {
+		text = maskMarkup(text, &spans)
+	}
+
+	pattern := protectedPattern.String() + "|" + placeholderPattern.String()
+	var quoted []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) > 0 {
+		pattern += `|\b(?:` + strings.Join(quoted, "|") + `)\b`
+	}
+
+	masked := regexp.MustCompile(pattern).ReplaceAllStringFunc(text, func(span string) string {
+		if span == "%%" {
+			return span
+		}
+		if strings.HasPrefix(span, "[[") {
+			span = strings.TrimSuffix(strings.TrimPrefix(span, "[["), "]]")
+		}
+		return maskSpan(span, &spans)
+	})
+	return Masked{Text: masked, Spans: spans}
+}
+
+// Restore puts the original spans back into a translation of the masked text.
+// It fails if a token is missing or duplicated, so a mangled translation is not used.
+func (m Masked) Restore(translated string) (string, error) {
+	for i, span := range m.Spans {
+		token := maskToken(i)
+		switch n := strings.Count(translated, token); {
+		case n == 0:
+			return "", fmt.Errorf("placeholder %s was lost in translation", span)
+		case n > 1:
+			return "", fmt.Errorf("placeholder %s was duplicated in translation", span)
+		}
+		translated = strings.Replace(translated, token, span, 1)
+	}
+	if strings.Contains(translated, "⟦") {
+		return "", fmt.Errorf("translation contains unknown placeholder tokens")
+	}
+	return translated, nil
+}
@@ -0,0 +1,735 @@
+package translator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"google.golang.org/genai"
+)
+
+// DefaultTranslationPrompt is the built-in template of the translation prompt.
+// Templates are executed with PromptData.
+const DefaultTranslationPrompt = `You are a professional translator. Translate the sentence and clean it if needed.
+
+INPUT:
+Sentence: "{{.Sentence}}"
+User's language: {{.UserLang}}
+Target language: {{.TargetLang}}
+
+TASK:
+1. Clean the input sentence: fix grammar errors, spelling mistakes, punctuation issues, and formatting problems
+2. Detect which language the cleaned sentence is in ({{.UserLang}} or {{.TargetLang}})
+3. Translate the cleaned sentence naturally and fluently to the OPPOSITE language
+4. The translation MUST be in a different language than the cleaned sentence
+5. The translation should be natural and idiomatic, not word-for-word
+
+IMPORTANT:
+- The cleaned_sentence and translation MUST be in different languages
+- Focus on natural, fluent translation quality
+- Fix any errors in the input sentence
+- Preserve the meaning and tone{{if .Formality}}
+- Use the {{.Formality}} register in the translation, including forms of address such as du/Sie or tu/vous{{end}}`
+
+// buildTranslationPrompt creates the prompt for the translation step from the template,
+// or the built-in one if it is nil.
+func buildTranslationPrompt(tmpl *template.Template, req Request) (string, error) {
+	prompt, err := executePrompt(tmpl, defaultTranslationTemplate, newPromptData(req.Sentence, req))
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(req.Sentence, "⟦") {
+		prompt += "\n- Copy tokens such as ⟦0⟧ unchanged into both sentences, exactly once each; they stand for text that must not be translated"
+	}
+	return prompt + languageNotes(req), nil
+}
+
+// buildTranslationConfig creates the configuration for the translation API call.
+func buildTranslationConfig(userLangName, targetLangName string, settings GeminiSettings) *genai.GenerateContentConfig {
+	config := &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		ResponseJsonSchema: buildTranslationSchema(userLangName, targetLangName),
+	}
+	settings.apply(config, settings.Generation.Translation, settings.TranslationThinkingBudget)
+	return config
+}
+
+// buildTranslationSchema creates the JSON schema of the translation response.
+func buildTranslationSchema(userLangName, targetLangName string) map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"input_language": map[string]any{
+				"type":        "string",
+				"description": fmt.Sprintf("The language of the input sentence: either '%s' or '%s'", userLangName, targetLangName),
+			},
+			"cleaned_sentence": map[string]any{
+				"type":        "string",
+				"description": "The input sentence after cleaning in original input language (fixing grammar, spelling, punctuation, formatting)",
+			},
+			"translation": map[string]any{
+				"type":        "string",
+				"description": "Natural, fluent translation to the opposite language",
+			},
+			"translation_language": map[string]any{
+				"type":        "string",
+				"description": fmt.Sprintf("The language of the translation: either '%s' or '%s'", userLangName, targetLangName),
+			},
+		},
+		"required": []string{"input_language", "cleaned_sentence", "translation", "translation_language"},
+	}
+}
+
+// DefaultAnalysisPrompt is the built-in template of the word analysis prompt.
+// Templates are executed with PromptData, where Sentence is the foreign-language sentence.
+const DefaultAnalysisPrompt = `Analyze each word from the foreign language sentence.
+
+Foreign language sentence ({{.TargetLang}}): "{{.Sentence}}"
+User's language: {{.UserLang}}
+
+TASK:
+For each word in the foreign language sentence, provide a short, concise analysis in {{.UserLang}}.
+Include: translation/meaning and brief grammatical explanation in the context of the whole sentence.
+Also give each word's dictionary form ("lemma"), its part of speech ("pos") and a gloss of a few words ("gloss") in {{.UserLang}}.
+Also estimate the CEFR level (A1-C2) at which a learner typically knows each word, and the level of the whole sentence.
+
+IDIOMS, EXPRESSIONS AND FALSE FRIENDS:
+- Analyze idioms and fixed expressions as a single item: put the whole expression in "word", set "idiom" to true and explain its figurative meaning (and the literal one, if it helps). Do not analyze their words separately.
+- Also group other multi-word expressions that only make sense together, such as reflexive or separable verbs with their prepositions ("ich freue mich auf"), into a single item.
+- For every grouped item, list its words in "parts", each with a short analysis. Leave "parts" empty for single words.
+- If a word looks or sounds like a {{.UserLang}} word but means something different, explain the difference in "false_friend"; otherwise leave it empty.
+
+NOUNS:
+- If {{.TargetLang}} has grammatical gender, always give each noun's gender, its definite article in the nominative singular (der/die/das, el/la, le/la) and its plural form, and mention them in the analysis too.
+- Leave gender, article and plural empty for other words and for languages without grammatical gender.
+
+IMPORTANT:
+- Only analyze actual words{{if .Words}}
+- Only analyze these words of the sentence, in this order: {{join .Words ", "}}{{end}}
+- Keep each analysis short and direct.{{if and (ne .Depth "brief") (not .Words)}}
+
+TONE:
+- In "tone", describe in {{.UserLang}} how the whole sentence comes across: its register ("register"), its level of politeness, including forms of address ("politeness"), and its emotional tone ("emotion"), each in a few words.
+- In "shifts", rephrase the sentence in {{.TargetLang}} to shift its tone, such as to make it more casual and more polite, saying in "toward" what each one shifts it to.{{end}}{{if eq .Depth "brief"}}
+
+DEPTH: brief
+- Give only a gloss of each word: "analysis" is its meaning in a few words, without grammar.{{else if eq .Depth "deep"}}
+
+DEPTH: deep
+- In "morphology", show how each word is built and inflected: its stem, endings and the form used here.
+- In "usage", note its register, typical contexts and common learner mistakes.
+- In "collocations", list up to three common {{.TargetLang}} collocations with it.{{end}}{{if .Cognates}}
+
+ETYMOLOGY:
+- In "etymology", give the origin of each content word in a few words and the cognates it has in {{join .Cognates ", "}}, such as "Swedish 'fönster' ~ German 'Fenster' ~ Latin 'fenestra'".
+- Only give cognates that really share the origin and help to remember the word; leave "etymology" empty for function words and words without such cognates.{{end}}{{if .Segment}}
+
+MORPHEMES:
+- In "morphemes", break each word down into its stem and affixes, or the words of a compound, in order, each as it appears in the word with its meaning or function in {{.UserLang}}, such as "Haus" (house) + "tür" (door).
+- Leave "morphemes" empty for words of a single morpheme.{{end}}{{if .Stress}}
+
+STRESS:
+- In "stress", write each word as it appears in the sentence with its stress marked, since {{.TargetLang}} spelling does not show it: an acute accent on the stressed vowel, such as "молоко́", and for pitch accent languages the pitch instead, such as "はしꜜ" with ꜜ after the mora where the pitch falls or "ta¹nken" and "ta²nken" for Swedish and Norwegian accent 1 and 2.
+- Leave "stress" empty for words of one syllable.{{end}}`
+
+// buildAnalysisPrompt creates the prompt for the word analysis step from the template,
+// or the built-in one if it is nil. If words are given, only they are analyzed.
+func buildAnalysisPrompt(tmpl *template.Template, foreignSentence string, words []string, req Request) (string, error) {
+	data := newPromptData(foreignSentence, req)
+	data.Words = words
+	prompt, err := executePrompt(tmpl, defaultAnalysisTemplate, data)
+	if err != nil {
+		return "", err
+	}
+	return prompt + languageNotes(req), nil
+}
+
+// buildAnalysisConfig creates the configuration for the word analysis API call.
+func buildAnalysisConfig(req Request, settings GeminiSettings) *genai.GenerateContentConfig {
+	config := &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		ResponseJsonSchema: buildAnalysisSchema(req),
+	}
+	settings.apply(config, settings.Generation.Analysis, settings.AnalysisThinkingBudget)
+	return config
+}
+
+// buildAnalysisSchema creates the JSON schema of the word analysis response of req at its
+// depth: brief asks for glosses only, deep adds morphology, usage notes and collocations.
+// Cognates add the etymology of each word, and some target languages its morphemes and
+// stress.
+func buildAnalysisSchema(req Request) map[string]any {
+	userLangName := LanguageName(req.UserLang)
+	targetLangName := LanguageName(req.TargetLang)
+	properties := map[string]any{
+		"word": map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("Exact word, or whole idiom or fixed expression, from the %s sentence", targetLangName),
+		},
+		"analysis": map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("Short, concise analysis in %s: translation/meaning and brief grammatical explanation", userLangName),
+		},
+		"level": map[string]any{
+			"type":        "string",
+			"enum":        CEFRLevels,
+			"description": "Estimated CEFR level at which a learner typically knows the word",
+		},
+		"idiom": map[string]any{
+			"type":        "boolean",
+			"description": "True if the item is an idiom or fixed expression analyzed as a unit",
+		},
+		"false_friend": map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("If the word resembles a %s word with a different meaning, the difference in %s; otherwise empty", userLangName, userLangName),
+		},
+		"lemma": map[string]any{
+			"type":        "string",
+			"description": "Dictionary form of the word, such as the infinitive of a verb; the whole expression for idioms",
+		},
+		"pos": map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("Part of speech in %s, such as verb, noun or preposition", userLangName),
+		},
+		"gloss": map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("Translation of the word into %s in a few words", userLangName),
+		},
+		"gender": map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("Grammatical gender of a %s noun: %s; empty for other words", targetLangName, strings.Join(genders, ", ")),
+		},
+		"article": map[string]any{
+			"type":        "string",
+			"description": "Definite article of a noun in the nominative singular; empty for other words or languages without articles",
+		},
+		"plural": map[string]any{
+			"type":        "string",
+			"description": "Plural form of a noun; empty for other words",
+		},
+		"parts": map[string]any{
+			"type":        "array",
+			"description": "The words of a multi-word expression or idiom with their analysis; empty for single words",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"word":     map[string]any{"type": "string"},
+					"analysis": map[string]any{"type": "string"},
+				},
+				"required": []string{"word", "analysis"},
+			},
+		},
+	}
+	required := []string{"word", "analysis", "level", "idiom", "false_friend", "lemma", "pos", "gloss", "gender", "article", "plural", "parts"}
+	switch req.Depth {
+	case DepthBrief:
+		required = []string{"word", "analysis", "level", "lemma", "pos", "gloss"}
+		for name := range properties {
+			if !slices.Contains(required, name) {
+				delete(properties, name)
+			}
+		}
+	case DepthDeep:
+		properties["morphology"] = map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("How the word is built and inflected: stem, endings and the form used here, in %s", userLangName),
+		}
+		properties["usage"] = map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("Usage notes in %s: register, typical contexts and common mistakes", userLangName),
+		}
+		properties["collocations"] = map[string]any{
+			"type":        "array",
+			"description": fmt.Sprintf("Up to three common %s collocations with the word", targetLangName),
+			"items":       map[string]any{"type": "string"},
+		}
+		required = append(required, "morphology", "usage", "collocations")
+	}
+	if len(req.Cognates) > 0 {
+		properties["etymology"] = map[string]any{
+			"type":        "string",
+			"description": fmt.Sprintf("Origin of the word and its cognates in %s, in %s; empty for function words and words without cognates", strings.Join(languageNames(req.Cognates), ", "), userLangName),
+		}
+		required = append(required, "etymology")
+	}
+	if segments(req) {
+		properties["morphemes"] = map[string]any{
+			"type":        "array",
+			"description": fmt.Sprintf("The stem and affixes of the word, or the words of a compound, in order, with their meaning in %s; empty for words of a single morpheme", userLangName),
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"text":  map[string]any{"type": "string"},
+					"gloss": map[string]any{"type": "string"},
+				},
+				"required": []string{"text", "gloss"},
+			},
+		}
+		required = append(required, "morphemes")
+	}
+	if marksStress(req) {
+		properties["stress"] = map[string]any{
+			"type":        "string",
+			"description": "The word as written in the sentence with its stress or pitch accent marked",
+		}
+		required = append(required, "stress")
+	}
+
+	schema := map[string]any{
+		"word_analysis": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type":       "object",
+				"properties": properties,
+				"required":   required,
+			},
+		},
+		"sentence_level": map[string]any{
+			"type":        "string",
+			"enum":        CEFRLevels,
+			"description": "Estimated CEFR level of the whole sentence",
+		},
+	}
+	required = []string{"word_analysis", "sentence_level"}
+	if analyzesTone(req) {
+		schema["tone"] = map[string]any{
+			"type":        "object",
+			"description": fmt.Sprintf("How the whole sentence comes across, in %s", userLangName),
+			"properties": map[string]any{
+				"register":   map[string]any{"type": "string"},
+				"politeness": map[string]any{"type": "string"},
+				"emotion":    map[string]any{"type": "string"},
+				"shifts": map[string]any{
+					"type":        "array",
+					"description": fmt.Sprintf("The sentence rephrased in %s to shift its tone, such as more casual or more polite", targetLangName),
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"toward":   map[string]any{"type": "string"},
+							"sentence": map[string]any{"type": "string"},
+						},
+						"required": []string{"toward", "sentence"},
+					},
+				},
+			},
+			"required": []string{"register", "politeness", "emotion", "shifts"},
+		}
+		required = append(required, "tone")
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": schema,
+		"required":   required,
+	}
+}
+
+// buildComparisonPrompt creates the prompt comparing two phrasings in the target language.
+func buildComparisonPrompt(first, second, userLangName, targetLangName string) string {
+	return fmt.Sprintf(`Explain the difference between two %s phrasings to a learner whose own language is %s.
+
+First: "%s"
+Second: "%s"
+
+TASK:
+Compare them in %s:
+1. Meaning: how the meaning or nuance differs, or that it is the same
+2. Register: formal, neutral, casual, regional or dated use
+3. Grammar: differences in grammar or construction
+4. When to use each of them, with a short %s example sentence
+
+IMPORTANT:
+- Be concrete and brief: one to three sentences per point
+- If one of them is wrong or unidiomatic, say so`, targetLangName, userLangName, first, second, userLangName, targetLangName)
+}
+
+// buildComparisonConfig creates the configuration for the comparison API call.
+func buildComparisonConfig() *genai.GenerateContentConfig {
+	return &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		Temperature:        genai.Ptr(float32(analysisTemperature)),
+		ResponseJsonSchema: buildComparisonSchema(),
+	}
+}
+
+// buildComparisonSchema creates the JSON schema of the comparison response.
+func buildComparisonSchema() map[string]any {
+	field := func(description string) map[string]any {
+		return map[string]any{"type": "string", "description": description}
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"meaning":    field("Difference in meaning or nuance"),
+			"register":   field("Difference in register: formal, neutral, casual, regional or dated"),
+			"grammar":    field("Differences in grammar or construction"),
+			"use_first":  field("When to use the first phrasing, with an example"),
+			"use_second": field("When to use the second phrasing, with an example"),
+		},
+		"required": []string{"meaning", "register", "grammar", "use_first", "use_second"},
+	}
+}
+
+// buildSimplificationPrompt creates the prompt rewriting a target-language sentence at a
+// simpler CEFR level, or asking for three paraphrases if level is empty.
+func buildSimplificationPrompt(sentence, level, userLangName, targetLangName string) string {
+	task := fmt.Sprintf(`Rewrite the sentence in simpler %s at CEFR level %s, keeping its meaning. Give one rewrite, or two if there are clearly different ways to simplify it.`, targetLangName, level)
+	if level == "" {
+		task = fmt.Sprintf(`Give three different paraphrases of the sentence in %s at about the same level, keeping its meaning.`, targetLangName)
+	}
+	return fmt.Sprintf(`You help a learner whose own language is %s read authentic %s material.
+
+Sentence: "%s"
+
+TASK:
+%s
+For each rewrite, estimate its CEFR level and explain briefly in %s what changed: words replaced by simpler ones, restructured grammar, split sentences.
+
+IMPORTANT:
+- Rewrites must be natural, correct %s
+- Keep each explanation to one or two sentences`, userLangName, targetLangName, sentence, task, userLangName, targetLangName)
+}
+
+// buildSimplificationConfig creates the configuration for the simplification API call.
+func buildSimplificationConfig() *genai.GenerateContentConfig {
+	return &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		Temperature:        genai.Ptr(float32(translationTemperature)),
+		ResponseJsonSchema: buildSimplificationSchema(),
+	}
+}
+
+// buildSimplificationSchema creates the JSON schema of the simplification response.
+func buildSimplificationSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"rewrites": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"text":    map[string]any{"type": "string", "description": "The rewritten sentence"},
+						"level":   map[string]any{"type": "string", "description": "Estimated CEFR level of the rewrite (A1 to C2)"},
+						"changes": map[string]any{"type": "string", "description": "What changed compared to the original"},
+					},
+					"required": []string{"text", "level", "changes"},
+				},
+			},
+		},
+		"required": []string{"rewrites"},
+	}
+}
+
+// buildDetectionPrompt creates the prompt identifying the language of a text.
+func buildDetectionPrompt(text string) string {
+	return fmt.Sprintf(`Identify the language the text is written in.
+
+Text: "%s"
+
+IMPORTANT:
+- Give the ISO 639-1 code of the language, such as "sv" or "pt", and its English name
+- Set ambiguous if the text is too short or too generic to tell between several languages, and give the most likely one
+- If the text mixes languages, give the one most of it is written in`, text)
+}
+
+// buildDetectionConfig creates the configuration for the language detection API call.
+func buildDetectionConfig() *genai.GenerateContentConfig {
+	return &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		Temperature:        genai.Ptr(float32(analysisTemperature)),
+		ResponseJsonSchema: buildDetectionSchema(),
+	}
+}
+
+// buildDetectionSchema creates the JSON schema of the language detection response.
+func buildDetectionSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"language":  map[string]any{"type": "string", "description": "ISO 639-1 code of the language"},
+			"name":      map[string]any{"type": "string", "description": "English name of the language"},
+			"ambiguous": map[string]any{"type": "boolean", "description": "Whether the text fits several languages equally well"},
+		},
+		"required": []string{"language", "name", "ambiguous"},
+	}
+}
+
+// buildConjugationPrompt creates the prompt asking for the conjugation table of a verb.
+func buildConjugationPrompt(verb, userLangName, targetLangName string) string {
+	return fmt.Sprintf(`Conjugate the %s verb "%s" for a learner whose own language is %s.
+
+TASK:
+1. Give the dictionary form of the verb, such as the infinitive
+2. Give its forms for every person, singular and plural, in the present tense and in the two or three other tenses and moods learners use most
+3. For each form, name the tense and the person in %s, give the subject pronoun in %s, and the form itself
+
+IMPORTANT:
+- The form does not include the pronoun, but does include auxiliaries and particles, such as "habe gesehen"
+- For a polite form that is conjugated like another person, add it as a person of its own only if its form differs
+- If the word is not a verb, give its dictionary form and no forms`, targetLangName, verb, userLangName, userLangName, targetLangName)
+}
+
+// buildConjugationConfig creates the configuration for the conjugation API call.
+func buildConjugationConfig() *genai.GenerateContentConfig {
+	return &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		Temperature:        genai.Ptr(float32(analysisTemperature)),
+		ResponseJsonSchema: buildConjugationSchema(),
+	}
+}
+
+// buildConjugationSchema creates the JSON schema of the conjugation response.
+func buildConjugationSchema() map[string]any {
+	field := func(description string) map[string]any {
+		return map[string]any{"type": "string", "description": description}
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"lemma": field("Dictionary form of the verb"),
+			"forms": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"tense":   field("Tense or mood, in the learner's language"),
+						"person":  field("Person and number, in the learner's language"),
+						"pronoun": field("Subject pronoun of the person, or empty"),
+						"form":    field("Conjugated form without the pronoun"),
+					},
+					"required": []string{"tense", "person", "pronoun", "form"},
+				},
+			},
+		},
+		"required": []string{"lemma", "forms"},
+	}
+}
+
+// buildCaseQuizPrompt creates the prompt making up a case government quiz from sentences.
+func buildCaseQuizPrompt(sentences []string, userLangName, targetLangName string) string {
+	quoted := make([]string, len(sentences))
+	for i, sentence := range sentences {
+		quoted[i] = fmt.Sprintf("- \"%s\"", sentence)
+	}
+	return fmt.Sprintf(`Make up a quiz on case government in %s for a learner whose own language is %s, from sentences the learner translated.
+
+Sentences:
+%s
+
+TASK:
+Find up to ten prepositions and verbs in the sentences whose object is in a case they govern, preferring different words. For each of them give:
+1. The preposition or verb as it appears in the sentence
+2. The phrase of the sentence it governs, with the governed noun phrase in brackets in its dictionary form, such as "mit [der Bus]" for "mit dem Bus"
+3. The case of the governed noun phrase in the sentence
+4. The phrase as it appears in the sentence
+5. In %s, in one or two sentences, why this case is used, such as the preposition always governing it, or motion as opposed to location
+
+IMPORTANT:
+- Leave out words whose object shows no case, and subjects in the nominative
+- For a preposition that governs more than one case, explain what decides it here`, targetLangName, userLangName, strings.Join(quoted, "\n"), userLangName)
+}
+
+// buildCaseQuizConfig creates the configuration for the case quiz API call.
+func buildCaseQuizConfig(cases []string) *genai.GenerateContentConfig {
+	return &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		Temperature:        genai.Ptr(float32(analysisTemperature)),
+		ResponseJsonSchema: buildCaseQuizSchema(cases),
+	}
+}
+
+// buildCaseQuizSchema creates the JSON schema of the case quiz response, with the cases
+// of the language as the answers.
+func buildCaseQuizSchema(cases []string) map[string]any {
+	field := func(description string) map[string]any {
+		return map[string]any{"type": "string", "description": description}
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"questions": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"word":        field("The preposition or verb governing the case"),
+						"phrase":      field("The governed phrase with the noun phrase in its dictionary form in brackets"),
+						"answer":      map[string]any{"type": "string", "enum": cases, "description": "Case of the governed noun phrase"},
+						"example":     field("The phrase as it appears in the sentence"),
+						"explanation": field("Why the case is used, in the learner's language"),
+					},
+					"required": []string{"word", "phrase", "answer", "example", "explanation"},
+				},
+			},
+		},
+		"required": []string{"questions"},
+	}
+}
+
+// buildWordDetailsPrompt creates the prompt asking for the synonyms, antonyms and common
+// phrases of a word.
+func buildWordDetailsPrompt(word, sentence, userLangName, targetLangName string) string {
+	return fmt.Sprintf(`Give the synonyms, antonyms and most common phrases of the %s word "%s" as it is used in this sentence, for a learner whose own language is %s.
+
+Sentence: "%s"
+
+TASK:
+1. List up to five %s synonyms that could replace the word in this sense, the most common first
+2. List up to three %s antonyms of the word in this sense, the most common first
+3. For each, give its dictionary form, a translation into %s in a few words, and in %s how its register or nuance differs from the word, such as formal, colloquial, regional, dated or stronger; leave the note empty if they are interchangeable
+4. List up to five of the most common %s collocations and set phrases with the word in this sense, such as the verbs and adjectives it goes with, in their dictionary form, each with its translation into %s
+
+IMPORTANT:
+- Only give real words and phrases in common use, never made-up ones or the word itself
+- Prefer phrases a learner needs to speak naturally, such as "donositi odluku" (to make a decision) for "odluka"
+- Leave a list empty if the word has none, as for most function words`, targetLangName, word, userLangName, sentence, targetLangName, targetLangName, userLangName, userLangName, targetLangName, userLangName)
+}
+
+// buildWordDetailsConfig creates the configuration for the word details API call.
+func buildWordDetailsConfig() *genai.GenerateContentConfig {
+	return &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		Temperature:        genai.Ptr(float32(analysisTemperature)),
+		ResponseJsonSchema: buildWordDetailsSchema(),
+	}
+}
+
+// buildWordDetailsSchema creates the JSON schema of the word details response.
+func buildWordDetailsSchema() map[string]any {
+	related := func(description string) map[string]any {
+		return map[string]any{
+			"type":        "array",
+			"description": description,
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"word":     map[string]any{"type": "string", "description": "Dictionary form"},
+					"gloss":    map[string]any{"type": "string", "description": "Translation in a few words, in the learner's language"},
+					"register": map[string]any{"type": "string", "description": "How its register or nuance differs, in the learner's language, or empty"},
+				},
+				"required": []string{"word", "gloss", "register"},
+			},
+		}
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"synonyms": related("Words of the same meaning in this sense"),
+			"antonyms": related("Words of the opposite meaning in this sense"),
+			"phrases": map[string]any{
+				"type":        "array",
+				"description": "Most common collocations and set phrases with the word",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"text":        map[string]any{"type": "string", "description": "The phrase in its dictionary form"},
+						"translation": map[string]any{"type": "string", "description": "Translation in the learner's language"},
+					},
+					"required": []string{"text", "translation"},
+				},
+			},
+		},
+		"required": []string{"synonyms", "antonyms", "phrases"},
+	}
+}
+
+// buildNumberGradePrompt creates the prompt grading a figure written out in words.
+func buildNumberGradePrompt(figure, kind, answer, userLangName, targetLangName string) string {
+	return fmt.Sprintf(`A learner whose own language is %s wrote out a %s in %s words, as it is said aloud.
+
+%s: %s
+Answer: "%s"
+
+Dates are given as year-month-day, prices in euros with a decimal point and times on the 24-hour clock; the answer may use any way of saying them that is natural in %s.
+
+TASK:
+1. Decide whether the answer is correct: a native speaker would say it this way and it means the same figure
+2. Give the figure written out correctly in %s, close to the answer if it was correct
+3. If it is wrong, explain the mistake in %s in one or two sentences
+
+IMPORTANT:
+- Ignore capitalization and punctuation, and spelling variants that are both accepted
+- A word order, ending or gender that is wrong makes the answer wrong`, userLangName, kind, targetLangName, strings.ToUpper(kind[:1])+kind[1:], figure, answer, targetLangName, targetLangName, userLangName)
+}
+
+// buildNumberGradeConfig creates the configuration for the number grading API call.
+func buildNumberGradeConfig() *genai.GenerateContentConfig {
+	return &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		Temperature:        genai.Ptr(float32(analysisTemperature)),
+		ResponseJsonSchema: buildNumberGradeSchema(),
+	}
+}
+
+// buildNumberGradeSchema creates the JSON schema of the number grading response.
+func buildNumberGradeSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"correct":     map[string]any{"type": "boolean", "description": "Whether the answer is the figure written out correctly"},
+			"expected":    map[string]any{"type": "string", "description": "The figure written out correctly in words"},
+			"explanation": map[string]any{"type": "string", "description": "The mistake, if any, explained in the learner's language"},
+		},
+		"required": []string{"correct", "expected", "explanation"},
+	}
+}
+
+// ConversationInstruction creates the system instruction of a conversation practice
+// session in the target language at a CEFR level such as "A2".
+func ConversationInstruction(userLang, targetLang, level string) string {
+	return fmt.Sprintf(`You are a friendly conversation partner helping someone practice %s. Their own language is %s and their level is %s (CEFR).
+
+RULES:
+- Reply only in %s, using vocabulary and grammar suitable for level %s
+- Keep each reply to one to three short sentences
+- Keep the conversation going, usually by ending with a question
+- If the learner makes a mistake, naturally use the correct form in your reply instead of lecturing
+- If the learner writes in %s, answer in %s anyway
+- Open the conversation with a greeting and a simple question`,
+		LanguageName(targetLang), LanguageName(userLang), level, LanguageName(targetLang), level, LanguageName(userLang), LanguageName(targetLang))
+}
+
+// withOpening returns the history of a conversation, or a request to open it if it is empty,
+// since models need at least one user turn.
+func withOpening(history []Message) []Message {
+	if len(history) == 0 {
+		return []Message{{Role: RoleUser, Text: "Please start the conversation."}}
+	}
+	return history
+}
+
+// transcript renders a conversation as text for call records.
+func transcript(system string, history []Message) string {
+	var b strings.Builder
+	b.WriteString("SYSTEM: " + system)
+	for _, msg := range history {
+		b.WriteString("\n\n" + strings.ToUpper(msg.Role) + ": " + msg.Text)
+	}
+	return b.String()
+}
+
+// FollowUpInstruction creates the system instruction for answering questions about a
+// translation result, which is given to the model as context.
+func FollowUpInstruction(userLang, targetLang string, result Result) string {
+	var words strings.Builder
+	for _, w := range result.Words {
+		fmt.Fprintf(&words, "- %s: %s\n", w.WordInTargetLang, w.GrammaticalExplanation)
+	}
+	level := ""
+	if result.Level != "" {
+		level = fmt.Sprintf("Estimated level: %s\n", result.Level)
+	}
+	return fmt.Sprintf(`You are a patient %s teacher. The learner's own language is %s. They translated a sentence and got this result:
+
+Original: "%s"
+Translation: "%s"
+%sWord analysis:
+%s
+TASK:
+- Answer the learner's follow-up questions about this sentence, its grammar, word choice and alternatives
+- Answer in %s, quoting %s examples where they help
+- Keep answers short and concrete: a few sentences, or a short list`,
+		LanguageName(targetLang), LanguageName(userLang), result.Original, result.Translation, level, words.String(), LanguageName(userLang), LanguageName(targetLang))
+}
@@ -0,0 +1,110 @@
+package translator
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// rateWindow is the period over which the rate limits are counted.
+const rateWindow = time.Minute
+
+// RateLimiter queues model API calls so that at most a number of requests and tokens
+// are used in any minute, whichever pipeline, batch or lookup makes them. It is safe
+// for concurrent use.
+type RateLimiter struct {
+	mu     sync.Mutex
+	qpm    int // Requests per minute; 0 means unlimited
+	tpm    int // Tokens per minute; 0 means unlimited
+	recent []*rateEntry
+	now    func() time.Time
+}
+
+// rateEntry is a call made within the last minute and the tokens it used, or the
+// estimate of them until the call finishes.
+type rateEntry struct {
+	time   time.Time
+	tokens int
+}
+
+// NewRateLimiter returns a limiter of qpm requests and tpm tokens per minute, where 0
+// leaves either unlimited.
+func NewRateLimiter(qpm, tpm int) *RateLimiter {
+	return &RateLimiter{qpm: qpm, tpm: tpm, now: time.Now}
+}
+
+// SetLimits changes the limits, keeping the calls already counted.
+func (l *RateLimiter) SetLimits(qpm, tpm int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.qpm, l.tpm = qpm, tpm
+}
+
+// Acquire waits until a call estimated to use tokens fits the limits, or ctx is done.
+// The returned function records the tokens the call actually used, if known (> 0).
+// A call larger than the token limit on its own waits for an empty minute.
+func (l *RateLimiter) Acquire(ctx context.Context, tokens int) (func(used int), error) {
+	for {
+		entry, wait := l.reserve(tokens)
+		if entry != nil {
+			return func(used int) {
+				if used > 0 {
+					l.mu.Lock()
+					entry.tokens = used
+					l.mu.Unlock()
+				}
+			}, nil
+		}
+		slog.Info("rate limit reached, waiting", "wait", wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve counts a call if it fits the limits now, or returns how long to wait before
+// trying again.
+func (l *RateLimiter) reserve(tokens int) (*rateEntry, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	i := 0
+	for i < len(l.recent) && now.Sub(l.recent[i].time) >= rateWindow {
+		i++
+	}
+	l.recent = l.recent[i:]
+
+	used := 0
+	for _, e := range l.recent {
+		used += e.tokens
+	}
+	full := l.qpm > 0 && len(l.recent) >= l.qpm
+	full = full || l.tpm > 0 && len(l.recent) > 0 && used+tokens > l.tpm
+	if full {
+		return nil, l.recent[0].time.Add(rateWindow).Sub(now)
+	}
+	entry := &rateEntry{time: now, tokens: tokens}
+	l.recent = append(l.recent, entry)
+	return entry, 0
+}
+
+// limiter is the rate limiter of all model API calls; unlimited until SetRateLimit.
+var limiter = NewRateLimiter(0, 0)
+
+// SetRateLimit limits the model API calls of all providers to qpm requests and tpm
+// tokens per minute, where 0 leaves either unlimited.
+func SetRateLimit(qpm, tpm int) {
+	limiter.SetLimits(qpm, tpm)
+}
+
+// EstimateTokens roughly estimates the tokens of a text, at four bytes per token as
+// is typical of English.
+func EstimateTokens(text string) int {
+	return max(len(text)/4, utf8.RuneCountInString(text)/3, 1)
+}
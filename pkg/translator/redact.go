@@ -0,0 +1,168 @@
+package translator
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// nameWord matches a capitalized word such as Anna, O'Brien or Jean-Luc.
+const nameWord = `\p{Lu}\p{Ll}+(?:['’-]\p{Lu}?\p{Ll}+)*`
+
+var (
+	// emailPattern matches e-mail addresses.
+	emailPattern = regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+
+	// phonePattern matches candidates for phone numbers: runs of digits with the
+	// separators and brackets phone numbers are written with. isPhoneNumber decides.
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d ()./-]{5,}\d`)
+
+	// datePattern matches numeric dates, which look like phone numbers.
+	datePattern = regexp.MustCompile(`^(?:\d{1,2}[./-]\d{1,2}[./-]\d{2,4}|\d{4}[./-]\d{1,2}[./-]\d{1,2})$`)
+
+	// namesPattern matches runs of capitalized words.
+	namesPattern = regexp.MustCompile(nameWord + `(?:[ \t]+` + nameWord + `)*`)
+
+	// nameGap matches the space between the words of a run of names.
+	nameGap = regexp.MustCompile(`[ \t]+`)
+
+	// addressedPattern matches names after titles (Mr Smith, Frau Weber), greetings
+	// (Dear Anna, Hej Erik) and sign-offs (Best regards, Anna); the names are submatch 1.
+	addressedPattern = regexp.MustCompile(`(?:\b(?:Mr|Mrs|Ms|Miss|Mx|Dr|Prof|Herr|Frau|Fru|Monsieur|Madame|Mme|Mlle|Señor|Señora|Señorita|Sr|Sra|Srta|Signor|Signora|Sig|Dott)\.?|(?i:\b(?:hi|hello|hey|dear|hej|hallo|liebe|lieber|sehr geehrte|sehr geehrter|hola|querida|querido|estimada|estimado|bonjour|salut|cher|chère|ciao|cara|caro|gentile|kära|bästa|regards|thanks|thank you|cheers|grüße|gruß|grüßen|hälsningar|mvh|saludos|cordialement|saluti)[,.!]?))[ \t\n]+(` + nameWord + `(?:[ \t]+` + nameWord + `)?)`)
+
+	// sentenceEnd matches the end of the text before a sentence.
+	sentenceEnd = regexp.MustCompile(`(?:^|[.!?:;…"“„«»(\n⟧])\s*$`)
+)
+
+// nounCapitalizing lists languages that capitalize every noun, where capitalized words
+// are no sign of names.
+var nounCapitalizing = map[string]bool{"de": true, "lb": true}
+
+// Redact masks the personal data in text with tokens, adding the data to the spans of
+// m, and returns the masked text: e-mail addresses, phone numbers and names. Names are
+// recognized after titles, greetings and sign-offs, and, unless text is in a language
+// that capitalizes nouns, as capitalized words within sentences. langs are the languages
+// text may be in. Recognizing names is a heuristic: it errs on masking too much, and
+// names written in lowercase get through.
+func (m *Masked) Redact(text string, langs ...string) string {
+	text = emailPattern.ReplaceAllStringFunc(text, func(span string) string {
+		return maskSpan(span, &m.Spans)
+	})
+	text = phonePattern.ReplaceAllStringFunc(text, func(span string) string {
+		if !isPhoneNumber(span) {
+			return span
+		}
+		return maskSpan(strings.TrimSpace(span), &m.Spans)
+	})
+	text = replaceSubmatch(addressedPattern, text, func(names string) string {
+		return maskSpan(names, &m.Spans)
+	})
+
+	lang := GuessLanguage(text, langs...)
+	if nounCapitalizing[lang] || (lang == "" && slices.ContainsFunc(langs, func(l string) bool { return nounCapitalizing[l] })) {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range namesPattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if sentenceEnd.MatchString(text[:start]) {
+			// The first word of a sentence is capitalized anyway, so only the words after
+			// it count as names
+			gap := nameGap.FindStringIndex(text[start:end])
+			if gap == nil {
+				continue
+			}
+			start += gap[1]
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(maskSpan(text[start:end], &m.Spans))
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// Expand puts the spans back wherever their tokens are in text. Unlike Restore, it does
+// not mind lost or repeated tokens, for text such as explanations that may mention
+// a span any number of times.
+func (m Masked) Expand(text string) string {
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		i := tokenIndex(token)
+		if i < 0 || i >= len(m.Spans) {
+			return token
+		}
+		return m.Spans[i]
+	})
+}
+
+// ExpandAll expands the tokens in all strings of v, a pointer to a value that is
+// encoded as JSON, such as a model's structured response.
+func (m Masked) ExpandAll(v any) error {
+	if len(m.Spans) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	expanded := tokenPattern.ReplaceAllStringFunc(string(data), func(token string) string {
+		i := tokenIndex(token)
+		if i < 0 || i >= len(m.Spans) {
+			return token
+		}
+		quoted, _ := json.Marshal(m.Spans[i])
+		return string(quoted[1 : len(quoted)-1])
+	})
+	return json.Unmarshal([]byte(expanded), v)
+}
+
+// isToken reports whether s consists of tokens alone, such as a word analysis entry
+// for a masked name.
+func isToken(s string) bool {
+	return strings.TrimSpace(tokenPattern.ReplaceAllString(s, "")) == "" && tokenPattern.MatchString(s)
+}
+
+// tokenIndex returns the span index of a token, or -1.
+func tokenIndex(token string) int {
+	n := 0
+	for _, r := range strings.TrimSuffix(strings.TrimPrefix(token, "⟦"), "⟧") {
+		if !unicode.IsDigit(r) {
+			return -1
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
+
+// isPhoneNumber reports whether a candidate span is a phone number: 7 to 15 digits
+// that start with + or 0 or are grouped, and are not a date.
+func isPhoneNumber(span string) bool {
+	span = strings.TrimSpace(span)
+	digits := 0
+	for _, r := range span {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	if digits < 7 || digits > 15 || datePattern.MatchString(span) {
+		return false
+	}
+	grouped := strings.ContainsAny(span, " ()./-")
+	return grouped || strings.HasPrefix(span, "+") || strings.HasPrefix(span, "0")
+}
+
+// replaceSubmatch replaces submatch 1 of each match of re in text with what fn makes of it.
+func replaceSubmatch(re *regexp.Regexp, text string, fn func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(text[last:loc[2]])
+		b.WriteString(fn(text[loc[2]:loc[3]]))
+		last = loc[3]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
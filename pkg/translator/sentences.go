@@ -0,0 +1,105 @@
+package translator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// abbreviations are words ending in a period that do not end a sentence.
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "st": true,
+	"e.g": true, "i.e": true, "vs": true, "z.b": true, "bzw": true, "usw": true,
+	"d.h": true, "sr": true, "sra": true, "srta": true, "t.ex": true, "bl.a": true,
+}
+
+// SplitSentences splits text into sentences at sentence-ending punctuation followed by
+// the start of a new sentence, and at blank lines. Known abbreviations and initials
+// do not end a sentence.
+func SplitSentences(text string) []string {
+	var sentences []string
+	var current strings.Builder
+	flush := func() {
+		if s := strings.Join(strings.Fields(current.String()), " "); s != "" {
+			sentences = append(sentences, s)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\n' && i+1 < len(runes) && isBlankLineAhead(runes[i+1:]) {
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+		if !isSentenceEnd(r) {
+			continue
+		}
+		// Keep repeated punctuation and closing quotes with the sentence
+		for i+1 < len(runes) && (isSentenceEnd(runes[i+1]) || isClosingMark(runes[i+1])) {
+			i++
+			current.WriteRune(runes[i])
+		}
+		if r == '.' && isAbbreviation(current.String()) {
+			continue
+		}
+		if startsSentence(runes[i+1:]) {
+			flush()
+		}
+	}
+	flush()
+	return sentences
+}
+
+// isSentenceEnd reports whether r ends a sentence.
+func isSentenceEnd(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '…' || r == '。'
+}
+
+// isClosingMark reports whether r is a closing quote or bracket.
+func isClosingMark(r rune) bool {
+	return strings.ContainsRune(`"')]»”’“«`, r)
+}
+
+// isBlankLineAhead reports whether rest starts with a line containing only whitespace.
+func isBlankLineAhead(rest []rune) bool {
+	for _, r := range rest {
+		if r == '\n' {
+			return true
+		}
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return false
+}
+
+// isAbbreviation reports whether the text so far ends with an abbreviation or an initial.
+func isAbbreviation(text string) bool {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return false
+	}
+	word := strings.ToLower(strings.TrimSuffix(fields[len(fields)-1], "."))
+	word = strings.TrimLeft(word, `"'(«“‘`)
+	return abbreviations[word] || len([]rune(word)) == 1 && unicode.IsLetter([]rune(word)[0])
+}
+
+// startsSentence reports whether rest begins with whitespace followed by the start of a
+// sentence: an upper-case letter, a digit or an opening mark. The end of the text also counts.
+func startsSentence(rest []rune) bool {
+	if len(rest) == 0 {
+		return true
+	}
+	if !unicode.IsSpace(rest[0]) {
+		return false
+	}
+	for _, r := range rest {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		return unicode.IsUpper(r) || unicode.IsDigit(r) || strings.ContainsRune(`"'(«“‘¿¡`, r)
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package translator
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// PromptData holds the variables available to prompt templates.
+type PromptData struct {
+	Sentence       string   // The sentence to translate, or the foreign-language sentence to analyze
+	UserLang       string   // Name of the language the user knows, such as "English"
+	TargetLang     string   // Name of the language being learned
+	UserLangCode   string   // Code of the language the user knows, such as "en"
+	TargetLangCode string   // Code of the language being learned
+	Level          string   // The user's CEFR level in the language being learned; may be empty
+	Glossary       []string // Terms that are never translated; may be empty
+	Formality      string   // "formal" or "informal" register of the translation; may be empty
+	Depth          string   // Depth of the word analysis: "brief", "standard" or "deep"
+	Cognates       []string // Names of the languages to give cognates in; empty leaves etymology out
+	Segment        bool     // Whether to break words down into morphemes
+	Stress         bool     // Whether to mark the stress or pitch accent of words
+	Words          []string // Words of the sentence to analyze; empty means all of them
+}
+
+// Prompts customizes the prompts of the pipeline steps.
+type Prompts struct {
+	// Translation and Analysis replace the built-in prompt templates; nil means the
+	// built-in one.
+	Translation *template.Template
+	Analysis    *template.Template
+
+	// System is sent as the system instruction of both steps, e.g. "Always explain
+	// Serbian clitics explicitly".
+	System string
+
+	// TranslationExamples and AnalysisExamples are shown to the model before the actual
+	// request of their step, each as a prompt built from the template and its answer.
+	TranslationExamples []Example
+	AnalysisExamples    []Example
+}
+
+// Example is a sample input of a pipeline step with the answer expected from the model,
+// for few-shot prompting.
+type Example struct {
+	Input  string `toml:"input"`  // The sentence of the prompt
+	Output string `toml:"output"` // The answer, JSON following the response schema of the step
+}
+
+// shot is one example turn pair of a few-shot prompt.
+type shot struct {
+	prompt string
+	answer string
+}
+
+// translationShots builds the prompts of the translation examples for the request.
+func (p Prompts) translationShots(req Request) ([]shot, error) {
+	shots := make([]shot, len(p.TranslationExamples))
+	for i, e := range p.TranslationExamples {
+		exampleReq := req
+		exampleReq.Sentence = e.Input
+		prompt, err := buildTranslationPrompt(p.Translation, exampleReq)
+		if err != nil {
+			return nil, err
+		}
+		shots[i] = shot{prompt: prompt, answer: e.Output}
+	}
+	return shots, nil
+}
+
+// analysisShots builds the prompts of the analysis examples for the request.
+func (p Prompts) analysisShots(req Request) ([]shot, error) {
+	shots := make([]shot, len(p.AnalysisExamples))
+	for i, e := range p.AnalysisExamples {
+		prompt, err := buildAnalysisPrompt(p.Analysis, e.Input, nil, req)
+		if err != nil {
+			return nil, err
+		}
+		shots[i] = shot{prompt: prompt, answer: e.Output}
+	}
+	return shots, nil
+}
+
+// promptFuncs are the functions available to prompt templates besides the built-in ones.
+var promptFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+var (
+	defaultTranslationTemplate = template.Must(ParsePrompt("translation", DefaultTranslationPrompt))
+	defaultAnalysisTemplate    = template.Must(ParsePrompt("analysis", DefaultAnalysisPrompt))
+)
+
+// ParsePrompt parses a prompt template. Besides the built-in functions of text/template,
+// templates can use join, lower and upper from the strings package. References to
+// variables that PromptData lacks are reported here rather than on the first request.
+func ParsePrompt(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(promptFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s prompt template: %w", name, err)
+	}
+	sample := PromptData{Sentence: "Ich bin müde", UserLang: "English", TargetLang: "German", UserLangCode: "en", TargetLangCode: "de", Level: "A2", Glossary: []string{"Anna"}, Formality: "formal", Depth: DepthStandard, Cognates: []string{"English"}, Segment: true, Stress: true, Words: []string{"müde"}}
+	if err := tmpl.Execute(&strings.Builder{}, sample); err != nil {
+		return nil, fmt.Errorf("invalid %s prompt template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// newPromptData returns the variables of a prompt about sentence for the request.
+func newPromptData(sentence string, req Request) PromptData {
+	return PromptData{
+		Sentence:       sentence,
+		UserLang:       LanguageName(req.UserLang),
+		TargetLang:     LanguageName(req.TargetLang),
+		UserLangCode:   req.UserLang,
+		TargetLangCode: req.TargetLang,
+		Level:          req.Level,
+		Glossary:       req.Glossary,
+		Formality:      req.Formality,
+		Depth:          cmp.Or(req.Depth, DepthStandard),
+		Cognates:       languageNames(req.Cognates),
+		Segment:        segments(req),
+		Stress:         marksStress(req),
+	}
+}
+
+// languageNames returns the names of the languages with the given codes.
+func languageNames(codes []string) []string {
+	var names []string
+	for _, code := range codes {
+		names = append(names, LanguageName(code))
+	}
+	return names
+}
+
+// executePrompt executes tmpl, or fallback if tmpl is nil.
+func executePrompt(tmpl, fallback *template.Template, data PromptData) (string, error) {
+	if tmpl == nil {
+		tmpl = fallback
+	}
+	var s strings.Builder
+	if err := tmpl.Execute(&s, data); err != nil {
+		return "", fmt.Errorf("failed to build %s prompt: %w", tmpl.Name(), err)
+	}
+	return s.String(), nil
+}
@@ -0,0 +1,94 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timings records how long the steps of a translation took, for choosing between
+// models. A cached result has none.
+type Timings struct {
+	Translation time.Duration // The translation API call
+	Analysis    time.Duration // The word analysis API call
+	Parse       time.Duration // Decoding the JSON responses of both
+}
+
+// Total returns the time of all steps.
+func (t Timings) Total() time.Duration {
+	return t.Translation + t.Analysis + t.Parse
+}
+
+// String formats the timings as "translation 1.2s · analysis 3.4s · parse 2ms",
+// leaving out the steps that did not run.
+func (t Timings) String() string {
+	var parts []string
+	for _, p := range []struct {
+		name string
+		d    time.Duration
+	}{{"translation", t.Translation}, {"analysis", t.Analysis}, {"parse", t.Parse}} {
+		if p.d > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", p.name, roundDuration(p.d)))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// roundDuration rounds d to milliseconds below a second and to 100ms above.
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(100 * time.Millisecond)
+}
+
+// stopwatch adds up the time a step spends in API calls and in parsing their answers,
+// including retries and repairs. It travels in the context of the step.
+type stopwatch struct {
+	mu    sync.Mutex
+	api   time.Duration
+	parse time.Duration
+}
+
+type stopwatchKey struct{}
+
+// withStopwatch returns a context whose API calls and parsing are timed by the
+// returned stopwatch.
+func withStopwatch(ctx context.Context) (context.Context, *stopwatch) {
+	w := &stopwatch{}
+	return context.WithValue(ctx, stopwatchKey{}, w), w
+}
+
+// addAPITime counts d as time spent in an API call, if ctx is timed.
+func addAPITime(ctx context.Context, d time.Duration) {
+	if w, ok := ctx.Value(stopwatchKey{}).(*stopwatch); ok {
+		w.mu.Lock()
+		w.api += d
+		w.mu.Unlock()
+	}
+}
+
+// timeParse runs parse, counting its time as parsing if ctx is timed.
+func timeParse(ctx context.Context, parse func() error) error {
+	start := time.Now()
+	err := parse()
+	if w, ok := ctx.Value(stopwatchKey{}).(*stopwatch); ok {
+		w.mu.Lock()
+		w.parse += time.Since(start)
+		w.mu.Unlock()
+	}
+	return err
+}
+
+// step returns the API and parse time of a step that took elapsed in total. Providers
+// that report no API calls, such as DeepL, count the whole step as the call.
+func (w *stopwatch) step(elapsed time.Duration) (api, parse time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.api == 0 {
+		return max(elapsed-w.parse, 0), w.parse
+	}
+	return w.api, w.parse
+}
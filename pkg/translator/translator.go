@@ -0,0 +1,892 @@
+// Package translator implements the translation and word analysis pipeline
+// used by translation-tui, so it can be reused by other Go programs.
+//
+// A Pipeline combines a TranslationProvider and an AnalysisProvider:
+//
+//	client, _ := genai.NewClient(ctx, &genai.ClientConfig{APIKey: key, Backend: genai.BackendGeminiAPI})
+//	gemini := translator.NewGeminiProvider([]translator.ContentGenerator{client.Models}, translator.DefaultModels())
+//	p := translator.Pipeline{Translator: gemini, Analyzer: gemini, Timeout: 30 * time.Second}
+//	result, err := p.Run(ctx, translator.Request{Sentence: "Ich bin müde", UserLang: "en", TargetLang: "de"}, nil)
+package translator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// Default model names for Gemini API
+	DefaultTranslationModel = "gemini-2.5-flash-lite-preview-09-2025"
+	DefaultAnalysisModel    = "gemini-2.5-flash-preview-09-2025"
+
+	// Default temperatures
+	translationTemperature = 0.3 // Higher for more natural translation
+	analysisTemperature    = 0.0 // Lower for consistent analysis
+	chatTemperature        = 0.7 // Varied replies in conversations
+)
+
+// Models selects the model used for each pipeline step.
+type Models struct {
+	Translation string `toml:"translation" json:"translation,omitempty"`
+	Analysis    string `toml:"analysis" json:"analysis,omitempty"`
+}
+
+// DefaultModels returns the default Gemini models of both steps.
+func DefaultModels() Models {
+	return Models{Translation: DefaultTranslationModel, Analysis: DefaultAnalysisModel}
+}
+
+// Merge overrides the models that are set in other.
+func (m *Models) Merge(other Models) {
+	if other.Translation != "" {
+		m.Translation = other.Translation
+	}
+	if other.Analysis != "" {
+		m.Analysis = other.Analysis
+	}
+}
+
+// StepParams are the sampling parameters of a pipeline step. Unset parameters are
+// left to the API default.
+type StepParams struct {
+	Temperature     *float64 `toml:"temperature" json:"temperature,omitempty"`
+	TopP            *float64 `toml:"top_p" json:"top_p,omitempty"`
+	MaxOutputTokens int      `toml:"max_output_tokens" json:"max_output_tokens,omitempty"` // 0 means unset
+}
+
+// Merge overrides the parameters that are set in other.
+func (p *StepParams) Merge(other StepParams) {
+	if other.Temperature != nil {
+		p.Temperature = other.Temperature
+	}
+	if other.TopP != nil {
+		p.TopP = other.TopP
+	}
+	if other.MaxOutputTokens != 0 {
+		p.MaxOutputTokens = other.MaxOutputTokens
+	}
+}
+
+// Generation holds the sampling parameters of each pipeline step.
+type Generation struct {
+	Translation StepParams `toml:"translation" json:"translation"`
+	Analysis    StepParams `toml:"analysis" json:"analysis"`
+}
+
+// DefaultGeneration returns the default parameters: a low temperature for natural
+// translations and zero for consistent analyses.
+func DefaultGeneration() Generation {
+	translation, analysis := translationTemperature, analysisTemperature
+	return Generation{
+		Translation: StepParams{Temperature: &translation},
+		Analysis:    StepParams{Temperature: &analysis},
+	}
+}
+
+// Merge overrides the parameters that are set in other.
+func (g *Generation) Merge(other Generation) {
+	g.Translation.Merge(other.Translation)
+	g.Analysis.Merge(other.Analysis)
+}
+
+// Request describes a sentence to translate between a language pair.
+// The sentence may be written in either language.
+type Request struct {
+	Sentence   string
+	UserLang   string // Code of the language the user knows well
+	TargetLang string // Code of the language being learned
+
+	// Level, Glossary and Formality are only passed to prompt templates (see PromptData).
+	Level     string   // The user's CEFR level in the language being learned
+	Glossary  []string // Terms that are never translated; Pipeline.Run sets it to DoNotTranslate
+	Formality string   // "formal" or "informal"; empty leaves the register to the model
+
+	// Depth selects the prompt and response schema of the word analysis: one of the Depth
+	// constants, where empty means DepthStandard.
+	Depth string
+
+	// Cognates asks the word analysis for the origin of each word and its cognates in
+	// these languages, by code, such as those the user knows; empty leaves it out, which
+	// saves tokens.
+	Cognates []string
+
+	// Words limits the word analysis to these words of the foreign-language sentence;
+	// empty analyzes all of them.
+	Words []string
+}
+
+// Depths of the word analysis
+const (
+	DepthBrief    = "brief"    // Glosses only
+	DepthStandard = "standard" // A concise analysis of each word
+	DepthDeep     = "deep"     // Also morphology, usage notes and collocations
+)
+
+// Depths lists the depths of the word analysis, from the briefest.
+var Depths = []string{DepthBrief, DepthStandard, DepthDeep}
+
+// Result is the outcome of a complete pipeline run.
+type Result struct {
+	Original    string     // The cleaned input sentence, in either language
+	Translation string     // The translation to the opposite language
+	Foreign     string     // Original or Translation, whichever is in the language being learned
+	Words       []WordInfo // Analysis of each word of the foreign-language sentence
+	Level       string     // Estimated CEFR level of the foreign-language sentence
+	Tone        *Tone      // Register, politeness and tone of the foreign-language sentence, if analyzed
+	Models      Models     // Models or services that produced the result
+	Timings     Timings    // How long each step took
+}
+
+// WordInfo represents a single word analysis result.
+type WordInfo struct {
+	WordInTargetLang       string `json:"word_in_target_lang"`
+	GrammaticalExplanation string `json:"grammatical_explanation"`
+	Level                  string `json:"level,omitempty"`        // Estimated CEFR level of the word
+	Idiom                  bool   `json:"idiom,omitempty"`        // An idiom or fixed expression analyzed as a unit
+	FalseFriend            string `json:"false_friend,omitempty"` // How it differs from a similar word of the user's language
+
+	// Lemma, PartOfSpeech and Gloss describe the word as a dictionary would.
+	Lemma        string `json:"lemma,omitempty"` // Dictionary form, such as "sein" for "bin"
+	PartOfSpeech string `json:"pos,omitempty"`   // Part of speech in the user's language, such as "verb"
+	Gloss        string `json:"gloss,omitempty"` // Short translation into the user's language
+
+	// Morphology, Usage and Collocations are only set by a deep analysis.
+	Morphology   string   `json:"morphology,omitempty"`   // Stem, endings and the form used
+	Usage        string   `json:"usage,omitempty"`        // Register, contexts and common mistakes
+	Collocations []string `json:"collocations,omitempty"` // Common word combinations with it
+
+	// Etymology is the origin of the word and its cognates in the languages the user
+	// knows, such as "Swedish 'fönster' ~ German 'Fenster' ~ Latin 'fenestra'". It is only
+	// set if the request asked for Cognates.
+	Etymology string `json:"etymology,omitempty"`
+
+	// Morphemes break a word of an agglutinative or compounding language down into its
+	// stem and affixes, or the words of a compound. They are empty for other languages,
+	// words of a single morpheme and brief analyses.
+	Morphemes []Morpheme `json:"morphemes,omitempty"`
+
+	// Stress is the word with its lexical stress or pitch accent marked, such as "молоко́"
+	// or "はしꜜ", for languages whose spelling does not show it.
+	Stress string `json:"stress,omitempty"`
+
+	// Gender, Article and Plural are set for nouns of languages with grammatical gender.
+	Gender  string `json:"gender,omitempty"`  // One of the Gender constants
+	Article string `json:"article,omitempty"` // Definite article in the nominative singular, such as "der" or "la"
+	Plural  string `json:"plural,omitempty"`  // Plural form of the noun
+
+	// Rank is the position of the word in a frequency list of the language, 1 for the most
+	// common word, or 0 if unknown. The pipeline does not set it.
+	Rank int `json:"rank,omitempty"`
+
+	// Parts break a multi-word expression such as "ich freue mich auf" down into its
+	// words. They are empty for single words.
+	Parts []WordPart `json:"parts,omitempty"`
+}
+
+// Grammatical genders of nouns
+const (
+	GenderMasculine = "masculine"
+	GenderFeminine  = "feminine"
+	GenderNeuter    = "neuter"
+	GenderCommon    = "common"
+)
+
+// genders lists the valid values of WordInfo.Gender.
+var genders = []string{GenderMasculine, GenderFeminine, GenderNeuter, GenderCommon}
+
+// LanguageGenders lists the genders of the nouns of common languages with grammatical
+// gender, by ISO 639-1 code.
+var LanguageGenders = map[string][]string{
+	"de": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"ru": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"uk": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"pl": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"cs": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"sr": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"hr": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"bs": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"is": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"el": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"ro": {GenderMasculine, GenderFeminine, GenderNeuter},
+	"fr": {GenderMasculine, GenderFeminine},
+	"es": {GenderMasculine, GenderFeminine},
+	"it": {GenderMasculine, GenderFeminine},
+	"pt": {GenderMasculine, GenderFeminine},
+	"ca": {GenderMasculine, GenderFeminine},
+	"sv": {GenderCommon, GenderNeuter},
+	"da": {GenderCommon, GenderNeuter},
+	"nl": {GenderCommon, GenderNeuter},
+}
+
+// segmentedLanguages lists the languages, by ISO 639-1 code, whose words are broken down
+// into morphemes: those that build words from many suffixes or compounds.
+var segmentedLanguages = map[string]bool{
+	"de": true, "nl": true, "sv": true, "da": true, "no": true, "nb": true, "is": true,
+	"fi": true, "et": true, "hu": true, "tr": true, "az": true, "kk": true, "uz": true,
+	"ja": true, "ko": true, "ka": true, "sw": true,
+}
+
+// segments reports whether the word analysis of req breaks words down into morphemes.
+func segments(req Request) bool {
+	return segmentedLanguages[req.TargetLang] && req.Depth != DepthBrief
+}
+
+// stressLanguages lists the languages, by ISO 639-1 code, whose words are given with
+// their stress or pitch accent marked: those whose spelling does not show it.
+var stressLanguages = map[string]bool{
+	"ru": true, "uk": true, "be": true, "bg": true, "sr": true, "hr": true, "bs": true,
+	"sl": true, "lt": true, "sv": true, "no": true, "nb": true, "ja": true,
+}
+
+// analyzesTone reports whether the word analysis of req describes the tone of the whole
+// sentence: not for brief analyses, nor for chosen words of it.
+func analyzesTone(req Request) bool {
+	return req.Depth != DepthBrief && len(req.Words) == 0
+}
+
+// marksStress reports whether the word analysis of req marks the stress of words.
+func marksStress(req Request) bool {
+	return stressLanguages[req.TargetLang] && req.Depth != DepthBrief
+}
+
+// Morpheme is a stem, affix or compound part of a word with its meaning.
+type Morpheme struct {
+	Text  string `json:"text"`  // As it appears in the word, such as "lar" in "evlerimiz"
+	Gloss string `json:"gloss"` // Meaning or function in the user's language, such as "plural"
+}
+
+// Tone describes how the foreign-language sentence of a result comes across, in the
+// user's language.
+type Tone struct {
+	Register   string      `json:"register"`   // Such as "colloquial" or "formal written"
+	Politeness string      `json:"politeness"` // Such as "polite, with Sie"
+	Emotion    string      `json:"emotion"`    // Such as "neutral" or "mildly annoyed"
+	Shifts     []ToneShift `json:"shifts"`     // Ways to say it in another tone
+}
+
+// ToneShift is the sentence rephrased to shift its tone.
+type ToneShift struct {
+	Toward   string `json:"toward"`   // Such as "more casual"
+	Sentence string `json:"sentence"` // The rephrased sentence
+}
+
+// WordPart is one word of a multi-word expression with its analysis.
+type WordPart struct {
+	Word     string `json:"word"`
+	Analysis string `json:"analysis"`
+}
+
+// Grouped reports whether the analysis covers a multi-word expression.
+func (w WordInfo) Grouped() bool {
+	return len(w.Parts) > 0
+}
+
+// TranslationStep represents the structured response of the translation step.
+type TranslationStep struct {
+	InputLanguage       string `json:"input_language"`
+	CleanedSentence     string `json:"cleaned_sentence"`
+	Translation         string `json:"translation"`
+	TranslationLanguage string `json:"translation_language"`
+}
+
+// WordAnalysisItem represents a single word analysis from the API.
+type WordAnalysisItem struct {
+	Word         string     `json:"word"`
+	Analysis     string     `json:"analysis"`
+	Level        string     `json:"level"`
+	Idiom        bool       `json:"idiom"`
+	FalseFriend  string     `json:"false_friend"`
+	Lemma        string     `json:"lemma"`
+	POS          string     `json:"pos"`
+	Gloss        string     `json:"gloss"`
+	Morphology   string     `json:"morphology"`
+	Usage        string     `json:"usage"`
+	Collocations []string   `json:"collocations"`
+	Etymology    string     `json:"etymology"`
+	Morphemes    []Morpheme `json:"morphemes"`
+	Stress       string     `json:"stress"`
+	Gender       string     `json:"gender"`
+	Article      string     `json:"article"`
+	Plural       string     `json:"plural"`
+	Parts        []WordPart `json:"parts"`
+}
+
+// AnalysisStep represents the structured response of the word analysis step.
+type AnalysisStep struct {
+	SentenceLevel string             `json:"sentence_level"`
+	Tone          *Tone              `json:"tone,omitempty"`
+	WordAnalysis  []WordAnalysisItem `json:"word_analysis"`
+}
+
+// CEFRLevels are the levels of the Common European Framework of Reference, from
+// beginner to mastery.
+var CEFRLevels = []string{"A1", "A2", "B1", "B2", "C1", "C2"}
+
+// AboveLevel reports whether the CEFR level is higher than known. It is false if
+// either level is unknown.
+func AboveLevel(level, known string) bool {
+	i := slices.Index(CEFRLevels, strings.ToUpper(strings.TrimSpace(level)))
+	j := slices.Index(CEFRLevels, strings.ToUpper(strings.TrimSpace(known)))
+	return i >= 0 && j >= 0 && i > j
+}
+
+// Comparison explains the difference between two phrasings in the target language.
+type Comparison struct {
+	Meaning   string `json:"meaning"`
+	Register  string `json:"register"`
+	Grammar   string `json:"grammar"`
+	UseFirst  string `json:"use_first"`
+	UseSecond string `json:"use_second"`
+}
+
+// Rewrite is a rewritten version of a sentence with an explanation of what changed.
+type Rewrite struct {
+	Text    string `json:"text"`
+	Level   string `json:"level"` // Estimated CEFR level of the rewrite
+	Changes string `json:"changes"`
+}
+
+// Simplification holds the rewrites of a sentence at a simpler level, or paraphrases.
+type Simplification struct {
+	Rewrites []Rewrite `json:"rewrites"`
+}
+
+// Detection is the language a text is written in, as identified by a model.
+type Detection struct {
+	Language  string `json:"language"`  // ISO 639-1 code such as "sv"
+	Name      string `json:"name"`      // English name of the language
+	Ambiguous bool   `json:"ambiguous"` // The text fits several languages equally well
+}
+
+// Conjugation is the conjugation table of a verb, in its common tenses.
+type Conjugation struct {
+	Lemma string           `json:"lemma"` // Infinitive or other dictionary form
+	Forms []ConjugatedForm `json:"forms"`
+}
+
+// ConjugatedForm is the form of a verb for a person in a tense.
+type ConjugatedForm struct {
+	Tense   string `json:"tense"`   // Such as "present" or "perfect", in the user's language
+	Person  string `json:"person"`  // Such as "1st person singular", in the user's language
+	Pronoun string `json:"pronoun"` // Such as "ich", or empty if the language has none
+	Form    string `json:"form"`    // Such as "bin" or "habe gesehen", without the pronoun
+}
+
+// Cases lists the grammatical cases of the languages the case quiz covers, by ISO
+// 639-1 code, in the order their grammars list them.
+var Cases = map[string][]string{
+	"de": {"nominative", "accusative", "dative", "genitive"},
+	"sr": {"nominative", "genitive", "dative", "accusative", "vocative", "instrumental", "locative"},
+	"hr": {"nominative", "genitive", "dative", "accusative", "vocative", "instrumental", "locative"},
+	"bs": {"nominative", "genitive", "dative", "accusative", "vocative", "instrumental", "locative"},
+}
+
+// CaseQuiz is a quiz on the cases that prepositions and verbs govern.
+type CaseQuiz struct {
+	Questions []CaseQuestion `json:"questions"`
+}
+
+// CaseQuestion asks which case a preposition or verb governs in a phrase.
+type CaseQuestion struct {
+	Word        string `json:"word"`        // The preposition or verb, such as "mit"
+	Phrase      string `json:"phrase"`      // With the governed words in their dictionary form in brackets, such as "mit [der Bus]"
+	Answer      string `json:"answer"`      // One of the Cases of the language
+	Example     string `json:"example"`     // The phrase as it is said, such as "mit dem Bus"
+	Explanation string `json:"explanation"` // Why the case is governed, in the user's language
+}
+
+// NumberGrade is a model's verdict on a number, date, price or time written out in words.
+type NumberGrade struct {
+	Correct     bool   `json:"correct"`
+	Expected    string `json:"expected"`    // The figure written out correctly
+	Explanation string `json:"explanation"` // What was wrong, in the learner's language
+}
+
+// WordDetails are details of a word asked for in the drill-down of the word analysis.
+type WordDetails struct {
+	Synonyms []RelatedWord `json:"synonyms"`
+	Antonyms []RelatedWord `json:"antonyms"`
+	Phrases  []Phrase      `json:"phrases"` // Most common collocations and set phrases with the word
+}
+
+// Phrase is a collocation or set phrase with its translation.
+type Phrase struct {
+	Text        string `json:"text"`        // Such as "donositi odluku"
+	Translation string `json:"translation"` // Into the user's language, such as "to make a decision"
+}
+
+// RelatedWord is a synonym or antonym of a word.
+type RelatedWord struct {
+	Word     string `json:"word"`     // In its dictionary form, such as "froh"
+	Gloss    string `json:"gloss"`    // Short translation into the user's language
+	Register string `json:"register"` // How its register or nuance differs, such as "colloquial", or empty
+}
+
+// TranslationProvider performs the translation and cleaning step.
+type TranslationProvider interface {
+	// TranslationModel names the model or service used for translation.
+	TranslationModel() string
+	Translate(ctx context.Context, req Request) (*TranslationStep, error)
+}
+
+// AnalysisProvider performs the word-by-word analysis step.
+type AnalysisProvider interface {
+	// AnalysisModel names the model or service used for analysis.
+	AnalysisModel() string
+	AnalyzeWords(ctx context.Context, foreignSentence string, req Request) (*AnalysisStep, error)
+}
+
+// Roles of the turns of a conversation
+const (
+	RoleUser  = "user"
+	RoleModel = "model"
+)
+
+// Message is one turn of a multi-turn conversation with a model.
+type Message struct {
+	Role string // RoleUser or RoleModel
+	Text string
+}
+
+// ChatProvider holds free-text, multi-turn conversations.
+type ChatProvider interface {
+	// ChatModel names the model used for conversations.
+	ChatModel() string
+	// Chat returns the model's reply to the conversation so far, following the
+	// system instruction.
+	Chat(ctx context.Context, system string, history []Message) (string, error)
+}
+
+// ComparisonProvider explains the difference between two phrasings.
+type ComparisonProvider interface {
+	Compare(ctx context.Context, first, second string, req Request) (*Comparison, error)
+}
+
+// SimplificationProvider rewrites target-language sentences at a simpler level.
+type SimplificationProvider interface {
+	// Simplify rewrites sentence at the CEFR level, or offers three paraphrases if level is empty.
+	Simplify(ctx context.Context, sentence, level string, req Request) (*Simplification, error)
+}
+
+// DetectionProvider identifies the language of a text.
+type DetectionProvider interface {
+	DetectLanguage(ctx context.Context, text string) (*Detection, error)
+}
+
+// ConjugationProvider conjugates verbs.
+type ConjugationProvider interface {
+	// Conjugate returns the conjugation table of a verb in the target language, with
+	// no forms if it is not a verb.
+	Conjugate(ctx context.Context, verb string, req Request) (*Conjugation, error)
+}
+
+// CaseQuizProvider makes up quizzes on case government.
+type CaseQuizProvider interface {
+	// CaseQuiz asks which case the prepositions and verbs of sentences in the target
+	// language govern, which must have Cases.
+	CaseQuiz(ctx context.Context, sentences []string, req Request) (*CaseQuiz, error)
+}
+
+// WordDetailsProvider gives details of single words.
+type WordDetailsProvider interface {
+	// WordDetails returns details of a word of the target language as used in sentence.
+	WordDetails(ctx context.Context, word, sentence string, req Request) (*WordDetails, error)
+}
+
+// NumberGradingProvider grades numbers, dates, prices and times written out in words.
+type NumberGradingProvider interface {
+	// GradeNumber grades answer as the figure of the kind ("number", "date", "price"
+	// or "time") written out in the target language.
+	GradeNumber(ctx context.Context, figure, kind, answer string, req Request) (*NumberGrade, error)
+}
+
+// Progress is called when a pipeline step starts, with the time it will time out.
+// The deadline is zero if the step has no timeout.
+type Progress func(step string, deadline time.Time)
+
+// Pipeline translates a sentence and analyzes the words of its foreign-language version.
+type Pipeline struct {
+	Translator TranslationProvider
+	Analyzer   AnalysisProvider
+	Timeout    time.Duration // Time limit of each step; zero means no limit
+
+	// DoNotTranslate lists terms, such as names, that pass through the translation
+	// untouched like code, URLs and placeholders do (see Protect).
+	DoNotTranslate []string
+
+	// SkipAnalysis leaves the word analysis out of Run, so that chosen words can be
+	// analyzed later with Analyze.
+	SkipAnalysis bool
+
+	// Redact masks personal data (see Masked.Redact) before anything is sent to the
+	// models, and restores it in the results. DoNotTranslate terms then are not sent
+	// either, so they can list names that must not leave the machine.
+	Redact bool
+}
+
+// Run performs the translation and word analysis steps. progress may be nil.
+func (p Pipeline) Run(ctx context.Context, req Request, progress Progress) (Result, error) {
+	targetLangName := LanguageName(req.TargetLang)
+	if req.Glossary == nil {
+		req.Glossary = p.DoNotTranslate
+	}
+	masked, analyzed := p.mask(req.Sentence, req.UserLang, req.TargetLang)
+	if p.Redact {
+		req.Glossary = nil
+	}
+
+	// Input that already is in the target language is analyzed as it is, so then the
+	// analysis can start right away when the input makes that clear
+	var early <-chan analysisOutcome
+	translating := "Translating"
+	if !p.SkipAnalysis && GuessLanguage(req.Sentence, req.UserLang, req.TargetLang) == req.TargetLang {
+		earlyCtx, cancel := context.WithCancel(ctx)
+		defer cancel() // Abandons the analysis if the guess was wrong or translating failed
+		// Without progress, which must not be reported once Run returned
+		early = p.analyzeAsync(earlyCtx, analyzed, req, nil)
+		translating = "Translating and analyzing words"
+	}
+
+	// Step 1: Translation and cleaning, with protected spans masked
+	maskedReq := req
+	maskedReq.Sentence = masked.Text
+	translateCtx, watch := withStopwatch(ctx)
+	started := time.Now()
+	translationStep, err := RunStep(translateCtx, p.Timeout, translating, progress, func(ctx context.Context) (*TranslationStep, error) {
+		return p.Translator.Translate(ctx, maskedReq)
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	maskedForeign := ForeignSentence(translationStep, targetLangName)
+	if err := restoreProtected(translationStep, masked); err != nil {
+		return Result{}, err
+	}
+
+	// Determine which sentence is in the foreign language (target language)
+	foreignSentence := ForeignSentence(translationStep, targetLangName)
+	result := Result{
+		Original:    translationStep.CleanedSentence,
+		Translation: translationStep.Translation,
+		Foreign:     foreignSentence,
+		Models:      Models{Translation: p.Translator.TranslationModel()},
+	}
+	result.Timings.Translation, result.Timings.Parse = watch.step(time.Since(started))
+	if p.SkipAnalysis {
+		return result, nil
+	}
+
+	// Step 2: Word-by-word analysis, unless it already ran on the same sentence
+	foreign := foreignSentence
+	if p.Redact {
+		foreign = maskedForeign
+	}
+	var outcome analysisOutcome
+	if early != nil && sameWords(foreign, analyzed) {
+		outcome = <-early
+	} else {
+		outcome = <-p.analyzeAsync(ctx, foreign, req, progress)
+	}
+	if outcome.err != nil {
+		return Result{}, outcome.err
+	}
+	analysisStep := outcome.step
+	if p.Redact {
+		if err := expandAnalysis(analysisStep, masked); err != nil {
+			return Result{}, err
+		}
+	}
+	result.Timings.Analysis = outcome.api
+	result.Timings.Parse += outcome.parse
+	result.Words = processWordAnalysis(analysisStep)
+	result.Level = analysisStep.SentenceLevel
+	result.Tone = processTone(analysisStep.Tone)
+	result.Models.Analysis = p.Analyzer.AnalysisModel()
+	return result, nil
+}
+
+// Analyze performs the word analysis step alone, analyzing req.Words of the
+// foreign-language sentence, or all of its words if req.Words is empty. progress may be nil.
+func (p Pipeline) Analyze(ctx context.Context, foreignSentence string, req Request, progress Progress) ([]WordInfo, error) {
+	masked, analyzed := p.mask(foreignSentence, req.TargetLang)
+	if p.Redact {
+		req.Glossary = nil
+		req.Words = maskWords(req.Words, masked)
+	}
+	analysisStep, err := p.analyze(ctx, analyzed, req, progress)
+	if err != nil {
+		return nil, err
+	}
+	if p.Redact {
+		if err := expandAnalysis(analysisStep, masked); err != nil {
+			return nil, err
+		}
+	}
+	return processWordAnalysis(analysisStep), nil
+}
+
+// mask returns text with its protected spans masked for translating, and the text to
+// analyze: text itself, or with personal data masked as well if redacting. langs are
+// the languages text may be in.
+func (p Pipeline) mask(text string, langs ...string) (Masked, string) {
+	masked := Protect(text, p.DoNotTranslate)
+	if !p.Redact {
+		return masked, text
+	}
+	masked.Text = masked.Redact(masked.Text, langs...)
+	return masked, masked.Text
+}
+
+// maskWords replaces chosen words that are part of a masked span with its token.
+func maskWords(words []string, masked Masked) []string {
+	out := make([]string, 0, len(words))
+	for _, w := range words {
+		for i, span := range masked.Spans {
+			if strings.Contains(span, w) {
+				w = maskToken(i)
+				break
+			}
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// expandAnalysis restores the masked spans in a word analysis, dropping the entries
+// of the tokens themselves, which say nothing about the masked words.
+func expandAnalysis(step *AnalysisStep, masked Masked) error {
+	step.WordAnalysis = slices.DeleteFunc(step.WordAnalysis, func(w WordAnalysisItem) bool {
+		return isToken(w.Word)
+	})
+	if err := masked.ExpandAll(step); err != nil {
+		return fmt.Errorf("failed to restore redacted words: %w", err)
+	}
+	return nil
+}
+
+// analyze runs the word analysis step under the pipeline's timeout.
+func (p Pipeline) analyze(ctx context.Context, foreignSentence string, req Request, progress Progress) (*AnalysisStep, error) {
+	if HasMarkup(foreignSentence) {
+		foreignSentence = StripMarkup(foreignSentence) // Analyze the words, not the syntax
+	}
+	return RunStep(ctx, p.Timeout, "Analyzing words", progress, func(ctx context.Context) (*AnalysisStep, error) {
+		return p.Analyzer.AnalyzeWords(ctx, foreignSentence, req)
+	})
+}
+
+// analysisOutcome is the result of a word analysis step with its timings.
+type analysisOutcome struct {
+	step       *AnalysisStep
+	err        error
+	api, parse time.Duration
+}
+
+// analyzeAsync runs the word analysis step in the background, delivering its outcome
+// on the returned channel.
+func (p Pipeline) analyzeAsync(ctx context.Context, foreignSentence string, req Request, progress Progress) <-chan analysisOutcome {
+	done := make(chan analysisOutcome, 1)
+	go func() {
+		ctx, watch := withStopwatch(ctx)
+		started := time.Now()
+		step, err := p.analyze(ctx, foreignSentence, req, progress)
+		api, parse := watch.step(time.Since(started))
+		done <- analysisOutcome{step: step, err: err, api: api, parse: parse}
+	}()
+	return done
+}
+
+// sameWords reports whether two sentences have the same words, ignoring case and
+// punctuation, as when cleaning only capitalized or punctuated a sentence.
+func sameWords(a, b string) bool {
+	return strings.EqualFold(removePunctuation(a), removePunctuation(b))
+}
+
+// RunStep runs a single API call under the given timeout, reporting its start to progress
+// and turning a missed deadline into a clear error.
+func RunStep[T any](ctx context.Context, timeout time.Duration, step string, progress Progress, fn func(context.Context) (T, error)) (T, error) {
+	if progress == nil {
+		progress = func(string, time.Time) {}
+	}
+	if timeout <= 0 {
+		progress(step, time.Time{})
+		return fn(ctx)
+	}
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	deadline, _ := stepCtx.Deadline()
+	progress(step, deadline)
+
+	result, err := fn(stepCtx)
+	if err != nil && errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+		return result, fmt.Errorf("%s timed out after %s: %w", strings.ToLower(step), timeout, err)
+	}
+	return result, err
+}
+
+// restoreProtected puts the masked spans back into both sentences of the translation step.
+// A translation that lost or duplicated a span fails; a cleaned sentence that did is
+// replaced by the original input.
+func restoreProtected(step *TranslationStep, masked Masked) error {
+	if len(masked.Spans) == 0 {
+		return nil
+	}
+	translation, err := masked.Restore(step.Translation)
+	if err != nil {
+		return fmt.Errorf("translation changed protected text: %w", err)
+	}
+	step.Translation = translation
+	if cleaned, err := masked.Restore(step.CleanedSentence); err == nil {
+		step.CleanedSentence = cleaned
+	} else {
+		step.CleanedSentence, _ = masked.Restore(masked.Text)
+	}
+	return nil
+}
+
+// ForeignSentence determines which sentence of the translation step is in the foreign language.
+func ForeignSentence(step *TranslationStep, targetLangName string) string {
+	if step.InputLanguage == targetLangName {
+		return step.CleanedSentence
+	}
+	return step.Translation
+}
+
+// processWordAnalysis processes and cleans word analysis results.
+func processWordAnalysis(analysis *AnalysisStep) []WordInfo {
+	wordAnalysis := make([]WordInfo, 0, len(analysis.WordAnalysis))
+	for _, w := range analysis.WordAnalysis {
+		cleanedWord := removePunctuation(w.Word)
+		if cleanedWord == "" {
+			continue // Skip entries that are only punctuation
+		}
+		wordAnalysis = append(wordAnalysis, WordInfo{
+			WordInTargetLang:       cleanedWord,
+			GrammaticalExplanation: w.Analysis,
+			Level:                  w.Level,
+			Idiom:                  w.Idiom,
+			FalseFriend:            strings.TrimSpace(w.FalseFriend),
+			Lemma:                  strings.TrimSpace(w.Lemma),
+			PartOfSpeech:           strings.TrimSpace(w.POS),
+			Gloss:                  strings.TrimSpace(w.Gloss),
+			Morphology:             strings.TrimSpace(w.Morphology),
+			Usage:                  strings.TrimSpace(w.Usage),
+			Collocations:           w.Collocations,
+			Etymology:              strings.TrimSpace(w.Etymology),
+			Morphemes:              processMorphemes(w.Morphemes),
+			Stress:                 strings.TrimSpace(w.Stress),
+			Gender:                 normalizeGender(w.Gender),
+			Article:                strings.TrimSpace(w.Article),
+			Plural:                 strings.TrimSpace(w.Plural),
+			Parts:                  processParts(w.Parts),
+		})
+	}
+	return wordAnalysis
+}
+
+// normalizeGender returns the Gender constant named by gender, or "" if it is none.
+func normalizeGender(gender string) string {
+	gender = strings.ToLower(strings.TrimSpace(gender))
+	if !slices.Contains(genders, gender) {
+		return ""
+	}
+	return gender
+}
+
+// processParts cleans the breakdown of a multi-word expression. A breakdown of less
+// than two words is dropped, so that only actual groups are shown as such.
+func processParts(parts []WordPart) []WordPart {
+	var cleaned []WordPart
+	for _, p := range parts {
+		if word := removePunctuation(p.Word); word != "" {
+			cleaned = append(cleaned, WordPart{Word: word, Analysis: p.Analysis})
+		}
+	}
+	if len(cleaned) < 2 {
+		return nil
+	}
+	return cleaned
+}
+
+// processTone trims the tone of a sentence, dropping shifts without a sentence, and
+// returns nil if nothing is left.
+func processTone(tone *Tone) *Tone {
+	if tone == nil {
+		return nil
+	}
+	cleaned := Tone{
+		Register:   strings.TrimSpace(tone.Register),
+		Politeness: strings.TrimSpace(tone.Politeness),
+		Emotion:    strings.TrimSpace(tone.Emotion),
+	}
+	for _, s := range tone.Shifts {
+		if sentence := strings.TrimSpace(s.Sentence); sentence != "" {
+			cleaned.Shifts = append(cleaned.Shifts, ToneShift{Toward: strings.TrimSpace(s.Toward), Sentence: sentence})
+		}
+	}
+	if cleaned.Register == "" && cleaned.Politeness == "" && cleaned.Emotion == "" && len(cleaned.Shifts) == 0 {
+		return nil
+	}
+	return &cleaned
+}
+
+// processMorphemes trims the morphemes of a word, dropping empty ones, and returns nil
+// if fewer than two are left: there is nothing to break down.
+func processMorphemes(morphemes []Morpheme) []Morpheme {
+	var cleaned []Morpheme
+	for _, m := range morphemes {
+		if text := strings.TrimSpace(m.Text); text != "" {
+			cleaned = append(cleaned, Morpheme{Text: text, Gloss: strings.TrimSpace(m.Gloss)})
+		}
+	}
+	if len(cleaned) < 2 {
+		return nil
+	}
+	return cleaned
+}
+
+// removePunctuation removes all punctuation marks from a string, keeping only letters, numbers, and spaces.
+func removePunctuation(s string) string {
+	var result strings.Builder
+	prevWasSpace := false
+	for _, r := range s {
+		// Keep letters, numbers, and spaces; remove punctuation
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			result.WriteRune(r)
+			prevWasSpace = false
+		} else if unicode.IsSpace(r) {
+			// Only add one space, collapse multiple spaces
+			if !prevWasSpace {
+				result.WriteRune(' ')
+				prevWasSpace = true
+			}
+		}
+	}
+	return strings.TrimSpace(result.String())
+}
+
+// LanguageName returns the full name of a language given its code, preferring a
+// registered language. If the code is not recognized, it returns the code itself.
+func LanguageName(code string) string {
+	if lang, ok := customLanguage(code); ok && lang.Name != "" {
+		return lang.Name
+	}
+	langMap := map[string]string{
+		"en": "English",
+		"es": "Spanish",
+		"fr": "French",
+		"it": "Italian",
+		"pt": "Portuguese",
+		"sr": "Serbian",
+		"sv": "Swedish",
+		"de": "German",
+	}
+	if name, ok := langMap[code]; ok {
+		return name
+	}
+	return code
+}
@@ -0,0 +1,804 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// fakeGenerator returns canned responses in order and records the prompts it received,
+// and the contents and config of the last call.
+type fakeGenerator struct {
+	responses []fakeResponse
+	prompts   []string
+	contents  []*genai.Content
+	config    *genai.GenerateContentConfig
+}
+
+// fakeResponse is a single canned reply of fakeGenerator.
+type fakeResponse struct {
+	resp *genai.GenerateContentResponse
+	err  error
+}
+
+func (f *fakeGenerator) GenerateContent(_ context.Context, _ string, contents []*genai.Content, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error) {
+	f.prompts = append(f.prompts, contents[len(contents)-1].Parts[0].Text)
+	f.contents, f.config = contents, config
+	i := len(f.prompts) - 1
+	if i >= len(f.responses) {
+		return nil, errors.New("unexpected call")
+	}
+	return f.responses[i].resp, f.responses[i].err
+}
+
+// textResponse builds a response with a single text candidate.
+func textResponse(text string) fakeResponse {
+	return fakeResponse{resp: &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{Content: genai.NewContentFromText(text, genai.RoleModel)}},
+	}}
+}
+
+// loadFixture reads a file from testdata.
+func loadFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return string(data)
+}
+
+func TestPerformTranslation(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses func(t *testing.T) []fakeResponse
+		want      *TranslationStep
+		wantErr   string
+		wantCalls int
+	}{
+		{
+			name: "valid JSON",
+			responses: func(t *testing.T) []fakeResponse {
+				return []fakeResponse{textResponse(loadFixture(t, "translation_valid.json"))}
+			},
+			want: &TranslationStep{
+				InputLanguage:       "English",
+				CleanedSentence:     "I am happy.",
+				Translation:         "Ich bin glücklich.",
+				TranslationLanguage: "German",
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "code fences and trailing comma are repaired locally",
+			responses: func(t *testing.T) []fakeResponse {
+				return []fakeResponse{textResponse(loadFixture(t, "translation_fenced.txt"))}
+			},
+			want: &TranslationStep{
+				InputLanguage:       "German",
+				CleanedSentence:     "Ich bin müde.",
+				Translation:         "I am tired.",
+				TranslationLanguage: "English",
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "malformed JSON is repaired by re-prompting",
+			responses: func(t *testing.T) []fakeResponse {
+				return []fakeResponse{
+					textResponse(loadFixture(t, "translation_malformed.txt")),
+					textResponse(loadFixture(t, "translation_valid.json")),
+				}
+			},
+			want: &TranslationStep{
+				InputLanguage:       "English",
+				CleanedSentence:     "I am happy.",
+				Translation:         "Ich bin glücklich.",
+				TranslationLanguage: "German",
+			},
+			wantCalls: 2,
+		},
+		{
+			name: "malformed JSON after repair fails",
+			responses: func(t *testing.T) []fakeResponse {
+				return []fakeResponse{
+					textResponse(loadFixture(t, "translation_malformed.txt")),
+					textResponse(loadFixture(t, "translation_malformed.txt")),
+				}
+			},
+			wantErr:   "failed to parse translation JSON",
+			wantCalls: 2,
+		},
+		{
+			name: "empty candidates",
+			responses: func(t *testing.T) []fakeResponse {
+				return []fakeResponse{{resp: &genai.GenerateContentResponse{}}}
+			},
+			wantErr:   "no response from translation API",
+			wantCalls: 1,
+		},
+		{
+			name: "API error",
+			responses: func(t *testing.T) []fakeResponse {
+				return []fakeResponse{{err: genai.APIError{Code: 500, Status: "INTERNAL"}}}
+			},
+			wantErr:   "translation API error",
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &fakeGenerator{responses: tt.responses(t)}
+			got, err := performTranslation(context.Background(), gen, "test-model", Prompts{}, GeminiSettings{}, Request{Sentence: "i am happy", UserLang: "en", TargetLang: "de"})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+			if len(gen.prompts) != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", len(gen.prompts), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestPerformWordAnalysis(t *testing.T) {
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "analysis_valid.json"))}}
+	got, err := performWordAnalysis(context.Background(), gen, "test-model", Prompts{}, GeminiSettings{}, "Ich bin glücklich.", nil, Request{UserLang: "en", TargetLang: "de"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.WordAnalysis) != 3 {
+		t.Fatalf("got %d words, want 3", len(got.WordAnalysis))
+	}
+	if !strings.Contains(gen.prompts[0], "Ich bin glücklich.") {
+		t.Errorf("prompt does not contain the foreign sentence: %q", gen.prompts[0])
+	}
+}
+
+func TestAnalysisDepth(t *testing.T) {
+	item := func(schema map[string]any) map[string]any {
+		return schema["properties"].(map[string]any)["word_analysis"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)
+	}
+	if brief := item(buildAnalysisSchema(Request{UserLang: "en", TargetLang: "de", Depth: DepthBrief})); brief["gloss"] == nil || brief["gender"] != nil {
+		t.Errorf("brief schema has %v", brief)
+	}
+	if deep := item(buildAnalysisSchema(Request{UserLang: "en", TargetLang: "de", Depth: DepthDeep})); deep["collocations"] == nil || deep["gender"] == nil {
+		t.Errorf("deep schema has %v", deep)
+	}
+
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "analysis_valid.json"))}}
+	if _, err := performWordAnalysis(context.Background(), gen, "test-model", Prompts{}, GeminiSettings{}, "Ich bin glücklich.", nil, Request{UserLang: "en", TargetLang: "de", Depth: DepthDeep}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(gen.prompts[0], "DEPTH: deep") || gen.config.ResponseJsonSchema == nil {
+		t.Errorf("deep prompt = %q", gen.prompts[0])
+	}
+}
+
+func TestAnalysisEtymology(t *testing.T) {
+	item := func(schema map[string]any) map[string]any {
+		return schema["properties"].(map[string]any)["word_analysis"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)
+	}
+	if plain := item(buildAnalysisSchema(Request{UserLang: "en", TargetLang: "sv"})); plain["etymology"] != nil {
+		t.Errorf("schema without cognates has %v", plain)
+	}
+	if with := item(buildAnalysisSchema(Request{UserLang: "en", TargetLang: "sv", Cognates: []string{"en", "de"}})); with["etymology"] == nil {
+		t.Errorf("schema with cognates has %v", with)
+	}
+
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "analysis_valid.json"))}}
+	if _, err := performWordAnalysis(context.Background(), gen, "test-model", Prompts{}, GeminiSettings{}, "Ich bin glücklich.", nil, Request{UserLang: "en", TargetLang: "de", Cognates: []string{"en", "sv"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(gen.prompts[0], "cognates it has in English, Swedish") {
+		t.Errorf("prompt = %q", gen.prompts[0])
+	}
+}
+
+func TestAnalysisMorphemes(t *testing.T) {
+	item := func(schema map[string]any) map[string]any {
+		return schema["properties"].(map[string]any)["word_analysis"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)
+	}
+	if with := item(buildAnalysisSchema(Request{UserLang: "en", TargetLang: "tr"})); with["morphemes"] == nil {
+		t.Errorf("segmented schema has %v", with)
+	}
+	for _, req := range []Request{{TargetLang: "es"}, {TargetLang: "de", Depth: DepthBrief}} {
+		if segments(req) {
+			t.Errorf("segments(%+v) = true", req)
+		}
+	}
+
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(`{"sentence_level": "A1", "word_analysis": [
+		{"word": "Haustür", "analysis": "front door", "morphemes": [{"text": "Haus", "gloss": "house"}, {"text": " tür ", "gloss": "door"}]},
+		{"word": "ist", "analysis": "is", "morphemes": [{"text": "ist", "gloss": "is"}]}]}`)}}
+	result, err := performWordAnalysis(context.Background(), gen, "test-model", Prompts{}, GeminiSettings{}, "Die Haustür ist offen.", nil, Request{UserLang: "en", TargetLang: "de"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(gen.prompts[0], "MORPHEMES:") {
+		t.Errorf("prompt = %q", gen.prompts[0])
+	}
+	want := []Morpheme{{"Haus", "house"}, {"tür", "door"}}
+	if got := processWordAnalysis(result); !slices.Equal(got[0].Morphemes, want) || got[1].Morphemes != nil {
+		t.Errorf("morphemes = %v, %v, want %v and none", got[0].Morphemes, got[1].Morphemes, want)
+	}
+}
+
+func TestAnalysisTone(t *testing.T) {
+	properties := func(req Request) map[string]any {
+		return buildAnalysisSchema(req)["properties"].(map[string]any)
+	}
+	if standard := properties(Request{UserLang: "en", TargetLang: "de"}); standard["tone"] == nil {
+		t.Errorf("standard schema has %v", standard)
+	}
+	for _, req := range []Request{{TargetLang: "de", Depth: DepthBrief}, {TargetLang: "de", Words: []string{"bin"}}} {
+		if props := properties(req); props["tone"] != nil {
+			t.Errorf("schema of %+v has tone", req)
+		}
+	}
+
+	got := processTone(&Tone{Register: " colloquial ", Shifts: []ToneShift{{Toward: "more polite", Sentence: "Würden Sie bitte kommen?"}, {Toward: "ruder", Sentence: " "}}})
+	want := &Tone{Register: "colloquial", Shifts: []ToneShift{{Toward: "more polite", Sentence: "Würden Sie bitte kommen?"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("processTone = %+v, want %+v", got, want)
+	}
+	if got := processTone(&Tone{Shifts: []ToneShift{{Toward: "more casual"}}}); got != nil {
+		t.Errorf("processTone of an empty tone = %+v, want nil", got)
+	}
+}
+
+func TestAnalyzeSelectedWords(t *testing.T) {
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "analysis_valid.json"))}}
+	if _, err := performWordAnalysis(context.Background(), gen, "test-model", Prompts{}, GeminiSettings{}, "Ich bin glücklich.", []string{"glücklich", "bin"}, Request{UserLang: "en", TargetLang: "de"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(gen.prompts[0], "Only analyze these words of the sentence, in this order: glücklich, bin") {
+		t.Errorf("prompt = %q", gen.prompts[0])
+	}
+}
+
+func TestTimings(t *testing.T) {
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "analysis_valid.json"))}}
+	ctx, watch := withStopwatch(context.Background())
+	if _, err := performWordAnalysis(ctx, gen, "test-model", Prompts{}, GeminiSettings{}, "Ich bin glücklich.", nil, Request{UserLang: "en", TargetLang: "de"}); err != nil {
+		t.Fatal(err)
+	}
+	if api, parse := watch.step(time.Hour); api <= 0 || api >= time.Hour || parse <= 0 {
+		t.Errorf("step = %v, %v; want the API call and parse times", api, parse)
+	}
+
+	// Providers reporting no API calls count the whole step
+	_, watch = withStopwatch(context.Background())
+	if api, _ := watch.step(time.Second); api != time.Second {
+		t.Errorf("step without calls = %v, want 1s", api)
+	}
+
+	timings := Timings{Translation: 1234 * time.Millisecond, Parse: 2300 * time.Microsecond}
+	if got, want := timings.String(), "translation 1.2s · parse 2ms"; got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+}
+
+func TestGuessLanguage(t *testing.T) {
+	RegisterLanguage(Language{Code: "ru", Name: "Russian", Script: "Cyrillic"})
+	tests := []struct {
+		sentence string
+		codes    []string
+		want     string
+	}{
+		{"Ich bin müde und das ist gut.", []string{"sv", "de"}, "de"},
+		{"Jag är trött och det är bra.", []string{"sv", "de"}, "sv"},
+		{"Я устал.", []string{"en", "ru"}, "ru"},
+		{"Okay.", []string{"en", "de"}, ""},
+	}
+	for _, tt := range tests {
+		if got := GuessLanguage(tt.sentence, tt.codes...); got != tt.want {
+			t.Errorf("GuessLanguage(%q, %v) = %q, want %q", tt.sentence, tt.codes, got, tt.want)
+		}
+	}
+}
+
+// fakeSteps runs both pipeline steps, recording the sentences it analyzed. Unless the
+// analysis started already, translating waits for it to start until a timeout.
+type fakeSteps struct {
+	step     TranslationStep
+	started  chan struct{}
+	wait     bool
+	analyzed chan string
+}
+
+func (f *fakeSteps) TranslationModel() string { return "fake" }
+func (f *fakeSteps) AnalysisModel() string    { return "fake" }
+
+func (f *fakeSteps) Translate(context.Context, Request) (*TranslationStep, error) {
+	if f.wait {
+		select {
+		case <-f.started:
+		case <-time.After(time.Second):
+			return nil, errors.New("analysis did not start alongside the translation")
+		}
+	}
+	return &f.step, nil
+}
+
+func (f *fakeSteps) AnalyzeWords(_ context.Context, sentence string, _ Request) (*AnalysisStep, error) {
+	f.analyzed <- sentence
+	close(f.started)
+	return &AnalysisStep{SentenceLevel: "A1", WordAnalysis: []WordAnalysisItem{{Word: "müde", Analysis: "tired"}}}, nil
+}
+
+func TestPipelineAnalyzesTargetLanguageInputEarly(t *testing.T) {
+	tests := []struct {
+		name     string
+		sentence string
+		step     TranslationStep
+		wait     bool
+	}{
+		{"input in target language", "Ich bin müde und das ist gut.", TranslationStep{InputLanguage: "German", CleanedSentence: "Ich bin müde und das ist gut.", Translation: "Jag är trött och det är bra."}, true},
+		{"input in user language", "Jag är trött och det är bra.", TranslationStep{InputLanguage: "Swedish", CleanedSentence: "Jag är trött och det är bra.", Translation: "Ich bin müde und das ist gut."}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSteps{step: tt.step, started: make(chan struct{}), wait: tt.wait, analyzed: make(chan string, 2)}
+			result, err := Pipeline{Translator: fake, Analyzer: fake}.Run(context.Background(), Request{Sentence: tt.sentence, UserLang: "sv", TargetLang: "de"}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := <-fake.analyzed; got != "Ich bin müde und das ist gut." || len(fake.analyzed) > 0 {
+				t.Errorf("analyzed %q, then %d more", got, len(fake.analyzed))
+			}
+			if result.Foreign != "Ich bin müde und das ist gut." || len(result.Words) != 1 {
+				t.Errorf("result = %+v", result)
+			}
+		})
+	}
+}
+
+func TestPromptTemplates(t *testing.T) {
+	tmpl, err := ParsePrompt("analysis", `Explain "{{.Sentence}}" ({{.TargetLangCode}}) to a {{.Level}} learner in {{.UserLang}}; keep {{join .Glossary ", "}}.`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "analysis_valid.json"))}}
+	req := Request{UserLang: "en", TargetLang: "de", Level: "B1", Glossary: []string{"Anna", "Berlin"}}
+	if _, err := performWordAnalysis(context.Background(), gen, "test-model", Prompts{Analysis: tmpl}, GeminiSettings{}, "Ich bin glücklich.", nil, req); err != nil {
+		t.Fatal(err)
+	}
+	want := `Explain "Ich bin glücklich." (de) to a B1 learner in English; keep Anna, Berlin.`
+	if gen.prompts[0] != want {
+		t.Errorf("prompt = %q, want %q", gen.prompts[0], want)
+	}
+
+	if _, err := ParsePrompt("translation", "{{.Sentense}}"); err == nil {
+		t.Error("unknown variable: got no error")
+	}
+}
+
+func TestFewShotExamples(t *testing.T) {
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "analysis_valid.json"))}}
+	prompts := Prompts{
+		System:           "Always explain Serbian clitics explicitly.",
+		AnalysisExamples: []Example{{Input: "Vidim ga.", Output: `{"word_analysis": []}`}},
+	}
+	if _, err := performWordAnalysis(context.Background(), gen, "test-model", prompts, GeminiSettings{}, "Dajem joj knjigu.", nil, Request{UserLang: "en", TargetLang: "sr"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := gen.config.SystemInstruction; got == nil || got.Parts[0].Text != prompts.System {
+		t.Errorf("system instruction = %+v", got)
+	}
+	if len(gen.contents) != 3 {
+		t.Fatalf("got %d contents, want the example prompt, its answer and the prompt", len(gen.contents))
+	}
+	if !strings.Contains(gen.contents[0].Parts[0].Text, `"Vidim ga."`) || gen.contents[1].Role != genai.RoleModel || gen.contents[1].Parts[0].Text != `{"word_analysis": []}` {
+		t.Errorf("example turns = %q, %q", gen.contents[0].Parts[0].Text, gen.contents[1].Parts[0].Text)
+	}
+	if !strings.Contains(gen.contents[2].Parts[0].Text, `"Dajem joj knjigu."`) {
+		t.Errorf("prompt = %q", gen.contents[2].Parts[0].Text)
+	}
+}
+
+func TestRegisterLanguage(t *testing.T) {
+	RegisterLanguage(Language{Code: "tlh", Name: "Klingon", Script: "pIqaD", Notes: "Use the standard dictionary forms."})
+	if got := LanguageName("tlh"); got != "Klingon" {
+		t.Errorf("LanguageName = %q, want Klingon", got)
+	}
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "translation_valid.json"))}}
+	if _, err := performTranslation(context.Background(), gen, "test-model", Prompts{}, GeminiSettings{}, Request{Sentence: "Hello", UserLang: "en", TargetLang: "tlh"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "- About Klingon: write it in pIqaD script. Use the standard dictionary forms."; !strings.Contains(gen.prompts[0], want) {
+		t.Errorf("prompt %q does not contain %q", gen.prompts[0], want)
+	}
+}
+
+func TestGeminiSettings(t *testing.T) {
+	threshold, err := ParseSafetyThreshold("block_only_high")
+	if err != nil || threshold != genai.HarmBlockThresholdBlockOnlyHigh {
+		t.Fatalf("ParseSafetyThreshold = %q, %v", threshold, err)
+	}
+	if _, err := ParseSafetyThreshold("lenient"); err == nil {
+		t.Error("unknown threshold: got no error")
+	}
+
+	settings := GeminiSettings{SafetyThreshold: threshold, AnalysisThinkingBudget: genai.Ptr[int32](2048)}
+	config := buildAnalysisConfig(Request{UserLang: "en", TargetLang: "de"}, settings)
+	if len(config.SafetySettings) != len(safetyCategories) || config.SafetySettings[0].Threshold != threshold {
+		t.Errorf("safety settings = %+v", config.SafetySettings)
+	}
+	if config.ThinkingConfig == nil || *config.ThinkingConfig.ThinkingBudget != 2048 {
+		t.Errorf("thinking config = %+v", config.ThinkingConfig)
+	}
+	if config := buildTranslationConfig("English", "German", settings); config.ThinkingConfig != nil {
+		t.Errorf("translation thinking config = %+v, want the model default", config.ThinkingConfig)
+	}
+
+	gen := &fakeGenerator{responses: []fakeResponse{{resp: &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonSafety}},
+	}}}}
+	_, err = performTranslation(context.Background(), gen, "test-model", Prompts{}, settings, Request{Sentence: "Er erschoss den Räuber.", UserLang: "en", TargetLang: "de"})
+	if !errors.Is(err, ErrBlocked) {
+		t.Errorf("blocked response: got %v, want ErrBlocked", err)
+	}
+}
+
+func TestGetForeignSentence(t *testing.T) {
+	step := &TranslationStep{
+		InputLanguage:   "German",
+		CleanedSentence: "Ich bin müde.",
+		Translation:     "I am tired.",
+	}
+	if got := ForeignSentence(step, "German"); got != "Ich bin müde." {
+		t.Errorf("input in target language: got %q", got)
+	}
+	step.InputLanguage = "English"
+	step.CleanedSentence, step.Translation = step.Translation, step.CleanedSentence
+	if got := ForeignSentence(step, "German"); got != "Ich bin müde." {
+		t.Errorf("input in user language: got %q", got)
+	}
+}
+
+func TestProcessWordAnalysis(t *testing.T) {
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "analysis_punctuation.json"))}}
+	analysis, err := performWordAnalysis(context.Background(), gen, "test-model", Prompts{}, GeminiSettings{}, "„Hallo, Welt!“ ...", nil, Request{UserLang: "en", TargetLang: "de"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := processWordAnalysis(analysis)
+	want := []WordInfo{
+		{WordInTargetLang: "Hallo", GrammaticalExplanation: "hello - interjection", Level: "A1"},
+		{WordInTargetLang: "Welt", GrammaticalExplanation: "world - feminine noun", Level: "A2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestProcessWordAnalysisIdioms(t *testing.T) {
+	gen := &fakeGenerator{responses: []fakeResponse{textResponse(loadFixture(t, "analysis_idioms.json"))}}
+	analysis, err := performWordAnalysis(context.Background(), gen, "test-model", Prompts{}, GeminiSettings{}, "Ich verstehe nur Bahnhof, das Gift.", nil, Request{UserLang: "en", TargetLang: "de"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := processWordAnalysis(analysis)
+	if len(got) != 4 {
+		t.Fatalf("got %d items, want 4: %+v", len(got), got)
+	}
+	if got[1].WordInTargetLang != "verstehe nur Bahnhof" || !got[1].Idiom {
+		t.Errorf("idiom was not kept as a unit: %+v", got[1])
+	}
+	wantParts := []WordPart{
+		{Word: "verstehe", Analysis: "understand - 1st person singular of verstehen"},
+		{Word: "nur", Analysis: "only - adverb"},
+		{Word: "Bahnhof", Analysis: "train station - masculine noun"},
+	}
+	if !reflect.DeepEqual(got[1].Parts, wantParts) {
+		t.Errorf("parts = %+v, want %+v", got[1].Parts, wantParts)
+	}
+	if got[2].Grouped() {
+		t.Errorf("single word with one part is grouped: %+v", got[2])
+	}
+	if got[3].FalseFriend != "Means poison, not a present" || got[3].Idiom {
+		t.Errorf("false friend = %+v", got[3])
+	}
+	if got[3].Gender != GenderNeuter || got[3].Article != "das" || got[3].Plural != "Gifte" {
+		t.Errorf("noun forms = %+v", got[3])
+	}
+	if got[3].Lemma != "Gift" || got[3].PartOfSpeech != "noun" || got[3].Gloss != "poison" {
+		t.Errorf("dictionary form = %+v", got[3])
+	}
+	if got[2].Gender != "" {
+		t.Errorf("gender of an article = %q, want none", got[2].Gender)
+	}
+}
+
+func TestAboveLevel(t *testing.T) {
+	tests := []struct {
+		level, known string
+		want         bool
+	}{
+		{"B2", "B1", true},
+		{"b2", "B1", true},
+		{"B1", "B1", false},
+		{"A2", "B1", false},
+		{"C2", "", false},
+		{"", "A1", false},
+		{"N5", "A1", false},
+	}
+	for _, tt := range tests {
+		if got := AboveLevel(tt.level, tt.known); got != tt.want {
+			t.Errorf("AboveLevel(%q, %q) = %v, want %v", tt.level, tt.known, got, tt.want)
+		}
+	}
+}
+
+func TestRemovePunctuation(t *testing.T) {
+	tests := map[string]string{
+		"Hallo!":          "Hallo",
+		"„Welt“":          "Welt",
+		"ich freue  mich": "ich freue mich",
+		"...":             "",
+		"čaša,":           "čaša",
+		"3.":              "3",
+	}
+	for in, want := range tests {
+		if got := removePunctuation(in); got != want {
+			t.Errorf("removePunctuation(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewRateLimiter(2, 100)
+	l.now = func() time.Time { return now }
+	expired, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	release, err := l.Acquire(context.Background(), 80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Acquire(expired, 30); !errors.Is(err, context.Canceled) {
+		t.Errorf("over the token limit: err = %v, want it to wait", err)
+	}
+	release(10) // The estimate was too high
+	if _, err := l.Acquire(expired, 30); err != nil {
+		t.Errorf("within the token limit: %v", err)
+	}
+	if _, err := l.Acquire(expired, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("over the request limit: err = %v, want it to wait", err)
+	}
+
+	now = now.Add(rateWindow)
+	if _, err := l.Acquire(expired, 500); err != nil {
+		t.Errorf("a minute later: %v", err)
+	}
+}
+
+func TestWithKeyRotation(t *testing.T) {
+	activeKey.Store(0)
+	defer activeKey.Store(0)
+
+	quota := genai.APIError{Code: 429, Status: "RESOURCE_EXHAUSTED"}
+	calls := 0
+	got, err := withKeyRotation([]string{"a", "b"}, func(key string) (string, error) {
+		calls++
+		if key == "a" {
+			return "", quota
+		}
+		return key, nil
+	})
+	if err != nil || got != "b" || calls != 2 {
+		t.Fatalf("got %q, %v after %d calls", got, err, calls)
+	}
+	if activeKey.Load() != 1 {
+		t.Errorf("active key = %d, want 1", activeKey.Load())
+	}
+}
+
+func TestModelFallback(t *testing.T) {
+	defer unavailableModels.Clear()
+
+	gen := &fakeGenerator{responses: []fakeResponse{
+		{err: genai.APIError{Code: 404, Status: "NOT_FOUND"}},
+		textResponse(loadFixture(t, "translation_valid.json")),
+		textResponse(loadFixture(t, "translation_valid.json")),
+	}}
+	p := NewGeminiProvider([]ContentGenerator{gen}, Models{Translation: "expired-preview", Analysis: "expired-preview"})
+	p.SetFallbackModels([]string{"stable"})
+	req := Request{Sentence: "i am happy", UserLang: "en", TargetLang: "de"}
+	if _, err := p.Translate(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.TranslationModel(); got != "stable" {
+		t.Errorf("translation model = %q, want the fallback", got)
+	}
+
+	// The unavailable model is skipped for the rest of the session
+	if _, err := p.Translate(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if len(gen.prompts) != 3 {
+		t.Errorf("got %d calls, want 3", len(gen.prompts))
+	}
+
+	// Other errors don't fall back
+	gen = &fakeGenerator{responses: []fakeResponse{{err: genai.APIError{Code: 500, Status: "INTERNAL"}}}}
+	p = NewGeminiProvider([]ContentGenerator{gen}, Models{Translation: "flaky", Analysis: "flaky"})
+	p.SetFallbackModels([]string{"stable"})
+	if _, err := p.Translate(context.Background(), req); err == nil || len(gen.prompts) != 1 {
+		t.Errorf("server error: got %v after %d calls", err, len(gen.prompts))
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := map[string][]string{
+		"Jag är glad.": {"Jag är glad."},
+		"Jag är glad. Du är trött!  Är vi hemma?":  {"Jag är glad.", "Du är trött!", "Är vi hemma?"},
+		"Das ist z.B. gut. Dr. Weber kommt.":       {"Das ist z.B. gut.", "Dr. Weber kommt."},
+		`Er sagte: „Hallo.“ Dann ging er.`:         {`Er sagte: „Hallo.“`, "Dann ging er."},
+		"¿Qué tal? ¡Muy bien!":                     {"¿Qué tal?", "¡Muy bien!"},
+		"It costs 3.50 euros. J. R. R. Tolkien...": {"It costs 3.50 euros.", "J. R. R. Tolkien..."},
+		"First line\nstill first.\n\nSecond":       {"First line still first.", "Second"},
+		"   ":                                      nil,
+	}
+	for in, want := range tests {
+		if got := SplitSentences(in); !slices.Equal(got, want) {
+			t.Errorf("SplitSentences(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMaskPlaceholders(t *testing.T) {
+	m := MaskPlaceholders("Hello %s, you have {count} new %(kind)s messages ({{n}} unread, 100%% sure)")
+	if want := "Hello ⟦0⟧, you have ⟦1⟧ new ⟦2⟧ messages (⟦3⟧ unread, 100%% sure)"; m.Text != want {
+		t.Fatalf("masked = %q, want %q", m.Text, want)
+	}
+	got, err := m.Restore("Hallo ⟦0⟧, du hast ⟦1⟧ neue ⟦2⟧ Nachrichten (⟦3⟧ ungelesen, 100%% sicher)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Hallo %s, du hast {count} neue %(kind)s Nachrichten ({{n}} ungelesen, 100%% sicher)"; got != want {
+		t.Errorf("restored = %q, want %q", got, want)
+	}
+	if _, err := m.Restore("Hallo ⟦0⟧, du hast neue Nachrichten"); err == nil {
+		t.Error("expected an error for lost placeholders")
+	}
+	if m := MaskPlaceholders("Hi {{.Name}}, see $t(common.more)"); m.Text != "Hi ⟦0⟧, see ⟦1⟧" {
+		t.Errorf("masked = %q", m.Text)
+	}
+}
+
+func TestProtect(t *testing.T) {
+	m := Protect("Ask [[Anna Berg]] at anna@example.com about `go vet` and Kubernetes, see https://go.dev/doc.", []string{"Kubernetes"})
+	if want := "Ask ⟦0⟧ at ⟦1⟧ about ⟦2⟧ and ⟦3⟧, see ⟦4⟧."; m.Text != want {
+		t.Fatalf("masked = %q, want %q", m.Text, want)
+	}
+	got, err := m.Restore("Fråga ⟦0⟧ på ⟦1⟧ om ⟦2⟧ och ⟦3⟧, se ⟦4⟧.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Fråga Anna Berg på anna@example.com om `go vet` och Kubernetes, se https://go.dev/doc."; got != want {
+		t.Errorf("restored = %q, want %q", got, want)
+	}
+
+	step := &TranslationStep{CleanedSentence: "Fråga ⟦0⟧.", Translation: "Ask Anna."}
+	if err := restoreProtected(step, Protect("Fråga [[Anna]].", nil)); err == nil {
+		t.Error("expected an error for a translation that dropped a protected span")
+	}
+}
+
+func TestProtectMarkup(t *testing.T) {
+	text := "Read the **[guide](https://go.dev/doc)** <em>now</em> &amp; run `go test`"
+	m := Protect(text, nil)
+	if want := "Read the ⟦0⟧⟦1⟧guide⟦2⟧⟦3⟧ ⟦4⟧now⟦5⟧ ⟦6⟧ run ⟦7⟧"; m.Text != want {
+		t.Fatalf("masked = %q, want %q", m.Text, want)
+	}
+	got, err := m.Restore("Läs ⟦0⟧⟦1⟧guiden⟦2⟧⟦3⟧ ⟦4⟧nu⟦5⟧ ⟦6⟧ kör ⟦7⟧")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Läs **[guiden](https://go.dev/doc)** <em>nu</em> &amp; kör `go test`"; got != want {
+		t.Errorf("restored = %q, want %q", got, want)
+	}
+
+	if HasMarkup("snake_case and 5 * 3 are plain text") {
+		t.Error("plain text detected as markup")
+	}
+	if got, want := StripMarkup("## Hello *dear* [friend](http://x.y)"), "Hello dear friend"; got != want {
+		t.Errorf("stripped = %q, want %q", got, want)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		text  string
+		langs []string
+		want  string
+		spans []string
+	}{
+		{"Hi Anna, please call me at +46 70 123 45 67 or write to anna.svensson@example.com.", []string{"en"},
+			"Hi ⟦2⟧, please call me at ⟦1⟧ or write to ⟦0⟧.", []string{"anna.svensson@example.com", "+46 70 123 45 67", "Anna"}},
+		{"The meeting with Erik Lindqvist moved to 2026-10-15.", []string{"en"},
+			"The meeting with ⟦0⟧ moved to 2026-10-15.", []string{"Erik Lindqvist"}},
+		{"Sehr geehrte Frau Weber, der Termin ist am Montag.", []string{"de"},
+			"Sehr geehrte ⟦0⟧, der Termin ist am Montag.", []string{"Frau Weber"}},
+		{"Der Vertrag liegt bei Herr Schmidt.", []string{"sv", "de"},
+			"Der Vertrag liegt bei Herr ⟦0⟧.", []string{"Schmidt"}},
+		{"Thanks for the update.\nBest regards,\nMaria", []string{"en"},
+			"Thanks for the update.\nBest regards,\n⟦0⟧", []string{"Maria"}},
+		{"Ask\tErik Lindqvist about the invoice.", []string{"en"},
+			"Ask\t⟦0⟧ about the invoice.", []string{"Erik Lindqvist"}},
+	}
+	for _, tt := range tests {
+		var m Masked
+		got := m.Redact(tt.text, tt.langs...)
+		if got != tt.want || !slices.Equal(m.Spans, tt.spans) {
+			t.Errorf("Redact(%q) = %q %q, want %q %q", tt.text, got, m.Spans, tt.want, tt.spans)
+		}
+		if restored := m.Expand(got); restored != tt.text {
+			t.Errorf("Expand(%q) = %q", got, restored)
+		}
+	}
+}
+
+// recordingSteps runs both pipeline steps on whatever it is sent, recording it.
+type recordingSteps struct {
+	sent []string
+}
+
+func (r *recordingSteps) TranslationModel() string { return "fake" }
+func (r *recordingSteps) AnalysisModel() string    { return "fake" }
+
+func (r *recordingSteps) Translate(_ context.Context, req Request) (*TranslationStep, error) {
+	r.sent = append(r.sent, req.Sentence+" "+strings.Join(req.Glossary, ","))
+	translation := strings.NewReplacer("Hi", "Hej", "call me", "ring mig", "tomorrow", "i morgon").Replace(req.Sentence)
+	return &TranslationStep{InputLanguage: "English", CleanedSentence: req.Sentence, Translation: translation}, nil
+}
+
+func (r *recordingSteps) AnalyzeWords(_ context.Context, sentence string, _ Request) (*AnalysisStep, error) {
+	r.sent = append(r.sent, sentence)
+	return &AnalysisStep{WordAnalysis: []WordAnalysisItem{
+		{Word: "Hej", Analysis: "greeting of ⟦2⟧"},
+		{Word: "⟦2⟧", Analysis: "a name"},
+		{Word: "ring", Analysis: "call"},
+	}}, nil
+}
+
+func TestPipelineRedacts(t *testing.T) {
+	steps := &recordingSteps{}
+	p := Pipeline{Translator: steps, Analyzer: steps, Redact: true, DoNotTranslate: []string{"Acme"}}
+	result, err := p.Run(context.Background(), Request{Sentence: "Hi Anna, call me tomorrow at 070-123 45 67 about Acme.", UserLang: "en", TargetLang: "sv"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sent := range steps.sent {
+		if strings.Contains(sent, "Anna") || strings.Contains(sent, "070") || strings.Contains(sent, "Acme") {
+			t.Errorf("sent personal data: %q", sent)
+		}
+	}
+	if result.Translation != "Hej Anna, ring mig i morgon at 070-123 45 67 about Acme." {
+		t.Errorf("translation = %q", result.Translation)
+	}
+	if len(result.Words) != 2 || result.Words[0].GrammaticalExplanation != "greeting of Anna" {
+		t.Errorf("words = %+v", result.Words)
+	}
+}